@@ -0,0 +1,7 @@
+data "okta_network_zone_overlaps" "example" {
+  zone_ids = [okta_network_zone.office.id, okta_network_zone.vpn.id]
+}
+
+output "overlapping_gateways" {
+  value = [for o in data.okta_network_zone_overlaps.example.overlaps : "${o.zone_name} ${o.gateway} and ${o.other_zone_name} ${o.other_gateway} share ${o.addresses}"]
+}
@@ -0,0 +1,15 @@
+resource "okta_network_zone" "a" {
+  name     = "testAcc_replace_with_uuid A"
+  type     = "IP"
+  gateways = ["10.0.0.0/16", "2001:db8::/48"]
+}
+
+resource "okta_network_zone" "b" {
+  name     = "testAcc_replace_with_uuid B"
+  type     = "IP"
+  gateways = ["10.0.255.0-10.1.0.255", "192.168.0.1"]
+}
+
+data "okta_network_zone_overlaps" "test" {
+  zone_ids = [okta_network_zone.a.id, okta_network_zone.b.id]
+}
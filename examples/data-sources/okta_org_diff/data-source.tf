@@ -0,0 +1,24 @@
+provider "okta" {
+  orgs {
+    name      = "preview"
+    org_name  = "acme-preview"
+    base_url  = "oktapreview.com"
+    api_token = var.preview_api_token
+  }
+  orgs {
+    name      = "prod"
+    org_name  = "acme"
+    base_url  = "okta.com"
+    api_token = var.prod_api_token
+  }
+}
+
+data "okta_org_diff" "promotion" {
+  source_org   = "preview"
+  target_org   = "prod"
+  object_types = ["policies", "policy_rules", "network_zones"]
+}
+
+output "changes_to_promote" {
+  value = [for d in data.okta_org_diff.promotion.differences : "${d.object_type} ${d.name}: ${d.change} ${join(", ", d.attributes)}"]
+}
@@ -0,0 +1,11 @@
+resource "okta_network_zone" "test" {
+  name     = "testAcc_replace_with_uuid"
+  type     = "IP"
+  gateways = ["10.0.0.0/16"]
+}
+
+data "okta_org_diff" "test" {
+  object_types = ["network_zones", "group_rules"]
+
+  depends_on = [okta_network_zone.test]
+}
@@ -0,0 +1,7 @@
+data "okta_saml_expiring_certificates" "example" {
+  within_days = 30
+}
+
+output "expiring_app_certificates" {
+  value = [for cert in data.okta_saml_expiring_certificates.example.certificates : cert.name if cert.type == "APP" && cert.active]
+}
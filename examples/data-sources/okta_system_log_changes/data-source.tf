@@ -0,0 +1,9 @@
+data "okta_system_log_changes" "example" {
+  target_ids        = [okta_group.example.id, okta_app_oauth.example.id]
+  since             = "2025-01-01T00:00:00Z"
+  exclude_actor_ids = [var.terraform_service_app_id]
+}
+
+output "out_of_band_changes" {
+  value = [for e in data.okta_system_log_changes.example.events : "${e.published} ${e.actor.alternate_id} ${e.event_type}"]
+}
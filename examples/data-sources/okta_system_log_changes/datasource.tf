@@ -0,0 +1,9 @@
+resource "okta_group" "test" {
+  name        = "testAcc_replace_with_uuid"
+  description = "testAcc_replace_with_uuid"
+}
+
+data "okta_system_log_changes" "test" {
+  target_ids = [okta_group.test.id]
+  filter     = "eventType eq \"group.lifecycle.create\""
+}
@@ -0,0 +1,3 @@
+data "okta_system_log_changes" "test" {
+  target_ids = ["00g1234567890abcdefg\" or target.id pr or \""]
+}
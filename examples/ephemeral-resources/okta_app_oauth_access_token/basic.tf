@@ -0,0 +1,31 @@
+variable "hostname" {
+  type = string
+}
+
+resource "okta_app_oauth" "test" {
+  label                      = "testAcc_replace_with_uuid"
+  type                       = "service"
+  grant_types                = ["client_credentials"]
+  response_types             = ["token"]
+  token_endpoint_auth_method = "client_secret_basic"
+}
+
+resource "okta_app_oauth_api_scope" "test" {
+  app_id = okta_app_oauth.test.id
+  issuer = "https://${var.hostname}"
+  scopes = ["okta.users.read"]
+}
+
+ephemeral "okta_app_oauth_access_token" "test" {
+  client_id     = okta_app_oauth.test.client_id
+  client_secret = okta_app_oauth.test.client_secret
+  scopes        = okta_app_oauth_api_scope.test.scopes
+}
+
+# the echo provider copies the ephemeral values into state so they can be
+# checked
+provider "echo" {
+  data = ephemeral.okta_app_oauth_access_token.test
+}
+
+resource "echo" "test" {}
@@ -0,0 +1,15 @@
+ephemeral "okta_app_oauth_access_token" "example" {
+  client_id     = okta_app_oauth.example.client_id
+  client_secret = var.client_secret
+  scopes        = ["okta.users.read"]
+}
+
+### With private_key_jwt client authentication and a custom authorization server
+
+ephemeral "okta_app_oauth_access_token" "example" {
+  client_id               = okta_app_oauth.example.client_id
+  private_key             = var.private_key
+  private_key_id          = "SIGNING_KEY_RSA"
+  authorization_server_id = okta_auth_server.example.id
+  scopes                  = ["example:read"]
+}
@@ -0,0 +1,44 @@
+variable "hostname" {
+  type = string
+}
+
+resource "okta_app_oauth" "test" {
+  label                      = "testAcc_replace_with_uuid"
+  type                       = "service"
+  grant_types                = ["client_credentials"]
+  response_types             = ["token"]
+  token_endpoint_auth_method = "client_secret_basic"
+}
+
+resource "okta_app_oauth_api_scope" "test" {
+  app_id = okta_app_oauth.test.id
+  issuer = "https://${var.hostname}"
+  scopes = ["okta.users.read"]
+}
+
+ephemeral "okta_app_oauth_client_secret" "test" {
+  app_id          = okta_app_oauth.test.id
+  delete_on_close = true
+
+  depends_on = [okta_app_oauth_api_scope.test]
+}
+
+ephemeral "okta_app_oauth_access_token" "test" {
+  client_id     = okta_app_oauth.test.client_id
+  client_secret = ephemeral.okta_app_oauth_client_secret.test.client_secret
+  scopes        = okta_app_oauth_api_scope.test.scopes
+}
+
+# the echo provider copies the ephemeral values into state so they can be
+# checked
+provider "echo" {
+  data = {
+    secret_id     = ephemeral.okta_app_oauth_client_secret.test.id
+    client_secret = ephemeral.okta_app_oauth_client_secret.test.client_secret
+    status        = ephemeral.okta_app_oauth_client_secret.test.status
+    access_token  = ephemeral.okta_app_oauth_access_token.test.access_token
+    token_type    = ephemeral.okta_app_oauth_access_token.test.token_type
+  }
+}
+
+resource "echo" "test" {}
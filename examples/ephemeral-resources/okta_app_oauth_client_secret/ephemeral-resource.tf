@@ -0,0 +1,12 @@
+# The secret is deleted again once Terraform is done with it, e.g. after it
+# was used to check that the app can request an access token.
+ephemeral "okta_app_oauth_client_secret" "example" {
+  app_id          = okta_app_oauth.example.id
+  delete_on_close = true
+}
+
+ephemeral "okta_app_oauth_access_token" "example" {
+  client_id     = okta_app_oauth.example.client_id
+  client_secret = ephemeral.okta_app_oauth_client_secret.example.client_secret
+  scopes        = ["okta.users.read"]
+}
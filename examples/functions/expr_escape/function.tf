@@ -0,0 +1,7 @@
+resource "okta_group_rule" "example" {
+  name              = "Engineering"
+  status            = "ACTIVE"
+  group_assignments = [okta_group.engineering.id]
+  expression_type   = "urn:okta:expression:1.0"
+  expression_value  = "user.department == ${provider::okta::expr_escape(var.department)}"
+}
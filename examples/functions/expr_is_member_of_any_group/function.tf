@@ -0,0 +1,7 @@
+resource "okta_group_rule" "example" {
+  name              = "All staff"
+  status            = "ACTIVE"
+  group_assignments = [okta_group.all_staff.id]
+  expression_type   = "urn:okta:expression:1.0"
+  expression_value  = provider::okta::expr_is_member_of_any_group([okta_group.engineering.id, okta_group.sales.id])
+}
@@ -0,0 +1,12 @@
+locals {
+  # e.g. "aus1234567890abcdefg/00p1234567890abcdefg/0pr1234567890abcdefg"
+  rule_id_parts = provider::okta::parse_import_id(var.policy_rule_import_id)
+}
+
+output "auth_server_id" {
+  value = local.rule_id_parts[0]
+}
+
+output "policy_id" {
+  value = local.rule_id_parts[1]
+}
@@ -0,0 +1,7 @@
+list "okta_app_oauth" "all" {
+  provider = okta
+
+  config {
+    active_only = true
+  }
+}
@@ -0,0 +1,7 @@
+list "okta_app_saml" "all" {
+  provider = okta
+
+  config {
+    label_prefix = "Finance"
+  }
+}
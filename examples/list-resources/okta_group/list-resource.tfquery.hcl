@@ -0,0 +1,11 @@
+list "okta_group" "all" {
+  provider = okta
+}
+
+list "okta_group" "engineering" {
+  provider = okta
+
+  config {
+    q = "Engineering"
+  }
+}
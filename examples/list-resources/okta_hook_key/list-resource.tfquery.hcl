@@ -0,0 +1,3 @@
+list "okta_hook_key" "all" {
+  provider = okta
+}
@@ -0,0 +1,3 @@
+list "okta_log_stream" "all" {
+  provider = okta
+}
@@ -0,0 +1,7 @@
+list "okta_network_zone" "policy" {
+  provider = okta
+
+  config {
+    usage = "POLICY"
+  }
+}
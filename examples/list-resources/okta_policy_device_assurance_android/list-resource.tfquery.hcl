@@ -0,0 +1,3 @@
+list "okta_policy_device_assurance_android" "all" {
+  provider = okta
+}
@@ -0,0 +1,3 @@
+list "okta_policy_device_assurance_macos" "all" {
+  provider = okta
+}
@@ -0,0 +1,7 @@
+list "okta_policy_password" "active" {
+  provider = okta
+
+  config {
+    status = "ACTIVE"
+  }
+}
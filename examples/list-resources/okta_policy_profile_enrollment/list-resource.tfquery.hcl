@@ -0,0 +1,3 @@
+list "okta_policy_profile_enrollment" "all" {
+  provider = okta
+}
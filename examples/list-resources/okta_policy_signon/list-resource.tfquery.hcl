@@ -0,0 +1,3 @@
+list "okta_policy_signon" "all" {
+  provider = okta
+}
@@ -0,0 +1,3 @@
+list "okta_realm" "all" {
+  provider = okta
+}
@@ -0,0 +1 @@
+terraform import okta_app_oauth_client_secret.example <app_id>/<secret_id>
@@ -0,0 +1,24 @@
+resource "okta_app_oauth" "example" {
+  label                      = "example"
+  type                       = "service"
+  grant_types                = ["client_credentials"]
+  response_types             = ["token"]
+  token_endpoint_auth_method = "client_secret_basic"
+  omit_secret                = true
+}
+
+ephemeral "random_password" "client_secret" {
+  length = 40
+}
+
+# Incrementing client_secret_wo_version rotates the secret, the previous one
+# stays active for a day so that the services using it can reload the new one.
+# The secret generated with the application counts towards the limit of two
+# secrets per application, import it into this resource before the first
+# rotation so that it is deleted after the grace period.
+resource "okta_app_oauth_client_secret" "example" {
+  app_id                   = okta_app_oauth.example.id
+  client_secret_wo         = ephemeral.random_password.client_secret.result
+  client_secret_wo_version = 1
+  grace_period_minutes     = 1440
+}
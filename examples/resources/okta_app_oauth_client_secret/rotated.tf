@@ -0,0 +1,15 @@
+resource "okta_app_oauth" "test" {
+  label                      = "testAcc_replace_with_uuid"
+  type                       = "service"
+  grant_types                = ["client_credentials"]
+  response_types             = ["token"]
+  token_endpoint_auth_method = "client_secret_basic"
+}
+
+resource "okta_app_oauth_client_secret" "test" {
+  app_id                   = okta_app_oauth.test.id
+  client_secret_wo         = "testAcc-secret-value-2"
+  client_secret_wo_version = 2
+  grace_period_minutes     = 0
+  status                   = "INACTIVE"
+}
@@ -0,0 +1,23 @@
+resource "okta_app_oauth" "test" {
+  label                      = "testAcc_replace_with_uuid"
+  type                       = "service"
+  grant_types                = ["client_credentials"]
+  response_types             = ["token"]
+  token_endpoint_auth_method = "client_secret_basic"
+}
+
+resource "okta_app_oauth_jwk" "rsa" {
+  app_id         = okta_app_oauth.test.id
+  alg            = "RS256"
+  public_key_pem = <<-EOT
+    -----BEGIN PUBLIC KEY-----
+    MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAu9fj9zM5gXnImoz8fqv8
+    fU+NgNoSYAiKxPtZj2A6kkhyhAL6ohwoNLbJNHkUdN4ayS0yUKCw15/Mn76rXn9g
+    +/sNEhcAarRKiDyECk3LZnuUhzSOGOh8sXpeNFIpKm6P7AJj2tp36OYlSjIAmsoK
+    fd1LYd5RwYW/vZEvuLXtZQ1bG1SkAdBWXC3Bqk8XPaRPF+2O60qGpOhpLObtTCcG
+    wqw9FuVIKMoxx9xH/YheySliFwX3rome0wqqEPy09AO7Lc2uTRLnL5eNpQJ8bpou
+    PxDttk2cOWH8AKp4E04SopSeRgrJqXjhkorp2WXO12Y6w//8wVxU6TzclbOpV6de
+    /wIDAQAB
+    -----END PUBLIC KEY-----
+  EOT
+}
@@ -0,0 +1,36 @@
+resource "okta_app_oauth" "example" {
+  label                      = "example"
+  type                       = "service"
+  grant_types                = ["client_credentials"]
+  response_types             = ["token"]
+  token_endpoint_auth_method = "client_secret_basic"
+}
+
+resource "tls_private_key" "example" {
+  algorithm = "RSA"
+}
+
+# Changing the key replaces it, create_before_destroy adds the new key before
+# the old one is deactivated and deleted.
+resource "okta_app_oauth_jwk" "example" {
+  app_id         = okta_app_oauth.example.id
+  alg            = "RS256"
+  public_key_pem = tls_private_key.example.public_key_pem
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "okta_app_oauth_jwk" "from_jwk" {
+  app_id = okta_app_oauth.example.id
+  jwk = jsonencode({
+    kty = "EC"
+    kid = "example-ec-key"
+    use = "sig"
+    alg = "ES256"
+    crv = "P-256"
+    x   = "VvCtEAUnpCwQqxDbuTQ243hOObQ0ptGEBsRgOcLA87U"
+    y   = "0ldJwNSKLUCBTH8qtm4v6bFM80T4s8DIHg9RDVcWACk"
+  })
+}
@@ -0,0 +1,4 @@
+terraform import okta_app_saml_key_rotation.example <app_id>
+
+# a key generated by an earlier rotation, which the resource activates
+terraform import okta_app_saml_key_rotation.example <app_id>/<next_key_id>
@@ -0,0 +1,27 @@
+resource "okta_app_saml" "example" {
+  label                    = "example"
+  sso_url                  = "https://example.com"
+  recipient                = "https://example.com"
+  destination              = "https://example.com"
+  audience                 = "https://example.com/audience"
+  subject_name_id_template = "$${user.userName}"
+  subject_name_id_format   = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+  response_signed          = true
+  signature_algorithm      = "RSA_SHA256"
+  digest_algorithm         = "SHA256"
+  authn_context_class_ref  = "urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport"
+}
+
+# Generates a new key 60 days before the active one expires and makes it the
+# active key 14 days later. Run plan and apply on a schedule, e.g. daily.
+resource "okta_app_saml_key_rotation" "example" {
+  app_id                      = okta_app_saml.example.id
+  key_years_valid             = 2
+  rotate_days_before_expiry   = 60
+  activate_days_after_publish = 14
+}
+
+# The service provider trusts both the active and the next certificate
+output "idp_metadata" {
+  value = okta_app_saml_key_rotation.example.metadata
+}
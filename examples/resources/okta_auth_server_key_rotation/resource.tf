@@ -0,0 +1,14 @@
+resource "okta_auth_server" "example" {
+  audiences                 = ["api://example"]
+  credentials_rotation_mode = "MANUAL"
+  name                      = "example"
+}
+
+resource "time_rotating" "signing_keys" {
+  rotation_days = 90
+}
+
+resource "okta_auth_server_key_rotation" "example" {
+  auth_server_id   = okta_auth_server.example.id
+  rotation_trigger = time_rotating.signing_keys.id
+}
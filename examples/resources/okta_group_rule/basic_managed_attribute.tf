@@ -0,0 +1,22 @@
+resource "okta_group" "test" {
+  name = "testAcc_replace_with_uuid"
+}
+
+resource "okta_user_schema_property" "test" {
+  index       = "testAcc_replace_with_uuid"
+  title       = "terraform acceptance test"
+  type        = "string"
+  master      = "PROFILE_MASTER"
+  scope       = "NONE"
+  permissions = "READ_ONLY"
+}
+
+resource "okta_group_rule" "test" {
+  name              = "testAcc_replace_with_uuid"
+  status            = "ACTIVE"
+  group_assignments = [okta_group.test.id]
+  expression_type   = "urn:okta:expression:1.0"
+  expression_value  = "user.testAcc_replace_with_uuid==\"Sales\""
+
+  depends_on = [okta_user_schema_property.test]
+}
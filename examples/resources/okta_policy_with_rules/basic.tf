@@ -0,0 +1,38 @@
+resource "okta_group" "test" {
+  name = "testAcc_replace_with_uuid"
+}
+
+resource "okta_policy_with_rules" "test" {
+  type            = "PASSWORD"
+  name            = "testAcc_replace_with_uuid"
+  groups_included = [okta_group.test.id]
+  settings = jsonencode({
+    password = {
+      complexity = {
+        minLength = 12
+      }
+    }
+  })
+
+  rule {
+    name               = "testAcc_replace_with_uuid_a"
+    network_connection = "OFF_NETWORK"
+    actions = jsonencode({
+      passwordChange = { access = "DENY" }
+    })
+  }
+
+  rule {
+    name = "testAcc_replace_with_uuid_b"
+    actions = jsonencode({
+      passwordChange = { access = "ALLOW" }
+    })
+  }
+
+  rule {
+    name = "testAcc_replace_with_uuid_c"
+    actions = jsonencode({
+      selfServiceUnlock = { access = "ALLOW" }
+    })
+  }
+}
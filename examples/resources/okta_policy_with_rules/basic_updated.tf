@@ -0,0 +1,32 @@
+resource "okta_group" "test" {
+  name = "testAcc_replace_with_uuid"
+}
+
+resource "okta_policy_with_rules" "test" {
+  type            = "PASSWORD"
+  name            = "testAcc_replace_with_uuid Updated"
+  status          = "INACTIVE"
+  groups_included = [okta_group.test.id]
+  settings = jsonencode({
+    password = {
+      complexity = {
+        minLength = 14
+      }
+    }
+  })
+
+  rule {
+    name = "testAcc_replace_with_uuid_c"
+    actions = jsonencode({
+      selfServiceUnlock = { access = "ALLOW" }
+    })
+  }
+
+  rule {
+    name   = "testAcc_replace_with_uuid_a"
+    status = "INACTIVE"
+    actions = jsonencode({
+      passwordChange = { access = "DENY" }
+    })
+  }
+}
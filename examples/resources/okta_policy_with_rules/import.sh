@@ -0,0 +1 @@
+terraform import okta_policy_with_rules.example <policy id>
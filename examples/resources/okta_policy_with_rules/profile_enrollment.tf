@@ -0,0 +1,17 @@
+resource "okta_policy_with_rules" "test" {
+  type = "PROFILE_ENROLLMENT"
+  name = "testAcc_replace_with_uuid"
+
+  rule {
+    name = "testAcc_replace_with_uuid_a"
+    actions = jsonencode({
+      profileEnrollment = {
+        access            = "ALLOW"
+        unknownUserAction = "REGISTER"
+        activationRequirements = {
+          emailVerification = true
+        }
+      }
+    })
+  }
+}
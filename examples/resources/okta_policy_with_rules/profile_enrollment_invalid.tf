@@ -0,0 +1,19 @@
+resource "okta_policy_with_rules" "test" {
+  type = "PROFILE_ENROLLMENT"
+  name = "testAcc_replace_with_uuid"
+
+  rule {
+    name               = "testAcc_replace_with_uuid_a"
+    network_connection = "OFF_NETWORK"
+    actions = jsonencode({
+      profileEnrollment = { access = "ALLOW" }
+    })
+  }
+
+  rule {
+    name = "testAcc_replace_with_uuid_b"
+    actions = jsonencode({
+      profileEnrollment = { access = "DENY" }
+    })
+  }
+}
@@ -0,0 +1,29 @@
+resource "okta_policy_with_rules" "example" {
+  type            = "PASSWORD"
+  name            = "Contractors"
+  description     = "Password policy of contractors"
+  groups_included = [okta_group.contractors.id]
+  settings = jsonencode({
+    password = {
+      complexity = {
+        minLength = 12
+      }
+    }
+  })
+
+  rule {
+    name               = "Deny outside the office"
+    network_connection = "OFF_NETWORK"
+    actions = jsonencode({
+      passwordChange           = { access = "DENY" }
+      selfServicePasswordReset = { access = "DENY" }
+    })
+  }
+
+  rule {
+    name = "Allow"
+    actions = jsonencode({
+      passwordChange = { access = "ALLOW" }
+    })
+  }
+}
@@ -5,13 +5,15 @@ import (
 	"context"
 	"flag"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
-	"github.com/hashicorp/terraform-plugin-mux/tf6to5server"
+	"github.com/okta/terraform-provider-okta/okta/api"
 	"github.com/okta/terraform-provider-okta/okta/fwprovider"
+	"github.com/okta/terraform-provider-okta/okta/generate"
 	"github.com/okta/terraform-provider-okta/okta/provider"
 	"github.com/okta/terraform-provider-okta/okta/version"
 )
@@ -24,24 +26,22 @@ import (
 
 func main() {
 	var debug bool
+	var generateConfigOut, generateResourceTypes string
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.StringVar(&generateConfigOut, "generate-config-out", "", "directory to write the configuration and import blocks of the existing objects of the org to, for the resource types supported by terraform query, instead of running the provider")
+	flag.StringVar(&generateResourceTypes, "generate-resource-types", "", "comma separated resource types to generate the configuration of, defaults to all of the ones supported by terraform query")
 	flag.Parse()
 
 	primary := provider.Provider()
 	// TODO: Uses v5 protocol for now, however lets swap to v6 when a drop of support for TF versions prior to 1.0 can be made
-	framework, err := tf6to5server.DowngradeServer(context.Background(), providerserver.NewProtocol6(fwprovider.NewFrameworkProvider(version.OktaTerraformProviderVersion, primary)))
-	if err != nil {
-		log.Fatal(err.Error())
-	}
-
+	// The framework provider is served over v5 directly, rather than downgraded from v6, so that the list resources of the
+	// v2 plugin resources can hand their v5 schemas to the framework.
 	providers := []func() tfprotov5.ProviderServer{
 		// v2 plugin
 		primary.GRPCProvider,
 		// v3 plugin
-		func() tfprotov5.ProviderServer {
-			return framework
-		},
+		providerserver.NewProtocol5(fwprovider.NewFrameworkProvider(version.OktaTerraformProviderVersion, primary)),
 	}
 	// At the moment, there is no way to use tf6muxserver to mux the new and old provider because the okta.Provider().GRPCProvider only return protocolv5
 	// Most likely we will have to convert all of the old provider to new provider to use v6
@@ -52,6 +52,22 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if generateConfigOut != "" {
+		opts := generate.Options{
+			OutDir: generateConfigOut,
+			Logf:   log.Printf,
+		}
+		if generateResourceTypes != "" {
+			opts.ResourceTypes = strings.Split(generateResourceTypes, ",")
+		}
+		err := generate.Run(context.Background(), muxServer.ProviderServer().(tfprotov5.ProviderServerWithListResource), opts)
+		flushRateLimitState()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	var serveOpts []tf5server.ServeOpt
 
 	if debug {
@@ -63,7 +79,16 @@ func main() {
 		muxServer.ProviderServer,
 		serveOpts...,
 	)
+	// Serve returns once Terraform shuts the plugin down, write the rate limit
+	// updates of the last second before the process exits
+	flushRateLimitState()
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+func flushRateLimitState() {
+	if err := api.FlushRateLimitState(); err != nil {
+		log.Printf("[WARN] unable to save the rate limit state file: %v", err)
+	}
+}
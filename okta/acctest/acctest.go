@@ -70,8 +70,13 @@ func OktaResourceTest(t *testing.T, c resource.TestCase) {
 	// plug in the VCR
 	mgr := currentVCRManager(t.Name())
 
+	if IsFakeOktaEnabled() && mgr.IsVcrEnabled() {
+		t.Fatalf("ENV variables OKTA_FAKE_TF_ACC and OKTA_VCR_TF_ACC can't be set together")
+		return
+	}
+
 	if !mgr.IsVcrEnabled() {
-		// live ACC / non-VCR test
+		// live ACC, fake org ACC / non-VCR test
 		resource.Test(t, c)
 		return
 	}
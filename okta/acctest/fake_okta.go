@@ -0,0 +1,75 @@
+package acctest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/okta/terraform-provider-okta/okta/acctest/fakeokta"
+	"github.com/okta/terraform-provider-okta/okta/fwprovider"
+	okta_provider "github.com/okta/terraform-provider-okta/okta/provider"
+)
+
+// fakeOkta is the in-memory Okta org the acceptance tests run against when
+// ENV var OKTA_FAKE_TF_ACC is not empty.
+var fakeOkta *fakeokta.Server
+
+// The fake org is started before any test package sets up its clients so that
+// the provider under test and the clients of the test helpers all reach it
+// through OKTA_HTTP_PROXY. It lives for the whole test binary.
+func init() {
+	if !IsFakeOktaEnabled() {
+		return
+	}
+	fakeOkta = fakeokta.NewServer()
+	os.Setenv("OKTA_ORG_NAME", "fake")
+	os.Setenv("OKTA_BASE_URL", TestDomainName)
+	os.Setenv("OKTA_API_TOKEN", "token")
+	os.Setenv("OKTA_HTTP_PROXY", fakeOkta.URL)
+}
+
+// IsFakeOktaEnabled acceptance tests run against an in-memory fake of the
+// Okta management API, rather than an org, if ENV var OKTA_FAKE_TF_ACC is not
+// empty. The fake only covers users, groups, group memberships, apps,
+// policies and their rules, network zones, authorization servers and inline
+// hook keys.
+func IsFakeOktaEnabled() bool {
+	return os.Getenv("OKTA_FAKE_TF_ACC") != ""
+}
+
+// ProviderServer returns the unconfigured provider server the provider binary
+// serves: the plugin SDK and framework providers muxed together, without the
+// VCR of the acceptance tests.
+func ProviderServer(t *testing.T) tfprotov5.ProviderServerWithListResource {
+	t.Helper()
+	primary := okta_provider.Provider()
+	muxServer, err := tf5muxserver.NewMuxServer(context.Background(),
+		primary.GRPCProvider,
+		providerserver.NewProtocol5(fwprovider.NewFrameworkProvider("test", primary)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create provider server: %v", err)
+	}
+	return muxServer.ProviderServer().(tfprotov5.ProviderServerWithListResource)
+}
+
+// FakeOktaProviderServer starts a fake Okta org for the duration of the test,
+// points the OKTA_* environment variables the provider is configured from at
+// it and returns the unconfigured ProviderServer.
+func FakeOktaProviderServer(t *testing.T) (tfprotov5.ProviderServerWithListResource, *fakeokta.Server) {
+	t.Helper()
+	org := fakeokta.NewServer()
+	t.Cleanup(org.Close)
+	t.Setenv("OKTA_ORG_NAME", "fake")
+	t.Setenv("OKTA_BASE_URL", TestDomainName)
+	t.Setenv("OKTA_API_TOKEN", "token")
+	t.Setenv("OKTA_HTTP_PROXY", org.URL)
+	// the API token is the only credential of the fake org
+	for _, name := range []string{"OKTA_ACCESS_TOKEN", "OKTA_API_CLIENT_ID", "OKTA_API_PRIVATE_KEY", "OKTA_API_PRIVATE_KEY_ID", "OKTA_API_SCOPES"} {
+		t.Setenv(name, "")
+	}
+	return ProviderServer(t), org
+}
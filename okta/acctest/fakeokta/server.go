@@ -0,0 +1,574 @@
+// Package fakeokta is an in-memory fake of the Okta management API for
+// running acceptance tests without an org. It keeps state for users, groups,
+// group memberships, apps, policies and their rules, network zones,
+// authorization servers and inline hook keys, pages lists with Link headers and returns rate limit
+// headers like Okta does.
+package fakeokta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the number of requests per minute allowed for each
+// endpoint unless SetRateLimit is called.
+const DefaultRateLimit = 600
+
+// AdminUserID is the ID of the user that GET /api/v1/users/me returns.
+const AdminUserID = "00ufakeadmin00000000"
+
+// collections are the top level collections of /api/v1 and the prefix of the
+// IDs of their objects.
+var collections = map[string]string{
+	"apps":                 "0oa",
+	"authorizationServers": "aus",
+	"groups":               "00g",
+	"hook-keys":            "hky",
+	"policies":             "00p",
+	"users":                "00u",
+	"zones":                "nzo",
+}
+
+// subCollections are the collections nested under an object of a top level
+// collection.
+var subCollections = map[string]map[string]string{
+	"authorizationServers": {"claims": "ocl", "policies": "00p", "scopes": "scp"},
+	"policies":             {"rules": "0pr"},
+}
+
+// Server is a fake Okta org served over HTTP. Point the provider at it with
+// the http_proxy argument or the OKTA_HTTP_PROXY environment variable.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	store       *store
+	rateLimit   int
+	rateBuckets map[string]*rateBucket
+	now         func() time.Time
+}
+
+type rateBucket struct {
+	reset     time.Time
+	remaining int
+}
+
+// NewServer starts a fake Okta org with the admin user that API tokens
+// authenticate as. Call Close when done with it.
+func NewServer() *Server {
+	s := &Server{
+		store:       newStore(),
+		rateLimit:   DefaultRateLimit,
+		rateBuckets: map[string]*rateBucket{},
+		now:         time.Now,
+	}
+	s.store.put("users", map[string]any{
+		"id":     AdminUserID,
+		"status": "ACTIVE",
+		"type":   map[string]any{"id": "otyfakedefault000000"},
+		"profile": map[string]any{
+			"firstName": "Fake",
+			"lastName":  "Admin",
+			"login":     "admin@example.com",
+			"email":     "admin@example.com",
+		},
+	}, s.now())
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// SetRateLimit sets the number of requests per minute allowed for each
+// endpoint. Requests over the limit get a 429 response.
+func (s *Server) SetRateLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = limit
+	s.rateBuckets = map[string]*rateBucket{}
+}
+
+// Create creates an object in a collection of the fake org, e.g. "groups",
+// with a POST request like a client of the API would, and returns it. Tests
+// use it to set up the objects the provider reads.
+func (s *Server) Create(collection string, object map[string]any) (map[string]any, error) {
+	body, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Post(s.URL+"/api/v1/"+collection, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to create %s: %s", collection, resp.Status)
+	}
+	var created map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// requests sent through a proxy carry the absolute URL of the org
+	r.URL.Scheme, r.URL.Host = "", ""
+	if !s.allow(w, r) {
+		writeError(w, http.StatusTooManyRequests, "E0000047", "API call exceeded rate limit due to too many requests.")
+		return
+	}
+
+	if r.URL.Path == "/.well-known/okta-organization" {
+		writeJSON(w, http.StatusOK, map[string]any{"id": "00ofakeorg0000000000", "pipeline": "idx"})
+		return
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/"), "/")
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/") || len(segments) == 0 {
+		notImplemented(w, r)
+		return
+	}
+	s.route(w, r, segments)
+}
+
+// allow counts the request against the rate limit bucket of its endpoint and
+// sets the rate limit headers.
+func (s *Server) allow(w http.ResponseWriter, r *http.Request) bool {
+	key := r.Method + " " + rateLimitPath(r.URL.Path)
+	now := s.now()
+	bucket, ok := s.rateBuckets[key]
+	if !ok || !now.Before(bucket.reset) {
+		bucket = &rateBucket{reset: now.Add(time.Minute), remaining: s.rateLimit}
+		s.rateBuckets[key] = bucket
+	}
+	allowed := bucket.remaining > 0
+	if allowed {
+		bucket.remaining--
+	}
+	w.Header().Set("X-Rate-Limit-Limit", strconv.Itoa(s.rateLimit))
+	w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(bucket.remaining))
+	w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(bucket.reset.Unix(), 10))
+	return allowed
+}
+
+// rateLimitPath replaces the IDs of a path so that the requests for all the
+// objects of a collection share a bucket, as they do in Okta.
+func rateLimitPath(p string) string {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	for i := 3; i < len(segments); i += 2 {
+		segments[i] = "{id}"
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, segments []string) {
+	// group memberships are links between two collections rather than a
+	// collection of their own
+	switch {
+	case len(segments) == 3 && segments[0] == "groups" && segments[2] == "users" && r.Method == http.MethodGet:
+		s.listGroupUsers(w, r, segments[1])
+		return
+	case len(segments) == 4 && segments[0] == "groups" && segments[2] == "users":
+		s.groupMembership(w, r, segments[1], segments[3])
+		return
+	case len(segments) == 3 && segments[0] == "users" && segments[2] == "groups" && r.Method == http.MethodGet:
+		s.listUserGroups(w, r, segments[1])
+		return
+	}
+
+	if len(segments) >= 3 && segments[len(segments)-2] == "lifecycle" {
+		collection, id, ok := resolve(segments[:len(segments)-2])
+		if !ok || id == "" {
+			notImplemented(w, r)
+			return
+		}
+		s.lifecycle(w, r, collection, id, segments[len(segments)-1])
+		return
+	}
+
+	collection, id, ok := resolve(segments)
+	if !ok {
+		notImplemented(w, r)
+		return
+	}
+	if collection == "users" && id == "me" {
+		id = AdminUserID
+	}
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		s.list(w, r, collection)
+	case id == "" && r.Method == http.MethodPost:
+		s.create(w, r, collection)
+	case id != "" && r.Method == http.MethodGet:
+		s.get(w, r, collection, id)
+	case id != "" && (r.Method == http.MethodPut || r.Method == http.MethodPost):
+		s.update(w, r, collection, id)
+	case id != "" && r.Method == http.MethodDelete:
+		s.delete(w, collection, id)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// resolve maps the segments of a path to the collection and, when the path
+// names one, the ID of an object. Collections are keyed by their path, e.g.
+// "policies/00p1/rules".
+func resolve(segments []string) (collection, id string, ok bool) {
+	if _, ok := collections[segments[0]]; !ok {
+		return "", "", false
+	}
+	switch len(segments) {
+	case 1:
+		return segments[0], "", true
+	case 2:
+		return segments[0], segments[1], true
+	case 3, 4:
+		if _, ok := subCollections[segments[0]][segments[2]]; !ok {
+			return "", "", false
+		}
+		collection = strings.Join(segments[:3], "/")
+		if len(segments) == 4 {
+			id = segments[3]
+		}
+		return collection, id, true
+	}
+	return "", "", false
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, collection string) {
+	if parent, ok := s.parentMissing(collection); ok {
+		notFound(w, parent)
+		return
+	}
+	query := r.URL.Query()
+	items, err := s.store.list(collection, query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "E0000031", err.Error())
+		return
+	}
+	for i := range items {
+		items[i] = s.present(r, collection, items[i])
+	}
+	s.writePage(w, r, items)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, collection string) {
+	if parent, ok := s.parentMissing(collection); ok {
+		notFound(w, parent)
+		return
+	}
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	status := "ACTIVE"
+	if activate := r.URL.Query().Get("activate"); activate == "false" {
+		status = "INACTIVE"
+		if collection == "users" {
+			status = "STAGED"
+		}
+	}
+	if _, ok := body["status"]; !ok || collection == "users" {
+		body["status"] = status
+	}
+	switch collection {
+	case "groups":
+		setDefault(body, "type", "OKTA_GROUP")
+		setDefault(body, "objectClass", []any{"okta:user_group"})
+	case "users":
+		if login := lookup(body, "profile.login"); login != nil && s.store.find("users", "profile.login", login) != nil {
+			writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: login: An object with this field already exists in the current organization")
+			return
+		}
+	case "zones":
+		setDefault(body, "usage", "POLICY")
+		setDefault(body, "system", false)
+	case "hook-keys":
+		setDefault(body, "keyId", newID("key"))
+		setDefault(body, "isUsed", false)
+	}
+	if strings.HasSuffix(collection, "policies") || strings.HasSuffix(collection, "rules") {
+		setDefault(body, "system", false)
+		setDefault(body, "priority", len(s.store.all(collection))+1)
+	}
+	writeJSON(w, http.StatusOK, s.present(r, collection, s.store.put(collection, body, s.now())))
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, collection, id string) {
+	item := s.store.get(collection, id)
+	if item == nil && collection == "users" {
+		item = s.store.find("users", "profile.login", id)
+	}
+	if item == nil {
+		notFound(w, id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.present(r, collection, item))
+}
+
+// update handles PUT, which replaces the object, and POST, which updates the
+// given fields of the object. Fields managed by Okta are kept either way.
+func (s *Server) update(w http.ResponseWriter, r *http.Request, collection, id string) {
+	item := s.store.get(collection, id)
+	if item == nil {
+		notFound(w, id)
+		return
+	}
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	if r.Method == http.MethodPost {
+		body = merge(item, body)
+	}
+	for _, field := range []string{"id", "created", "status", "system"} {
+		if v, ok := item[field]; ok {
+			body[field] = v
+		}
+	}
+	if collection == "groups" {
+		body["type"] = item["type"]
+	}
+	writeJSON(w, http.StatusOK, s.present(r, collection, s.store.put(collection, body, s.now())))
+}
+
+// delete deletes the object. Users are deprovisioned by the first delete and
+// deleted by the second, as they are in Okta.
+func (s *Server) delete(w http.ResponseWriter, collection, id string) {
+	item := s.store.get(collection, id)
+	if item == nil {
+		notFound(w, id)
+		return
+	}
+	if collection == "users" && item["status"] != "DEPROVISIONED" {
+		item["status"] = "DEPROVISIONED"
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.store.remove(collection, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) lifecycle(w http.ResponseWriter, r *http.Request, collection, id, action string) {
+	item := s.store.get(collection, id)
+	if item == nil {
+		notFound(w, id)
+		return
+	}
+	if r.Method != http.MethodPost {
+		notImplemented(w, r)
+		return
+	}
+	statuses := map[string]string{
+		"activate":   "ACTIVE",
+		"deactivate": "INACTIVE",
+		"reactivate": "ACTIVE",
+		"suspend":    "SUSPENDED",
+		"unsuspend":  "ACTIVE",
+		"unlock":     "ACTIVE",
+	}
+	status, ok := statuses[action]
+	if !ok {
+		notImplemented(w, r)
+		return
+	}
+	if collection == "users" && action == "deactivate" {
+		status = "DEPROVISIONED"
+	}
+	item["status"] = status
+	item["lastUpdated"] = timestamp(s.now())
+	switch collection {
+	case "zones", "authorizationServers":
+		writeJSON(w, http.StatusOK, s.present(r, collection, item))
+	case "users":
+		writeJSON(w, http.StatusOK, map[string]any{})
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) listGroupUsers(w http.ResponseWriter, r *http.Request, groupID string) {
+	if s.store.get("groups", groupID) == nil {
+		notFound(w, groupID)
+		return
+	}
+	var users []map[string]any
+	for _, userID := range s.store.members(groupID) {
+		if user := s.store.get("users", userID); user != nil {
+			users = append(users, s.present(r, "users", user))
+		}
+	}
+	s.writePage(w, r, users)
+}
+
+func (s *Server) listUserGroups(w http.ResponseWriter, r *http.Request, userID string) {
+	if userID == "me" {
+		userID = AdminUserID
+	}
+	if s.store.get("users", userID) == nil {
+		notFound(w, userID)
+		return
+	}
+	var groups []map[string]any
+	for _, group := range s.store.all("groups") {
+		if s.store.isMember(group["id"].(string), userID) {
+			groups = append(groups, s.present(r, "groups", group))
+		}
+	}
+	s.writePage(w, r, groups)
+}
+
+func (s *Server) groupMembership(w http.ResponseWriter, r *http.Request, groupID, userID string) {
+	if s.store.get("groups", groupID) == nil {
+		notFound(w, groupID)
+		return
+	}
+	if s.store.get("users", userID) == nil {
+		notFound(w, userID)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		s.store.addMember(groupID, userID)
+	case http.MethodDelete:
+		s.store.removeMember(groupID, userID)
+	default:
+		notImplemented(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parentMissing reports the ID of the parent object of a nested collection
+// when it doesn't exist.
+func (s *Server) parentMissing(collection string) (string, bool) {
+	parts := strings.Split(collection, "/")
+	if len(parts) != 3 {
+		return "", false
+	}
+	return parts[1], s.store.get(parts[0], parts[1]) == nil
+}
+
+// writePage writes the page of items starting after the "after" cursor with
+// Link headers for the current page and, when there is one, the next page.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []map[string]any) {
+	query := r.URL.Query()
+	limit := 200
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	start := 0
+	if after := query.Get("after"); after != "" {
+		for i, item := range items {
+			if item["id"] == after {
+				start = i + 1
+				break
+			}
+		}
+	}
+	end := min(start+limit, len(items))
+	page := append([]map[string]any{}, items[start:end]...)
+
+	self := *r.URL
+	self.Scheme, self.Host = "http", r.Host
+	w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"self\"", self.String()))
+	if end < len(items) {
+		next := self
+		nextQuery := url.Values{}
+		for k, v := range query {
+			nextQuery[k] = v
+		}
+		nextQuery.Set("after", page[len(page)-1]["id"].(string))
+		nextQuery.Set("limit", strconv.Itoa(limit))
+		next.RawQuery = nextQuery.Encode()
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// present returns the object as Okta returns it, with its _links pointing at
+// the host of the request and without the secrets that are only ever
+// written.
+func (s *Server) present(r *http.Request, collection string, item map[string]any) map[string]any {
+	out := merge(item, nil)
+	if collection == "users" {
+		if credentials, ok := out["credentials"].(map[string]any); ok {
+			credentials = merge(credentials, nil)
+			if _, ok := credentials["password"]; ok {
+				credentials["password"] = map[string]any{}
+			}
+			out["credentials"] = credentials
+		}
+	}
+	host := s.URL
+	if r.Host != "" {
+		host = "http://" + r.Host
+	}
+	out["_links"] = map[string]any{
+		"self": map[string]any{"href": fmt.Sprintf("%s/api/v1/%s/%s", host, collection, item["id"])},
+	}
+	return out
+}
+
+func readBody(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	body := map[string]any{}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r.Body); err != nil {
+		writeError(w, http.StatusBadRequest, "E0000003", "The request body was not well-formed.")
+		return nil, false
+	}
+	if buf.Len() == 0 {
+		return body, true
+	}
+	decoder := json.NewDecoder(&buf)
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "E0000003", "The request body was not well-formed.")
+		return nil, false
+	}
+	return body, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, summary string) {
+	writeJSON(w, status, map[string]any{
+		"errorCode":    code,
+		"errorSummary": summary,
+		"errorLink":    code,
+		"errorId":      "oaefake" + strconv.Itoa(status),
+		"errorCauses":  []any{},
+	})
+}
+
+func notFound(w http.ResponseWriter, id string) {
+	writeError(w, http.StatusNotFound, "E0000007", fmt.Sprintf("Not found: Resource not found: %s", id))
+}
+
+func notImplemented(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, "E0000007", fmt.Sprintf("Not found: the fake Okta API doesn't implement %s %s", r.Method, r.URL.Path))
+}
+
+func setDefault(item map[string]any, field string, value any) {
+	if _, ok := item[field]; !ok {
+		item[field] = value
+	}
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
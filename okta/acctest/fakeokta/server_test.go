@@ -0,0 +1,259 @@
+package fakeokta
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	v5okta "github.com/okta/okta-sdk-golang/v5/okta"
+	v6okta "github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/okta/terraform-provider-okta/okta/api"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newClient returns the provider's API clients pointed at a new fake org.
+func newClient(t *testing.T) (*Server, api.OktaIDaaSClient) {
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+	client, err := api.NewOktaIDaaSAPIClient(&api.OktaAPIConfig{
+		OrgName:        "fake",
+		Domain:         "dne-okta.com",
+		ApiToken:       "token",
+		HttpProxy:      srv.URL,
+		Logger:         hclog.NewNullLogger(),
+		MaxAPICapacity: 100,
+		MaxWait:        1,
+		RequestTimeout: 10,
+	})
+	require.NoError(t, err)
+	return srv, client
+}
+
+func TestServer_Groups(t *testing.T) {
+	ctx := context.Background()
+	_, client := newClient(t)
+	v5 := client.OktaSDKClientV5()
+
+	for _, name := range []string{"testAcc_a", "testAcc_b", "testAcc_c", "other"} {
+		_, _, err := v5.GroupAPI.CreateGroup(ctx).Group(v5okta.Group{Profile: &v5okta.GroupProfile{Name: v5okta.PtrString(name)}}).Execute()
+		require.NoError(t, err)
+	}
+
+	groups, resp, err := v5.GroupAPI.ListGroups(ctx).Q("testAcc_").Limit(2).Execute()
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	require.True(t, resp.HasNextPage())
+	var more []v5okta.Group
+	resp, err = resp.Next(&more)
+	require.NoError(t, err)
+	require.Len(t, more, 1)
+	assert.False(t, resp.HasNextPage())
+	assert.Equal(t, "testAcc_c", more[0].Profile.GetName())
+	assert.Equal(t, "OKTA_GROUP", more[0].GetType())
+
+	groups, _, err = v5.GroupAPI.ListGroups(ctx).Search(`type eq "OKTA_GROUP" and profile.name sw "oth"`).Execute()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	id := groups[0].GetId()
+
+	_, _, err = v5.GroupAPI.ReplaceGroup(ctx, id).Group(v5okta.Group{Profile: &v5okta.GroupProfile{Name: v5okta.PtrString("renamed"), Description: v5okta.PtrString("d")}}).Execute()
+	require.NoError(t, err)
+	group, _, err := v5.GroupAPI.GetGroup(ctx, id).Execute()
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", group.Profile.GetName())
+	assert.Equal(t, "OKTA_GROUP", group.GetType())
+
+	_, err = v5.GroupAPI.DeleteGroup(ctx, id).Execute()
+	require.NoError(t, err)
+	_, resp, err = v5.GroupAPI.GetGroup(ctx, id).Execute()
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_UsersAndMemberships(t *testing.T) {
+	ctx := context.Background()
+	_, client := newClient(t)
+	v2 := client.OktaSDKClientV2()
+
+	me, _, err := v2.User.GetUser(ctx, "me")
+	require.NoError(t, err)
+	assert.Equal(t, AdminUserID, me.Id)
+
+	user, _, err := v2.User.CreateUser(ctx, sdk.CreateUserRequest{
+		Profile: &sdk.UserProfile{"login": "jane@example.com", "email": "jane@example.com", "firstName": "Jane", "lastName": "Doe"},
+		Credentials: &sdk.UserCredentials{
+			Password: &sdk.PasswordCredential{Value: "Passw0rd!"},
+		},
+	}, &query.Params{Activate: boolPtr(false)})
+	require.NoError(t, err)
+	assert.Equal(t, "STAGED", user.Status)
+	assert.Empty(t, user.Credentials.Password.Value, "passwords are never returned")
+
+	_, _, err = v2.User.CreateUser(ctx, sdk.CreateUserRequest{
+		Profile: &sdk.UserProfile{"login": "JANE@example.com"},
+	}, nil)
+	require.Error(t, err, "logins are unique")
+
+	byLogin, _, err := v2.User.GetUser(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, user.Id, byLogin.Id)
+
+	_, _, err = v2.User.ActivateUser(ctx, user.Id, nil)
+	require.NoError(t, err)
+	user, _, err = v2.User.GetUser(ctx, user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "ACTIVE", user.Status)
+
+	group, _, err := v2.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "members"}})
+	require.NoError(t, err)
+	_, err = v2.Group.AddUserToGroup(ctx, group.Id, user.Id)
+	require.NoError(t, err)
+	_, err = v2.Group.AddUserToGroup(ctx, group.Id, AdminUserID)
+	require.NoError(t, err)
+
+	users, _, err := v2.Group.ListGroupUsers(ctx, group.Id, nil)
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+	groups, _, err := v2.User.ListUserGroups(ctx, user.Id)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, group.Id, groups[0].Id)
+
+	// the first delete deprovisions the user, the second deletes it
+	_, err = v2.User.DeactivateOrDeleteUser(ctx, user.Id, nil)
+	require.NoError(t, err)
+	user, _, err = v2.User.GetUser(ctx, user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "DEPROVISIONED", user.Status)
+	_, err = v2.User.DeactivateOrDeleteUser(ctx, user.Id, nil)
+	require.NoError(t, err)
+	users, _, err = v2.Group.ListGroupUsers(ctx, group.Id, nil)
+	require.NoError(t, err)
+	assert.Len(t, users, 1, "deleted users are removed from their groups")
+}
+
+func TestServer_PoliciesAndRules(t *testing.T) {
+	ctx := context.Background()
+	_, client := newClient(t)
+	v2 := client.OktaSDKClientV2()
+	supplement := client.OktaSDKSupplementClient()
+
+	template := sdk.PasswordPolicy()
+	template.Name = "testAcc"
+	policy, _, err := supplement.CreatePolicy(ctx, template)
+	require.NoError(t, err)
+	assert.Equal(t, "ACTIVE", policy.Status)
+	template = sdk.SignOnPolicy()
+	template.Name = "sign on"
+	_, _, err = supplement.CreatePolicy(ctx, template)
+	require.NoError(t, err)
+
+	policies, _, err := v2.Policy.ListPolicies(ctx, &query.Params{Type: sdk.PasswordPolicyType})
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+
+	_, err = v2.Policy.DeactivatePolicy(ctx, policy.Id)
+	require.NoError(t, err)
+	policy, _, err = supplement.GetPolicy(ctx, policy.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "INACTIVE", policy.Status)
+
+	rule, _, err := supplement.CreatePolicyRule(ctx, policy.Id, sdk.PasswordPolicyRule())
+	require.NoError(t, err)
+	rules, _, err := v2.Policy.ListPolicyRules(ctx, policy.Id)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, rule.Id, rules[0].Id)
+
+	_, err = v2.Policy.DeletePolicy(ctx, policy.Id)
+	require.NoError(t, err)
+	_, resp, err := v2.Policy.ListPolicyRules(ctx, policy.Id)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_NetworkZones(t *testing.T) {
+	ctx := context.Background()
+	_, client := newClient(t)
+	v6 := client.OktaSDKClientV6()
+
+	ipnz := v6okta.IPNetworkZone{}
+	ipnz.SetName("testAcc")
+	ipnz.SetType("IP")
+	ipnz.SetGateways([]v6okta.NetworkZoneAddress{{Type: v6okta.PtrString("CIDR"), Value: v6okta.PtrString("1.2.3.4/32")}})
+	zone := v6okta.IPNetworkZoneAsListNetworkZones200ResponseInner(&ipnz)
+	created, _, err := v6.NetworkZoneAPI.CreateNetworkZone(ctx).Zone(zone).Execute()
+	require.NoError(t, err)
+	id := created.IPNetworkZone.GetId()
+	require.NotEmpty(t, id)
+	assert.Equal(t, "POLICY", created.IPNetworkZone.GetUsage())
+
+	deactivated, _, err := v6.NetworkZoneAPI.DeactivateNetworkZone(ctx, id).Execute()
+	require.NoError(t, err)
+	assert.Equal(t, "INACTIVE", deactivated.IPNetworkZone.GetStatus())
+
+	zones, _, err := v6.NetworkZoneAPI.ListNetworkZones(ctx).Filter(`usage eq "BLOCKLIST"`).Execute()
+	require.NoError(t, err)
+	assert.Empty(t, zones)
+
+	_, err = v6.NetworkZoneAPI.DeleteNetworkZone(ctx, id).Execute()
+	require.NoError(t, err)
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.SetRateLimit(2)
+	now := time.Unix(1700000000, 0)
+	srv.now = func() time.Time { return now }
+
+	get := func(path string) *http.Response {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+	resp := get("/api/v1/groups/00g1")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("X-Rate-Limit-Limit"))
+	assert.Equal(t, "1", resp.Header.Get("X-Rate-Limit-Remaining"))
+	assert.Equal(t, "1700000060", resp.Header.Get("X-Rate-Limit-Reset"))
+
+	// the objects of a collection share a bucket
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/groups/00g2").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/v1/groups/00g3").StatusCode)
+	assert.Equal(t, http.StatusOK, get("/api/v1/groups").StatusCode)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/groups/00g3").StatusCode)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestServer_HookKeys(t *testing.T) {
+	ctx := context.Background()
+	_, client := newClient(t)
+	v5 := client.OktaSDKClientV5()
+
+	created, _, err := v5.HookKeyAPI.CreateHookKey(ctx).KeyRequest(v5okta.KeyRequest{Name: v5okta.PtrString("testAcc")}).Execute()
+	require.NoError(t, err)
+	require.NotEmpty(t, created.GetId())
+	assert.NotEmpty(t, created.GetKeyId())
+	assert.False(t, created.GetIsUsed())
+
+	hookKeys, _, err := v5.HookKeyAPI.ListHookKeys(ctx).Execute()
+	require.NoError(t, err)
+	require.Len(t, hookKeys, 1)
+	assert.Equal(t, "testAcc", hookKeys[0].GetName())
+	assert.False(t, hookKeys[0].GetCreated().IsZero())
+
+	_, err = v5.HookKeyAPI.DeleteHookKey(ctx, created.GetId()).Execute()
+	require.NoError(t, err)
+}
@@ -0,0 +1,251 @@
+package fakeokta
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// store holds the objects of the fake org by collection, in the order they
+// were created, and the group memberships.
+type store struct {
+	items       map[string]map[string]map[string]any
+	order       map[string][]string
+	memberships map[string][]string
+}
+
+func newStore() *store {
+	return &store{
+		items:       map[string]map[string]map[string]any{},
+		order:       map[string][]string{},
+		memberships: map[string][]string{},
+	}
+}
+
+// put creates or replaces an object, giving it an ID when it has none.
+func (s *store) put(collection string, item map[string]any, now time.Time) map[string]any {
+	id, _ := item["id"].(string)
+	if id == "" {
+		id = newID(idPrefix(collection))
+		item["id"] = id
+	}
+	if s.items[collection] == nil {
+		s.items[collection] = map[string]map[string]any{}
+	}
+	if existing, ok := s.items[collection][id]; ok {
+		item["created"] = existing["created"]
+	} else {
+		item["created"] = timestamp(now)
+		s.order[collection] = append(s.order[collection], id)
+	}
+	item["lastUpdated"] = timestamp(now)
+	s.items[collection][id] = item
+	return item
+}
+
+func (s *store) get(collection, id string) map[string]any {
+	return s.items[collection][id]
+}
+
+// remove deletes an object along with its nested collections and group
+// memberships.
+func (s *store) remove(collection, id string) {
+	delete(s.items[collection], id)
+	s.order[collection] = slices.DeleteFunc(s.order[collection], func(v string) bool { return v == id })
+	prefix := collection + "/" + id + "/"
+	for c := range s.items {
+		if strings.HasPrefix(c, prefix) {
+			delete(s.items, c)
+			delete(s.order, c)
+		}
+	}
+	switch collection {
+	case "groups":
+		delete(s.memberships, id)
+	case "users":
+		for groupID := range s.memberships {
+			s.removeMember(groupID, id)
+		}
+	}
+}
+
+// all returns the objects of a collection in the order they were created.
+func (s *store) all(collection string) []map[string]any {
+	items := make([]map[string]any, 0, len(s.order[collection]))
+	for _, id := range s.order[collection] {
+		items = append(items, s.items[collection][id])
+	}
+	return items
+}
+
+// find returns the first object of a collection with the given value at
+// path, compared case insensitively as Okta does for logins.
+func (s *store) find(collection, path string, value any) map[string]any {
+	for _, item := range s.all(collection) {
+		if strings.EqualFold(fmt.Sprint(lookup(item, path)), fmt.Sprint(value)) {
+			return item
+		}
+	}
+	return nil
+}
+
+// list returns the objects of a collection matching the q, filter, search and
+// type query parameters.
+func (s *store) list(collection string, query url.Values) ([]map[string]any, error) {
+	var conditions []condition
+	for _, param := range []string{"filter", "search"} {
+		if expr := query.Get(param); expr != "" {
+			parsed, err := parseConditions(expr)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, parsed...)
+		}
+	}
+	if t := query.Get("type"); t != "" {
+		conditions = append(conditions, condition{path: "type", op: "eq", value: t})
+	}
+	q := strings.ToLower(query.Get("q"))
+
+	var items []map[string]any
+	for _, item := range s.all(collection) {
+		if q != "" && !matchesQ(item, q) {
+			continue
+		}
+		if !slices.ContainsFunc(conditions, func(c condition) bool { return !c.matches(item) }) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (s *store) members(groupID string) []string {
+	return s.memberships[groupID]
+}
+
+func (s *store) isMember(groupID, userID string) bool {
+	return slices.Contains(s.memberships[groupID], userID)
+}
+
+func (s *store) addMember(groupID, userID string) {
+	if !s.isMember(groupID, userID) {
+		s.memberships[groupID] = append(s.memberships[groupID], userID)
+	}
+}
+
+func (s *store) removeMember(groupID, userID string) {
+	s.memberships[groupID] = slices.DeleteFunc(s.memberships[groupID], func(v string) bool { return v == userID })
+}
+
+// matchesQ implements the q parameter, a case insensitive prefix match on the
+// names of an object.
+func matchesQ(item map[string]any, q string) bool {
+	for _, path := range []string{"profile.name", "profile.login", "profile.firstName", "profile.lastName", "profile.email", "label", "name"} {
+		if v, ok := lookup(item, path).(string); ok && strings.HasPrefix(strings.ToLower(v), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// condition is one comparison of a filter or search expression.
+type condition struct {
+	path  string
+	op    string
+	value string
+}
+
+var conditionRegexp = regexp.MustCompile(`^\(?\s*([\w.]+)\s+(eq|sw|co|ne)\s+"([^"]*)"\s*\)?$`)
+
+// parseConditions parses the subset of the Okta filter and search syntax made
+// of comparisons joined by "and", e.g. `type eq "OKTA_GROUP" and profile.name
+// sw "test"`.
+func parseConditions(expr string) ([]condition, error) {
+	var conditions []condition
+	for _, part := range regexp.MustCompile(`\s+and\s+`).Split(strings.TrimSpace(expr), -1) {
+		m := conditionRegexp.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil {
+			return nil, fmt.Errorf("Invalid search criteria: the fake Okta API can't parse %q", expr)
+		}
+		conditions = append(conditions, condition{path: m[1], op: m[2], value: m[3]})
+	}
+	return conditions, nil
+}
+
+func (c condition) matches(item map[string]any) bool {
+	v := lookup(item, c.path)
+	if v == nil {
+		return c.op == "ne"
+	}
+	actual := fmt.Sprint(v)
+	switch c.op {
+	case "eq":
+		return strings.EqualFold(actual, c.value)
+	case "ne":
+		return !strings.EqualFold(actual, c.value)
+	case "sw":
+		return strings.HasPrefix(strings.ToLower(actual), strings.ToLower(c.value))
+	case "co":
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(c.value))
+	}
+	return false
+}
+
+// lookup returns the value at a dotted path of an object.
+func lookup(item map[string]any, path string) any {
+	var v any = item
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// merge returns a copy of item with the fields of update merged in, nested
+// objects included.
+func merge(item, update map[string]any) map[string]any {
+	out := make(map[string]any, len(item)+len(update))
+	for k, v := range item {
+		out[k] = v
+	}
+	for k, v := range update {
+		existing, ok1 := out[k].(map[string]any)
+		updated, ok2 := v.(map[string]any)
+		if ok1 && ok2 {
+			out[k] = merge(existing, updated)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// idPrefix returns the prefix of the IDs of a collection, either top level or
+// nested.
+func idPrefix(collection string) string {
+	parts := strings.Split(collection, "/")
+	if len(parts) == 3 {
+		return subCollections[parts[0]][parts[2]]
+	}
+	return collections[collection]
+}
+
+const idAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// newID returns an ID in the shape of Okta IDs, a three character prefix
+// followed by 17 random characters.
+func newID(prefix string) string {
+	b := make([]byte, 17)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = idAlphabet[int(b[i])%len(idAlphabet)]
+	}
+	return prefix + string(b)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
@@ -59,12 +60,31 @@ func getV6ClientConfig(c *OktaAPIConfig) (*v6okta.Configuration, *v6okta.APIClie
 	// adds transport governor to retryable or default client
 	if c.MaxAPICapacity > 0 && c.MaxAPICapacity < 100 {
 		c.Logger.Info(fmt.Sprintf("v6 running with experimental max_api_capacity configuration at %d%%", c.MaxAPICapacity))
-		apiMutex, err := apimutex.NewAPIMutex(c.MaxAPICapacity)
+		apiMutex, err := c.governor()
 		if err != nil {
 			return nil, nil, err
 		}
 		httpClient.Transport = transport.NewGovernedTransport(httpClient.Transport, apiMutex, c.Logger)
 	}
+	// adds shared response cache to retryable or default client
+	if c.ResponseCache {
+		httpClient.Transport = transport.NewCachingTransport(httpClient.Transport, c.responseCache(), c.Logger)
+	}
+	// adds the proofs of DPoP bound access tokens
+	httpClient.Transport = transport.NewDPoPTransport(httpClient.Transport, c.dpopKey(), c.Logger)
+	// sets the current token of credentials re-read or refreshed while running
+	if scheme := c.tokenScheme(); scheme != "" && c.TokenSource != nil {
+		httpClient.Transport = transport.NewAuthorizationTransport(httpClient.Transport, scheme, c.TokenSource, c.Logger)
+	}
+	// authorizes requests with the access tokens of the service app shared by
+	// every SDK client
+	if c.usesOAuthTokenSource() {
+		source, err := c.oauthTokenSource(httpClient.Transport)
+		if err != nil {
+			return nil, nil, err
+		}
+		httpClient.Transport = transport.NewOAuthTransport(httpClient.Transport, source, c.Logger)
+	}
 	var orgURL string
 	var disableHTTPS bool
 	if c.HttpProxy != "" {
@@ -124,7 +144,7 @@ func getV6ClientConfig(c *OktaAPIConfig) (*v6okta.Configuration, *v6okta.APIClie
 	case c.PrivateKey != "":
 		setters = append(
 			setters,
-			v6okta.WithPrivateKey(c.PrivateKey), v6okta.WithPrivateKeyId(c.PrivateKeyId), v6okta.WithScopes(c.Scopes), v6okta.WithClientId(c.ClientID), v6okta.WithAuthorizationMode("PrivateKey"),
+			v6okta.WithToken(oauthTokenPlaceholder), v6okta.WithAuthorizationMode("Bearer"),
 		)
 	}
 
@@ -175,12 +195,31 @@ func getV5ClientConfig(c *OktaAPIConfig) (*v5okta.Configuration, *v5okta.APIClie
 	// adds transport governor to retryable or default client
 	if c.MaxAPICapacity > 0 && c.MaxAPICapacity < 100 {
 		c.Logger.Info(fmt.Sprintf("running with experimental max_api_capacity configuration at %d%%", c.MaxAPICapacity))
-		apiMutex, err := apimutex.NewAPIMutex(c.MaxAPICapacity)
+		apiMutex, err := c.governor()
 		if err != nil {
 			return nil, nil, err
 		}
 		httpClient.Transport = transport.NewGovernedTransport(httpClient.Transport, apiMutex, c.Logger)
 	}
+	// adds shared response cache to retryable or default client
+	if c.ResponseCache {
+		httpClient.Transport = transport.NewCachingTransport(httpClient.Transport, c.responseCache(), c.Logger)
+	}
+	// adds the proofs of DPoP bound access tokens
+	httpClient.Transport = transport.NewDPoPTransport(httpClient.Transport, c.dpopKey(), c.Logger)
+	// sets the current token of credentials re-read or refreshed while running
+	if scheme := c.tokenScheme(); scheme != "" && c.TokenSource != nil {
+		httpClient.Transport = transport.NewAuthorizationTransport(httpClient.Transport, scheme, c.TokenSource, c.Logger)
+	}
+	// authorizes requests with the access tokens of the service app shared by
+	// every SDK client
+	if c.usesOAuthTokenSource() {
+		source, err := c.oauthTokenSource(httpClient.Transport)
+		if err != nil {
+			return nil, nil, err
+		}
+		httpClient.Transport = transport.NewOAuthTransport(httpClient.Transport, source, c.Logger)
+	}
 	var orgUrl string
 	var disableHTTPS bool
 	if c.HttpProxy != "" {
@@ -240,7 +279,7 @@ func getV5ClientConfig(c *OktaAPIConfig) (*v5okta.Configuration, *v5okta.APIClie
 	case c.PrivateKey != "":
 		setters = append(
 			setters,
-			v5okta.WithPrivateKey(c.PrivateKey), v5okta.WithPrivateKeyId(c.PrivateKeyId), v5okta.WithScopes(c.Scopes), v5okta.WithClientId(c.ClientID), v5okta.WithAuthorizationMode("PrivateKey"),
+			v5okta.WithToken(oauthTokenPlaceholder), v5okta.WithAuthorizationMode("Bearer"),
 		)
 	}
 
@@ -255,6 +294,140 @@ func getV5ClientConfig(c *OktaAPIConfig) (*v5okta.Configuration, *v5okta.APIClie
 	return config, nil, nil
 }
 
+// governor returns the api mutex shared by every SDK client built from this
+// configuration. It is created on first use unless one was provided so that the
+// v2, v3, v5, v6 and governance clients all account for each other's rate limit
+// consumption.
+func (c *OktaAPIConfig) governor() (*apimutex.APIMutex, error) {
+	if c.APIMutex != nil {
+		return c.APIMutex, nil
+	}
+
+	var err error
+	if c.RateLimitStateFile != "" {
+		c.Logger.Info(fmt.Sprintf("loading and saving rate limit state with file %q", c.RateLimitStateFile))
+		c.APIMutex, err = apimutex.NewPersistentAPIMutex(c.MaxAPICapacity, c.RateLimitStateFile)
+	} else {
+		c.APIMutex, err = apimutex.NewAPIMutex(c.MaxAPICapacity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.APIMutex.SetParallelism(c.Parallelism)
+	return c.APIMutex, nil
+}
+
+// FlushRateLimitState synchronously writes the rate limit state file of every
+// governor that persists its state. The provider calls it when it stops so the
+// rate limit updates of the last moments of a run are kept for the next one.
+func FlushRateLimitState() error {
+	return apimutex.FlushAll()
+}
+
+// responseCache returns the response cache shared by every SDK client built
+// from this configuration, creating it on first use unless one was provided.
+func (c *OktaAPIConfig) responseCache() *transport.ResponseCache {
+	if c.APIResponseCache == nil {
+		c.Logger.Info("caching API responses for the duration of the operation")
+		c.APIResponseCache = transport.NewResponseCache(transport.DefaultResponseCacheTTL)
+	}
+	return c.APIResponseCache
+}
+
+// oauthTokenPlaceholder is the token of the SDK clients when the provider gets
+// the access tokens of its service app, the OAuth transport replaces it with
+// the current access token.
+const oauthTokenPlaceholder = "oauth-token-source"
+
+// tokenScheme returns the authorization scheme of the token the SDK clients
+// use, empty for private key authentication.
+func (c *OktaAPIConfig) tokenScheme() string {
+	switch {
+	case c.AccessToken != "":
+		return "Bearer"
+	case c.ApiToken != "":
+		return "SSWS"
+	}
+	return ""
+}
+
+// usesOAuthTokenSource tells whether the provider gets the access tokens of
+// its service app itself rather than being given a token.
+func (c *OktaAPIConfig) usesOAuthTokenSource() bool {
+	return c.tokenScheme() == "" && c.PrivateKey != ""
+}
+
+// oauthTokenSource returns the OAuth token source shared by every SDK client
+// built from this configuration, creating it on first use unless one was
+// provided. Its token requests are sent with base.
+func (c *OktaAPIConfig) oauthTokenSource(base http.RoundTripper) (*transport.OAuthTokenSource, error) {
+	if c.OAuthTokenSource != nil {
+		return c.OAuthTokenSource, nil
+	}
+	// like the SDK clients, private_key can be the path of the key
+	privateKey := c.PrivateKey
+	if info, err := os.Stat(privateKey); err == nil && !info.IsDir() {
+		content, err := os.ReadFile(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private_key %s: %w", privateKey, err)
+		}
+		privateKey = string(content)
+	}
+	signer, err := sdk.CreateKeySigner(privateKey, c.PrivateKeyId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private_key: %w", err)
+	}
+	orgURL := fmt.Sprintf("https://%v.%v", c.OrgName, c.Domain)
+	if c.HttpProxy != "" {
+		orgURL = strings.TrimSuffix(c.HttpProxy, "/")
+	}
+	clientAssertion := func() (string, error) {
+		return sdk.CreateClientAssertion(orgURL, c.ClientID, signer)
+	}
+	if c.PrivateKeySource != nil {
+		// the key is renewed while the provider runs, each token request
+		// signs its assertion with the current key
+		var lock sync.Mutex
+		currentKey, currentKeyID := c.PrivateKey, c.PrivateKeyId
+		clientAssertion = func() (string, error) {
+			key, keyID, err := c.PrivateKeySource(context.Background())
+			if err != nil {
+				return "", fmt.Errorf("failed to get the private key: %w", err)
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if key != currentKey || keyID != currentKeyID {
+				newSigner, err := sdk.CreateKeySigner(key, keyID)
+				if err != nil {
+					return "", fmt.Errorf("invalid private_key: %w", err)
+				}
+				signer, currentKey, currentKeyID = newSigner, key, keyID
+			}
+			return sdk.CreateClientAssertion(orgURL, c.ClientID, signer)
+		}
+	}
+	c.OAuthTokenSource = transport.NewOAuthTokenSource(transport.OAuthTokenSourceConfig{
+		HTTPClient:      &http.Client{Transport: base},
+		TokenURL:        orgURL + "/oauth2/v1/token",
+		ClientAssertion: clientAssertion,
+		Scopes:          c.Scopes,
+		UserAgent:       version.OktaTerraformProviderUserAgent,
+		Logger:          c.Logger,
+		DPoPKey:         c.dpopKey(),
+	})
+	return c.OAuthTokenSource, nil
+}
+
+// dpopKey returns the DPoP key shared by the token source and every SDK client
+// built from this configuration, creating it on first use unless one was
+// provided.
+func (c *OktaAPIConfig) dpopKey() *transport.DPoPKey {
+	if c.DPoPKey == nil {
+		c.DPoPKey = transport.NewDPoPKey()
+	}
+	return c.DPoPKey
+}
+
 func errHandler(resp *http.Response, err error, numTries int) (*http.Response, error) {
 	if err != nil {
 		return resp, err
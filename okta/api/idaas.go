@@ -44,23 +44,44 @@ type OktaIDaaSClient interface {
 	HTTPClient() *http.Client
 }
 
+// PrivateKeyFunc returns the current private key of the provider's service
+// app and its key ID.
+type PrivateKeyFunc func(ctx context.Context) (privateKey, privateKeyID string, err error)
+
 type OktaAPIConfig struct {
-	AccessToken    string
-	ApiToken       string
-	Backoff        bool
-	ClientID       string
-	Domain         string
-	HttpProxy      string
-	Logger         hclog.Logger
-	MaxAPICapacity int
-	MaxWait        int
-	MinWait        int
-	OrgName        string
-	PrivateKey     string
-	PrivateKeyId   string
-	RequestTimeout int
-	RetryCount     int
-	Scopes         []string
+	AccessToken        string
+	ApiToken           string
+	APIMutex           *apimutex.APIMutex
+	APIResponseCache   *transport.ResponseCache
+	Backoff            bool
+	ClientID           string
+	Domain             string
+	HttpProxy          string
+	Logger             hclog.Logger
+	MaxAPICapacity     int
+	MaxWait            int
+	MinWait            int
+	OrgName            string
+	Parallelism        int
+	PrivateKey         string
+	PrivateKeyId       string
+	RateLimitStateFile string
+	RequestTimeout     int
+	ResponseCache      bool
+	RetryCount         int
+	Scopes             []string
+	// TokenSource returns the current API token or access token when they
+	// are re-read or refreshed while the provider runs
+	TokenSource transport.TokenFunc
+	// PrivateKeySource returns the current private key and key ID of the
+	// service app when they are re-read or renewed while the provider runs
+	PrivateKeySource PrivateKeyFunc
+	// OAuthTokenSource gets the access tokens of the service app for private
+	// key authentication, created on first use unless one was provided
+	OAuthTokenSource *transport.OAuthTokenSource
+	// DPoPKey binds the access tokens of service apps requiring DPoP, created
+	// on first use unless one was provided
+	DPoPKey *transport.DPoPKey
 }
 
 type iDaaSAPIClient struct {
@@ -197,12 +218,31 @@ func GetV3ClientConfig(c *OktaAPIConfig) (*okta.Configuration, *okta.APIClient,
 	// adds transport governor to retryable or default client
 	if c.MaxAPICapacity > 0 && c.MaxAPICapacity < 100 {
 		c.Logger.Info(fmt.Sprintf("running with experimental max_api_capacity configuration at %d%%", c.MaxAPICapacity))
-		apiMutex, err := apimutex.NewAPIMutex(c.MaxAPICapacity)
+		apiMutex, err := c.governor()
 		if err != nil {
 			return nil, nil, err
 		}
 		httpClient.Transport = transport.NewGovernedTransport(httpClient.Transport, apiMutex, c.Logger)
 	}
+	// adds shared response cache to retryable or default client
+	if c.ResponseCache {
+		httpClient.Transport = transport.NewCachingTransport(httpClient.Transport, c.responseCache(), c.Logger)
+	}
+	// adds the proofs of DPoP bound access tokens
+	httpClient.Transport = transport.NewDPoPTransport(httpClient.Transport, c.dpopKey(), c.Logger)
+	// sets the current token of credentials re-read or refreshed while running
+	if scheme := c.tokenScheme(); scheme != "" && c.TokenSource != nil {
+		httpClient.Transport = transport.NewAuthorizationTransport(httpClient.Transport, scheme, c.TokenSource, c.Logger)
+	}
+	// authorizes requests with the access tokens of the service app shared by
+	// every SDK client
+	if c.usesOAuthTokenSource() {
+		source, err := c.oauthTokenSource(httpClient.Transport)
+		if err != nil {
+			return nil, nil, err
+		}
+		httpClient.Transport = transport.NewOAuthTransport(httpClient.Transport, source, c.Logger)
+	}
 	var orgUrl string
 	var disableHTTPS bool
 	if c.HttpProxy != "" {
@@ -262,7 +302,7 @@ func GetV3ClientConfig(c *OktaAPIConfig) (*okta.Configuration, *okta.APIClient,
 	case c.PrivateKey != "":
 		setters = append(
 			setters,
-			okta.WithPrivateKey(c.PrivateKey), okta.WithPrivateKeyId(c.PrivateKeyId), okta.WithScopes(c.Scopes), okta.WithClientId(c.ClientID), okta.WithAuthorizationMode("PrivateKey"),
+			okta.WithToken(oauthTokenPlaceholder), okta.WithAuthorizationMode("Bearer"),
 		)
 	}
 
@@ -317,7 +357,7 @@ func oktaV2SDKClient(httpClient *http.Client, c *OktaAPIConfig) (client *sdk.Cli
 	case c.PrivateKey != "":
 		setters = append(
 			setters,
-			sdk.WithPrivateKey(c.PrivateKey), sdk.WithPrivateKeyId(c.PrivateKeyId), sdk.WithScopes(c.Scopes), sdk.WithClientId(c.ClientID), sdk.WithAuthorizationMode("PrivateKey"),
+			sdk.WithToken(oauthTokenPlaceholder), sdk.WithAuthorizationMode("Bearer"),
 		)
 	}
 
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// Capability is a feature of Okta that an org may or may not have, e.g.
+// because it is a separately licensed product or because it is only available
+// in Okta Identity Engine.
+type Capability string
+
+const (
+	// CapabilityIdentityEngine is Okta Identity Engine, orgs without it are
+	// Classic Engine orgs.
+	// Detection: GET /.well-known/okta-organization (pipeline "idx")
+	CapabilityIdentityEngine Capability = "identity_engine"
+	// CapabilityGovernance is Okta Identity Governance.
+	// Detection: GET /governance/api/v1/settings
+	CapabilityGovernance Capability = "governance"
+	// CapabilityIdentityThreatProtection is Identity Threat Protection with
+	// Okta AI, which brings the entity risk and post auth session policies.
+	// Detection: GET /api/v1/policies?type=ENTITY_RISK
+	CapabilityIdentityThreatProtection Capability = "identity_threat_protection"
+	// CapabilityRealms is Okta realms.
+	// Detection: GET /api/v1/realms
+	CapabilityRealms Capability = "realms"
+	// CapabilityWorkflows is Okta Workflows.
+	// Detection: the Okta Workflows app is in the org's apps
+	CapabilityWorkflows Capability = "workflows"
+)
+
+// errorCodeFeatureNotEnabled is the Okta error code of requests to a feature
+// the org doesn't have.
+const errorCodeFeatureNotEnabled = "E0000015"
+
+// ErrCapabilityProbeUnauthorized is the error of probes whose request the org
+// rejected with a 401. Unlike other probe failures it isn't taken as the org
+// having the capability, the credentials of the provider are wrong.
+var ErrCapabilityProbeUnauthorized = errors.New("the org rejected the credentials of the provider")
+
+var capabilityNames = map[Capability]string{
+	CapabilityIdentityEngine:           "Okta Identity Engine",
+	CapabilityGovernance:               "Okta Identity Governance",
+	CapabilityIdentityThreatProtection: "Identity Threat Protection",
+	CapabilityRealms:                   "Realms",
+	CapabilityWorkflows:                "Okta Workflows",
+}
+
+// String returns the product name of the capability.
+func (c Capability) String() string {
+	if name, ok := capabilityNames[c]; ok {
+		return name
+	}
+	return string(c)
+}
+
+// CapabilityProbe reports whether the org has a capability. It returns an
+// error when that can't be determined, e.g. because the credentials of the
+// provider aren't allowed to make the request.
+type CapabilityProbe func(ctx context.Context) (bool, error)
+
+// Capabilities is the registry of the capabilities of an org. Each capability
+// is probed the first time it is needed and the result is kept for the life
+// of the provider instance. A probe that fails is tried again the next time
+// the capability is needed.
+type Capabilities struct {
+	lock    sync.Mutex
+	probes  map[Capability]CapabilityProbe
+	results map[Capability]*capabilityResult
+}
+
+type capabilityResult struct {
+	lock  sync.Mutex
+	known bool
+	has   bool
+}
+
+// NewCapabilities returns a registry of capabilities detected with probes.
+func NewCapabilities(probes map[Capability]CapabilityProbe) *Capabilities {
+	return &Capabilities{
+		probes:  probes,
+		results: map[Capability]*capabilityResult{},
+	}
+}
+
+// Has reports whether the org has capability. ok is false when it couldn't be
+// determined, in which case the capability should be assumed to be there and
+// the API left to reject what the org doesn't support.
+func (c *Capabilities) Has(ctx context.Context, capability Capability) (has, ok bool) {
+	has, err := c.probe(ctx, capability)
+	return has, err == nil
+}
+
+// probe returns whether the org has capability, probing it unless a probe
+// already succeeded.
+func (c *Capabilities) probe(ctx context.Context, capability Capability) (bool, error) {
+	if c == nil {
+		return false, errors.New("the capabilities of the org aren't known")
+	}
+	c.lock.Lock()
+	probe, known := c.probes[capability]
+	result, found := c.results[capability]
+	if !found {
+		result = &capabilityResult{}
+		c.results[capability] = result
+	}
+	c.lock.Unlock()
+	if !known {
+		return false, fmt.Errorf("%s can't be probed", capability)
+	}
+	result.lock.Lock()
+	defer result.lock.Unlock()
+	if !result.known {
+		has, err := probe(ctx)
+		if err != nil {
+			return false, err
+		}
+		result.has, result.known = has, true
+	}
+	return result.has, nil
+}
+
+// Missing returns the capabilities of required that the org is known not to
+// have. It returns an error when a probe was rejected for the credentials of
+// the provider, other probe failures are ignored.
+func (c *Capabilities) Missing(ctx context.Context, required ...Capability) ([]Capability, error) {
+	var missing []Capability
+	for _, capability := range required {
+		has, err := c.probe(ctx, capability)
+		if errors.Is(err, ErrCapabilityProbeUnauthorized) {
+			return nil, fmt.Errorf("failed to check whether the org has %s: %w", capability, err)
+		}
+		if err == nil && !has {
+			missing = append(missing, capability)
+		}
+	}
+	return missing, nil
+}
+
+// MissingCapabilitiesError describes the capabilities an object of the
+// provider needs that the org doesn't have.
+func MissingCapabilitiesError(name string, missing []Capability) (summary, detail string) {
+	names := make([]string, len(missing))
+	for i, capability := range missing {
+		names[i] = capability.String()
+	}
+	summary = fmt.Sprintf("%s requires %s", name, strings.Join(names, " and "))
+	detail = fmt.Sprintf("%s can only be used in orgs that have %s, which the org of the provider doesn't have. "+
+		"Enable the feature in the org or remove %s from the configuration.", name, strings.Join(names, " and "), name)
+	return summary, detail
+}
+
+// capabilityProbes returns the probes of the capabilities of the org of the
+// configuration's API clients.
+func (c *Config) capabilityProbes() map[Capability]CapabilityProbe {
+	return map[Capability]CapabilityProbe{
+		CapabilityIdentityEngine: func(ctx context.Context) (bool, error) {
+			org, _, err := c.OktaIDaaSClient.OktaSDKClientV3().OrgSettingAPI.GetWellknownOrgMetadata(ctx).Execute()
+			if err != nil {
+				return false, err
+			}
+			// v1 == Classic, idx == OIE
+			c.ClassicOrg, c.QueriedWellKnown = org.GetPipeline() == "v1", true
+			return !c.ClassicOrg, nil
+		},
+		CapabilityGovernance: func(ctx context.Context) (bool, error) {
+			return c.probeEndpoint(ctx, "/governance/api/v1/settings")
+		},
+		CapabilityIdentityThreatProtection: func(ctx context.Context) (bool, error) {
+			// the policy type is unknown to orgs without it
+			return c.probeEndpoint(ctx, "/api/v1/policies?type=ENTITY_RISK", http.StatusBadRequest)
+		},
+		CapabilityRealms: func(ctx context.Context) (bool, error) {
+			return c.probeEndpoint(ctx, "/api/v1/realms?limit=1")
+		},
+		CapabilityWorkflows: func(ctx context.Context) (bool, error) {
+			var apps []map[string]interface{}
+			path := "/api/v1/apps?limit=1&filter=" + url.QueryEscape(`name eq "okta_flow_sso"`)
+			if _, err := c.getJSON(ctx, path, &apps); err != nil {
+				return false, err
+			}
+			return len(apps) > 0, nil
+		},
+	}
+}
+
+// probeEndpoint reports whether GET path succeeds. The org doesn't have the
+// capability when the request is rejected with one of missingStatuses, with a
+// 404, or with the feature not enabled error. A 401 is an
+// ErrCapabilityProbeUnauthorized error.
+func (c *Config) probeEndpoint(ctx context.Context, path string, missingStatuses ...int) (bool, error) {
+	var body interface{}
+	resp, err := c.getJSON(ctx, path, &body)
+	if err == nil {
+		return true, nil
+	}
+	var oktaErr *sdk.Error
+	if errors.As(err, &oktaErr) && oktaErr.ErrorCode == errorCodeFeatureNotEnabled {
+		return false, nil
+	}
+	if resp != nil && resp.Response != nil {
+		if resp.StatusCode == http.StatusUnauthorized {
+			return false, fmt.Errorf("%w: %v", ErrCapabilityProbeUnauthorized, err)
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		for _, status := range missingStatuses {
+			if resp.StatusCode == status {
+				return false, nil
+			}
+		}
+	}
+	return false, err
+}
+
+func (c *Config) getJSON(ctx context.Context, path string, v interface{}) (*sdk.Response, error) {
+	re := c.OktaIDaaSClient.OktaSDKClientV2().GetRequestExecutor()
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return re.Do(ctx, req, v)
+}
@@ -12,36 +12,55 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/okta/terraform-provider-okta/okta/api"
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+	"github.com/okta/terraform-provider-okta/okta/internal/transport"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 )
 
 type (
 	// Config contains our provider schema values and Okta clients
 	Config struct {
-		AccessToken          string
-		ApiToken             string
-		Backoff              bool
-		ClassicOrg           bool
-		ClientID             string
-		Domain               string
-		HttpProxy            string
-		HttpTransport        http.RoundTripper
-		LogLevel             int
-		Logger               hclog.Logger
-		MaxAPICapacity       int
-		MaxWait              int
-		MinWait              int
-		OktaIDaaSClient      api.OktaIDaaSClient
-		OktaGovernanceClient api.OktaGovernanceClient
-		OrgName              string
-		Parallelism          int
-		PrivateKey           string
-		PrivateKeyId         string
-		QueriedWellKnown     bool
-		RequestTimeout       int
-		RetryCount           int
-		Scopes               []string
-		TimeOperations       TimeOperations
+		AccessToken           string
+		ApiToken              string
+		APITokenFile          string
+		APIMutex              *apimutex.APIMutex
+		APIResponseCache      *transport.ResponseCache
+		Backoff               bool
+		Capabilities          *Capabilities
+		ClassicOrg            bool
+		ClientID              string
+		CredentialProcess     string
+		Domain                string
+		HttpProxy             string
+		HttpTransport         http.RoundTripper
+		LogLevel              int
+		Logger                hclog.Logger
+		MaxAPICapacity        int
+		MaxMembershipRemovals string
+		MaxWait               int
+		MinWait               int
+		OktaIDaaSClient       api.OktaIDaaSClient
+		OktaGovernanceClient  api.OktaGovernanceClient
+		OrgAlias              string
+		OrgName               string
+		Orgs                  map[string]*Config
+		Parallelism           int
+		PrivateKey            string
+		PrivateKeyFile        string
+		PrivateKeyId          string
+		QueriedWellKnown      bool
+		RateLimitStateFile    string
+		RequestTimeout        int
+		ResponseCache         bool
+		RetryCount            int
+		Scopes                []string
+		TimeOperations        TimeOperations
+		UserSchemaProperties  *UserSchemaProperties
+
+		apiTokenFile      *secretFile
+		privateKeyFile    *secretFile
+		credentialProcess *credentialProcess
+		credentialSources map[string]string
 	}
 )
 
@@ -55,6 +74,8 @@ func NewConfig(d *schema.ResourceData) *Config {
 		Parallelism:    1,
 		RequestTimeout: 0,
 		RetryCount:     5,
+
+		UserSchemaProperties: NewUserSchemaProperties(),
 	}
 
 	if val, ok := d.GetOk("org_name"); ok {
@@ -73,19 +94,10 @@ func NewConfig(d *schema.ResourceData) *Config {
 		}
 	}
 
-	if val, ok := d.GetOk("api_token"); ok {
-		config.ApiToken = val.(string)
-	}
-	if config.ApiToken == "" && os.Getenv("OKTA_API_TOKEN") != "" {
-		config.ApiToken = os.Getenv("OKTA_API_TOKEN")
-	}
+	config.ApiToken = config.credential(d, "api_token", "OKTA_API_TOKEN")
+	config.APITokenFile = setting(d, "api_token_file", "OKTA_API_TOKEN_FILE")
 
-	if val, ok := d.GetOk("access_token"); ok {
-		config.AccessToken = val.(string)
-	}
-	if config.AccessToken == "" && os.Getenv("OKTA_ACCESS_TOKEN") != "" {
-		config.AccessToken = os.Getenv("OKTA_ACCESS_TOKEN")
-	}
+	config.AccessToken = config.credential(d, "access_token", "OKTA_ACCESS_TOKEN")
 
 	if val, ok := d.GetOk("client_id"); ok {
 		config.ClientID = val.(string)
@@ -94,12 +106,8 @@ func NewConfig(d *schema.ResourceData) *Config {
 		config.ClientID = os.Getenv("OKTA_API_CLIENT_ID")
 	}
 
-	if val, ok := d.GetOk("private_key"); ok {
-		config.PrivateKey = val.(string)
-	}
-	if config.PrivateKey == "" && os.Getenv("OKTA_API_PRIVATE_KEY") != "" {
-		config.PrivateKey = os.Getenv("OKTA_API_PRIVATE_KEY")
-	}
+	config.PrivateKey = config.credential(d, "private_key", "OKTA_API_PRIVATE_KEY")
+	config.PrivateKeyFile = setting(d, "private_key_file", "OKTA_API_PRIVATE_KEY_FILE")
 
 	if val, ok := d.GetOk("private_key_id"); ok {
 		config.PrivateKeyId = val.(string)
@@ -108,6 +116,8 @@ func NewConfig(d *schema.ResourceData) *Config {
 		config.PrivateKeyId = os.Getenv("OKTA_API_PRIVATE_KEY_ID")
 	}
 
+	config.CredentialProcess = setting(d, "credential_process", "OKTA_CREDENTIAL_PROCESS")
+
 	if val, ok := d.GetOk("scopes"); ok {
 		config.Scopes = utils.ConvertInterfaceToStringSet(val)
 	}
@@ -157,6 +167,29 @@ func NewConfig(d *schema.ResourceData) *Config {
 		}
 	}
 
+	if val, ok := d.GetOk("response_cache"); ok {
+		config.ResponseCache = val.(bool)
+	}
+	if !config.ResponseCache && os.Getenv("OKTA_RESPONSE_CACHE") != "" {
+		if val, err := strconv.ParseBool(os.Getenv("OKTA_RESPONSE_CACHE")); err == nil {
+			config.ResponseCache = val
+		}
+	}
+
+	if val, ok := d.GetOk("rate_limit_state_file"); ok {
+		config.RateLimitStateFile = val.(string)
+	}
+	if config.RateLimitStateFile == "" && os.Getenv("OKTA_RATE_LIMIT_STATE_FILE") != "" {
+		config.RateLimitStateFile = os.Getenv("OKTA_RATE_LIMIT_STATE_FILE")
+	}
+
+	if val, ok := d.GetOk("max_membership_removals"); ok {
+		config.MaxMembershipRemovals = val.(string)
+	}
+	if config.MaxMembershipRemovals == "" && os.Getenv("OKTA_MAX_MEMBERSHIP_REMOVALS") != "" {
+		config.MaxMembershipRemovals = os.Getenv("OKTA_MAX_MEMBERSHIP_REMOVALS")
+	}
+
 	if httpProxy, ok := d.Get("http_proxy").(string); ok {
 		config.HttpProxy = httpProxy
 	}
@@ -188,6 +221,13 @@ func (c *Config) SetupLogger() {
 // IsClassicOrg returns true if the org is a classic org. Does lazy evaluation
 // of the well known endpoint.
 func (c *Config) IsClassicOrg(ctx context.Context) bool {
+	if c.Capabilities != nil {
+		// the probe of Okta Identity Engine queries the well known endpoint
+		if _, ok := c.Capabilities.Has(ctx, CapabilityIdentityEngine); !ok {
+			c.Logger.Error("error querying GET /.well-known/okta-organization")
+		}
+		return c.ClassicOrg
+	}
 	if !c.QueriedWellKnown {
 		// Discover if the Okta Org is Classic or OIE
 		org, _, err := c.OktaIDaaSClient.OktaSDKClientV3().OrgSettingAPI.GetWellknownOrgMetadata(ctx).Execute()
@@ -203,6 +243,15 @@ func (c *Config) IsClassicOrg(ctx context.Context) bool {
 	return c.ClassicOrg
 }
 
+// OrgURL returns the URL of the Okta org, or of the HTTP proxy standing in for
+// it when one is configured.
+func (c *Config) OrgURL() string {
+	if c.HttpProxy != "" {
+		return strings.TrimSuffix(c.HttpProxy, "/")
+	}
+	return fmt.Sprintf("https://%s.%s", c.OrgName, c.Domain)
+}
+
 func (c *Config) IsOAuth20Auth() bool {
 	return c.PrivateKey != "" || c.AccessToken != ""
 }
@@ -211,25 +260,34 @@ func (c *Config) SetTimeOperations(op TimeOperations) {
 	c.TimeOperations = op
 }
 
-// LoadAPIClient initializes the Okta SDK clients
+// LoadAPIClient initializes the Okta SDK clients. All of the SDK clients share
+// one api mutex so that rate limit governance accounts for the requests of
+// every client, and one response cache when response caching is enabled.
 func (c *Config) LoadAPIClient() (err error) {
 	iDaaSConfig := &api.OktaAPIConfig{
-		AccessToken:    c.AccessToken,
-		ApiToken:       c.ApiToken,
-		Backoff:        c.Backoff,
-		ClientID:       c.ClientID,
-		Domain:         c.Domain,
-		HttpProxy:      c.HttpProxy,
-		Logger:         c.Logger,
-		MaxAPICapacity: c.MaxAPICapacity,
-		MaxWait:        c.MaxWait,
-		MinWait:        c.MinWait,
-		OrgName:        c.OrgName,
-		PrivateKey:     c.PrivateKey,
-		PrivateKeyId:   c.PrivateKeyId,
-		RequestTimeout: c.RequestTimeout,
-		RetryCount:     c.RetryCount,
-		Scopes:         c.Scopes,
+		AccessToken:        c.AccessToken,
+		ApiToken:           c.ApiToken,
+		APIMutex:           c.APIMutex,
+		APIResponseCache:   c.APIResponseCache,
+		Backoff:            c.Backoff,
+		ClientID:           c.ClientID,
+		Domain:             c.Domain,
+		HttpProxy:          c.HttpProxy,
+		Logger:             c.Logger,
+		MaxAPICapacity:     c.MaxAPICapacity,
+		MaxWait:            c.MaxWait,
+		MinWait:            c.MinWait,
+		OrgName:            c.OrgName,
+		Parallelism:        c.Parallelism,
+		PrivateKey:         c.PrivateKey,
+		PrivateKeyId:       c.PrivateKeyId,
+		RateLimitStateFile: c.RateLimitStateFile,
+		RequestTimeout:     c.RequestTimeout,
+		ResponseCache:      c.ResponseCache,
+		RetryCount:         c.RetryCount,
+		Scopes:             c.Scopes,
+		TokenSource:        c.tokenSource(),
+		PrivateKeySource:   c.privateKeySource(),
 	}
 
 	idaasClient, err := api.NewOktaIDaaSAPIClient(iDaaSConfig)
@@ -242,12 +300,16 @@ func (c *Config) LoadAPIClient() (err error) {
 	}
 	c.SetIdaasAPIClient(idaasClient)
 	c.SetGovernanceAPIClient(governanceClient)
+	c.APIMutex = iDaaSConfig.APIMutex
+	c.APIResponseCache = iDaaSConfig.APIResponseCache
 	return
 }
 
-// SetIdaasAPIClient allow other environments to inject an alternative idaas client to the config
+// SetIdaasAPIClient allow other environments to inject an alternative idaas client to the config.
+// The capabilities of the org are probed again with the new client.
 func (c *Config) SetIdaasAPIClient(client api.OktaIDaaSClient) {
 	c.OktaIDaaSClient = client
+	c.Capabilities = NewCapabilities(c.capabilityProbes())
 }
 
 func (c *Config) SetGovernanceAPIClient(client api.OktaGovernanceClient) {
@@ -258,12 +320,13 @@ func (c *Config) VerifyCredentials(ctx context.Context) error {
 	// NOTE: validate credentials during initial config with a call to
 	// GET /api/v1/users/me
 	// only for SSWS API token. Should we keep doing this?
+	c.Logger.Info(fmt.Sprintf("using the credentials of %s", c.CredentialSource()))
 	if c.ApiToken != "" {
 		if _, _, err := c.OktaIDaaSClient.OktaSDKClientV3().UserAPI.GetUser(ctx, "me").Execute(); err != nil {
-			return fmt.Errorf("error with v3 SDK client: %v", err)
+			return fmt.Errorf("error with v3 SDK client using the credentials of %s: %v", c.CredentialSource(), err)
 		}
 		if _, _, err := c.OktaIDaaSClient.OktaSDKClientV2().User.GetUser(ctx, "me"); err != nil {
-			return fmt.Errorf("error with v2 SDK client: %v", err)
+			return fmt.Errorf("error with v2 SDK client using the credentials of %s: %v", c.CredentialSource(), err)
 		}
 	}
 
@@ -2,10 +2,20 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/okta/terraform-provider-okta/okta/api"
+	"github.com/okta/terraform-provider-okta/sdk"
 )
 
 func TestConfigLoadAndValidate(t *testing.T) {
@@ -51,3 +61,349 @@ func TestConfigLoadAndValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestUserSchemaProperties(t *testing.T) {
+	loads := 0
+	load := func(context.Context) ([]string, error) {
+		loads++
+		return []string{"customAttribute"}, nil
+	}
+	properties := NewUserSchemaProperties()
+	properties.Add("managedAttribute")
+	tests := []struct {
+		index string
+		known bool
+	}{
+		{"managedAttribute", true},
+		{"customAttribute", true},
+		{"unknownAttribute", false},
+	}
+	for _, test := range tests {
+		known, ok := properties.Has(context.Background(), test.index, load)
+		if !ok || known != test.known {
+			t.Errorf("expected %q to be known %t, got known %t ok %t", test.index, test.known, known, ok)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected the org's user schema properties to be loaded once, got %d loads", loads)
+	}
+
+	failing := NewUserSchemaProperties()
+	if _, ok := failing.Has(context.Background(), "customAttribute", func(context.Context) ([]string, error) {
+		return nil, fmt.Errorf("forbidden")
+	}); ok {
+		t.Error("expected user schema property check to be skipped when the org's properties can't be loaded")
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	ctx := context.Background()
+	probes := 0
+	capabilities := NewCapabilities(map[Capability]CapabilityProbe{
+		CapabilityIdentityEngine: func(ctx context.Context) (bool, error) {
+			probes++
+			return true, nil
+		},
+		CapabilityGovernance: func(ctx context.Context) (bool, error) {
+			return false, nil
+		},
+		CapabilityRealms: func(ctx context.Context) (bool, error) {
+			return false, fmt.Errorf("forbidden")
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		if has, ok := capabilities.Has(ctx, CapabilityIdentityEngine); !has || !ok {
+			t.Errorf("Has(%s) = %t, %t, want true, true", CapabilityIdentityEngine, has, ok)
+		}
+	}
+	if probes != 1 {
+		t.Errorf("the capability was probed %d times, want once", probes)
+	}
+	if has, ok := capabilities.Has(ctx, CapabilityGovernance); has || !ok {
+		t.Errorf("Has(%s) = %t, %t, want false, true", CapabilityGovernance, has, ok)
+	}
+	if _, ok := capabilities.Has(ctx, CapabilityRealms); ok {
+		t.Errorf("expected %s to be unknown when its probe fails", CapabilityRealms)
+	}
+	if _, ok := capabilities.Has(ctx, CapabilityWorkflows); ok {
+		t.Errorf("expected %s to be unknown without a probe", CapabilityWorkflows)
+	}
+
+	missing, err := capabilities.Missing(ctx, CapabilityIdentityEngine, CapabilityGovernance, CapabilityRealms, CapabilityWorkflows)
+	if err != nil || len(missing) != 1 || missing[0] != CapabilityGovernance {
+		t.Errorf("Missing() = %v, %v, want only %s", missing, err, CapabilityGovernance)
+	}
+	summary, _ := MissingCapabilitiesError("okta_group_owner", missing)
+	if summary != "okta_group_owner requires Okta Identity Governance" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	var unset *Capabilities
+	if missing, err := unset.Missing(ctx, CapabilityGovernance); err != nil || len(missing) != 0 {
+		t.Errorf("expected no missing capabilities without a registry, got %v, %v", missing, err)
+	}
+}
+
+func TestCapabilitiesProbeFailures(t *testing.T) {
+	ctx := context.Background()
+	var probes int
+	var probeErr error
+	capabilities := NewCapabilities(map[Capability]CapabilityProbe{
+		CapabilityRealms: func(ctx context.Context) (bool, error) {
+			probes++
+			return false, probeErr
+		},
+	})
+
+	probeErr = fmt.Errorf("connection reset")
+	if _, ok := capabilities.Has(ctx, CapabilityRealms); ok {
+		t.Errorf("expected %s to be unknown when its probe fails", CapabilityRealms)
+	}
+	probeErr = fmt.Errorf("%w: invalid token", ErrCapabilityProbeUnauthorized)
+	if _, err := capabilities.Missing(ctx, CapabilityRealms); !errors.Is(err, ErrCapabilityProbeUnauthorized) {
+		t.Errorf("expected the rejected credentials to be reported, got %v", err)
+	}
+	probeErr = nil
+	for i := 0; i < 2; i++ {
+		if missing, err := capabilities.Missing(ctx, CapabilityRealms); err != nil || len(missing) != 1 {
+			t.Errorf("Missing() = %v, %v, want %s", missing, err, CapabilityRealms)
+		}
+	}
+	if probes != 3 {
+		t.Errorf("the capability was probed %d times, want until a probe succeeded (3)", probes)
+	}
+}
+
+func TestProbeEndpoint(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		has    bool
+		err    error
+	}{
+		{status: http.StatusOK, body: `{}`, has: true},
+		{status: http.StatusNotFound, body: `{"errorCode":"E0000007"}`},
+		{status: http.StatusBadRequest, body: `{"errorCode":"E0000015"}`},
+		{status: http.StatusUnauthorized, body: `{"errorCode":"E0000011"}`, err: ErrCapabilityProbeUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, test.body)
+			}))
+			defer server.Close()
+			_, client, err := sdk.NewClient(context.Background(), sdk.WithOrgUrl(server.URL), sdk.WithToken("token"),
+				sdk.WithTestingDisableHttpsCheck(true), sdk.WithCache(false), sdk.WithRateLimitMaxRetries(0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := &Config{OktaIDaaSClient: &testIDaaSClient{client: client}}
+			has, err := c.probeEndpoint(context.Background(), "/governance/api/v1/settings")
+			if has != test.has || !errors.Is(err, test.err) || (test.err == nil && err != nil) {
+				t.Errorf("probeEndpoint() = %t, %v, want %t, %v", has, err, test.has, test.err)
+			}
+		})
+	}
+}
+
+// testIDaaSClient serves the v2 client of an API served by a test server.
+type testIDaaSClient struct {
+	api.OktaIDaaSClient
+	client *sdk.Client
+}
+
+func (c *testIDaaSClient) OktaSDKClientV2() *sdk.Client { return c.client }
+
+func TestOrgs(t *testing.T) {
+	base := &Config{
+		Domain:             "okta.com",
+		Logger:             hclog.NewNullLogger(),
+		MaxAPICapacity:     50,
+		OrgName:            "acme",
+		ApiToken:           "token",
+		RateLimitStateFile: "/tmp/okta-rate-limits",
+		RetryCount:         3,
+	}
+	orgs := []interface{}{
+		map[string]interface{}{"name": "dev", "org_name": "acme-dev", "base_url": "oktapreview.com", "api_token": "dev-token"},
+		map[string]interface{}{"name": "prod", "org_name": "acme", "access_token": "prod-token"},
+	}
+	if err := base.LoadOrgs(orgs); err != nil {
+		t.Fatal(err)
+	}
+
+	dev, err := base.Org("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.OrgURL() != "https://acme-dev.oktapreview.com" || dev.ApiToken != "dev-token" {
+		t.Errorf("unexpected dev org %s with token %q", dev.OrgURL(), dev.ApiToken)
+	}
+	if dev.RetryCount != 3 || dev.MaxAPICapacity != 50 {
+		t.Error("expected the orgs to have the settings of the provider")
+	}
+	if dev.RateLimitStateFile != "/tmp/okta-rate-limits.dev" {
+		t.Errorf("expected the org to have its own rate limit state, got %q", dev.RateLimitStateFile)
+	}
+	prod, _ := base.Org("prod")
+	if prod.OrgURL() != "https://acme.okta.com" || prod.ApiToken != "" || prod.AccessToken != "prod-token" {
+		t.Error("expected the orgs not to inherit the credentials of the provider")
+	}
+	if other, _ := dev.Org("prod"); other != prod {
+		t.Error("expected the orgs to find each other")
+	}
+	if c, _ := base.Org(""); c != base {
+		t.Error("expected the empty org to be the org of the provider")
+	}
+	if _, err := base.Org("preview"); err == nil || err.Error() != `org "preview" is not declared, the orgs of the provider are: dev, prod` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if base.UseFirstOrg(orgs) != dev {
+		t.Error("expected the first org to be the default")
+	}
+
+	for _, invalid := range [][]interface{}{
+		{map[string]interface{}{"name": "dev", "org_name": "acme-dev"}},
+		{map[string]interface{}{"name": "dev", "api_token": "token"}},
+		{orgs[0], orgs[0]},
+	} {
+		if err := base.LoadOrgs(invalid); err == nil {
+			t.Errorf("expected orgs %v to be invalid", invalid)
+		}
+	}
+}
+
+func TestCredentialFiles(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := &Config{APITokenFile: tokenFile}
+	if err := c.LoadCredentials(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.ApiToken != "first" {
+		t.Errorf("expected the token of the file, got %q", c.ApiToken)
+	}
+	if source := c.CredentialSource(); source != "api_token_file "+tokenFile {
+		t.Errorf("unexpected credential source %q", source)
+	}
+
+	token := c.tokenSource()
+	if token == nil {
+		t.Fatal("expected the token of the file to be re-read")
+	}
+	if err := os.WriteFile(tokenFile, []byte("rotated"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := token(context.Background()); err != nil || got != "rotated" {
+		t.Errorf("expected the rotated token, got %q, %v", got, err)
+	}
+
+	c = &Config{ApiToken: "direct", APITokenFile: filepath.Join(dir, "missing")}
+	if err := c.LoadCredentials(context.Background()); err != nil || c.ApiToken != "direct" {
+		t.Error("expected the api_token to take precedence over the api_token_file")
+	}
+	if c.tokenSource() != nil {
+		t.Error("expected a token set directly not to be re-read")
+	}
+	c = &Config{APITokenFile: filepath.Join(dir, "missing")}
+	if err := c.LoadCredentials(context.Background()); err == nil {
+		t.Error("expected an error for a missing api_token_file")
+	}
+
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("first key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c = &Config{PrivateKeyFile: keyFile, PrivateKeyId: "kid1"}
+	if err := c.LoadCredentials(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.PrivateKey != "first key" {
+		t.Errorf("expected the key of the file, got %q", c.PrivateKey)
+	}
+	if c.tokenSource() != nil {
+		t.Error("expected no token source for a private key")
+	}
+	key := c.privateKeySource()
+	if key == nil {
+		t.Fatal("expected the key of the file to be re-read")
+	}
+	if err := os.WriteFile(keyFile, []byte("rotated key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, kid, err := key(context.Background()); err != nil || got != "rotated key" || kid != "kid1" {
+		t.Errorf("expected the rotated key, got %q, %q, %v", got, kid, err)
+	}
+	c = &Config{PrivateKey: "direct", PrivateKeyFile: keyFile}
+	if err := c.LoadCredentials(context.Background()); err != nil || c.privateKeySource() != nil {
+		t.Error("expected a key set directly not to be re-read")
+	}
+}
+
+func TestCredentialProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	count := filepath.Join(dir, "count")
+	expiration := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	command := fmt.Sprintf(`echo run >> %s; echo '{"access_token": "token-'$(wc -l < %s | tr -d " ")'", "expiration": "%s"}'`,
+		count, count, expiration.Format(time.RFC3339))
+
+	c := &Config{CredentialProcess: command}
+	if err := c.LoadCredentials(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.AccessToken != "token-1" || c.CredentialSource() != "credential_process" {
+		t.Errorf("unexpected access token %q from %s", c.AccessToken, c.CredentialSource())
+	}
+
+	now := expiration.Add(-time.Hour)
+	c.credentialProcess.now = func() time.Time { return now }
+	token := c.tokenSource()
+	if got, _ := token(context.Background()); got != "token-1" {
+		t.Errorf("expected the credentials to be kept until they expire, got %q", got)
+	}
+	now = expiration.Add(-time.Minute)
+	if got, _ := token(context.Background()); got != "token-2" {
+		t.Errorf("expected the credentials to be renewed before they expire, got %q", got)
+	}
+
+	// the private key of the credential process is renewed too
+	keyCommand := fmt.Sprintf(`echo run >> %s; echo '{"client_id": "app", "private_key": "key-'$(wc -l < %s | tr -d " ")'", "private_key_id": "kid", "expiration": "%s"}'`,
+		count, count, expiration.Format(time.RFC3339))
+	c = &Config{CredentialProcess: keyCommand}
+	if err := c.LoadCredentials(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.PrivateKey != "key-3" || c.ClientID != "app" {
+		t.Errorf("unexpected private key %q of client %q", c.PrivateKey, c.ClientID)
+	}
+	now = expiration.Add(-time.Hour)
+	c.credentialProcess.now = func() time.Time { return now }
+	key := c.privateKeySource()
+	if got, kid, _ := key(context.Background()); got != "key-3" || kid != "kid" {
+		t.Errorf("expected the private key to be kept until it expires, got %q, %q", got, kid)
+	}
+	now = expiration.Add(-time.Minute)
+	if got, _, _ := key(context.Background()); got != "key-4" {
+		t.Errorf("expected the private key to be renewed before it expires, got %q", got)
+	}
+
+	for command, message := range map[string]string{
+		"echo oops >&2; exit 1": "credential_process failed: exit status 1: oops",
+		"echo not json":         "credential_process didn't print a JSON object of credentials",
+		"echo '{}'":             "credential_process returned no api_token, access_token or private_key",
+	} {
+		c := &Config{CredentialProcess: command}
+		if err := c.LoadCredentials(context.Background()); err == nil || !strings.HasPrefix(err.Error(), message) {
+			t.Errorf("expected %q for %q, got %v", message, command, err)
+		}
+	}
+}
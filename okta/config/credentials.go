@@ -0,0 +1,276 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/api"
+	"github.com/okta/terraform-provider-okta/okta/internal/transport"
+)
+
+const (
+	// credentialProcessTimeout is how long the credential process may run.
+	credentialProcessTimeout = time.Minute
+	// credentialExpiryWindow is how long before their expiration credentials
+	// of the credential process are renewed.
+	credentialExpiryWindow = 5 * time.Minute
+)
+
+// credential returns the value of the credential argument key, or else of the
+// environment variable env, and records where it comes from.
+func (c *Config) credential(d *schema.ResourceData, key, env string) string {
+	if val, ok := d.GetOk(key); ok && val.(string) != "" {
+		c.setCredentialSource(key, fmt.Sprintf("the provider's %s", key))
+		return val.(string)
+	}
+	if val := os.Getenv(env); val != "" {
+		c.setCredentialSource(key, fmt.Sprintf("the %s environment variable", env))
+		return val
+	}
+	return ""
+}
+
+// setting returns the value of the argument key, or else of the environment
+// variable env.
+func setting(d *schema.ResourceData, key, env string) string {
+	if val, ok := d.GetOk(key); ok && val.(string) != "" {
+		return val.(string)
+	}
+	return os.Getenv(env)
+}
+
+func (c *Config) setCredentialSource(key, source string) {
+	if c.credentialSources == nil {
+		c.credentialSources = map[string]string{}
+	}
+	c.credentialSources[key] = source
+}
+
+// CredentialSource describes where the credentials the API clients use come
+// from, e.g. "the OKTA_API_TOKEN environment variable".
+func (c *Config) CredentialSource() string {
+	// the precedence of the credentials in the API clients
+	for _, key := range []struct {
+		name  string
+		value string
+	}{
+		{"access_token", c.AccessToken},
+		{"api_token", c.ApiToken},
+		{"private_key", c.PrivateKey},
+	} {
+		if key.value == "" {
+			continue
+		}
+		if source, ok := c.credentialSources[key.name]; ok {
+			return source
+		}
+		return key.name
+	}
+	return "no credentials"
+}
+
+// LoadCredentials reads the credentials of the provider that aren't set
+// directly: the api_token_file and private_key_file, and else the output of
+// the credential_process.
+func (c *Config) LoadCredentials(ctx context.Context) error {
+	if c.ApiToken == "" && c.AccessToken == "" && c.APITokenFile != "" {
+		c.apiTokenFile = &secretFile{path: c.APITokenFile}
+		token, err := c.apiTokenFile.read()
+		if err != nil {
+			return fmt.Errorf("failed to read api_token_file: %w", err)
+		}
+		c.ApiToken = token
+		c.setCredentialSource("api_token", fmt.Sprintf("api_token_file %s", c.APITokenFile))
+	}
+	if c.PrivateKey == "" && c.PrivateKeyFile != "" {
+		c.privateKeyFile = &secretFile{path: c.PrivateKeyFile}
+		key, err := c.privateKeyFile.read()
+		if err != nil {
+			return fmt.Errorf("failed to read private_key_file: %w", err)
+		}
+		c.PrivateKey = key
+		c.setCredentialSource("private_key", fmt.Sprintf("private_key_file %s", c.PrivateKeyFile))
+	}
+	if c.HasCredentials() || c.CredentialProcess == "" {
+		return nil
+	}
+
+	c.credentialProcess = &credentialProcess{command: c.CredentialProcess}
+	credentials, err := c.credentialProcess.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	c.ApiToken = credentials.APIToken
+	c.AccessToken = credentials.AccessToken
+	c.PrivateKey = credentials.PrivateKey
+	if credentials.PrivateKeyID != "" {
+		c.PrivateKeyId = credentials.PrivateKeyID
+	}
+	if credentials.ClientID != "" {
+		c.ClientID = credentials.ClientID
+	}
+	if len(credentials.Scopes) > 0 {
+		c.Scopes = credentials.Scopes
+	}
+	for _, key := range []string{"access_token", "api_token", "private_key"} {
+		c.setCredentialSource(key, "credential_process")
+	}
+	if !c.HasCredentials() {
+		return errors.New("credential_process returned no api_token, access_token or private_key")
+	}
+	return nil
+}
+
+// tokenSource returns the current API token or access token of the provider
+// when it is re-read or renewed while the provider runs, nil when the token
+// doesn't change.
+func (c *Config) tokenSource() transport.TokenFunc {
+	switch {
+	case c.credentialProcess != nil && (c.AccessToken != "" || c.ApiToken != ""):
+		accessToken := c.AccessToken != ""
+		return func(ctx context.Context) (string, error) {
+			credentials, err := c.credentialProcess.credentials(ctx)
+			if err != nil {
+				return "", err
+			}
+			if accessToken {
+				return credentials.AccessToken, nil
+			}
+			return credentials.APIToken, nil
+		}
+	case c.apiTokenFile != nil && c.AccessToken == "":
+		return func(context.Context) (string, error) {
+			return c.apiTokenFile.read()
+		}
+	}
+	return nil
+}
+
+// privateKeySource returns the current private key of the provider's service
+// app and its key ID when the key is re-read or renewed while the provider
+// runs, nil when the key doesn't change.
+func (c *Config) privateKeySource() api.PrivateKeyFunc {
+	if c.AccessToken != "" || c.ApiToken != "" || c.PrivateKey == "" {
+		return nil
+	}
+	switch {
+	case c.credentialProcess != nil:
+		return func(ctx context.Context) (string, string, error) {
+			credentials, err := c.credentialProcess.credentials(ctx)
+			if err != nil {
+				return "", "", err
+			}
+			if credentials.PrivateKey == "" {
+				return "", "", errors.New("credential_process returned no private_key")
+			}
+			if credentials.PrivateKeyID != "" {
+				return credentials.PrivateKey, credentials.PrivateKeyID, nil
+			}
+			return credentials.PrivateKey, c.PrivateKeyId, nil
+		}
+	case c.privateKeyFile != nil:
+		return func(context.Context) (string, string, error) {
+			key, err := c.privateKeyFile.read()
+			return key, c.PrivateKeyId, err
+		}
+	}
+	return nil
+}
+
+// secretFile is a file holding a credential. It is read again when it
+// changes, e.g. when the credential is rotated.
+type secretFile struct {
+	path    string
+	lock    sync.Mutex
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+func (f *secretFile) read() (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", err
+	}
+	if f.value != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.value, nil
+	}
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(b))
+	if value == "" {
+		return "", fmt.Errorf("%s is empty", f.path)
+	}
+	f.value, f.modTime, f.size = value, info.ModTime(), info.Size()
+	return f.value, nil
+}
+
+// processCredentials are the credentials a credential process prints, as
+// JSON.
+type processCredentials struct {
+	APIToken     string     `json:"api_token"`
+	AccessToken  string     `json:"access_token"`
+	ClientID     string     `json:"client_id"`
+	PrivateKey   string     `json:"private_key"`
+	PrivateKeyID string     `json:"private_key_id"`
+	Scopes       []string   `json:"scopes"`
+	Expiration   *time.Time `json:"expiration"`
+}
+
+// credentialProcess is an external command printing the credentials of the
+// provider. Its credentials are kept until shortly before they expire, or for
+// the life of the provider when they don't expire.
+type credentialProcess struct {
+	command string
+	lock    sync.Mutex
+	cached  *processCredentials
+	// now is time.Now, replaced in tests
+	now func() time.Time
+}
+
+func (p *credentialProcess) credentials(ctx context.Context) (*processCredentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	if p.cached != nil && (p.cached.Expiration == nil || now().Add(credentialExpiryWindow).Before(*p.cached.Expiration)) {
+		return p.cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", p.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", p.command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credential_process failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	var credentials processCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		// the output holds secrets, it isn't part of the error
+		return nil, fmt.Errorf("credential_process didn't print a JSON object of credentials: %v", err)
+	}
+	p.cached = &credentials
+	return p.cached, nil
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/okta/terraform-provider-okta/okta/utils"
+)
+
+// LoadOrgs adds the orgs of the provider's orgs blocks to the configuration.
+// Each org has its own credentials and, once its API clients are loaded, its
+// own SDK clients and rate limit state. The other settings of the provider,
+// like retries and back off, apply to all of them.
+func (c *Config) LoadOrgs(orgs []interface{}) error {
+	if len(orgs) == 0 {
+		return nil
+	}
+	c.Orgs = map[string]*Config{}
+	for _, raw := range orgs {
+		block, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := block["name"].(string)
+		if _, ok := c.Orgs[name]; ok {
+			return fmt.Errorf("org %q is declared more than once", name)
+		}
+		org := c.newOrgConfig(name)
+		if v, ok := block["org_name"].(string); ok && v != "" {
+			org.OrgName = v
+		}
+		if v, ok := block["base_url"].(string); ok && v != "" {
+			org.Domain = v
+		}
+		if v, ok := block["http_proxy"].(string); ok && v != "" {
+			org.HttpProxy = v
+		}
+		org.ApiToken, _ = block["api_token"].(string)
+		org.AccessToken, _ = block["access_token"].(string)
+		org.ClientID, _ = block["client_id"].(string)
+		org.PrivateKey, _ = block["private_key"].(string)
+		org.PrivateKeyId, _ = block["private_key_id"].(string)
+		if v, ok := block["scopes"]; ok {
+			org.Scopes = utils.ConvertInterfaceToStringSet(v)
+		}
+		if org.OrgName == "" && org.HttpProxy == "" {
+			return fmt.Errorf("org %q has no org_name", name)
+		}
+		if !org.HasCredentials() {
+			return fmt.Errorf("org %q has no credentials, set one of api_token, access_token or private_key and client_id", name)
+		}
+		c.Orgs[name] = org
+	}
+	for _, org := range c.Orgs {
+		org.Orgs = c.Orgs
+	}
+	return nil
+}
+
+// newOrgConfig returns the configuration of the org name, with the settings
+// of c but none of its credentials or clients.
+func (c *Config) newOrgConfig(name string) *Config {
+	org := &Config{
+		Backoff:               c.Backoff,
+		Domain:                c.Domain,
+		LogLevel:              c.LogLevel,
+		Logger:                c.Logger.Named(name),
+		MaxAPICapacity:        c.MaxAPICapacity,
+		MaxMembershipRemovals: c.MaxMembershipRemovals,
+		MaxWait:               c.MaxWait,
+		MinWait:               c.MinWait,
+		OrgAlias:              name,
+		Parallelism:           c.Parallelism,
+		RequestTimeout:        c.RequestTimeout,
+		ResponseCache:         c.ResponseCache,
+		RetryCount:            c.RetryCount,
+		TimeOperations:        c.TimeOperations,
+		UserSchemaProperties:  NewUserSchemaProperties(),
+	}
+	// every org has its own rate limits
+	if c.RateLimitStateFile != "" {
+		org.RateLimitStateFile = c.RateLimitStateFile + "." + name
+	}
+	return org
+}
+
+// HasCredentials reports whether the configuration has credentials for the
+// Okta API.
+func (c *Config) HasCredentials() bool {
+	return c.ApiToken != "" || c.AccessToken != "" || c.PrivateKey != ""
+}
+
+// UseFirstOrg makes the first of the orgs blocks the default org, for
+// providers that only declare orgs blocks.
+func (c *Config) UseFirstOrg(orgs []interface{}) *Config {
+	if len(orgs) == 0 {
+		return c
+	}
+	block, ok := orgs[0].(map[string]interface{})
+	if !ok {
+		return c
+	}
+	if org, ok := c.Orgs[block["name"].(string)]; ok {
+		return org
+	}
+	return c
+}
+
+// Org returns the configuration of the org name, declared in an orgs block of
+// the provider. The empty name is the default org of the provider.
+func (c *Config) Org(name string) (*Config, error) {
+	if name == "" || name == c.OrgAlias {
+		return c, nil
+	}
+	if org, ok := c.Orgs[name]; ok {
+		return org, nil
+	}
+	names := c.OrgAliases()
+	if len(names) == 0 {
+		return nil, fmt.Errorf("org %q is not declared, the provider has no orgs blocks", name)
+	}
+	return nil, fmt.Errorf("org %q is not declared, the orgs of the provider are: %s", name, strings.Join(names, ", "))
+}
+
+// OrgAliases returns the sorted names of the orgs declared in orgs blocks.
+func (c *Config) OrgAliases() []string {
+	names := make([]string, 0, len(c.Orgs))
+	for name := range c.Orgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadOrgAPIClients initializes the Okta SDK clients of the orgs declared in
+// orgs blocks other than c.
+func (c *Config) LoadOrgAPIClients() error {
+	for _, name := range c.OrgAliases() {
+		org := c.Orgs[name]
+		if org == c {
+			continue
+		}
+		if err := org.LoadAPIClient(); err != nil {
+			return fmt.Errorf("org %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// VerifyOrgCredentials verifies the credentials of the orgs declared in orgs
+// blocks other than c.
+func (c *Config) VerifyOrgCredentials(ctx context.Context) error {
+	for _, name := range c.OrgAliases() {
+		org := c.Orgs[name]
+		if org == c {
+			continue
+		}
+		if err := org.VerifyCredentials(ctx); err != nil {
+			return fmt.Errorf("org %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// SetOrgsTimeOperations sets the time operations of all of the orgs.
+func (c *Config) SetOrgsTimeOperations(op TimeOperations) {
+	c.SetTimeOperations(op)
+	for _, org := range c.Orgs {
+		org.SetTimeOperations(op)
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 // OktaSKU represents an Okta product SKU that an org may or may not have provisioned.
+// The acceptance tests skip on SKUs, the provider detects the features of the
+// org with Capabilities.
 //
 // SKU detection approaches:
 //
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"sync"
+)
+
+// UserSchemaProperties tracks the custom user profile attributes known to a
+// provider instance, both those managed in the configuration being planned
+// and those already present in the org. It lets Okta Expression Language be
+// checked for unknown user attributes at plan time.
+type UserSchemaProperties struct {
+	lock    sync.Mutex
+	managed map[string]bool
+	org     map[string]bool
+	loaded  bool
+	loadErr error
+}
+
+// NewUserSchemaProperties returns an empty set of user schema properties.
+func NewUserSchemaProperties() *UserSchemaProperties {
+	return &UserSchemaProperties{managed: map[string]bool{}}
+}
+
+// Add records a custom user schema property managed in the configuration.
+func (p *UserSchemaProperties) Add(index string) {
+	if p == nil || index == "" {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.managed[index] = true
+}
+
+// Has reports whether index is a known custom user schema property. The
+// properties of the org are loaded with load the first time they are needed,
+// if they can't be loaded ok is false and the property should be treated as
+// known.
+func (p *UserSchemaProperties) Has(ctx context.Context, index string, load func(context.Context) ([]string, error)) (known, ok bool) {
+	if p == nil {
+		return false, false
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.managed[index] {
+		return true, true
+	}
+	if !p.loaded {
+		p.loaded = true
+		var indexes []string
+		indexes, p.loadErr = load(ctx)
+		p.org = make(map[string]bool, len(indexes))
+		for _, i := range indexes {
+			p.org[i] = true
+		}
+	}
+	if p.loadErr != nil {
+		return false, false
+	}
+	return p.org[index], true
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -23,7 +26,10 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &FrameworkProvider{}
+	_ provider.Provider                       = &FrameworkProvider{}
+	_ provider.ProviderWithEphemeralResources = &FrameworkProvider{}
+	_ provider.ProviderWithFunctions          = &FrameworkProvider{}
+	_ provider.ProviderWithListResources      = &FrameworkProvider{}
 )
 
 // NewFrameworkProvider is a helper function to simplify provider server and
@@ -43,23 +49,30 @@ type FrameworkProvider struct {
 }
 
 type FrameworkProviderData struct {
-	OrgName        types.String `tfsdk:"org_name"`
-	AccessToken    types.String `tfsdk:"access_token"`
-	APIToken       types.String `tfsdk:"api_token"`
-	ClientID       types.String `tfsdk:"client_id"`
-	Scopes         types.Set    `tfsdk:"scopes"`
-	PrivateKey     types.String `tfsdk:"private_key"`
-	PrivateKeyID   types.String `tfsdk:"private_key_id"`
-	BaseURL        types.String `tfsdk:"base_url"`
-	HTTPProxy      types.String `tfsdk:"http_proxy"`
-	Backoff        types.Bool   `tfsdk:"backoff"`
-	MinWaitSeconds types.Int64  `tfsdk:"min_wait_seconds"`
-	MaxWaitSeconds types.Int64  `tfsdk:"max_wait_seconds"`
-	MaxRetries     types.Int64  `tfsdk:"max_retries"`
-	Parallelism    types.Int64  `tfsdk:"parallelism"`
-	LogLevel       types.Int64  `tfsdk:"log_level"`
-	MaxAPICapacity types.Int64  `tfsdk:"max_api_capacity"`
-	RequestTimeout types.Int64  `tfsdk:"request_timeout"`
+	OrgName               types.String `tfsdk:"org_name"`
+	AccessToken           types.String `tfsdk:"access_token"`
+	APIToken              types.String `tfsdk:"api_token"`
+	ClientID              types.String `tfsdk:"client_id"`
+	Scopes                types.Set    `tfsdk:"scopes"`
+	PrivateKey            types.String `tfsdk:"private_key"`
+	PrivateKeyID          types.String `tfsdk:"private_key_id"`
+	APITokenFile          types.String `tfsdk:"api_token_file"`
+	PrivateKeyFile        types.String `tfsdk:"private_key_file"`
+	CredentialProcess     types.String `tfsdk:"credential_process"`
+	BaseURL               types.String `tfsdk:"base_url"`
+	HTTPProxy             types.String `tfsdk:"http_proxy"`
+	Backoff               types.Bool   `tfsdk:"backoff"`
+	MinWaitSeconds        types.Int64  `tfsdk:"min_wait_seconds"`
+	MaxWaitSeconds        types.Int64  `tfsdk:"max_wait_seconds"`
+	MaxRetries            types.Int64  `tfsdk:"max_retries"`
+	Parallelism           types.Int64  `tfsdk:"parallelism"`
+	LogLevel              types.Int64  `tfsdk:"log_level"`
+	MaxAPICapacity        types.Int64  `tfsdk:"max_api_capacity"`
+	RateLimitStateFile    types.String `tfsdk:"rate_limit_state_file"`
+	RequestTimeout        types.Int64  `tfsdk:"request_timeout"`
+	MaxMembershipRemovals types.String `tfsdk:"max_membership_removals"`
+	ResponseCache         types.Bool   `tfsdk:"response_cache"`
+	Orgs                  types.List   `tfsdk:"orgs"`
 }
 
 // Metadata returns the provider type name.
@@ -140,6 +153,34 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 					}...),
 				},
 			},
+			"api_token_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a file holding the API token. The file is read again when it changes, so the token can be rotated while the provider runs. Can also be sourced from the `OKTA_API_TOKEN_FILE` environment variable.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.Expressions{
+						path.MatchRoot("api_token"),
+						path.MatchRoot("access_token"),
+					}...),
+				},
+			},
+			"private_key_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a file holding the private key of the API service app. The file is read again when it changes, so the key can be rotated while the provider runs. Can also be sourced from the `OKTA_API_PRIVATE_KEY_FILE` environment variable.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.Expressions{
+						path.MatchRoot("private_key"),
+						path.MatchRoot("access_token"),
+						path.MatchRoot("api_token"),
+					}...),
+				},
+			},
+			"credential_process": schema.StringAttribute{
+				Optional: true,
+				Description: "Command printing the credentials of the provider as a JSON object with `api_token`, `access_token`, or `private_key`, " +
+					"`private_key_id`, `client_id` and `scopes`, and an optional RFC 3339 `expiration`. The credentials are kept until shortly " +
+					"before they expire, then the command runs again. Used when no other credentials are set. Can also be sourced from the " +
+					"`OKTA_CREDENTIAL_PROCESS` environment variable.",
+			},
 			"base_url": schema.StringAttribute{
 				Optional:    true,
 				Description: "The Okta url. (Use 'oktapreview.com' for Okta testing)",
@@ -189,6 +230,25 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 					int64validator.AtMost(100),
 				},
 			},
+			"response_cache": schema.BoolAttribute{
+				Optional: true,
+				Description: "Cache successful GET responses from the Okta API for the duration of one Terraform operation. The cache is " +
+					"shared by all of the provider's API clients and responses are invalidated by create, update and delete calls to " +
+					"the same path. Can also be sourced from the `OKTA_RESPONSE_CACHE` environment variable.",
+			},
+			"rate_limit_state_file": schema.StringAttribute{
+				Optional: true,
+				Description: "Path to a local file where the provider saves the rate limit state it has observed when " +
+					"`max_api_capacity` is in effect. The state is loaded at start up so back to back runs pick up where the " +
+					"last run stopped. Can also be sourced from the `OKTA_RATE_LIMIT_STATE_FILE` environment variable.",
+			},
+			"max_membership_removals": schema.StringAttribute{
+				Optional: true,
+				Description: "Largest number of memberships, like `100`, or percentage of the existing memberships, like `10%`, that a change " +
+					"to `okta_group_memberships`, `okta_user_group_memberships`, `okta_app_group_assignments` or `okta_group_rule` may " +
+					"remove. Resources can set their own `max_membership_removals`. Unlimited by default. Can also be sourced from " +
+					"the `OKTA_MAX_MEMBERSHIP_REMOVALS` environment variable.",
+			},
 			"request_timeout": schema.Int64Attribute{
 				Optional:    true,
 				Description: "Timeout for single request (in seconds) which is made to Okta, the default is `0` (means no limit is set). The maximum value can be `300`.",
@@ -198,6 +258,62 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"orgs": schema.ListNestedBlock{
+				Description: "Additional orgs managed by the provider. Resources and data sources select one with their `org` argument, " +
+					"and use the org of the provider when it isn't set. Each org has its own API clients and rate " +
+					"limits, the other settings of the provider apply to all of them. When the provider has no credentials of its " +
+					"own, the first org is its org.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the org, referenced by the `org` argument of resources and data sources.",
+						},
+						"org_name": schema.StringAttribute{
+							Optional:    true,
+							Description: "The organization to manage in Okta.",
+						},
+						"base_url": schema.StringAttribute{
+							Optional:    true,
+							Description: "The Okta url. Defaults to the `base_url` of the provider.",
+						},
+						"http_proxy": schema.StringAttribute{
+							Optional:    true,
+							Description: "Alternate HTTP proxy of scheme://hostname or scheme://hostname:port format",
+						},
+						"api_token": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "API Token granting privileges to Okta API.",
+						},
+						"access_token": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Bearer token granting privileges to Okta API.",
+						},
+						"client_id": schema.StringAttribute{
+							Optional:    true,
+							Description: "Client ID of the API service app granting privileges to Okta API.",
+						},
+						"scopes": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Scopes of the API service app granting privileges to Okta API.",
+						},
+						"private_key": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Private key of the API service app granting privileges to Okta API.",
+						},
+						"private_key_id": schema.StringAttribute{
+							Optional:    true,
+							Description: "Key ID of the private key of the API service app.",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -230,6 +346,7 @@ func (p *FrameworkProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 	resp.EphemeralResourceData = meta
+	resp.ListResourceData = meta
 	resp.DataSourceData = meta
 	resp.ResourceData = meta
 }
@@ -255,3 +372,26 @@ func (p *FrameworkProvider) Resources(_ context.Context) []func() resource.Resou
 	// Wrap all resources with SafeResource for panic recovery
 	return resources.WrapResources(res)
 }
+
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *FrameworkProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	var res []func() ephemeral.EphemeralResource
+	res = append(res, idaas.FWProviderEphemeralResources()...)
+
+	// Wrap all ephemeral resources with SafeEphemeralResource for panic recovery
+	return resources.WrapEphemeralResources(res)
+}
+
+// ListResources defines the list resources implemented in the provider.
+func (p *FrameworkProvider) ListResources(_ context.Context) []func() list.ListResource {
+	var res []func() list.ListResource
+	res = append(res, idaas.FWProviderListResources()...)
+
+	// Wrap all list resources with SafeListResource for panic recovery
+	return resources.WrapListResources(res)
+}
+
+// Functions defines the provider defined functions implemented in the provider.
+func (p *FrameworkProvider) Functions(_ context.Context) []func() function.Function {
+	return idaas.FWProviderFunctions()
+}
@@ -0,0 +1,246 @@
+// Package generate writes Terraform configuration, import blocks included,
+// for the objects that already exist in an Okta org. Only the resource types
+// with a list resource, the ones supported by terraform query, can be
+// generated; the configuration of the other objects of the org has to be
+// written by hand.
+package generate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Options controls what Run generates and where it is written.
+type Options struct {
+	// OutDir is the directory the .tf files are written to, one file per
+	// resource type.
+	OutDir string
+
+	// ResourceTypes limits the generation to the given resource types, all
+	// the resource types of the provider that can be listed are walked when
+	// it is empty.
+	ResourceTypes []string
+
+	// Logf reports the resource types that are skipped and the warnings
+	// returned by the provider.
+	Logf func(format string, args ...any)
+}
+
+// SkippedReportFile is the file of the output directory listing the resource
+// types that weren't generated and why, e.g. because no objects were found.
+const SkippedReportFile = "skipped_resource_types.txt"
+
+func (o Options) logf(format string, args ...any) {
+	if o.Logf != nil {
+		o.Logf(format, args...)
+	}
+}
+
+// Run configures the provider server the same way Terraform would, from the
+// OKTA_* environment variables, and walks the resource types that have a
+// list resource. The objects of every one of them are written as an import
+// block and a resource block holding all of the arguments read back from the
+// org. The resource types that weren't generated are listed in the
+// SkippedReportFile of the output directory. Asking for a resource type that
+// can't be listed is an error.
+func Run(ctx context.Context, server tfprotov5.ProviderServerWithListResource, opts Options) error {
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get provider schema: %w", err)
+	}
+	if err := diagnosticsError(schemas.Diagnostics); err != nil {
+		return fmt.Errorf("failed to get provider schema: %w", err)
+	}
+	identities, err := server.GetResourceIdentitySchemas(ctx, &tfprotov5.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get resource identity schemas: %w", err)
+	}
+	if err := diagnosticsError(identities.Diagnostics); err != nil {
+		return fmt.Errorf("failed to get resource identity schemas: %w", err)
+	}
+	if err := configure(ctx, server, schemas.Provider, opts); err != nil {
+		return err
+	}
+
+	typeNames := opts.ResourceTypes
+	if len(typeNames) == 0 {
+		for typeName := range schemas.ListResourceSchemas {
+			typeNames = append(typeNames, typeName)
+		}
+		slices.Sort(typeNames)
+	}
+	if err := os.MkdirAll(opts.OutDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", opts.OutDir, err)
+	}
+
+	var errs []error
+	var skipped []string
+	skip := func(typeName, reason string) {
+		opts.logf("skipping %s: %s", typeName, reason)
+		skipped = append(skipped, typeName+": "+reason)
+	}
+	for _, typeName := range typeNames {
+		resourceSchema, ok := schemas.ResourceSchemas[typeName]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s is not a resource type of the provider", typeName))
+			continue
+		}
+		listSchema, ok := schemas.ListResourceSchemas[typeName]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s can't be listed, only the resource types supported by terraform query can be generated", typeName))
+			continue
+		}
+		file, n, err := generateResourceType(ctx, server, typeName, resourceSchema, listSchema, identities.IdentitySchemas[typeName], opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", typeName, err))
+			skipped = append(skipped, fmt.Sprintf("%s: failed: %s", typeName, err))
+			continue
+		}
+		if n == 0 {
+			skip(typeName, "no objects found")
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(opts.OutDir, typeName+".tf"), file, 0o644); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", typeName, err))
+			continue
+		}
+		opts.logf("generated %d %s", n, typeName)
+	}
+	if len(skipped) > 0 {
+		report := filepath.Join(opts.OutDir, SkippedReportFile)
+		if err := os.WriteFile(report, []byte(strings.Join(skipped, "\n")+"\n"), 0o644); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write %s: %w", report, err))
+		} else {
+			opts.logf("skipped %d resource types, they are listed in %s", len(skipped), report)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// configure prepares and configures the provider with an empty configuration
+// so that every setting falls back to its environment variable.
+func configure(ctx context.Context, server tfprotov5.ProviderServer, providerSchema *tfprotov5.Schema, opts Options) error {
+	config, err := nullConfig(providerSchema)
+	if err != nil {
+		return fmt.Errorf("failed to build provider configuration: %w", err)
+	}
+	prepared, err := server.PrepareProviderConfig(ctx, &tfprotov5.PrepareProviderConfigRequest{Config: config})
+	if err != nil {
+		return fmt.Errorf("failed to prepare provider configuration: %w", err)
+	}
+	if err := diagnosticsError(prepared.Diagnostics); err != nil {
+		return fmt.Errorf("failed to prepare provider configuration: %w", err)
+	}
+	if prepared.PreparedConfig != nil {
+		config = prepared.PreparedConfig
+	}
+	configured, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: "1.14.0",
+		Config:           config,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure provider: %w", err)
+	}
+	if err := diagnosticsError(configured.Diagnostics); err != nil {
+		return fmt.Errorf("failed to configure provider: %w", err)
+	}
+	logWarnings(opts, "provider", configured.Diagnostics)
+	return nil
+}
+
+// generateResourceType lists every object of a resource type and returns the
+// content of its .tf file and the number of objects in it.
+func generateResourceType(ctx context.Context, server tfprotov5.ListResourceServer, typeName string, resourceSchema, listSchema *tfprotov5.Schema, identitySchema *tfprotov5.ResourceIdentitySchema, opts Options) ([]byte, int, error) {
+	if identitySchema == nil {
+		return nil, 0, errors.New("the resource type has no identity schema")
+	}
+	config, err := nullConfig(listSchema)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build list configuration: %w", err)
+	}
+	stream, err := server.ListResource(ctx, &tfprotov5.ListResourceRequest{
+		TypeName:        typeName,
+		Config:          config,
+		IncludeResource: true,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list: %w", err)
+	}
+
+	w := newWriter(typeName, resourceSchema.Block)
+	for result := range stream.Results {
+		if err := diagnosticsError(result.Diagnostics); err != nil {
+			return nil, 0, fmt.Errorf("failed to list: %w", err)
+		}
+		logWarnings(opts, typeName, result.Diagnostics)
+		if result.Resource == nil || result.Identity == nil || result.Identity.IdentityData == nil {
+			return nil, 0, fmt.Errorf("list result %q is missing its resource or identity", result.DisplayName)
+		}
+		identity, err := result.Identity.IdentityData.Unmarshal(identitySchema.ValueType())
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode identity of %q: %w", result.DisplayName, err)
+		}
+		resource, err := result.Resource.Unmarshal(resourceSchema.ValueType())
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode %q: %w", result.DisplayName, err)
+		}
+		if err := w.add(result.DisplayName, identity, resource); err != nil {
+			return nil, 0, fmt.Errorf("failed to write %q: %w", result.DisplayName, err)
+		}
+	}
+	return w.bytes(), w.count, nil
+}
+
+// nullConfig returns a configuration where every attribute and block of the
+// schema is null.
+func nullConfig(s *tfprotov5.Schema) (*tfprotov5.DynamicValue, error) {
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{}}
+	values := map[string]tftypes.Value{}
+	if s != nil {
+		objectType = s.ValueType().(tftypes.Object)
+		for name, attrType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	config, err := tfprotov5.NewDynamicValue(objectType, tftypes.NewValue(objectType, values))
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func diagnosticsError(diags []*tfprotov5.Diagnostic) error {
+	var msgs []string
+	for _, d := range diags {
+		if d != nil && d.Severity == tfprotov5.DiagnosticSeverityError {
+			msgs = append(msgs, diagnosticString(d))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+func logWarnings(opts Options, subject string, diags []*tfprotov5.Diagnostic) {
+	for _, d := range diags {
+		if d != nil && d.Severity == tfprotov5.DiagnosticSeverityWarning {
+			opts.logf("warning for %s: %s", subject, diagnosticString(d))
+		}
+	}
+}
+
+func diagnosticString(d *tfprotov5.Diagnostic) string {
+	if d.Detail == "" {
+		return d.Summary
+	}
+	return d.Summary + ": " + d.Detail
+}
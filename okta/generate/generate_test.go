@@ -0,0 +1,273 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var thingSchema = &tfprotov5.Schema{
+	Block: &tfprotov5.SchemaBlock{
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Optional: true, Computed: true},
+			{Name: "name", Type: tftypes.String, Required: true},
+			{Name: "description", Type: tftypes.String, Optional: true},
+			{Name: "priority", Type: tftypes.Number, Optional: true, Computed: true},
+			{Name: "secret", Type: tftypes.String, Optional: true, Sensitive: true},
+			{Name: "status", Type: tftypes.String, Computed: true},
+			{Name: "old_name", Type: tftypes.String, Optional: true, Deprecated: true},
+		},
+		BlockTypes: []*tfprotov5.SchemaNestedBlock{
+			{
+				TypeName: "rule",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{Name: "values", Type: tftypes.Set{ElementType: tftypes.String}, Optional: true},
+					},
+				},
+			},
+		},
+	},
+}
+
+var idIdentitySchema = &tfprotov5.ResourceIdentitySchema{
+	IdentityAttributes: []*tfprotov5.ResourceIdentitySchemaAttribute{
+		{Name: "id", Type: tftypes.String, RequiredForImport: true},
+	},
+}
+
+type fakeServer struct {
+	tfprotov5.ProviderServerWithListResource
+	configured bool
+	things     []map[string]tftypes.Value
+}
+
+func (s *fakeServer) GetProviderSchema(context.Context, *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	return &tfprotov5.GetProviderSchemaResponse{
+		Provider: &tfprotov5.Schema{Block: &tfprotov5.SchemaBlock{
+			Attributes: []*tfprotov5.SchemaAttribute{{Name: "org_name", Type: tftypes.String, Optional: true}},
+		}},
+		ResourceSchemas: map[string]*tfprotov5.Schema{
+			"okta_thing":       thingSchema,
+			"okta_other_thing": thingSchema,
+		},
+		ListResourceSchemas: map[string]*tfprotov5.Schema{
+			"okta_thing": {Block: &tfprotov5.SchemaBlock{}},
+		},
+	}, nil
+}
+
+func (s *fakeServer) GetResourceIdentitySchemas(context.Context, *tfprotov5.GetResourceIdentitySchemasRequest) (*tfprotov5.GetResourceIdentitySchemasResponse, error) {
+	return &tfprotov5.GetResourceIdentitySchemasResponse{
+		IdentitySchemas: map[string]*tfprotov5.ResourceIdentitySchema{"okta_thing": idIdentitySchema},
+	}, nil
+}
+
+func (s *fakeServer) PrepareProviderConfig(_ context.Context, req *tfprotov5.PrepareProviderConfigRequest) (*tfprotov5.PrepareProviderConfigResponse, error) {
+	return &tfprotov5.PrepareProviderConfigResponse{PreparedConfig: req.Config}, nil
+}
+
+func (s *fakeServer) ConfigureProvider(context.Context, *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	s.configured = true
+	return &tfprotov5.ConfigureProviderResponse{}, nil
+}
+
+func (s *fakeServer) ListResource(_ context.Context, req *tfprotov5.ListResourceRequest) (*tfprotov5.ListResourceServerStream, error) {
+	if !s.configured {
+		return nil, fmt.Errorf("provider is not configured")
+	}
+	if !req.IncludeResource {
+		return nil, fmt.Errorf("expected the resources to be included")
+	}
+	resourceType := thingSchema.ValueType()
+	identityType := idIdentitySchema.ValueType()
+	return &tfprotov5.ListResourceServerStream{
+		Results: func(push func(tfprotov5.ListResourceResult) bool) {
+			for _, thing := range s.things {
+				resource, _ := tfprotov5.NewDynamicValue(resourceType, tftypes.NewValue(resourceType, thing))
+				identity, _ := tfprotov5.NewDynamicValue(identityType, tftypes.NewValue(identityType, map[string]tftypes.Value{"id": thing["id"]}))
+				var name string
+				_ = thing["name"].As(&name)
+				if !push(tfprotov5.ListResourceResult{
+					DisplayName: name,
+					Resource:    &resource,
+					Identity:    &tfprotov5.ResourceIdentityData{IdentityData: &identity},
+				}) {
+					return
+				}
+			}
+		},
+	}, nil
+}
+
+func thing(id, name, description string, rules ...[]string) map[string]tftypes.Value {
+	ruleType := thingSchema.Block.BlockTypes[0].Block.ValueType()
+	valuesType := tftypes.Set{ElementType: tftypes.String}
+	var ruleValues []tftypes.Value
+	for _, rule := range rules {
+		var values []tftypes.Value
+		for _, v := range rule {
+			values = append(values, tftypes.NewValue(tftypes.String, v))
+		}
+		ruleValues = append(ruleValues, tftypes.NewValue(ruleType, map[string]tftypes.Value{
+			"values": tftypes.NewValue(valuesType, values),
+		}))
+	}
+	return map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, id),
+		"name":        tftypes.NewValue(tftypes.String, name),
+		"description": tftypes.NewValue(tftypes.String, description),
+		"priority":    tftypes.NewValue(tftypes.Number, 1),
+		"secret":      tftypes.NewValue(tftypes.String, "s3cr3t"),
+		"status":      tftypes.NewValue(tftypes.String, "ACTIVE"),
+		"old_name":    tftypes.NewValue(tftypes.String, name),
+		"rule":        tftypes.NewValue(tftypes.List{ElementType: ruleType}, ruleValues),
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := &fakeServer{things: []map[string]tftypes.Value{
+		thing("00g1", "Everyone Else", "", []string{"a", "b"}),
+		thing("00g2", "everyone else", "Second one"),
+		thing("00g3", "2 Factor", ""),
+	}}
+	dir := t.TempDir()
+	var logs []string
+	err := Run(context.Background(), server, Options{
+		OutDir: dir,
+		Logf: func(format string, args ...any) {
+			logs = append(logs, fmt.Sprintf(format, args...))
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"generated 3 okta_thing"}, logs)
+
+	_, err = os.Stat(filepath.Join(dir, SkippedReportFile))
+	assert.True(t, os.IsNotExist(err))
+
+	b, err := os.ReadFile(filepath.Join(dir, "okta_thing.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `import {
+  to = okta_thing.everyone_else
+  id = "00g1"
+}
+
+resource "okta_thing" "everyone_else" {
+  name     = "Everyone Else"
+  priority = 1
+  # secret is sensitive and has to be set by hand
+  rule {
+    values = ["a", "b"]
+  }
+}
+
+import {
+  to = okta_thing.everyone_else_2
+  id = "00g2"
+}
+
+resource "okta_thing" "everyone_else_2" {
+  description = "Second one"
+  name        = "everyone else"
+  priority    = 1
+  # secret is sensitive and has to be set by hand
+}
+
+import {
+  to = okta_thing._2_factor
+  id = "00g3"
+}
+
+resource "okta_thing" "_2_factor" {
+  name     = "2 Factor"
+  priority = 1
+  # secret is sensitive and has to be set by hand
+}
+`, string(b))
+	_, err = os.Stat(filepath.Join(dir, "okta_other_thing.tf"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRun_SkippedReport(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(context.Background(), &fakeServer{}, Options{OutDir: dir})
+	require.NoError(t, err)
+
+	report, err := os.ReadFile(filepath.Join(dir, SkippedReportFile))
+	require.NoError(t, err)
+	assert.Equal(t, "okta_thing: no objects found\n", string(report))
+	_, err = os.Stat(filepath.Join(dir, "okta_thing.tf"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRun_UnknownResourceType(t *testing.T) {
+	err := Run(context.Background(), &fakeServer{}, Options{
+		OutDir:        t.TempDir(),
+		ResourceTypes: []string{"okta_nope"},
+	})
+	assert.ErrorContains(t, err, "okta_nope is not a resource type of the provider")
+}
+
+func TestRun_UnlistableResourceType(t *testing.T) {
+	dir := t.TempDir()
+	err := Run(context.Background(), &fakeServer{things: []map[string]tftypes.Value{thing("00g1", "One", "")}}, Options{
+		OutDir:        dir,
+		ResourceTypes: []string{"okta_other_thing", "okta_thing"},
+	})
+	assert.ErrorContains(t, err, "okta_other_thing can't be listed")
+	_, err = os.Stat(filepath.Join(dir, "okta_thing.tf"))
+	assert.NoError(t, err)
+}
+
+// TestRun_ProviderServer generates the configuration of a plugin SDK and a
+// framework resource type with the provider server the provider binary
+// serves, against the fake Okta API.
+func TestRun_ProviderServer(t *testing.T) {
+	server, org := acctest.FakeOktaProviderServer(t)
+	group, err := org.Create("groups", map[string]any{"profile": map[string]any{"name": "Engineering", "description": "Builds things"}})
+	require.NoError(t, err)
+	hookKey, err := org.Create("hook-keys", map[string]any{"name": "Signing"})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	err = Run(context.Background(), server, Options{
+		OutDir:        dir,
+		ResourceTypes: []string{"okta_group", "okta_hook_key"},
+	})
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(filepath.Join(dir, "okta_group.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`import {
+  to = okta_group.engineering
+  id = %q
+}
+
+resource "okta_group" "engineering" {
+  custom_profile_attributes = "{}"
+  description               = "Builds things"
+  name                      = "Engineering"
+}
+`, group["id"]), string(b))
+
+	b, err = os.ReadFile(filepath.Join(dir, "okta_hook_key.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`import {
+  to = okta_hook_key.signing
+  id = %q
+}
+
+resource "okta_hook_key" "signing" {
+  name = "Signing"
+}
+`, hookKey["id"]), string(b))
+}
@@ -0,0 +1,318 @@
+package generate
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// writer renders the import and resource blocks of one resource type.
+type writer struct {
+	typeName string
+	block    *tfprotov5.SchemaBlock
+	file     *hclwrite.File
+	names    map[string]bool
+	count    int
+}
+
+func newWriter(typeName string, block *tfprotov5.SchemaBlock) *writer {
+	return &writer{
+		typeName: typeName,
+		block:    block,
+		file:     hclwrite.NewEmptyFile(),
+		names:    map[string]bool{},
+	}
+}
+
+func (w *writer) bytes() []byte {
+	return w.file.Bytes()
+}
+
+// add appends the import block and the resource block of an object. The
+// import block uses the id of the identity when there is one and the whole
+// identity otherwise.
+func (w *writer) add(displayName string, identity, resource tftypes.Value) error {
+	name := w.resourceName(displayName)
+	body := w.file.Body()
+	if w.count > 0 {
+		body.AppendNewline()
+	}
+
+	var identityValues map[string]tftypes.Value
+	if err := identity.As(&identityValues); err != nil {
+		return err
+	}
+	importBody := body.AppendNewBlock("import", nil).Body()
+	importBody.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: w.typeName},
+		hcl.TraverseAttr{Name: name},
+	})
+	if id, ok := identityValues["id"]; ok && len(identityValues) == 1 {
+		value, err := toCty(id)
+		if err != nil {
+			return err
+		}
+		importBody.SetAttributeValue("id", value)
+	} else {
+		value, err := toCty(identity)
+		if err != nil {
+			return err
+		}
+		importBody.SetAttributeValue("identity", value)
+	}
+
+	body.AppendNewline()
+	resourceBody := body.AppendNewBlock("resource", []string{w.typeName, name}).Body()
+	if err := writeBlock(resourceBody, w.block, resource); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// resourceName turns a display name into a resource name that is unique
+// within the file.
+func (w *writer) resourceName(displayName string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(displayName) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	base := strings.Trim(b.String(), "_")
+	if base == "" {
+		base = strings.TrimPrefix(w.typeName, "okta_")
+	}
+	if base[0] >= '0' && base[0] <= '9' || base[0] == '-' {
+		base = "_" + base
+	}
+	name := base
+	for i := 2; w.names[name]; i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	w.names[name] = true
+	return name
+}
+
+// writeBlock writes the arguments of a block. Computed only, deprecated and
+// write-only attributes are left out along with the arguments that aren't
+// set, and sensitive values are replaced by a comment so that they don't
+// end up in plain text on disk.
+func writeBlock(body *hclwrite.Body, block *tfprotov5.SchemaBlock, value tftypes.Value) error {
+	var values map[string]tftypes.Value
+	if err := value.As(&values); err != nil {
+		return err
+	}
+
+	attributes := slices.Clone(block.Attributes)
+	slices.SortFunc(attributes, func(a, b *tfprotov5.SchemaAttribute) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, attribute := range attributes {
+		if attribute.Name == "id" || !(attribute.Required || attribute.Optional) || attribute.Deprecated || attribute.WriteOnly {
+			continue
+		}
+		v := values[attribute.Name]
+		if !attribute.Required && isEmpty(v) {
+			continue
+		}
+		if attribute.Sensitive {
+			body.AppendUnstructuredTokens(hclwrite.Tokens{{
+				Type:  hclsyntax.TokenComment,
+				Bytes: []byte(fmt.Sprintf("# %s is sensitive and has to be set by hand\n", attribute.Name)),
+			}})
+			continue
+		}
+		ctyValue, err := toCty(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", attribute.Name, err)
+		}
+		body.SetAttributeValue(attribute.Name, ctyValue)
+	}
+
+	blockTypes := slices.Clone(block.BlockTypes)
+	slices.SortFunc(blockTypes, func(a, b *tfprotov5.SchemaNestedBlock) int {
+		return strings.Compare(a.TypeName, b.TypeName)
+	})
+	for _, blockType := range blockTypes {
+		v := values[blockType.TypeName]
+		if v.IsNull() || !v.IsKnown() {
+			continue
+		}
+		var elements []tftypes.Value
+		switch blockType.Nesting {
+		case tfprotov5.SchemaNestedBlockNestingModeSingle, tfprotov5.SchemaNestedBlockNestingModeGroup:
+			elements = []tftypes.Value{v}
+		case tfprotov5.SchemaNestedBlockNestingModeList, tfprotov5.SchemaNestedBlockNestingModeSet:
+			if err := v.As(&elements); err != nil {
+				return fmt.Errorf("%s: %w", blockType.TypeName, err)
+			}
+		default:
+			continue
+		}
+		for _, element := range elements {
+			if err := writeBlock(body.AppendNewBlock(blockType.TypeName, nil).Body(), blockType.Block, element); err != nil {
+				return fmt.Errorf("%s: %w", blockType.TypeName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isEmpty reports whether an optional argument can be left out. The SDKv2
+// resources store an empty string or collection for arguments that aren't
+// set.
+func isEmpty(v tftypes.Value) bool {
+	if v.IsNull() || !v.IsKnown() {
+		return true
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		return v.As(&s) == nil && s == ""
+	case v.Type().Is(tftypes.List{}), v.Type().Is(tftypes.Set{}), v.Type().Is(tftypes.Tuple{}):
+		var elements []tftypes.Value
+		return v.As(&elements) == nil && len(elements) == 0
+	case v.Type().Is(tftypes.Map{}):
+		var elements map[string]tftypes.Value
+		return v.As(&elements) == nil && len(elements) == 0
+	}
+	return false
+}
+
+// toCty converts a value decoded from the provider protocol to the cty value
+// hclwrite renders.
+func toCty(v tftypes.Value) (cty.Value, error) {
+	t, err := toCtyType(v.Type())
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if !v.IsKnown() {
+		return cty.UnknownVal(t), nil
+	}
+	if v.IsNull() {
+		return cty.NullVal(t), nil
+	}
+
+	switch v.Type().(type) {
+	case tftypes.List, tftypes.Set, tftypes.Tuple:
+		var elements []tftypes.Value
+		if err := v.As(&elements); err != nil {
+			return cty.NilVal, err
+		}
+		values := make([]cty.Value, len(elements))
+		for i, element := range elements {
+			if values[i], err = toCty(element); err != nil {
+				return cty.NilVal, err
+			}
+		}
+		switch {
+		case t.IsListType() && len(values) == 0:
+			return cty.ListValEmpty(t.ElementType()), nil
+		case t.IsListType():
+			return cty.ListVal(values), nil
+		case t.IsSetType() && len(values) == 0:
+			return cty.SetValEmpty(t.ElementType()), nil
+		case t.IsSetType():
+			return cty.SetVal(values), nil
+		default:
+			return cty.TupleVal(values), nil
+		}
+	case tftypes.Map, tftypes.Object:
+		var elements map[string]tftypes.Value
+		if err := v.As(&elements); err != nil {
+			return cty.NilVal, err
+		}
+		values := make(map[string]cty.Value, len(elements))
+		for k, element := range elements {
+			if values[k], err = toCty(element); err != nil {
+				return cty.NilVal, err
+			}
+		}
+		if t.IsMapType() {
+			if len(values) == 0 {
+				return cty.MapValEmpty(t.ElementType()), nil
+			}
+			return cty.MapVal(values), nil
+		}
+		return cty.ObjectVal(values), nil
+	}
+
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		if err := v.As(&s); err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(s), nil
+	case v.Type().Is(tftypes.Number):
+		n := new(big.Float)
+		if err := v.As(&n); err != nil {
+			return cty.NilVal, err
+		}
+		return cty.NumberVal(n), nil
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		if err := v.As(&b); err != nil {
+			return cty.NilVal, err
+		}
+		return cty.BoolVal(b), nil
+	}
+	return cty.NilVal, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func toCtyType(t tftypes.Type) (cty.Type, error) {
+	switch {
+	case t.Is(tftypes.String):
+		return cty.String, nil
+	case t.Is(tftypes.Number):
+		return cty.Number, nil
+	case t.Is(tftypes.Bool):
+		return cty.Bool, nil
+	case t.Is(tftypes.DynamicPseudoType):
+		return cty.DynamicPseudoType, nil
+	}
+
+	switch typ := t.(type) {
+	case tftypes.List:
+		element, err := toCtyType(typ.ElementType)
+		return cty.List(element), err
+	case tftypes.Set:
+		element, err := toCtyType(typ.ElementType)
+		return cty.Set(element), err
+	case tftypes.Map:
+		element, err := toCtyType(typ.ElementType)
+		return cty.Map(element), err
+	case tftypes.Tuple:
+		elements := make([]cty.Type, len(typ.ElementTypes))
+		for i, element := range typ.ElementTypes {
+			var err error
+			if elements[i], err = toCtyType(element); err != nil {
+				return cty.NilType, err
+			}
+		}
+		return cty.Tuple(elements), nil
+	case tftypes.Object:
+		attributes := make(map[string]cty.Type, len(typ.AttributeTypes))
+		for name, attribute := range typ.AttributeTypes {
+			var err error
+			if attributes[name], err = toCtyType(attribute); err != nil {
+				return cty.NilType, err
+			}
+		}
+		return cty.Object(attributes), nil
+	}
+	return cty.NilType, fmt.Errorf("unsupported type %s", t)
+}
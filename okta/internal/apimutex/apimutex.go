@@ -1,13 +1,22 @@
 package apimutex
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
 )
 
+// stateSaveInterval is the minimum amount of time between writes of the rate
+// limit state file. Okta API rate limits operate in one minute windows so a
+// state file that is at most a second stale is still accurate enough.
+const stateSaveInterval = time.Second
+
 // APIMutex synchronizes keeping account of current known rate limit values
 // from Okta management endpoints. See:
 // https://developer.okta.com/docs/reference/rl-global-mgmt/
@@ -15,11 +24,20 @@ import (
 // The Okta Terraform Provider can not account for other clients consumption of
 // API limits but it can account for its own usage and attempt to preemptively
 // react appropriately.
+//
+// A single APIMutex is intended to be shared by all of the SDK clients of a
+// provider instance so that each client is aware of the rate limit consumption
+// of the others.
 type APIMutex struct {
-	lock     sync.Mutex
-	capacity int
-	status   map[string]*APIStatus
-	buckets  map[string]string
+	lock         sync.Mutex
+	capacity     int
+	status       map[string]*APIStatus
+	buckets      map[string]string
+	stateFile    string
+	saveTimer    *time.Timer
+	burst        int
+	tokenBuckets map[string]*tokenBucket
+	clock        func() time.Time
 }
 
 // APIStatus is used to hold rate limit information from Okta's API, see:
@@ -39,13 +57,59 @@ func NewAPIMutex(capacity int) (*APIMutex, error) {
 		status: map[string]*APIStatus{
 			"/": rootStatus,
 		},
-		buckets: map[string]string{},
+		buckets:      map[string]string{},
+		burst:        1,
+		tokenBuckets: map[string]*tokenBucket{},
 	}
 	mutex.initRateLimitLookup()
 
 	return mutex, nil
 }
 
+// NewPersistentAPIMutex returns a new api mutex like NewAPIMutex that also
+// saves its known rate limit status to stateFile. Status previously saved to
+// stateFile is loaded so that back to back runs of the provider can pick up
+// where the last run stopped. Saved status from an expired one minute window
+// is ignored.
+func NewPersistentAPIMutex(capacity int, stateFile string) (*APIMutex, error) {
+	mutex, err := NewAPIMutex(capacity)
+	if err != nil {
+		return nil, err
+	}
+	mutex.stateFile = stateFile
+	if err := mutex.load(); err != nil {
+		return nil, err
+	}
+
+	persistentLock.Lock()
+	persistent = append(persistent, mutex)
+	persistentLock.Unlock()
+
+	return mutex, nil
+}
+
+var (
+	persistentLock sync.Mutex
+	persistent     []*APIMutex
+)
+
+// FlushAll synchronously writes the state file of every api mutex created by
+// NewPersistentAPIMutex. It is meant to be called when the provider stops so
+// that rate limit updates still waiting on a scheduled save aren't lost.
+func FlushAll() error {
+	persistentLock.Lock()
+	mutexes := append([]*APIMutex{}, persistent...)
+	persistentLock.Unlock()
+
+	var errs []error
+	for _, mutex := range mutexes {
+		if err := mutex.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // HasCapacity approximates if there is capacity below the api mutex's maximum
 // capacity threshold.
 func (m *APIMutex) HasCapacity(method, endPoint string) bool {
@@ -75,6 +139,7 @@ func (m *APIMutex) Update(method, endPoint string, limit, remaining int, reset i
 		status.reset = reset
 		status.remaining = remaining
 		status.limit = limit
+		m.scheduleSave()
 		return
 	}
 
@@ -85,7 +150,123 @@ func (m *APIMutex) Update(method, endPoint string, limit, remaining int, reset i
 
 	if remaining < status.remaining {
 		status.remaining = remaining
+		m.scheduleSave()
+	}
+}
+
+// persistedStatus is the on disk representation of an APIStatus.
+type persistedStatus struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"`
+}
+
+// persistedState is the on disk representation of the status of all rate limit
+// buckets.
+type persistedState struct {
+	Buckets map[string]persistedStatus `json:"buckets"`
+}
+
+// Save writes the known rate limit status of all buckets to the api mutex's
+// state file. It is a no-op if the api mutex has no state file.
+func (m *APIMutex) Save() error {
+	if m.stateFile == "" {
+		return nil
+	}
+
+	m.lock.Lock()
+	state := persistedState{Buckets: map[string]persistedStatus{}}
+	for bucket, status := range m.status {
+		if status.reset == 0 {
+			continue
+		}
+		state.Buckets[bucket] = persistedStatus{
+			Limit:     status.limit,
+			Remaining: status.remaining,
+			Reset:     status.reset,
+		}
 	}
+	m.lock.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it so a concurrent reader never
+	// observes a partially written state file
+	tmp, err := os.CreateTemp(filepath.Dir(m.stateFile), filepath.Base(m.stateFile)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), m.stateFile)
+}
+
+// Flush cancels any scheduled save of the state file and writes it
+// immediately. It is a no-op if the api mutex has no state file.
+func (m *APIMutex) Flush() error {
+	m.lock.Lock()
+	if m.saveTimer != nil {
+		m.saveTimer.Stop()
+		m.saveTimer = nil
+	}
+	m.lock.Unlock()
+
+	return m.Save()
+}
+
+// scheduleSave schedules a save of the state file if one isn't already
+// pending. The caller must hold the lock.
+func (m *APIMutex) scheduleSave() {
+	if m.stateFile == "" || m.saveTimer != nil {
+		return
+	}
+	m.saveTimer = time.AfterFunc(stateSaveInterval, func() {
+		m.lock.Lock()
+		m.saveTimer = nil
+		m.lock.Unlock()
+		_ = m.Save()
+	})
+}
+
+func (m *APIMutex) load() error {
+	data, err := os.ReadFile(m.stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read rate limit state file %q: %w", m.stateFile, err)
+	}
+
+	var state persistedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		// a corrupt state file is no worse than a cold start
+		return nil
+	}
+
+	now := time.Now().Unix()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for bucket, saved := range state.Buckets {
+		status, ok := m.status[bucket]
+		if !ok || saved.Reset < now {
+			continue
+		}
+		status.limit = saved.Limit
+		status.remaining = saved.Remaining
+		status.reset = saved.Reset
+	}
+
+	return nil
 }
 
 // Status Returns the APIStatus for the given method + endpoint combination.
@@ -3,6 +3,7 @@ package apimutex
 import (
 	"math/rand"
 	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -123,3 +124,78 @@ func minRemaining(remaining []int) int {
 
 	return result
 }
+
+func TestPersistentAPIMutex(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "rate-limits.json")
+
+	amu, err := NewPersistentAPIMutex(50, stateFile)
+	if err != nil {
+		t.Fatalf("api mutex constructor had error %+v", err)
+	}
+
+	endPoint := "/api/v1/users"
+	reset := time.Now().Unix() + int64(60)
+	amu.Update(http.MethodGet, endPoint, 90, 44, reset)
+	if err := amu.Save(); err != nil {
+		t.Fatalf("saving api mutex state had error %+v", err)
+	}
+
+	loaded, err := NewPersistentAPIMutex(50, stateFile)
+	if err != nil {
+		t.Fatalf("api mutex constructor had error %+v", err)
+	}
+	status := loaded.Status(http.MethodGet, endPoint)
+	if status.Limit() != 90 || status.Remaining() != 44 || status.Reset() != reset {
+		t.Fatalf("expected loaded status to have limit 90, remaining 44, reset %d, got %+v", reset, status)
+	}
+	if loaded.HasCapacity(http.MethodGet, endPoint) {
+		t.Fatalf("loaded api mutex shouldn't have capacity, 50%% threshold, 90 limit, 44 remaining")
+	}
+
+	// status from an expired one minute window is not loaded
+	expired := time.Now().Unix() - int64(60)
+	amu.Update(http.MethodGet, "/api/v1/groups", 500, 1, expired)
+	if err := amu.Save(); err != nil {
+		t.Fatalf("saving api mutex state had error %+v", err)
+	}
+	loaded, err = NewPersistentAPIMutex(50, stateFile)
+	if err != nil {
+		t.Fatalf("api mutex constructor had error %+v", err)
+	}
+	if status := loaded.Status(http.MethodGet, "/api/v1/groups"); status.Reset() != 0 {
+		t.Fatalf("expected expired status to be ignored, got %+v", status)
+	}
+}
+
+func TestPersistentAPIMutexFlush(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "rate-limits.json")
+
+	amu, err := NewPersistentAPIMutex(50, stateFile)
+	if err != nil {
+		t.Fatalf("api mutex constructor had error %+v", err)
+	}
+
+	// the update schedules a save a second from now, flushing must write it
+	// right away
+	endPoint := "/api/v1/users"
+	reset := time.Now().Unix() + int64(60)
+	amu.Update(http.MethodGet, endPoint, 90, 12, reset)
+	if err := amu.Flush(); err != nil {
+		t.Fatalf("flushing api mutex state had error %+v", err)
+	}
+
+	loaded, err := NewPersistentAPIMutex(50, stateFile)
+	if err != nil {
+		t.Fatalf("api mutex constructor had error %+v", err)
+	}
+	if status := loaded.Status(http.MethodGet, endPoint); status.Remaining() != 12 || status.Reset() != reset {
+		t.Fatalf("expected flushed status to have remaining 12, reset %d, got %+v", reset, status)
+	}
+}
+
+func TestPersistentAPIMutexMissingFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "does-not-exist.json")
+	if _, err := NewPersistentAPIMutex(50, stateFile); err != nil {
+		t.Fatalf("missing state file should be treated as a cold start, got error %+v", err)
+	}
+}
@@ -0,0 +1,292 @@
+package apimutex
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+)
+
+// Priority is the scheduling priority of an API request. Requests waiting on
+// the same rate limit bucket are granted capacity in proportion to the weight
+// of their priority so that no class of request is starved.
+type Priority int
+
+const (
+	// PriorityRefresh is for reads refreshing state during plan.
+	PriorityRefresh Priority = iota
+	// PriorityRead is for reads that are not a state refresh, e.g. data
+	// sources.
+	PriorityRead
+	// PriorityMutate is for create, update, and delete calls and the reads
+	// made while applying them.
+	PriorityMutate
+
+	numPriorities = 3
+)
+
+// priorityWeights are the relative share of a rate limit bucket each priority
+// receives when requests of several priorities are waiting.
+var priorityWeights = [numPriorities]float64{
+	PriorityRefresh: 1,
+	PriorityRead:    2,
+	PriorityMutate:  4,
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityRefresh:
+		return "refresh"
+	case PriorityRead:
+		return "read"
+	case PriorityMutate:
+		return "mutate"
+	default:
+		return "unknown"
+	}
+}
+
+type priorityContextKey struct{}
+
+// WithPriority returns a copy of ctx carrying the scheduling priority for API
+// requests made with it.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority)
+}
+
+// RequestPriority returns the scheduling priority of a request. Mutating
+// methods are always PriorityMutate, otherwise the priority carried by ctx is
+// used, defaulting to PriorityRead.
+func RequestPriority(ctx context.Context, method string) Priority {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return PriorityMutate
+	}
+	if priority, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityRead
+}
+
+// SetParallelism sets the number of requests to a rate limit bucket that may
+// be made at once, i.e. the burst size of each bucket's token bucket.
+func (m *APIMutex) SetParallelism(parallelism int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	m.burst = parallelism
+}
+
+// tokenBucket paces the requests made to one rate limit bucket. Tokens refill
+// at the rate permitted by the api mutex's capacity of the bucket's limit and
+// requests that can not be granted a token wait in per priority queues.
+type tokenBucket struct {
+	tokens  float64
+	last    time.Time
+	waiters [numPriorities][]*waiter
+	served  [numPriorities]float64
+	// virtualTime is the weighted share served at the last grant
+	virtualTime float64
+	timer       *time.Timer
+	timerDue    time.Time
+}
+
+type waiter struct {
+	method   string
+	endPoint string
+	ready    chan struct{}
+	granted  bool
+}
+
+// Acquire blocks until the request may be made without exceeding the api
+// mutex's capacity of its rate limit bucket, or until ctx is done. Requests
+// are paced at the bucket's permitted rate rather than all sleeping until the
+// rate limit reset.
+func (m *APIMutex) Acquire(ctx context.Context, method, endPoint string, priority Priority) error {
+	if priority < 0 || priority >= numPriorities {
+		priority = PriorityRead
+	}
+
+	m.lock.Lock()
+	tb := m.tokenBucket(m.Bucket(method, endPoint))
+	now := m.now()
+	m.refill(tb, method, endPoint, now)
+	if !tb.hasWaiters() && tb.tokens >= 1 && m.hasCapacityAt(method, endPoint, now) {
+		tb.tokens--
+		m.lock.Unlock()
+		return nil
+	}
+
+	w := &waiter{method: method, endPoint: endPoint, ready: make(chan struct{})}
+	if len(tb.waiters[priority]) == 0 {
+		// a priority that was idle joins at the current virtual time so it
+		// can't claim a backlog of unused share
+		tb.served[priority] = math.Max(tb.served[priority], tb.virtualTime)
+	}
+	tb.waiters[priority] = append(tb.waiters[priority], w)
+	m.dispatch(tb, now)
+	m.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		if w.granted {
+			// the token was granted as the context finished, give it back
+			tb.tokens++
+			m.dispatch(tb, m.now())
+			return ctx.Err()
+		}
+		tb.remove(priority, w)
+		return ctx.Err()
+	}
+}
+
+// Wait returns how long the next waiter of the request's bucket is expected to
+// wait. It is informational only.
+func (m *APIMutex) Wait(method, endPoint string) time.Duration {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tb := m.tokenBucket(m.Bucket(method, endPoint))
+	return m.nextDispatch(tb, method, endPoint, m.now())
+}
+
+func (m *APIMutex) tokenBucket(bucket string) *tokenBucket {
+	tb, ok := m.tokenBuckets[bucket]
+	if !ok {
+		tb = &tokenBucket{tokens: float64(m.burst), last: m.now()}
+		m.tokenBuckets[bucket] = tb
+	}
+	return tb
+}
+
+// rate returns the tokens per second the bucket of the request refills at. A
+// rate of zero means the bucket's limit isn't known yet and requests are not
+// paced.
+func (m *APIMutex) rate(method, endPoint string) float64 {
+	status := m.get(method, endPoint)
+	if status.limit <= 0 {
+		return 0
+	}
+	return float64(status.limit) * float64(m.capacity) / 100.0 / 60.0
+}
+
+func (m *APIMutex) refill(tb *tokenBucket, method, endPoint string, now time.Time) {
+	rate := m.rate(method, endPoint)
+	if rate == 0 {
+		tb.tokens = float64(m.burst)
+	} else {
+		tb.tokens = math.Min(float64(m.burst), tb.tokens+now.Sub(tb.last).Seconds()*rate)
+	}
+	tb.last = now
+}
+
+// hasCapacityAt is HasCapacity except that once the bucket's reset time has
+// passed the bucket is treated as having capacity as a new one minute window
+// has started.
+func (m *APIMutex) hasCapacityAt(method, endPoint string, now time.Time) bool {
+	if m.HasCapacity(method, endPoint) {
+		return true
+	}
+	return now.Unix() >= m.get(method, endPoint).reset
+}
+
+// dispatch grants tokens to waiters by weighted fair share of their priorities
+// and arranges to be called again if waiters remain. The caller must hold the
+// lock.
+func (m *APIMutex) dispatch(tb *tokenBucket, now time.Time) {
+	for tb.hasWaiters() {
+		priority := tb.nextPriority()
+		w := tb.waiters[priority][0]
+		m.refill(tb, w.method, w.endPoint, now)
+		if tb.tokens < 1 || !m.hasCapacityAt(w.method, w.endPoint, now) {
+			break
+		}
+		tb.tokens--
+		tb.waiters[priority] = tb.waiters[priority][1:]
+		tb.virtualTime = tb.served[priority]
+		tb.served[priority] += 1 / priorityWeights[priority]
+		w.granted = true
+		close(w.ready)
+	}
+
+	if !tb.hasWaiters() {
+		return
+	}
+
+	priority := tb.nextPriority()
+	w := tb.waiters[priority][0]
+	wait := m.nextDispatch(tb, w.method, w.endPoint, now)
+	due := now.Add(wait)
+	if tb.timer != nil {
+		if !due.Before(tb.timerDue) {
+			return
+		}
+		tb.timer.Stop()
+	}
+	tb.timerDue = due
+	tb.timer = time.AfterFunc(wait, func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		tb.timer = nil
+		m.dispatch(tb, m.now())
+	})
+}
+
+// nextDispatch returns how long until the bucket can grant the request a token.
+func (m *APIMutex) nextDispatch(tb *tokenBucket, method, endPoint string, now time.Time) time.Duration {
+	if !m.hasCapacityAt(method, endPoint, now) {
+		return time.Unix(m.get(method, endPoint).reset, 0).Sub(now)
+	}
+	rate := m.rate(method, endPoint)
+	if tb.tokens >= 1 || rate == 0 {
+		return 0
+	}
+	return time.Duration((1 - tb.tokens) / rate * float64(time.Second))
+}
+
+func (m *APIMutex) now() time.Time {
+	if m.clock != nil {
+		return m.clock()
+	}
+	return time.Now()
+}
+
+func (tb *tokenBucket) hasWaiters() bool {
+	for _, waiters := range tb.waiters {
+		if len(waiters) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// nextPriority returns the waiting priority that has received the least of its
+// weighted share, ties going to the higher priority.
+func (tb *tokenBucket) nextPriority() Priority {
+	next := Priority(-1)
+	for p := Priority(numPriorities - 1); p >= 0; p-- {
+		if len(tb.waiters[p]) == 0 {
+			continue
+		}
+		if next < 0 || tb.served[p] < tb.served[next] {
+			next = p
+		}
+	}
+	return next
+}
+
+func (tb *tokenBucket) remove(priority Priority, w *waiter) {
+	waiters := tb.waiters[priority]
+	for i, candidate := range waiters {
+		if candidate == w {
+			tb.waiters[priority] = append(waiters[:i:i], waiters[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package apimutex
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRequestPriority(t *testing.T) {
+	ctx := context.Background()
+	refreshCtx := WithPriority(ctx, PriorityRefresh)
+	tests := []struct {
+		ctx      context.Context
+		method   string
+		expected Priority
+	}{
+		{ctx, http.MethodGet, PriorityRead},
+		{ctx, http.MethodPost, PriorityMutate},
+		{refreshCtx, http.MethodGet, PriorityRefresh},
+		{refreshCtx, http.MethodHead, PriorityRefresh},
+		{refreshCtx, http.MethodDelete, PriorityMutate},
+		{WithPriority(ctx, PriorityMutate), http.MethodGet, PriorityMutate},
+	}
+	for _, test := range tests {
+		if priority := RequestPriority(test.ctx, test.method); priority != test.expected {
+			t.Errorf("expected %s request priority to be %s, got %s", test.method, test.expected, priority)
+		}
+	}
+}
+
+func TestAcquireUnknownLimitIsNotPaced(t *testing.T) {
+	amu, _ := NewAPIMutex(50)
+	for i := 0; i < 100; i++ {
+		if err := amu.Acquire(context.Background(), http.MethodGet, "/api/v1/users", PriorityRead); err != nil {
+			t.Fatalf("expected request %d to acquire capacity, got %+v", i, err)
+		}
+	}
+}
+
+func TestAcquirePacesRequests(t *testing.T) {
+	now := time.Now()
+	amu, _ := NewAPIMutex(50)
+	amu.clock = func() time.Time { return now }
+	amu.SetParallelism(2)
+
+	// 600 limit at 50% capacity refills 5 tokens a second
+	endPoint := "/api/v1/users"
+	amu.Update(http.MethodGet, endPoint, 600, 600, now.Unix()+60)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 2; i++ {
+		if err := amu.Acquire(ctx, http.MethodGet, endPoint, PriorityRead); err != nil {
+			t.Fatalf("expected burst request %d to acquire capacity, got %+v", i, err)
+		}
+	}
+	if err := amu.Acquire(ctx, http.MethodGet, endPoint, PriorityRead); err != context.Canceled {
+		t.Fatalf("expected request beyond burst to wait, got %+v", err)
+	}
+	if wait := amu.Wait(http.MethodGet, endPoint); wait != 200*time.Millisecond {
+		t.Fatalf("expected wait of 200ms for next token, got %s", wait)
+	}
+
+	now = now.Add(200 * time.Millisecond)
+	if err := amu.Acquire(ctx, http.MethodGet, endPoint, PriorityRead); err != nil {
+		t.Fatalf("expected request to acquire refilled token, got %+v", err)
+	}
+}
+
+func TestAcquireWaitsForResetWithoutCapacity(t *testing.T) {
+	now := time.Now()
+	amu, _ := NewAPIMutex(50)
+	amu.clock = func() time.Time { return now }
+
+	endPoint := "/api/v1/apps"
+	reset := now.Unix() + 30
+	amu.Update(http.MethodGet, endPoint, 100, 10, reset)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := amu.Acquire(ctx, http.MethodGet, endPoint, PriorityMutate); err != context.Canceled {
+		t.Fatalf("expected request without capacity to wait, got %+v", err)
+	}
+	if wait := amu.Wait(http.MethodGet, endPoint); wait != time.Unix(reset, 0).Sub(now) {
+		t.Fatalf("expected wait until reset, got %s", wait)
+	}
+
+	now = time.Unix(reset, 0)
+	if err := amu.Acquire(ctx, http.MethodGet, endPoint, PriorityMutate); err != nil {
+		t.Fatalf("expected request after reset to acquire capacity, got %+v", err)
+	}
+}
+
+func TestDispatchWeightedFairness(t *testing.T) {
+	now := time.Now()
+	amu, _ := NewAPIMutex(100)
+	amu.clock = func() time.Time { return now }
+	endPoint := "/api/v1/groups"
+	amu.Update(http.MethodGet, endPoint, 600, 600, now.Unix()+60)
+
+	amu.lock.Lock()
+	tb := amu.tokenBucket(amu.Bucket(http.MethodGet, endPoint))
+	tb.tokens = 0
+	var granted []Priority
+	waiters := map[*waiter]Priority{}
+	for _, priority := range []Priority{PriorityRefresh, PriorityRead, PriorityMutate} {
+		for i := 0; i < 8; i++ {
+			w := &waiter{method: http.MethodGet, endPoint: endPoint, ready: make(chan struct{})}
+			tb.waiters[priority] = append(tb.waiters[priority], w)
+			waiters[w] = priority
+		}
+	}
+	// grant seven tokens, one at a time, and record the order of priorities
+	for i := 0; i < 7; i++ {
+		tb.tokens = 1
+		amu.dispatch(tb, now)
+		for w, priority := range waiters {
+			if w.granted {
+				granted = append(granted, priority)
+				delete(waiters, w)
+			}
+		}
+	}
+	if tb.timer != nil {
+		tb.timer.Stop()
+	}
+	amu.lock.Unlock()
+
+	counts := map[Priority]int{}
+	for _, priority := range granted {
+		counts[priority]++
+	}
+	if counts[PriorityMutate] != 4 || counts[PriorityRead] != 2 || counts[PriorityRefresh] != 1 {
+		t.Fatalf("expected grants in 4:2:1 mutate:read:refresh proportion, got %v", counts)
+	}
+	if granted[0] != PriorityMutate {
+		t.Fatalf("expected first grant to go to the mutate priority, got %s", granted[0])
+	}
+}
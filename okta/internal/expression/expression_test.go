@@ -0,0 +1,272 @@
+package expression
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseValid(t *testing.T) {
+	tests := []string{
+		`isMemberOfAnyGroup("00g1234567890abcdefg","00g1234567890abcdefh")`,
+		`user.department == "Engineering" AND user.title != 'Manager'`,
+		`String.stringContains(user.email, "@example.com") || user.isAdmin`,
+		`user.profile.login ?: "unknown"`,
+		`appuser.role == null ? "none" : appuser.role`,
+		`Arrays.contains({"a", "b"}, user.costCenter)`,
+		`NOT isMemberOfGroupName("Contractors") and user.employeeNumber > 1000`,
+		`user.getInternalProperty("id")`,
+		`user.groups[0] eq 'admins'`,
+		`'it''s quoted'`,
+		`-1 + 2 * (3 % 4)`,
+		`user.isMemberOf({'group.profile.name': 'Everyone', 'operator': 'EXACT'})`,
+		`user.getGroups({'group.type': {'OKTA_GROUP'}, operator: 'STARTS_WITH'}).![name]`,
+		`user.getGroups({:}).?[#this.profile.name matches 'eng.*'].$[id]`,
+		`user.email matches '^[a-z]+@example\.com$' ? {'a': 1} : {}`,
+		`Arrays.contains(user.getGroups({'group.profile.name': 'West'}).![profile.name], 'West').^[true]`,
+	}
+	for _, test := range tests {
+		if _, err := Parse(test); err != nil {
+			t.Errorf("expected %q to parse, got %+v", test, err)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+	}{
+		{``, 0},
+		{`   `, 0},
+		{`user.department == `, 19},
+		{`isMemberOfAnyGroup("00g1234567890abcdefg"`, 41},
+		{`user.department == "Engineering`, 19},
+		{`user. == "x"`, 6},
+		{`user.department = "x"`, 16},
+		{`user.login AND`, 14},
+		{`a ? b`, 5},
+		{`user.department "x"`, 16},
+		{`user.isMemberOf({'group.profile.name': })`, 39},
+		{`user.isMemberOf({'group.profile.name': 'Everyone',})`, 50},
+		{`user.isMemberOf({'group.profile.name' 'Everyone'})`, 38},
+		{`user.isMemberOf({'operator': 'EXACT', 'Everyone'})`, 48},
+		{`user.isMemberOf({'Everyone', 'operator': 'EXACT'})`, 39},
+		{`user.isMemberOf({1: 'EXACT'})`, 17},
+		{`user.getGroups({}).![name`, 25},
+		{`user.email matches`, 18},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.expr)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("expected %q to have a syntax error, got %+v", test.expr, err)
+			continue
+		}
+		if syntaxErr.Pos != test.pos {
+			t.Errorf("expected %q syntax error at position %d, got %d: %s", test.expr, test.pos, syntaxErr.Pos, syntaxErr.Msg)
+		}
+	}
+}
+
+func TestParseTree(t *testing.T) {
+	node, err := Parse(`user.a == "x" AND isMemberOfGroup('00g1234567890abcdefg')`)
+	if err != nil {
+		t.Fatalf("expected expression to parse, got %+v", err)
+	}
+	and, ok := node.(*Binary)
+	if !ok || and.Op != "&&" {
+		t.Fatalf("expected top level && operation, got %#v", node)
+	}
+	eq, ok := and.X.(*Binary)
+	if !ok || eq.Op != "==" {
+		t.Fatalf("expected left hand == operation, got %#v", and.X)
+	}
+	if member, ok := eq.X.(*Member); !ok || member.Name != "a" {
+		t.Fatalf("expected user.a member access, got %#v", eq.X)
+	}
+	call, ok := and.Y.(*Call)
+	if !ok || len(call.Args) != 1 {
+		t.Fatalf("expected right hand call with one argument, got %#v", and.Y)
+	}
+	if literal, ok := call.Args[0].(*Literal); !ok || literal.Value != "00g1234567890abcdefg" {
+		t.Fatalf("expected group ID string argument, got %#v", call.Args[0])
+	}
+}
+
+func TestParseMapAndSelection(t *testing.T) {
+	node, err := Parse(`user.getGroups({'group.profile.name': 'Everyone', operator: 'EXACT'}).![name]`)
+	if err != nil {
+		t.Fatalf("expected expression to parse, got %+v", err)
+	}
+	projection, ok := node.(*Selection)
+	if !ok || projection.Op != "!" {
+		t.Fatalf("expected top level projection, got %#v", node)
+	}
+	if name, ok := projection.Expr.(*Ident); !ok || name.Name != "name" {
+		t.Fatalf("expected projection of name, got %#v", projection.Expr)
+	}
+	call, ok := projection.X.(*Call)
+	if !ok || len(call.Args) != 1 {
+		t.Fatalf("expected projected call with one argument, got %#v", projection.X)
+	}
+	m, ok := call.Args[0].(*Map)
+	if !ok || len(m.Keys) != 2 || len(m.Values) != 2 {
+		t.Fatalf("expected map argument with two entries, got %#v", call.Args[0])
+	}
+	if key, ok := m.Keys[0].(*Literal); !ok || key.Value != "group.profile.name" {
+		t.Fatalf("expected string key, got %#v", m.Keys[0])
+	}
+	if key, ok := m.Keys[1].(*Ident); !ok || key.Name != "operator" {
+		t.Fatalf("expected identifier key, got %#v", m.Keys[1])
+	}
+	if value, ok := m.Values[1].(*Literal); !ok || value.Value != "EXACT" {
+		t.Fatalf("expected string value, got %#v", m.Values[1])
+	}
+
+	node, err = Parse(`user.login matches ".*@example.com" AND {} != {:}`)
+	if err != nil {
+		t.Fatalf("expected expression to parse, got %+v", err)
+	}
+	and := node.(*Binary)
+	if matches, ok := and.X.(*Binary); !ok || matches.Op != "matches" {
+		t.Fatalf("expected left hand matches operation, got %#v", and.X)
+	}
+	ne := and.Y.(*Binary)
+	if _, ok := ne.X.(*List); !ok {
+		t.Fatalf("expected {} to be an empty list, got %#v", ne.X)
+	}
+	if _, ok := ne.Y.(*Map); !ok {
+		t.Fatalf("expected {:} to be an empty map, got %#v", ne.Y)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		expr  string
+		valid bool
+	}{
+		{`isMemberOfAnyGroup("00g1234567890abcdefg")`, true},
+		{`isMemberOfAnyGroup(user.groupId)`, true},
+		{`isMemberOfAnyGroup()`, false},
+		{`isMemberOfAnyGroup("")`, false},
+		{`isMemberOfAnyGroup(1)`, false},
+		{`isMemberOfGroup("00g1234567890abcdefg", "00g1234567890abcdefh")`, false},
+		{`user.a == "b" OR isMemberOfGroupName(" ")`, false},
+		{`someOtherFunction()`, false},
+		{`String.stringContains(user.email, "@example.com")`, true},
+		{`String.stringContain(user.email, "@example.com")`, false},
+		{`Strings.stringContains(user.email, "@example.com")`, false},
+		{`Arrays.contains(user.groups, "admins") AND Convert.toInt(user.employeeNumber) > 100`, true},
+		{`user.getInternalProperty("status") == "ACTIVE"`, true},
+		{`user.getStatus()`, false},
+		{`user.email.toLowerCase()`, true},
+	}
+	for _, test := range tests {
+		err := Validate(test.expr)
+		if test.valid && err != nil {
+			t.Errorf("expected %q to be valid, got %+v", test.expr, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("expected %q to be invalid", test.expr)
+		}
+	}
+}
+
+// TestValidateDocumentedExamples checks the group rule and claim expressions
+// of the Okta Expression Language documentation.
+func TestValidateDocumentedExamples(t *testing.T) {
+	groupRules := []string{
+		`String.stringContains(user.department,"Sales")`,
+		`user.title == "Sales Manager" AND user.city == "San Francisco"`,
+		`String.startsWith(user.firstName,"andy")`,
+		`isMemberOfAnyGroup("00gjitX9HqABSoqTB0g3","00gjitX9HqABSoqTB0g4")`,
+		`isMemberOfGroupNameStartsWith("Sales") OR isMemberOfGroupNameRegex("/.*admin.*")`,
+		`user.isMemberOf({'group.profile.name': 'Everyone', 'operator': 'EXACT'})`,
+		`user.isMemberOf({'group.profile.name': 'West Coast', 'operator': 'STARTS_WITH'})`,
+		`user.isMemberOf({'group.id': {'00gjitX9HqABSoqTB0g3', '00gjitX9HqABSoqTB0g4'}})`,
+		`user.isMemberOf({'group.type': 'APP_GROUP', 'group.source.id': '0oa1234567890abcdefg'})`,
+		`user.isMemberOf({'group.profile.name': 'Sales', 'operator': 'CONTAINS'}) AND !user.isMemberOf({'group.profile.name': 'Contractors', 'operator': 'EXACT'})`,
+		`user.login matches ".*@example\.com"`,
+	}
+	claims := []string{
+		`isMemberOfGroupName("Everyone") ? "Yes" : "No"`,
+		`Groups.startsWith("active_directory", "myGroup", 10)`,
+		`Arrays.flatten(Groups.startsWith("OKTA", "admin", 10), Groups.contains("OKTA", "sales", 10))`,
+		`getFilteredGroups({"00gml2xHE3RYRx7cM0g3"}, "group.name", 40)`,
+		`user.getGroups({'group.profile.name': 'West&East', 'operator': 'STARTS_WITH'}).![name]`,
+		`user.getGroups({'group.type': {'OKTA_GROUP'}, 'group.source.id': '0oa1234567890abcdefg'}).![id]`,
+		`Arrays.toCsvString(user.getGroups({'group.type': {'OKTA_GROUP', 'APP_GROUP'}}).![name])`,
+		`user.getGroups({'group.profile.name': 'Admins'}).?[profile.description != null].![name]`,
+		`user.getInternalProperty("id")`,
+		`user.getLinkedObject("manager").lastName`,
+		`String.toUpperCase(user.firstName) + " " + user.lastName`,
+		`Time.fromIso8601ToUnix(user.getInternalProperty("lastUpdated"))`,
+		`appuser.role != null ? appuser.role : "member"`,
+	}
+	for _, test := range append(groupRules, claims...) {
+		if err := Validate(test); err != nil {
+			t.Errorf("expected documented expression %q to be valid, got %+v", test, err)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := map[string]string{
+		`Engineering`:   `"Engineering"`,
+		`say "hi"`:      `"say ""hi"""`,
+		`it's`:          `"it's"`,
+		``:              `""`,
+		`back\slash`:    `"back\slash"`,
+		`"`:             `""""`,
+		`a "b" and "c"`: `"a ""b"" and ""c"""`,
+	}
+	for value, expected := range tests {
+		quoted := Quote(value)
+		if quoted != expected {
+			t.Errorf("expected %q to be quoted as %s, got %s", value, expected, quoted)
+			continue
+		}
+		node, err := Parse(quoted)
+		if err != nil {
+			t.Errorf("expected quoted %q to parse, got %+v", value, err)
+			continue
+		}
+		if literal, ok := node.(*Literal); !ok || literal.Value != value {
+			t.Errorf("expected quoted %q to round trip, got %#v", value, node)
+		}
+	}
+}
+
+func TestIsMemberOfAnyGroup(t *testing.T) {
+	expr, err := IsMemberOfAnyGroup([]string{"00g1234567890abcdefg", "00g1234567890abcdefh"})
+	if err != nil {
+		t.Fatalf("expected expression, got %+v", err)
+	}
+	if expected := `isMemberOfAnyGroup("00g1234567890abcdefg","00g1234567890abcdefh")`; expr != expected {
+		t.Fatalf("expected %s, got %s", expected, expr)
+	}
+	if _, err := IsMemberOfAnyGroup(nil); err == nil {
+		t.Fatal("expected error for no group IDs")
+	}
+	if _, err := IsMemberOfAnyGroup([]string{"00g1234567890abcdefg", " "}); err == nil {
+		t.Fatal("expected error for an empty group ID")
+	}
+}
+
+func TestUserAttributes(t *testing.T) {
+	node, err := Parse(`String.stringContains(user.email, "x") AND user.profile.costCenter == user.getInternalProperty("id") OR user.departmnet.toLowerCase() == appuser.role`)
+	if err != nil {
+		t.Fatalf("expected expression to parse, got %+v", err)
+	}
+	var names []string
+	for _, attribute := range UserAttributes(node) {
+		names = append(names, attribute.Name)
+	}
+	if strings.Join(names, ",") != "email,costCenter,departmnet" {
+		t.Fatalf("expected user attributes email, costCenter and departmnet, got %v", names)
+	}
+	if !IsBaseUserAttribute("department") || IsBaseUserAttribute("departmnet") {
+		t.Fatal("expected department and only department to be a base user attribute")
+	}
+}
@@ -0,0 +1,172 @@
+// Package expression parses the subset of the Okta Expression Language used
+// in group rules, authorization server claims and policy rule conditions, so
+// expressions can be checked before they are sent to the Okta API.
+//
+// Okta Expression Language is based on the Spring Expression Language. String
+// literals are single or double quoted and a quote is escaped by doubling it.
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind is the kind of a lexical token.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of expression"
+	case tokenIdent:
+		return "identifier"
+	case tokenString:
+		return "string"
+	case tokenNumber:
+		return "number"
+	default:
+		return "operator"
+	}
+}
+
+type token struct {
+	kind tokenKind
+	// text is the source text of the token except for strings where it is
+	// the unquoted value
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return t.kind.String()
+	case tokenString:
+		return Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// SyntaxError is returned for an expression that can not be parsed. Pos is
+// the byte offset in the expression the error was found at.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid expression at position %d: %s", e.Pos, e.Msg)
+}
+
+// operators are ordered longest first so the lexer matches greedily
+var operators = []string{
+	"==", "!=", "<=", ">=", "&&", "||", "?.",
+	"<", ">", "!", "+", "-", "*", "/", "%", "?", ":", ".", ",", "(", ")", "[", "]", "{", "}", "^",
+}
+
+// lex splits src into tokens. The final token is always tokenEOF.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	pos := 0
+	for pos < len(src) {
+		r, size := utf8.DecodeRuneInString(src[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+		case r == '\'' || r == '"':
+			value, end, err := lexString(src, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, pos: pos})
+			pos = end
+		case isDigit(r):
+			end := pos
+			for end < len(src) && isDigit(rune(src[end])) {
+				end++
+			}
+			if end+1 < len(src) && src[end] == '.' && isDigit(rune(src[end+1])) {
+				end++
+				for end < len(src) && isDigit(rune(src[end])) {
+					end++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[pos:end], pos: pos})
+			pos = end
+		case isIdentStart(r) || r == '#' && pos+1 < len(src) && isIdentStart(rune(src[pos+1])):
+			// #this and #root are the variables of collection selections
+			// and projections
+			end := pos + size
+			for end < len(src) {
+				r, size := utf8.DecodeRuneInString(src[end:])
+				if !isIdentStart(r) && !isDigit(r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[pos:end], pos: pos})
+			pos = end
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[pos:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: pos})
+			pos += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// lexString returns the value of the string literal starting at pos and the
+// offset just past its closing quote.
+func lexString(src string, pos int) (string, int, error) {
+	quote := src[pos]
+	var value strings.Builder
+	i := pos + 1
+	for i < len(src) {
+		if src[i] != quote {
+			value.WriteByte(src[i])
+			i++
+			continue
+		}
+		if i+1 < len(src) && src[i+1] == quote {
+			// a doubled quote is an escaped quote
+			value.WriteByte(quote)
+			i += 2
+			continue
+		}
+		return value.String(), i + 1, nil
+	}
+	return "", 0, &SyntaxError{Pos: pos, Msg: "unterminated string"}
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+// Quote returns s as an Okta Expression Language string literal.
+func Quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,510 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is a node of a parsed expression.
+type Node interface {
+	// Pos is the byte offset of the node in the expression.
+	Pos() int
+}
+
+// LiteralKind is the type of a literal value.
+type LiteralKind int
+
+const (
+	LiteralString LiteralKind = iota
+	LiteralNumber
+	LiteralBool
+	LiteralNull
+)
+
+type (
+	// Literal is a string, number, boolean or null literal. Value is the
+	// unquoted value of strings and the source text of everything else.
+	Literal struct {
+		Kind  LiteralKind
+		Value string
+		At    int
+	}
+
+	// Ident is a bare identifier such as user or String.
+	Ident struct {
+		Name string
+		At   int
+	}
+
+	// Member is a property access, X.Name, or X?.Name when Safe.
+	Member struct {
+		X    Node
+		Name string
+		Safe bool
+		At   int
+	}
+
+	// Call is a function or method call. Fun is an Ident for functions such
+	// as isMemberOfAnyGroup and a Member for methods such as
+	// String.stringContains.
+	Call struct {
+		Fun  Node
+		Args []Node
+		At   int
+	}
+
+	// Index is an indexing expression, X[Index].
+	Index struct {
+		X     Node
+		Index Node
+		At    int
+	}
+
+	// Unary is a unary operation. Op is "!" or "-", textual operators are
+	// normalized to their symbol.
+	Unary struct {
+		Op string
+		X  Node
+		At int
+	}
+
+	// Binary is a binary operation. Textual operators such as AND and eq are
+	// normalized to their symbol.
+	Binary struct {
+		Op string
+		X  Node
+		Y  Node
+		At int
+	}
+
+	// Ternary is Cond ? Then : Else, or the elvis operator Cond ?: Else when
+	// Then is nil.
+	Ternary struct {
+		Cond Node
+		Then Node
+		Else Node
+		At   int
+	}
+
+	// List is an inline list, {a, b}.
+	List struct {
+		Elems []Node
+		At    int
+	}
+
+	// Map is an inline map, {key: value}. Keys are string literals or bare
+	// identifiers, e.g. {'group.profile.name': 'Everyone', operator: 'EXACT'}.
+	Map struct {
+		Keys   []Node
+		Values []Node
+		At     int
+	}
+
+	// Selection is a collection selection or projection. Op is "!" for the
+	// projection X.![Expr], "?" for the selection X.?[Expr], and "^" and "$"
+	// for the selection of the first and last matching element.
+	Selection struct {
+		Op   string
+		X    Node
+		Expr Node
+		At   int
+	}
+)
+
+func (n *Literal) Pos() int   { return n.At }
+func (n *Ident) Pos() int     { return n.At }
+func (n *Member) Pos() int    { return n.At }
+func (n *Call) Pos() int      { return n.At }
+func (n *Index) Pos() int     { return n.At }
+func (n *Unary) Pos() int     { return n.At }
+func (n *Binary) Pos() int    { return n.At }
+func (n *Ternary) Pos() int   { return n.At }
+func (n *List) Pos() int      { return n.At }
+func (n *Map) Pos() int       { return n.At }
+func (n *Selection) Pos() int { return n.At }
+
+// textualOperators maps the case insensitive word forms of operators to their
+// symbols.
+var textualOperators = map[string]string{
+	"and": "&&",
+	"or":  "||",
+	"not": "!",
+	"eq":  "==",
+	"ne":  "!=",
+	"lt":  "<",
+	"le":  "<=",
+	"gt":  ">",
+	"ge":  ">=",
+	// matches is only textual, its operands are a string and a regular
+	// expression
+	"matches": "matches",
+}
+
+// Parse parses an Okta Expression Language expression.
+func Parse(src string) (Node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, &SyntaxError{Pos: 0, Msg: "expression is empty"}
+	}
+	p := &parser{tokens: tokens}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// operator returns the normalized operator of t, if it is one.
+func operator(t token) string {
+	switch t.kind {
+	case tokenOperator:
+		return t.text
+	case tokenIdent:
+		return textualOperators[strings.ToLower(t.text)]
+	}
+	return ""
+}
+
+// accept consumes the next token if it is one of ops and returns its
+// normalized operator.
+func (p *parser) accept(ops ...string) (string, int, bool) {
+	t := p.peek()
+	op := operator(t)
+	for _, candidate := range ops {
+		if op == candidate {
+			p.next()
+			return op, t.pos, true
+		}
+	}
+	return "", 0, false
+}
+
+func (p *parser) expect(op string) error {
+	if _, _, ok := p.accept(op); !ok {
+		t := p.peek()
+		return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected %q, found %s", op, t)}
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+}
+
+// parseExpr parses a ternary expression, the lowest precedence level.
+func (p *parser) parseExpr() (Node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	_, at, ok := p.accept("?")
+	if !ok {
+		return cond, nil
+	}
+	var then Node
+	if _, _, elvis := p.accept(":"); !elvis {
+		if then, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+	}
+	otherwise, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &Ternary{Cond: cond, Then: then, Else: otherwise, At: at}, nil
+}
+
+// binaryPrecedence lists the binary operators from lowest to highest
+// precedence.
+var binaryPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">=", "matches"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (Node, error) {
+	if level == len(binaryPrecedence) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, at, ok := p.accept(binaryPrecedence[level]...)
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{Op: op, X: x, Y: y, At: at}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if op, at, ok := p.accept("!", "-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{Op: op, X: x, At: at}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (Node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch operator(t) {
+		case ".", "?.":
+			p.next()
+			if op := selectionOperator(p.peek()); t.text == "." && op != "" && operator(p.tokens[p.pos+1]) == "[" {
+				p.pos += 2
+				expr, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				if err = p.expect("]"); err != nil {
+					return nil, err
+				}
+				x = &Selection{Op: op, X: x, Expr: expr, At: t.pos}
+				continue
+			}
+			name := p.next()
+			if name.kind != tokenIdent {
+				return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("expected property name, found %s", name)}
+			}
+			x = &Member{X: x, Name: name.text, Safe: t.text == "?.", At: name.pos}
+		case "(":
+			p.next()
+			args, err := p.parseList(")")
+			if err != nil {
+				return nil, err
+			}
+			x = &Call{Fun: x, Args: args, At: x.Pos()}
+		case "[":
+			p.next()
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &Index{X: x, Index: index, At: t.pos}
+		default:
+			return x, nil
+		}
+	}
+}
+
+// selectionOperator returns the operator of a collection selection or
+// projection if t is one. $ lexes as an identifier.
+func selectionOperator(t token) string {
+	switch op := operator(t); {
+	case op == "!" || op == "?" || op == "^":
+		return op
+	case t.kind == tokenIdent && t.text == "$":
+		return "$"
+	}
+	return ""
+}
+
+// parseList parses comma separated expressions up to and including the
+// closing operator.
+func (p *parser) parseList(closing string) ([]Node, error) {
+	var elems []Node
+	if _, _, ok := p.accept(closing); ok {
+		return elems, nil
+	}
+	for {
+		elem, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+		if _, _, ok := p.accept(","); ok {
+			continue
+		}
+		if err = p.expect(closing); err != nil {
+			return nil, err
+		}
+		return elems, nil
+	}
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &Literal{Kind: LiteralString, Value: t.text, At: t.pos}, nil
+	case tokenNumber:
+		return &Literal{Kind: LiteralNumber, Value: t.text, At: t.pos}, nil
+	case tokenIdent:
+		switch strings.ToLower(t.text) {
+		case "true", "false":
+			return &Literal{Kind: LiteralBool, Value: strings.ToLower(t.text), At: t.pos}, nil
+		case "null":
+			return &Literal{Kind: LiteralNull, Value: "null", At: t.pos}, nil
+		}
+		if _, ok := textualOperators[strings.ToLower(t.text)]; ok {
+			return nil, p.unexpected(t)
+		}
+		return &Ident{Name: t.text, At: t.pos}, nil
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "{":
+			return p.parseBraces(t.pos)
+		}
+	}
+	return nil, p.unexpected(t)
+}
+
+// parseBraces parses an inline list or map after its opening brace at pos. It
+// is a map if its first element is followed by a colon, {:} being the empty
+// map.
+func (p *parser) parseBraces(pos int) (Node, error) {
+	if _, _, ok := p.accept(":"); ok {
+		if err := p.expect("}"); err != nil {
+			return nil, err
+		}
+		return &Map{At: pos}, nil
+	}
+	if _, _, ok := p.accept("}"); ok {
+		return &List{At: pos}, nil
+	}
+	first, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if operator(p.peek()) != ":" {
+		elems := []Node{first}
+		if _, _, ok := p.accept(","); ok {
+			rest, err := p.parseList("}")
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, rest...)
+		} else if err = p.expect("}"); err != nil {
+			return nil, err
+		}
+		return &List{Elems: elems, At: pos}, nil
+	}
+
+	m := &Map{At: pos}
+	key := first
+	for {
+		switch k := key.(type) {
+		case *Ident:
+		case *Literal:
+			if k.Kind != LiteralString {
+				return nil, &SyntaxError{Pos: k.Pos(), Msg: "map keys must be strings or identifiers"}
+			}
+		default:
+			return nil, &SyntaxError{Pos: k.Pos(), Msg: "map keys must be strings or identifiers"}
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		m.Keys = append(m.Keys, key)
+		m.Values = append(m.Values, value)
+		if _, _, ok := p.accept(","); !ok {
+			if err = p.expect("}"); err != nil {
+				return nil, err
+			}
+			return m, nil
+		}
+		if key, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Inspect traverses the expression depth first, calling f for each node. The
+// children of a node are skipped if f returns false for it.
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *Member:
+		Inspect(n.X, f)
+	case *Call:
+		Inspect(n.Fun, f)
+		for _, arg := range n.Args {
+			Inspect(arg, f)
+		}
+	case *Index:
+		Inspect(n.X, f)
+		Inspect(n.Index, f)
+	case *Unary:
+		Inspect(n.X, f)
+	case *Binary:
+		Inspect(n.X, f)
+		Inspect(n.Y, f)
+	case *Ternary:
+		Inspect(n.Cond, f)
+		Inspect(n.Then, f)
+		Inspect(n.Else, f)
+	case *List:
+		for _, elem := range n.Elems {
+			Inspect(elem, f)
+		}
+	case *Map:
+		for i := range n.Keys {
+			Inspect(n.Keys[i], f)
+			Inspect(n.Values[i], f)
+		}
+	case *Selection:
+		Inspect(n.X, f)
+		Inspect(n.Expr, f)
+	}
+}
@@ -0,0 +1,230 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// groupFunctions are the group membership functions of Okta Expression
+// Language and whether they accept more than one argument.
+var groupFunctions = map[string]bool{
+	"isMemberOfGroup":               false,
+	"isMemberOfAnyGroup":            true,
+	"isMemberOfGroupName":           false,
+	"isMemberOfGroupNameStartsWith": false,
+	"isMemberOfGroupNameContains":   false,
+	"isMemberOfGroupNameRegex":      false,
+}
+
+// functions are the functions of Okta Expression Language that are called
+// without a receiver.
+var functions = map[string]bool{
+	"findDirectoryUser":   true,
+	"findWorkdayUser":     true,
+	"getAssistantAppUser": true,
+	"getAssistantUser":    true,
+	"getFilteredGroups":   true,
+	"getManagerAppUser":   true,
+	"getManagerUser":      true,
+	"hasDirectoryUser":    true,
+	"hasWorkdayUser":      true,
+	// deprecated forms of the String functions
+	"substring":       true,
+	"substringAfter":  true,
+	"substringBefore": true,
+	"toLowerCase":     true,
+	"toUpperCase":     true,
+}
+
+// namespaceFunctions are the functions of Okta Expression Language called on
+// a namespace, e.g. String.stringContains.
+var namespaceFunctions = map[string]map[string]bool{
+	"Arrays": {
+		"add": true, "clear": true, "contains": true, "flatten": true, "get": true,
+		"isEmpty": true, "remove": true, "size": true, "toCsvString": true,
+	},
+	"Convert": {
+		"toInt": true, "toNum": true,
+	},
+	"Groups": {
+		"contains": true, "endsWith": true, "startsWith": true,
+	},
+	"Iso3166Convert": {
+		"toAlpha2": true, "toAlpha3": true, "toName": true, "toNumeric": true,
+	},
+	"String": {
+		"append": true, "endsWith": true, "join": true, "len": true, "removeSpaces": true,
+		"replace": true, "replaceFirst": true, "startsWith": true, "stringContains": true,
+		"stringSwitch": true, "substring": true, "substringAfter": true, "substringBefore": true,
+		"toLowerCase": true, "toUpperCase": true,
+	},
+	"Time": {
+		"fromIso8601ToString": true, "fromIso8601ToUnix": true, "fromIso8601ToWindows": true,
+		"fromStringToIso8601": true, "fromUnixToIso8601": true, "fromWindowsToIso8601": true,
+		"now": true,
+	},
+	// methods of the user object
+	"user": {
+		"getGroups": true, "getInternalProperty": true, "getLinkedObject": true, "isMemberOf": true,
+	},
+}
+
+// baseUserAttributes are the properties of the Okta base user profile.
+var baseUserAttributes = map[string]bool{
+	"city": true, "costCenter": true, "countryCode": true, "department": true,
+	"displayName": true, "division": true, "email": true, "employeeNumber": true,
+	"firstName": true, "honorificPrefix": true, "honorificSuffix": true, "lastName": true,
+	"locale": true, "login": true, "manager": true, "managerId": true, "middleName": true,
+	"mobilePhone": true, "nickName": true, "organization": true, "postalAddress": true,
+	"preferredLanguage": true, "primaryPhone": true, "profileUrl": true, "secondEmail": true,
+	"state": true, "streetAddress": true, "timezone": true, "title": true, "userType": true,
+	"zipCode": true,
+}
+
+// IsBaseUserAttribute reports whether name is a property of the Okta base
+// user profile.
+func IsBaseUserAttribute(name string) bool {
+	return baseUserAttributes[name]
+}
+
+// Validate parses an Okta Expression Language expression and checks that the
+// functions it calls are known and that the group membership functions are
+// called with valid arguments. Methods called on anything but a namespace or
+// the user object aren't checked.
+func Validate(src string) error {
+	node, err := Parse(src)
+	if err != nil {
+		return err
+	}
+	Inspect(node, func(n Node) bool {
+		if err != nil {
+			return false
+		}
+		if call, ok := n.(*Call); ok {
+			err = validateCall(call)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func validateCall(call *Call) error {
+	switch fun := call.Fun.(type) {
+	case *Ident:
+		if _, ok := groupFunctions[fun.Name]; ok {
+			return validateGroupFunctionCall(fun.Name, call)
+		}
+		if !functions[fun.Name] {
+			return &SyntaxError{Pos: call.Pos(), Msg: fmt.Sprintf("unknown function %s", fun.Name)}
+		}
+	case *Member:
+		namespace, ok := fun.X.(*Ident)
+		if !ok {
+			return nil
+		}
+		methods, ok := namespaceFunctions[namespace.Name]
+		if !ok {
+			if !isNamespace(namespace.Name) {
+				return nil
+			}
+			return &SyntaxError{Pos: namespace.Pos(), Msg: fmt.Sprintf("unknown function %s.%s", namespace.Name, fun.Name)}
+		}
+		if !methods[fun.Name] {
+			return &SyntaxError{Pos: fun.Pos(), Msg: fmt.Sprintf("unknown function %s.%s", namespace.Name, fun.Name)}
+		}
+	}
+	return nil
+}
+
+// isNamespace reports whether name looks like a function namespace rather
+// than a variable, namespaces being capitalized.
+func isNamespace(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+func validateGroupFunctionCall(name string, call *Call) error {
+	variadic := groupFunctions[name]
+	switch {
+	case len(call.Args) == 0:
+		return &SyntaxError{Pos: call.Pos(), Msg: fmt.Sprintf("%s requires at least one argument", name)}
+	case !variadic && len(call.Args) > 1:
+		return &SyntaxError{Pos: call.Pos(), Msg: fmt.Sprintf("%s takes one argument, got %d", name, len(call.Args))}
+	}
+	for _, arg := range call.Args {
+		literal, ok := arg.(*Literal)
+		if !ok {
+			continue
+		}
+		if literal.Kind != LiteralString {
+			return &SyntaxError{Pos: literal.Pos(), Msg: fmt.Sprintf("%s arguments must be strings", name)}
+		}
+		if strings.TrimSpace(literal.Value) == "" {
+			return &SyntaxError{Pos: literal.Pos(), Msg: fmt.Sprintf("%s arguments must not be empty", name)}
+		}
+	}
+	return nil
+}
+
+// UserAttributes returns the user profile attributes the expression reads,
+// e.g. department for user.department, in the order they appear. Both
+// user.attribute and user.profile.attribute are recognized.
+func UserAttributes(node Node) []*Member {
+	var attributes []*Member
+	Inspect(node, func(n Node) bool {
+		switch n := n.(type) {
+		case *Call:
+			// a method called on the user object isn't an attribute
+			if member, ok := n.Fun.(*Member); ok {
+				attributes = append(attributes, UserAttributes(member.X)...)
+				for _, arg := range n.Args {
+					attributes = append(attributes, UserAttributes(arg)...)
+				}
+				return false
+			}
+		case *Member:
+			if attribute := userAttribute(n); attribute != nil {
+				attributes = append(attributes, attribute)
+				return false
+			}
+		}
+		return true
+	})
+	return attributes
+}
+
+// userAttribute returns the member naming the attribute if m reads a user
+// profile attribute.
+func userAttribute(m *Member) *Member {
+	if ident, ok := m.X.(*Ident); ok && ident.Name == "user" {
+		if m.Name == "profile" {
+			return nil
+		}
+		return m
+	}
+	if profile, ok := m.X.(*Member); ok && profile.Name == "profile" {
+		if ident, ok := profile.X.(*Ident); ok && ident.Name == "user" {
+			return m
+		}
+	}
+	return nil
+}
+
+// IsMemberOfAnyGroup returns an expression that is true when the user is a
+// member of any of the groups with the given IDs.
+func IsMemberOfAnyGroup(groupIDs []string) (string, error) {
+	if len(groupIDs) == 0 {
+		return "", fmt.Errorf("at least one group ID is required")
+	}
+	args := make([]string, len(groupIDs))
+	for i, id := range groupIDs {
+		if strings.TrimSpace(id) == "" {
+			return "", fmt.Errorf("group ID %d is empty", i)
+		}
+		args[i] = Quote(id)
+	}
+	expr := fmt.Sprintf("isMemberOfAnyGroup(%s)", strings.Join(args, ","))
+	return expr, Validate(expr)
+}
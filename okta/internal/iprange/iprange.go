@@ -0,0 +1,187 @@
+// Package iprange parses the IP addresses, CIDRs and ranges of Okta network
+// zones into a canonical form, so that equivalent notations compare equal, and
+// computes their overlaps and coverage. IPv4 and IPv6 are supported.
+package iprange
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// Range is the inclusive range of addresses From to To of the same family.
+type Range struct {
+	From netip.Addr
+	To   netip.Addr
+}
+
+// Parse parses an address ("10.0.0.1"), a CIDR ("10.0.0.0/8") or a range
+// ("10.0.0.1-10.0.0.9"). The host bits of a CIDR are ignored.
+func Parse(s string) (Range, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return Range{}, fmt.Errorf("%q is not a valid CIDR: %w", s, err)
+		}
+		prefix = prefix.Masked()
+		return Range{From: prefix.Addr(), To: lastAddr(prefix)}, nil
+	}
+	from, to, isRange := strings.Cut(s, "-")
+	if !isRange {
+		addr, err := parseAddr(s)
+		if err != nil {
+			return Range{}, err
+		}
+		return Range{From: addr, To: addr}, nil
+	}
+	r := Range{}
+	var err error
+	if r.From, err = parseAddr(strings.TrimSpace(from)); err != nil {
+		return Range{}, err
+	}
+	if r.To, err = parseAddr(strings.TrimSpace(to)); err != nil {
+		return Range{}, err
+	}
+	if r.From.Is4() != r.To.Is4() {
+		return Range{}, fmt.Errorf("%q is not a valid range: the addresses are of different IP versions", s)
+	}
+	if r.To.Less(r.From) {
+		return Range{}, fmt.Errorf("%q is not a valid range: %s comes after %s", s, r.From, r.To)
+	}
+	return r, nil
+}
+
+func parseAddr(s string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%q is not a valid IP address: %w", s, err)
+	}
+	if addr.Zone() != "" {
+		return netip.Addr{}, fmt.Errorf("%q is not a valid IP address: zones aren't supported", s)
+	}
+	// IPv4-mapped IPv6 addresses are the same addresses as their IPv4 form
+	return addr.Unmap(), nil
+}
+
+// Canonical returns the canonical form of an address, CIDR or range: a CIDR
+// if the range is one, the range otherwise. s is returned as is if it can't
+// be parsed.
+func Canonical(s string) string {
+	r, err := Parse(s)
+	if err != nil {
+		return s
+	}
+	return r.String()
+}
+
+// String returns the range as a CIDR if it is one, e.g. "10.0.0.0/8" or
+// "10.0.0.1/32", otherwise as "from-to".
+func (r Range) String() string {
+	if prefix, ok := r.Prefix(); ok {
+		return prefix.String()
+	}
+	return r.From.String() + "-" + r.To.String()
+}
+
+// Prefix returns the CIDR covering exactly the range, if there is one.
+func (r Range) Prefix() (netip.Prefix, bool) {
+	for bits := r.From.BitLen(); bits >= 0; bits-- {
+		prefix := netip.PrefixFrom(r.From, bits)
+		if prefix.Masked().Addr() != r.From {
+			break
+		}
+		if last := lastAddr(prefix); last == r.To {
+			return prefix, true
+		} else if r.To.Less(last) {
+			break
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+// Is4 reports whether the range is an IPv4 range.
+func (r Range) Is4() bool {
+	return r.From.Is4()
+}
+
+// Intersect returns the addresses of both r and o, if there are any.
+func (r Range) Intersect(o Range) (Range, bool) {
+	if r.Is4() != o.Is4() {
+		return Range{}, false
+	}
+	from, to := r.From, r.To
+	if from.Less(o.From) {
+		from = o.From
+	}
+	if o.To.Less(to) {
+		to = o.To
+	}
+	if to.Less(from) {
+		return Range{}, false
+	}
+	return Range{From: from, To: to}, true
+}
+
+// Size returns the number of addresses of the range.
+func (r Range) Size() *big.Int {
+	size := new(big.Int).Sub(toInt(r.To), toInt(r.From))
+	return size.Add(size, big.NewInt(1))
+}
+
+// Merge returns the ranges covering the same addresses as ranges, sorted and
+// with overlapping and adjacent ranges merged.
+func Merge(ranges []Range) []Range {
+	sorted := slices.Clone(ranges)
+	slices.SortFunc(sorted, func(a, b Range) int {
+		if a.Is4() != b.Is4() {
+			if a.Is4() {
+				return -1
+			}
+			return 1
+		}
+		return a.From.Compare(b.From)
+	})
+	var merged []Range
+	for _, r := range sorted {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if last.Is4() == r.Is4() && (!last.To.Next().IsValid() || !last.To.Next().Less(r.From)) {
+				if last.To.Less(r.To) {
+					last.To = r.To
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Coverage returns the number of distinct IPv4 and IPv6 addresses of ranges.
+func Coverage(ranges []Range) (ipv4, ipv6 *big.Int) {
+	ipv4, ipv6 = new(big.Int), new(big.Int)
+	for _, r := range Merge(ranges) {
+		if r.Is4() {
+			ipv4.Add(ipv4, r.Size())
+		} else {
+			ipv6.Add(ipv6, r.Size())
+		}
+	}
+	return ipv4, ipv6
+}
+
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - i%8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+func toInt(addr netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(addr.AsSlice())
+}
@@ -0,0 +1,126 @@
+package iprange
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"10.0.0.0/8", "10.0.0.0/8"},
+		{"10.1.2.3/8", "10.0.0.0/8"},
+		{"10.0.0.0-10.255.255.255", "10.0.0.0/8"},
+		{" 10.0.0.0 - 10.255.255.255 ", "10.0.0.0/8"},
+		{"10.0.0.1-10.255.255.255", "10.0.0.1-10.255.255.255"},
+		{"1.2.3.4", "1.2.3.4/32"},
+		{"1.2.3.4-1.2.3.4", "1.2.3.4/32"},
+		{"1.2.3.4/32", "1.2.3.4/32"},
+		{"0.0.0.0/0", "0.0.0.0/0"},
+		{"0.0.0.0-255.255.255.255", "0.0.0.0/0"},
+		{"::ffff:1.2.3.4", "1.2.3.4/32"},
+		{"2001:db8::/32", "2001:db8::/32"},
+		{"2001:DB8:0:0::1/32", "2001:db8::/32"},
+		{"2001:db8::-2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", "2001:db8::/32"},
+		{"2001:db8::1-2001:db8::2", "2001:db8::1-2001:db8::2"},
+		{"::/0", "::/0"},
+		{"not an ip", "not an ip"},
+	}
+	for _, test := range tests {
+		if got := Canonical(test.in); got != test.want {
+			t.Errorf("Canonical(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		in  string
+		err string
+	}{
+		{"", "is not a valid IP address"},
+		{"10.0.0.0/33", "is not a valid CIDR"},
+		{"10.0.0.256", "is not a valid IP address"},
+		{"10.0.0.9-10.0.0.1", "10.0.0.9 comes after 10.0.0.1"},
+		{"10.0.0.1-::1", "different IP versions"},
+		{"fe80::1%eth0", "zones aren't supported"},
+		{"10.0.0.1-", "is not a valid IP address"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.in)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Parse(%q) returned error %v, want one containing %q", test.in, err, test.err)
+		}
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"10.0.0.0/8", "10.1.0.0/16", "10.1.0.0/16"},
+		{"10.0.0.0-10.0.0.10", "10.0.0.5-10.0.0.20", "10.0.0.5-10.0.0.10"},
+		{"10.0.0.0/24", "10.0.1.0/24", ""},
+		{"10.0.0.0/24", "10.0.0.255", "10.0.0.255/32"},
+		{"0.0.0.0/0", "::/0", ""},
+		{"10.0.0.0-10.0.0.9", "10.0.0.10-10.0.0.20", ""},
+		{"10.0.0.0-10.0.0.10", "10.0.0.10-10.0.0.20", "10.0.0.10/32"},
+		{"10.0.0.0-10.0.0.255", "10.0.0.0/24", "10.0.0.0/24"},
+		{"::ffff:10.0.0.1", "10.0.0.1", "10.0.0.1/32"},
+		{"2001:db8::/32", "2001:db8:1::/48", "2001:db8:1::/48"},
+	}
+	for _, test := range tests {
+		a, _ := Parse(test.a)
+		b, _ := Parse(test.b)
+		got, ok := a.Intersect(b)
+		if test.want == "" {
+			if ok {
+				t.Errorf("%s and %s intersect in %s, want no intersection", test.a, test.b, got)
+			}
+			continue
+		}
+		if !ok || got.String() != test.want {
+			t.Errorf("%s and %s intersect in %s (%t), want %s", test.a, test.b, got, ok, test.want)
+		}
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	var ranges []Range
+	for _, s := range []string{
+		"10.0.0.0/24",
+		"10.0.0.128-10.0.1.9",
+		"10.0.1.10",
+		"192.168.0.1",
+		"255.255.255.255",
+		"255.255.255.254/31",
+		"2001:db8::/64",
+		"2001:db8::/120",
+	} {
+		r, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ranges = append(ranges, r)
+	}
+
+	var merged []string
+	for _, r := range Merge(ranges) {
+		merged = append(merged, r.String())
+	}
+	want := "10.0.0.0-10.0.1.10 192.168.0.1/32 255.255.255.254/31 2001:db8::/64"
+	if got := strings.Join(merged, " "); got != want {
+		t.Errorf("Merge() = %s, want %s", got, want)
+	}
+
+	ipv4, ipv6 := Coverage(ranges)
+	if ipv4.String() != "270" {
+		t.Errorf("IPv4 coverage is %s, want 270", ipv4)
+	}
+	if ipv6.String() != "18446744073709551616" {
+		t.Errorf("IPv6 coverage is %s, want 2^64", ipv6)
+	}
+}
@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// TokenFunc returns the current token of the credentials of the provider.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// AuthorizationTransport sets the token of the Authorization header of the
+// requests the SDK clients make to the current one, for credentials that are
+// re-read or refreshed while the provider runs. The SDK clients only know the
+// token they were created with.
+type AuthorizationTransport struct {
+	base   http.RoundTripper
+	scheme string
+	token  TokenFunc
+	logger hclog.Logger
+}
+
+// NewAuthorizationTransport returns a transport setting the tokens of scheme,
+// e.g. "SSWS" or "Bearer", with token.
+func NewAuthorizationTransport(base http.RoundTripper, scheme string, token TokenFunc, logger hclog.Logger) *AuthorizationTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &AuthorizationTransport{
+		base:   base,
+		scheme: scheme,
+		token:  token,
+		logger: logger,
+	}
+}
+
+func (t *AuthorizationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// requests with other kinds of authorization, like those getting OAuth
+	// access tokens, are left alone
+	if !strings.HasPrefix(req.Header.Get("Authorization"), t.scheme+" ") {
+		return t.base.RoundTrip(req)
+	}
+	token, err := t.token(req.Context())
+	if err != nil {
+		t.logger.Error("failed to get the credentials of the provider", "error", err)
+		return nil, fmt.Errorf("failed to get the credentials of the provider: %w", err)
+	}
+	// a RoundTripper must not modify the request it is given
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", t.scheme+" "+token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestAuthorizationTransport(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	token := "first"
+	transport := NewAuthorizationTransport(http.DefaultTransport, "SSWS", func(context.Context) (string, error) {
+		return token, nil
+	}, hclog.NewNullLogger())
+	client := &http.Client{Transport: transport}
+
+	for _, authorization := range []string{"SSWS stale", "SSWS stale", "Basic other", ""} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if req.Header.Get("Authorization") != authorization {
+			t.Error("expected the transport not to modify the request")
+		}
+		token = "rotated"
+	}
+	want := []string{"SSWS first", "SSWS rotated", "Basic other", ""}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d had Authorization %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	failing := NewAuthorizationTransport(http.DefaultTransport, "SSWS", func(context.Context) (string, error) {
+		return "", errors.New("command failed")
+	}, hclog.NewNullLogger())
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Authorization", "SSWS stale")
+	if _, err := failing.RoundTrip(req); err == nil {
+		t.Error("expected an error when the token can't be read")
+	}
+}
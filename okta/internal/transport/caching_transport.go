@@ -0,0 +1,190 @@
+package transport
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/okta/terraform-provider-okta/sdk/cache"
+)
+
+// DefaultResponseCacheTTL is how long a cached response is served before it is
+// fetched again.
+const DefaultResponseCacheTTL = 5 * time.Minute
+
+var reOktaIDSegment = regexp.MustCompile(`^[\w]{20}$`)
+
+// ResponseCache holds successful GET responses from the Okta API. It is meant
+// to be shared by the transports of all the SDK clients of a provider instance
+// so that a response fetched by one SDK generation is served to the others.
+// The cache lives as long as the provider instance, i.e. for one Terraform
+// operation.
+type ResponseCache struct {
+	lock  sync.Mutex
+	cache cache.Cache
+	// paths indexes the cache keys by the URL path of the cached request so
+	// entries can be invalidated by path prefix
+	paths map[string]string
+}
+
+// NewResponseCache returns a new response cache whose entries expire after ttl.
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	seconds := int32(ttl / time.Second)
+	return &ResponseCache{
+		cache: cache.NewGoCache(seconds, seconds),
+		paths: map[string]string{},
+	}
+}
+
+func (c *ResponseCache) get(key string) *http.Response {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.cache.Get(key)
+}
+
+func (c *ResponseCache) set(key, path string, resp *http.Response) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cache.Set(key, resp)
+	c.paths[key] = path
+}
+
+// Invalidate removes the cached responses made stale by a mutating request to
+// path. That is every response whose path shares path's prefix up to and
+// including its first Okta ID, e.g. a PUT to /api/v1/apps/{id}/groups/{groupId}
+// invalidates everything under /api/v1/apps/{id}, and the listings of the
+// collection holding that ID, /api/v1/apps with any query. Responses that
+// mention any of the Okta IDs in path, like /api/v1/users/{userId}/groups after
+// a change to /api/v1/groups/{groupId}/users/{userId}, are removed too.
+func (c *ResponseCache) Invalidate(path string) int {
+	prefix, collection, ids := invalidationScope(path)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	removed := 0
+	for key, cachedPath := range c.paths {
+		// cached paths never have a query, so a collection's query variants
+		// all share its path
+		if !pathHasPrefix(cachedPath, prefix) && strings.TrimSuffix(cachedPath, "/") != collection && !pathHasAnySegment(cachedPath, ids) {
+			continue
+		}
+		c.cache.Delete(key)
+		delete(c.paths, key)
+		removed++
+	}
+	return removed
+}
+
+// Clear removes all cached responses.
+func (c *ResponseCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cache.Clear()
+	c.paths = map[string]string{}
+}
+
+// invalidationScope returns the path prefix up to and including the first Okta
+// ID in path, or path itself if it has none, the collection path holding that
+// first ID, or path itself if it has none, and all of the Okta IDs in path.
+func invalidationScope(path string) (string, string, []string) {
+	path = strings.TrimSuffix(path, "/")
+	segments := strings.Split(path, "/")
+	prefixLen := len(segments)
+	collectionLen := len(segments)
+	var ids []string
+	for i, segment := range segments {
+		if !isIDSegment(segments, i) {
+			continue
+		}
+		if len(ids) == 0 {
+			prefixLen = i + 1
+			collectionLen = i
+		}
+		ids = append(ids, segment)
+	}
+	return strings.Join(segments[:prefixLen], "/"), strings.Join(segments[:collectionLen], "/"), ids
+}
+
+// isIDSegment tells if the i-th segment of a path is an Okta ID. The default
+// authorization server is addressed by the "default" ID instead of a generated
+// one.
+func isIDSegment(segments []string, i int) bool {
+	segment := segments[i]
+	if segment == "default" {
+		return i > 0 && segments[i-1] == "authorizationServers"
+	}
+	return segment != "authorizationServers" && reOktaIDSegment.MatchString(segment)
+}
+
+func pathHasPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+func pathHasAnySegment(path string, segments []string) bool {
+	if len(segments) == 0 {
+		return false
+	}
+	for _, segment := range strings.Split(path, "/") {
+		for _, s := range segments {
+			if segment == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CachingTransport serves GET requests to the Okta API from a shared response
+// cache and invalidates the cache on mutating requests.
+type CachingTransport struct {
+	base   http.RoundTripper
+	cache  *ResponseCache
+	logger hclog.Logger
+}
+
+// NewCachingTransport returns a caching transport that serves GET requests from
+// responseCache when possible and otherwise from base.
+func NewCachingTransport(base http.RoundTripper, responseCache *ResponseCache, logger hclog.Logger) *CachingTransport {
+	return &CachingTransport{
+		base:   base,
+		cache:  responseCache,
+		logger: logger,
+	}
+}
+
+// RoundTrip returns a cached response for GET requests that have one. Any
+// other request is made with the base round tripper, successful GET responses
+// are cached, and mutating requests invalidate related cached responses.
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := t.base.RoundTrip(req)
+		if req.Method != http.MethodHead && req.Method != http.MethodOptions {
+			// invalidate even if the request failed, the server may still have
+			// applied it
+			if removed := t.cache.Invalidate(req.URL.Path); removed > 0 {
+				t.logger.Debug("invalidated cached API responses", "method", req.Method, "path", req.URL.Path, "count", removed)
+			}
+		}
+		return resp, err
+	}
+
+	key := cache.CreateCacheKey(req)
+	if resp := t.cache.get(key); resp != nil {
+		t.logger.Debug("serving cached API response", "path", req.URL.Path)
+		resp.Request = req
+		return resp, nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		t.cache.set(key, req.URL.Path, resp)
+	}
+	return resp, nil
+}
@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestInvalidationScope(t *testing.T) {
+	tests := []struct {
+		path       string
+		prefix     string
+		collection string
+		ids        []string
+	}{
+		{"/api/v1/groups", "/api/v1/groups", "/api/v1/groups", nil},
+		{"/api/v1/apps/0oa1234567890abcdefg", "/api/v1/apps/0oa1234567890abcdefg", "/api/v1/apps", []string{"0oa1234567890abcdefg"}},
+		{"/api/v1/apps/0oa1234567890abcdefg/groups/00g1234567890abcdefg", "/api/v1/apps/0oa1234567890abcdefg", "/api/v1/apps", []string{"0oa1234567890abcdefg", "00g1234567890abcdefg"}},
+		{"/api/v1/authorizationServers/aus1234567890abcdefg/claims", "/api/v1/authorizationServers/aus1234567890abcdefg", "/api/v1/authorizationServers", []string{"aus1234567890abcdefg"}},
+		{"/api/v1/authorizationServers/default/claims/ocl1234567890abcdefg", "/api/v1/authorizationServers/default", "/api/v1/authorizationServers", []string{"default", "ocl1234567890abcdefg"}},
+		{"/api/v1/policies/default", "/api/v1/policies/default", "/api/v1/policies/default", nil},
+	}
+	for _, test := range tests {
+		prefix, collection, ids := invalidationScope(test.path)
+		if prefix != test.prefix {
+			t.Errorf("expected %q invalidation prefix to be %q, got %q", test.path, test.prefix, prefix)
+		}
+		if collection != test.collection {
+			t.Errorf("expected %q invalidation collection to be %q, got %q", test.path, test.collection, collection)
+		}
+		if strings.Join(ids, ",") != strings.Join(test.ids, ",") {
+			t.Errorf("expected %q invalidation ids to be %v, got %v", test.path, test.ids, ids)
+		}
+	}
+}
+
+func TestCachingTransport(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	responseCache := NewResponseCache(time.Minute)
+	client := &http.Client{Transport: NewCachingTransport(http.DefaultTransport, responseCache, hclog.NewNullLogger())}
+	// a second client shares the same cache, as the SDK clients of one provider do
+	other := &http.Client{Transport: NewCachingTransport(http.DefaultTransport, responseCache, hclog.NewNullLogger())}
+
+	appPath := "/api/v1/apps/0oa1234567890abcdefg"
+	groupsPath := appPath + "/groups"
+	userGroupsPath := "/api/v1/users/00u1234567890abcdefg/groups"
+	get := func(c *http.Client, path string) string {
+		resp, err := c.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s had error %+v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	for _, c := range []*http.Client{client, other, client} {
+		if body := get(c, groupsPath); body != `{"path":"`+groupsPath+`"}` {
+			t.Fatalf("unexpected response body %q", body)
+		}
+		get(c, userGroupsPath)
+	}
+	if requests["GET "+groupsPath] != 1 || requests["GET "+userGroupsPath] != 1 {
+		t.Fatalf("expected repeated GETs to be served from the cache, got requests %v", requests)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL+appPath+"/groups/00g1234567890abcdefg", nil)
+	if _, err := other.Do(req); err != nil {
+		t.Fatalf("PUT had error %+v", err)
+	}
+	get(client, groupsPath)
+	get(client, userGroupsPath)
+	if requests["GET "+groupsPath] != 2 {
+		t.Fatalf("expected PUT under the same path prefix to invalidate cached response, got requests %v", requests)
+	}
+	if requests["GET "+userGroupsPath] != 1 {
+		t.Fatalf("expected unrelated cached response to be kept, got requests %v", requests)
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, server.URL+"/api/v1/groups/00g1234567890abcdefg/users/00u1234567890abcdefg", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("DELETE had error %+v", err)
+	}
+	get(client, userGroupsPath)
+	if requests["GET "+userGroupsPath] != 2 {
+		t.Fatalf("expected DELETE mentioning the same user ID to invalidate cached response, got requests %v", requests)
+	}
+
+	// a change to a child of the default authorization server invalidates
+	// the authorization server itself and the listings of its collection, whatever
+	// their query
+	defaultPath := "/api/v1/authorizationServers/default"
+	for _, path := range []string{defaultPath, defaultPath + "/claims", "/api/v1/authorizationServers", "/api/v1/authorizationServers?q=default", "/api/v1/apps?q=example"} {
+		get(client, path)
+	}
+	req, _ = http.NewRequest(http.MethodPut, server.URL+defaultPath+"/claims/ocl1234567890abcdefg", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("PUT had error %+v", err)
+	}
+	for _, path := range []string{defaultPath, defaultPath + "/claims", "/api/v1/authorizationServers", "/api/v1/authorizationServers?q=default", "/api/v1/apps?q=example"} {
+		get(client, path)
+	}
+	if requests["GET "+defaultPath] != 2 || requests["GET "+defaultPath+"/claims"] != 2 {
+		t.Fatalf("expected PUT under the default authorization server to invalidate its cached responses, got requests %v", requests)
+	}
+	if requests["GET /api/v1/authorizationServers"] != 4 {
+		t.Fatalf("expected PUT under the default authorization server to invalidate the cached listings of all authorization servers, got requests %v", requests)
+	}
+
+	// an update of an app invalidates the app listings, whatever their query
+	req, _ = http.NewRequest(http.MethodPut, server.URL+appPath, nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("PUT had error %+v", err)
+	}
+	get(client, "/api/v1/apps?q=example")
+	if requests["GET /api/v1/apps"] != 2 {
+		t.Fatalf("expected PUT of an app to invalidate the cached app listings, got requests %v", requests)
+	}
+}
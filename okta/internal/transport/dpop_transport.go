@@ -0,0 +1,188 @@
+package transport
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-hclog"
+)
+
+// dpopKeyBits is the size of the RSA key DPoP proofs are signed with.
+const dpopKeyBits = 2048
+
+// DPoPKey is the key the provider proves the possession of its DPoP bound
+// access tokens with, see RFC 9449. One key is shared by the token requests
+// and the API requests of every SDK client of an org, the tokens are bound to
+// it. The key is generated when the first proof is made.
+type DPoPKey struct {
+	lock sync.Mutex
+	key  *rsa.PrivateKey
+	// nonce is the last nonce the org asked the proofs to carry
+	nonce string
+	// now is time.Now, replaced in tests
+	now func() time.Time
+}
+
+// NewDPoPKey returns a DPoP key.
+func NewDPoPKey() *DPoPKey {
+	return &DPoPKey{now: time.Now}
+}
+
+// SetNonce sets the nonce of the next proofs.
+func (k *DPoPKey) SetNonce(nonce string) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.nonce = nonce
+}
+
+// Proof returns the DPoP proof of a request to rawURL and the nonce it
+// carries. The proof of a request carrying an access token holds the hash of
+// the token.
+func (k *DPoPKey) Proof(method, rawURL, accessToken string) (proof, nonce string, err error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if k.key == nil {
+		key, err := rsa.GenerateKey(rand.Reader, dpopKeyBits)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate the DPoP key: %w", err)
+		}
+		k.key = key
+	}
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: k.key},
+		(&jose.SignerOptions{EmbedJWK: true}).WithType("dpop+jwt"),
+	)
+	if err != nil {
+		return "", "", err
+	}
+	// the proof is of the URL without its query and fragment
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	u.RawQuery, u.Fragment = "", ""
+	claims := dpopClaims{
+		ID:         uuid.New().String(),
+		HTTPMethod: method,
+		HTTPURI:    u.String(),
+		IssuedAt:   jwt.NewNumericDate(k.now()),
+		Nonce:      k.nonce,
+	}
+	if accessToken != "" {
+		hash := sha256.Sum256([]byte(accessToken))
+		claims.AccessToken = base64.RawURLEncoding.EncodeToString(hash[:])
+	}
+	proof, err = jwt.Signed(signer).Claims(claims).Serialize()
+	return proof, k.nonce, err
+}
+
+// dpopClaims are the claims of a DPoP proof.
+type dpopClaims struct {
+	ID          string           `json:"jti"`
+	HTTPMethod  string           `json:"htm"`
+	HTTPURI     string           `json:"htu"`
+	IssuedAt    *jwt.NumericDate `json:"iat"`
+	Nonce       string           `json:"nonce,omitempty"`
+	AccessToken string           `json:"ath,omitempty"`
+}
+
+// DPoPTransport adds a DPoP proof to the requests of the SDK clients carrying
+// a DPoP bound access token. A request the org rejects because its proof lacks
+// the nonce the org now requires is sent once more with the new nonce.
+type DPoPTransport struct {
+	base   http.RoundTripper
+	key    *DPoPKey
+	logger hclog.Logger
+}
+
+// NewDPoPTransport returns a transport adding proofs signed with key.
+func NewDPoPTransport(base http.RoundTripper, key *DPoPKey, logger hclog.Logger) *DPoPTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &DPoPTransport{
+		base:   base,
+		key:    key,
+		logger: logger,
+	}
+}
+
+func (t *DPoPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// requests without a DPoP bound token are left alone
+	accessToken, ok := strings.CutPrefix(req.Header.Get("Authorization"), "DPoP ")
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	resp, proofNonce, err := t.send(req, req.Body, accessToken)
+	if err != nil || !isUseDPoPNonce(resp) {
+		return resp, err
+	}
+	// the nonce is compared with the one of this request's proof, another
+	// request may have set the nonce of the key since
+	nonce := resp.Header.Get("DPoP-Nonce")
+	if nonce == "" || nonce == proofNonce {
+		return resp, nil
+	}
+	body, ok := replayBody(req)
+	if !ok {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	t.logger.Debug("the org requires a new DPoP nonce, retrying with it", "url", req.URL.Path)
+	t.key.SetNonce(nonce)
+	resp, _, err = t.send(req, body, accessToken)
+	return resp, err
+}
+
+// send sends a copy of req with a proof of accessToken and returns the nonce
+// of the proof, a RoundTripper must not modify the request it is given.
+func (t *DPoPTransport) send(req *http.Request, body io.ReadCloser, accessToken string) (*http.Response, string, error) {
+	proof, nonce, err := t.key.Proof(req.Method, req.URL.String(), accessToken)
+	if err != nil {
+		t.logger.Error("failed to create the DPoP proof", "error", err)
+		return nil, "", fmt.Errorf("failed to create the DPoP proof: %w", err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	clone.Header.Set("DPoP", proof)
+	clone.Header.Set("X-Okta-User-Agent-Extended", "isDPoP:true")
+	resp, err := t.base.RoundTrip(clone)
+	return resp, nonce, err
+}
+
+// isUseDPoPNonce tells whether resp rejected the DPoP proof of its request
+// because it lacks the current nonce of the org.
+func isUseDPoPNonce(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusUnauthorized &&
+		strings.Contains(resp.Header.Get("WWW-Authenticate"), `error="use_dpop_nonce"`)
+}
+
+// replayBody returns the body of req to send it once more, the first attempt
+// consumed it.
+func replayBody(req *http.Request) (io.ReadCloser, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req.Body, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestDPoPTransport(t *testing.T) {
+	fake := &fakeAuthorizationServer{requireDPoP: true, apiNonce: "api-nonce"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client, _ := newTestOAuthClient(server.URL)
+
+	// the body of a request rejected for its nonce is sent again
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/groups", strings.NewReader(`{"profile": {}}`))
+	req.Header.Set("Authorization", "Bearer placeholder")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the request to be retried with the nonce of the API, got status %d", resp.StatusCode)
+	}
+	last := fake.proofs[len(fake.proofs)-1]
+	if last.Nonce != "api-nonce" || last.HTTPMethod != http.MethodPost {
+		t.Errorf("unexpected proof of %s with nonce %q", last.HTTPMethod, last.Nonce)
+	}
+
+	// the nonce is kept for the next requests
+	proofs := len(fake.proofs)
+	if status := getWithPlaceholder(t, client, server.URL+"/api/v1/users/me"); status != http.StatusOK || len(fake.proofs) != proofs+1 {
+		t.Errorf("expected the nonce to be reused, got status %d after %d proofs", status, len(fake.proofs)-proofs)
+	}
+
+	// requests without a DPoP bound token are left alone
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/api/v1/users/me", nil)
+	req.Header.Set("Authorization", "SSWS token")
+	resp, err = NewDPoPTransport(http.DefaultTransport, NewDPoPKey(), hclog.NewNullLogger()).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(fake.proofs) != proofs+1 {
+		t.Error("expected no proof for a request without a DPoP bound token")
+	}
+}
+
+// nonceRotatingAPI is an API requiring the DPoP nonce "nonce" that holds its
+// responses to the proofs without it until all of the concurrent requests
+// arrived. It then answers them one at a time, each once the previous request
+// was retried, so that every request but the first one is answered after
+// another request set the nonce of the key.
+type nonceRotatingAPI struct {
+	arrived sync.WaitGroup
+	turn    chan struct{}
+}
+
+func (a *nonceRotatingAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	var claims dpopClaims
+	proof, err := jwt.ParseSigned(req.Header.Get("DPoP"), []jose.SignatureAlgorithm{jose.RS256})
+	if err != nil {
+		return nil, err
+	}
+	if err := proof.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		return nil, err
+	}
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{}`)), Request: req}
+	if claims.Nonce == "nonce" {
+		a.turn <- struct{}{}
+		return resp, nil
+	}
+	a.arrived.Done()
+	a.arrived.Wait()
+	select {
+	case <-a.turn:
+	case <-time.After(5 * time.Second):
+	}
+	resp.StatusCode = http.StatusUnauthorized
+	resp.Header.Set("DPoP-Nonce", "nonce")
+	resp.Header.Set("WWW-Authenticate", `DPoP error="use_dpop_nonce", error_description="Resource server requires nonce in DPoP proof"`)
+	return resp, nil
+}
+
+func TestDPoPTransport_concurrentNonce(t *testing.T) {
+	const requests = 3
+	api := &nonceRotatingAPI{turn: make(chan struct{}, requests+1)}
+	api.arrived.Add(requests)
+	api.turn <- struct{}{}
+	transport := NewDPoPTransport(api, NewDPoPKey(), hclog.NewNullLogger())
+
+	statuses := make([]int, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "https://example.okta.com/api/v1/users/me", nil)
+			req.Header.Set("Authorization", "DPoP token")
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			statuses[i] = resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	for i, status := range statuses {
+		if status != http.StatusOK {
+			t.Errorf("expected request %d to be retried with the nonce set by another request, got status %d", i, status)
+		}
+	}
+}
@@ -25,8 +25,9 @@ type GovernedTransport struct {
 }
 
 // NewGovernedTransport returns a governed transport that relies on pre- and post-
-// requests from the http round tripper. The pre request consults the api mutex
-// to determine if sleeping for the Okta API one minute bucket is called for.
+// requests from the http round tripper. The pre request waits on the api
+// mutex's scheduler for capacity in the request's Okta API rate limit bucket,
+// mutating requests being favored over reads and reads over plan refreshes.
 // The post request updates the information it is holding about the current api
 // rate limits.
 func NewGovernedTransport(base http.RoundTripper, apiMutex *apimutex.APIMutex, logger hclog.Logger) *GovernedTransport {
@@ -56,31 +57,24 @@ func (t *GovernedTransport) RoundTrip(req *http.Request) (*http.Response, error)
 }
 
 func (t *GovernedTransport) preRequestHook(ctx context.Context, method, path string) error {
-	if t.apiMutex.HasCapacity(method, path) {
-		return nil
-	}
-
-	status := t.apiMutex.Status(method, path)
-	now := time.Now().Unix()
-	timeToSleep := status.Reset() - now
+	priority := apimutex.RequestPriority(ctx, method)
 
-	line := fmt.Sprintf("Throttling API requests; sleeping for %d seconds until rate limit reset (path class %q, bucket %q: %d remaining of %d total); current request \"%s %s\"",
-		timeToSleep,
-		t.apiMutex.Class(method, path),
-		t.apiMutex.Bucket(method, path),
-		status.Remaining(),
-		status.Limit(),
-		method,
-		path,
-	)
-	t.logger.Info(line)
-
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.NewTimer(time.Second * time.Duration(timeToSleep)).C:
-		return nil
+	if wait := t.apiMutex.Wait(method, path); wait >= time.Second {
+		status := t.apiMutex.Status(method, path)
+		line := fmt.Sprintf("Throttling API requests; waiting about %d seconds for rate limit capacity (path class %q, bucket %q: %d remaining of %d total); current %s request \"%s %s\"",
+			int64(wait.Seconds()),
+			t.apiMutex.Class(method, path),
+			t.apiMutex.Bucket(method, path),
+			status.Remaining(),
+			status.Limit(),
+			priority,
+			method,
+			path,
+		)
+		t.logger.Info(line)
 	}
+
+	return t.apiMutex.Acquire(ctx, method, path, priority)
 }
 
 func (t *GovernedTransport) postRequestHook(method, path string, resp *http.Response) {
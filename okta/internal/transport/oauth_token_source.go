@@ -0,0 +1,198 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// oauthTokenRefreshWindow is how long before it expires an access token is
+	// replaced, so that requests in flight don't carry an expired token.
+	oauthTokenRefreshWindow = time.Minute
+	// oauthTokenAttempts bounds the token requests of one refresh: a service
+	// app requiring DPoP first rejects the request without a proof, then the
+	// proof without the nonce of the authorization server.
+	oauthTokenAttempts = 3
+)
+
+// OAuthToken is an access token of the provider's service app.
+type OAuthToken struct {
+	// Type is "Bearer", or "DPoP" for tokens bound to a DPoP key.
+	Type   string
+	Value  string
+	Expiry time.Time
+}
+
+// OAuthTokenSourceConfig configures an OAuthTokenSource.
+type OAuthTokenSourceConfig struct {
+	// HTTPClient sends the token requests. Its transport must not be an
+	// OAuthTransport of the token source.
+	HTTPClient *http.Client
+	// TokenURL is the token endpoint of the org authorization server.
+	TokenURL string
+	// ClientAssertion returns a new private_key_jwt client assertion of the
+	// service app.
+	ClientAssertion func() (string, error)
+	Scopes          []string
+	UserAgent       string
+	Logger          hclog.Logger
+	// DPoPKey binds the tokens of service apps requiring DPoP, the DPoP
+	// transports of the SDK clients must share it.
+	DPoPKey *DPoPKey
+}
+
+// OAuthTokenSource gets the access tokens of the provider's service app with
+// the client credentials grant. One token source is shared by every SDK
+// client of an org so that they use the same token, which is replaced shortly
+// before it expires. Service apps requiring DPoP get tokens bound to the DPoP
+// key of the token source.
+type OAuthTokenSource struct {
+	config OAuthTokenSourceConfig
+
+	lock  sync.Mutex
+	token *OAuthToken
+	// dpop is set once the service app asked for DPoP proofs
+	dpop bool
+	// now is time.Now, replaced in tests
+	now func() time.Time
+}
+
+// NewOAuthTokenSource returns a token source getting tokens as configured.
+func NewOAuthTokenSource(config OAuthTokenSourceConfig) *OAuthTokenSource {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	if config.Logger == nil {
+		config.Logger = hclog.NewNullLogger()
+	}
+	if config.DPoPKey == nil {
+		config.DPoPKey = NewDPoPKey()
+	}
+	return &OAuthTokenSource{config: config, now: time.Now}
+}
+
+// Token returns the current access token, getting a new one when there is
+// none or it is about to expire.
+func (s *OAuthTokenSource) Token(ctx context.Context) (*OAuthToken, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token != nil && s.now().Add(oauthTokenRefreshWindow).Before(s.token.Expiry) {
+		return s.token, nil
+	}
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return s.token, nil
+}
+
+// Invalidate drops token when it is still the current one, e.g. after the
+// API rejected it, so that the next call of Token gets a new one.
+func (s *OAuthTokenSource) Invalidate(token *OAuthToken) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token == token {
+		s.token = nil
+	}
+}
+
+type oauthTokenResponse struct {
+	TokenType        string `json:"token_type"`
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// fetch gets a new access token. DPoP proofs and their nonce are added along
+// the way when the authorization server asks for them.
+func (s *OAuthTokenSource) fetch(ctx context.Context) (*OAuthToken, error) {
+	for attempt := 1; ; attempt++ {
+		resp, nonce, err := s.requestToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if nonce != "" {
+			s.config.DPoPKey.SetNonce(nonce)
+		}
+		switch {
+		case resp.Error == "":
+			token := &OAuthToken{
+				Type:   resp.TokenType,
+				Value:  resp.AccessToken,
+				Expiry: s.now().Add(time.Duration(resp.ExpiresIn) * time.Second),
+			}
+			s.config.Logger.Debug("acquired an OAuth access token", "token_type", token.Type, "expires_in", resp.ExpiresIn, "scope", resp.Scope)
+			return token, nil
+		case attempt == oauthTokenAttempts:
+		case resp.Error == "invalid_dpop_proof" && !s.dpop:
+			s.config.Logger.Debug("the service app requires DPoP, requesting a DPoP bound access token")
+			s.dpop = true
+			continue
+		case resp.Error == "use_dpop_nonce" && nonce != "":
+			s.config.Logger.Debug("requesting the OAuth access token again with the DPoP nonce of the authorization server")
+			continue
+		}
+		return nil, fmt.Errorf("failed to get an OAuth access token: %s: %s", resp.Error, resp.ErrorDescription)
+	}
+}
+
+// requestToken makes one token request and returns its response along with
+// the DPoP nonce the authorization server sent, if any.
+func (s *OAuthTokenSource) requestToken(ctx context.Context) (*oauthTokenResponse, string, error) {
+	assertion, err := s.config.ClientAssertion()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create the client assertion: %w", err)
+	}
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", strings.Join(s.config.Scopes, " "))
+	form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	form.Set("client_assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if s.config.UserAgent != "" {
+		req.Header.Set("User-Agent", s.config.UserAgent)
+	}
+	if s.dpop {
+		proof, _, err := s.config.DPoPKey.Proof(http.MethodPost, s.config.TokenURL, "")
+		if err != nil {
+			return nil, "", err
+		}
+		req.Header.Set("DPoP", proof)
+	}
+
+	s.config.Logger.Debug("requesting an OAuth access token", "token_url", s.config.TokenURL, "scopes", strings.Join(s.config.Scopes, " "))
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to request an OAuth access token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	var tokenResp oauthTokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, "", fmt.Errorf("failed to get an OAuth access token: received status code %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK && tokenResp.Error == "" {
+		return nil, "", fmt.Errorf("failed to get an OAuth access token: received status code %d", resp.StatusCode)
+	}
+	return &tokenResp, resp.Header.Get("DPoP-Nonce"), nil
+}
@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// OAuthTransport authorizes the requests of the SDK clients with the access
+// tokens of an OAuthTokenSource, replacing the placeholder Bearer token the
+// SDK clients are configured with. A request rejected because its token is
+// no longer valid, e.g. revoked, is sent once more with a new token.
+type OAuthTransport struct {
+	base   http.RoundTripper
+	source *OAuthTokenSource
+	logger hclog.Logger
+}
+
+// NewOAuthTransport returns a transport authorizing requests with the tokens
+// of source.
+func NewOAuthTransport(base http.RoundTripper, source *OAuthTokenSource, logger hclog.Logger) *OAuthTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &OAuthTransport{
+		base:   base,
+		source: source,
+		logger: logger,
+	}
+}
+
+func (t *OAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// requests with other kinds of authorization are left alone
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+		return t.base.RoundTrip(req)
+	}
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		t.logger.Error("failed to get an OAuth access token", "error", err)
+		return nil, err
+	}
+	resp, err := t.send(req, req.Body, token)
+	if err != nil || !isInvalidToken(resp) {
+		return resp, err
+	}
+	body, ok := replayBody(req)
+	if !ok {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	t.logger.Debug("the OAuth access token was rejected, retrying with a new one", "url", req.URL.Path)
+	t.source.Invalidate(token)
+	if token, err = t.source.Token(req.Context()); err != nil {
+		t.logger.Error("failed to get an OAuth access token", "error", err)
+		return nil, err
+	}
+	return t.send(req, body, token)
+}
+
+// send sends a copy of req authorized with token, a RoundTripper must not
+// modify the request it is given. The proofs of DPoP bound tokens are added
+// by the DPoPTransport beneath.
+func (t *OAuthTransport) send(req *http.Request, body io.ReadCloser, token *OAuthToken) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	clone.Header.Set("Authorization", token.Type+" "+token.Value)
+	return t.base.RoundTrip(clone)
+}
+
+// isInvalidToken tells whether resp rejected the access token of its request
+// because it expired or was revoked.
+func isInvalidToken(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusUnauthorized &&
+		strings.Contains(resp.Header.Get("WWW-Authenticate"), `error="invalid_token"`)
+}
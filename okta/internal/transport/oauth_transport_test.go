@@ -0,0 +1,190 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/hashicorp/go-hclog"
+)
+
+// fakeAuthorizationServer issues access tokens and serves an API accepting
+// only the last one, optionally requiring DPoP like a service app would.
+type fakeAuthorizationServer struct {
+	requireDPoP bool
+	// apiNonce is the DPoP nonce the API requires, if any
+	apiNonce string
+
+	lock          sync.Mutex
+	tokenRequests int
+	issued        int
+	revoked       bool
+	proofs        []dpopClaims
+}
+
+func (s *fakeAuthorizationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if r.URL.Path == "/oauth2/v1/token" {
+		s.tokenRequests++
+		if s.requireDPoP {
+			proof, ok := s.proof(r)
+			switch {
+			case !ok:
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error": "invalid_dpop_proof", "error_description": "DPoP proof JWT header is missing."}`)
+				return
+			case proof.Nonce != "nonce":
+				w.Header().Set("DPoP-Nonce", "nonce")
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error": "use_dpop_nonce", "error_description": "Authorization server requires nonce in DPoP proof."}`)
+				return
+			}
+		}
+		s.issued++
+		tokenType := "Bearer"
+		if s.requireDPoP {
+			tokenType = "DPoP"
+		}
+		fmt.Fprintf(w, `{"token_type": %q, "access_token": "token-%d", "expires_in": 3600, "scope": "okta.users.read"}`, tokenType, s.issued)
+		return
+	}
+
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if s.requireDPoP {
+		proof, ok := s.proof(r)
+		if !ok || scheme != "DPoP" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if s.apiNonce != "" && proof.Nonce != s.apiNonce {
+			w.Header().Set("DPoP-Nonce", s.apiNonce)
+			w.Header().Set("WWW-Authenticate", `DPoP error="use_dpop_nonce", error_description="Resource server requires nonce in DPoP proof"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	if s.revoked || token != fmt.Sprintf("token-%d", s.issued) {
+		s.revoked = false
+		w.Header().Set("WWW-Authenticate", `Bearer realm="IdpMyAccountAPI", error="invalid_token", error_description="The access token is invalid."`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	fmt.Fprint(w, `{}`)
+}
+
+func (s *fakeAuthorizationServer) proof(r *http.Request) (dpopClaims, bool) {
+	var claims dpopClaims
+	header := r.Header.Get("DPoP")
+	if header == "" {
+		return claims, false
+	}
+	proof, err := jwt.ParseSigned(header, []jose.SignatureAlgorithm{jose.RS256})
+	if err != nil || proof.Headers[0].JSONWebKey == nil {
+		return claims, false
+	}
+	if err := proof.Claims(proof.Headers[0].JSONWebKey.Key, &claims); err != nil {
+		return claims, false
+	}
+	s.proofs = append(s.proofs, claims)
+	return claims, true
+}
+
+func newTestOAuthClient(serverURL string) (*http.Client, *OAuthTokenSource) {
+	key := NewDPoPKey()
+	source := NewOAuthTokenSource(OAuthTokenSourceConfig{
+		TokenURL:        serverURL + "/oauth2/v1/token",
+		ClientAssertion: func() (string, error) { return "assertion", nil },
+		Scopes:          []string{"okta.users.read"},
+		Logger:          hclog.NewNullLogger(),
+		DPoPKey:         key,
+	})
+	dpop := NewDPoPTransport(http.DefaultTransport, key, hclog.NewNullLogger())
+	return &http.Client{Transport: NewOAuthTransport(dpop, source, hclog.NewNullLogger())}, source
+}
+
+func getWithPlaceholder(t *testing.T, client *http.Client, url string) int {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Authorization", "Bearer placeholder")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestOAuthTransport(t *testing.T) {
+	fake := &fakeAuthorizationServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client, source := newTestOAuthClient(server.URL)
+
+	for i := 0; i < 3; i++ {
+		if status := getWithPlaceholder(t, client, server.URL+"/api/v1/users/me"); status != http.StatusOK {
+			t.Fatalf("unexpected status %d", status)
+		}
+	}
+	if fake.tokenRequests != 1 {
+		t.Errorf("expected the token to be reused, got %d token requests", fake.tokenRequests)
+	}
+
+	// the token is replaced shortly before it expires
+	source.now = func() time.Time { return time.Now().Add(time.Hour - 30*time.Second) }
+	if status := getWithPlaceholder(t, client, server.URL+"/api/v1/users/me"); status != http.StatusOK || fake.tokenRequests != 2 {
+		t.Errorf("expected the token to be refreshed, got status %d after %d token requests", status, fake.tokenRequests)
+	}
+	source.now = time.Now
+
+	// a rejected token is replaced once
+	fake.revoked = true
+	if status := getWithPlaceholder(t, client, server.URL+"/api/v1/users/me"); status != http.StatusOK || fake.tokenRequests != 3 {
+		t.Errorf("expected the request to be retried with a new token, got status %d after %d token requests", status, fake.tokenRequests)
+	}
+
+	// requests with other kinds of authorization are left alone
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/users/me", nil)
+	req.Header.Set("Authorization", "SSWS token")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || fake.tokenRequests != 3 {
+		t.Error("expected requests with other kinds of authorization to be sent as is")
+	}
+}
+
+func TestOAuthTransport_DPoP(t *testing.T) {
+	fake := &fakeAuthorizationServer{requireDPoP: true}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client, source := newTestOAuthClient(server.URL)
+
+	if status := getWithPlaceholder(t, client, server.URL+"/api/v1/users/me?expand=x"); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if fake.tokenRequests != 3 {
+		t.Errorf("expected a request without proof, one without nonce, then one with both, got %d token requests", fake.tokenRequests)
+	}
+	token, err := source.Token(context.Background())
+	if err != nil || token.Type != "DPoP" {
+		t.Fatalf("expected a DPoP bound token, got %v, %v", token, err)
+	}
+
+	last := fake.proofs[len(fake.proofs)-1]
+	if last.HTTPMethod != http.MethodGet || last.HTTPURI != server.URL+"/api/v1/users/me" {
+		t.Errorf("unexpected proof of %s %s", last.HTTPMethod, last.HTTPURI)
+	}
+	if last.AccessToken == "" || last.Nonce != "nonce" {
+		t.Error("expected the proof of the API request to have the hash of the token and the nonce")
+	}
+}
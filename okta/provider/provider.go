@@ -11,6 +11,7 @@ import (
 
 	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/services/idaas"
+	"github.com/okta/terraform-provider-okta/okta/utils"
 )
 
 // Provider establishes a client connection to an okta site
@@ -60,6 +61,26 @@ func Provider() *schema.Provider {
 				Description:   "API Token Id granting privileges to Okta API.",
 				ConflictsWith: []string{"api_token"},
 			},
+			"api_token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a file holding the API token. The file is read again when it changes, so the token can be rotated while the provider runs. Can also be sourced from the `OKTA_API_TOKEN_FILE` environment variable.",
+				ConflictsWith: []string{"api_token", "access_token"},
+			},
+			"private_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a file holding the private key of the API service app. The file is read again when it changes, so the key can be rotated while the provider runs. Can also be sourced from the `OKTA_API_PRIVATE_KEY_FILE` environment variable.",
+				ConflictsWith: []string{"private_key", "access_token", "api_token"},
+			},
+			"credential_process": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Command printing the credentials of the provider as a JSON object with `api_token`, `access_token`, or `private_key`, " +
+					"`private_key_id`, `client_id` and `scopes`, and an optional RFC 3339 `expiration`. The credentials are kept until shortly " +
+					"before they expire, then the command runs again. Used when no other credentials are set. Can also be sourced from the " +
+					"`OKTA_CREDENTIAL_PROCESS` environment variable.",
+			},
 			"base_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -110,12 +131,101 @@ func Provider() *schema.Provider {
 					"capacity while making calls to the Okta management API endpoints. Okta API operates in one minute buckets. " +
 					"See Okta Management API Rate Limits: https://developer.okta.com/docs/reference/rl-global-mgmt/",
 			},
+			"response_cache": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Description: "Cache successful GET responses from the Okta API for the duration of one Terraform operation. The cache is " +
+					"shared by all of the provider's API clients and responses are invalidated by create, update and delete calls to " +
+					"the same path. Can also be sourced from the `OKTA_RESPONSE_CACHE` environment variable.",
+			},
+			"rate_limit_state_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Path to a local file where the provider saves the rate limit state it has observed when " +
+					"`max_api_capacity` is in effect. The state is loaded at start up so back to back runs pick up where the " +
+					"last run stopped. Can also be sourced from the `OKTA_RATE_LIMIT_STATE_FILE` environment variable.",
+			},
+			"max_membership_removals": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: utils.ValidateRemovalLimit,
+				Description: "Largest number of memberships, like `100`, or percentage of the existing memberships, like `10%`, that a change " +
+					"to `okta_group_memberships`, `okta_user_group_memberships`, `okta_app_group_assignments` or `okta_group_rule` may " +
+					"remove. Resources can set their own `max_membership_removals`. Unlimited by default. Can also be sourced from " +
+					"the `OKTA_MAX_MEMBERSHIP_REMOVALS` environment variable.",
+			},
 			"request_timeout": {
 				Type:             schema.TypeInt,
 				Optional:         true,
 				ValidateDiagFunc: intBetween(0, 300),
 				Description:      "Timeout for single request (in seconds) which is made to Okta, the default is `0` (means no limit is set). The maximum value can be `300`.",
 			},
+			"orgs": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Description: "Additional orgs managed by the provider. Resources and data sources select one with their `org` argument, " +
+					"and use the org of the provider when it isn't set. Each org has its own API clients and rate " +
+					"limits, the other settings of the provider apply to all of them. When the provider has no credentials of its " +
+					"own, the first org is its org.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the org, referenced by the `org` argument of resources and data sources.",
+						},
+						"org_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The organization to manage in Okta.",
+						},
+						"base_url": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The Okta url. Defaults to the `base_url` of the provider.",
+						},
+						"http_proxy": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Alternate HTTP proxy of scheme://hostname or scheme://hostname:port format",
+						},
+						"api_token": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "API Token granting privileges to Okta API.",
+						},
+						"access_token": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Bearer token granting privileges to Okta API.",
+						},
+						"client_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Client ID of the API service app granting privileges to Okta API.",
+						},
+						"scopes": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Scopes of the API service app granting privileges to Okta API.",
+						},
+						"private_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Private key of the API service app granting privileges to Okta API.",
+						},
+						"private_key_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Key ID of the private key of the API service app.",
+						},
+					},
+				},
+			},
 		},
 		ResourcesMap:         idaas.ProviderResources(),
 		DataSourcesMap:       idaas.ProviderDataSources(),
@@ -128,18 +238,34 @@ func Provider() *schema.Provider {
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	log.Printf("[INFO] Initializing Okta client")
 	cfg := config.NewConfig(d)
-	if cfg.ApiToken == "" && cfg.AccessToken == "" && cfg.PrivateKey == "" {
+	if err := cfg.LoadCredentials(ctx); err != nil {
+		return nil, diag.Errorf("[ERROR] failed to load credentials: %v", err)
+	}
+	orgs := d.Get("orgs").([]interface{})
+	if err := cfg.LoadOrgs(orgs); err != nil {
+		return nil, diag.Errorf("[ERROR] invalid orgs: %v", err)
+	}
+	if !cfg.HasCredentials() {
+		// a provider declaring only orgs blocks defaults to the first of them
+		cfg = cfg.UseFirstOrg(orgs)
+	}
+	if !cfg.HasCredentials() {
 		return nil, diag.Errorf(
 			"[ERROR] no Okta credentials provided. Please set one of the following: " +
 				"'api_token' (or OKTA_API_TOKEN env var), " +
-				"'access_token' (or OKTA_ACCESS_TOKEN env var), or " +
-				"'private_key' + 'client_id' (or OKTA_API_PRIVATE_KEY + OKTA_API_CLIENT_ID env vars). " +
+				"'access_token' (or OKTA_ACCESS_TOKEN env var), " +
+				"'private_key' + 'client_id' (or OKTA_API_PRIVATE_KEY + OKTA_API_CLIENT_ID env vars), " +
+				"'api_token_file' or 'private_key_file' (or OKTA_API_TOKEN_FILE or OKTA_API_PRIVATE_KEY_FILE env vars), or " +
+				"'credential_process' (or OKTA_CREDENTIAL_PROCESS env var). " +
 				"See https://registry.terraform.io/providers/okta/okta/latest/docs for more information")
 	}
 	if err := cfg.LoadAPIClient(); err != nil {
 		return nil, diag.Errorf("[ERROR] failed to load sdk clients: %v", err)
 	}
-	cfg.SetTimeOperations(config.NewProductionTimeOperations())
+	if err := cfg.LoadOrgAPIClients(); err != nil {
+		return nil, diag.Errorf("[ERROR] failed to load sdk clients: %v", err)
+	}
+	cfg.SetOrgsTimeOperations(config.NewProductionTimeOperations())
 
 	// NOTE: production runtime needs to know about VCR test environment for
 	// this one case where the validate function calls GET /api/v1/users/me to
@@ -148,6 +274,9 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		if err := cfg.VerifyCredentials(ctx); err != nil {
 			return nil, diag.Errorf("[ERROR] failed validate configuration: %v", err)
 		}
+		if err := cfg.VerifyOrgCredentials(ctx); err != nil {
+			return nil, diag.Errorf("[ERROR] failed validate configuration: %v", err)
+		}
 	}
 
 	return cfg, nil
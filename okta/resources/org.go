@@ -0,0 +1,192 @@
+package resources
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+// OrgArgument is the argument of the resources and data sources selecting the
+// org they manage, one of the orgs blocks of the provider.
+const OrgArgument = "org"
+
+const orgArgumentDescription = "Name of the org of the provider's orgs blocks to manage. Defaults to the org of the provider."
+
+// orgImportSeparator separates the org from the ID of the object when
+// importing a resource of an org declared in an orgs block, e.g. "prod:00u1".
+const orgImportSeparator = ":"
+
+// withOrgArgument adds the org argument to r and gives its functions the
+// configuration of the selected org as meta. The org of a resource can't
+// change, objects don't move between orgs.
+func withOrgArgument(r *schema.Resource, isDataSource bool) *schema.Resource {
+	if r == nil || r.Schema == nil {
+		return r
+	}
+	if _, ok := r.Schema[OrgArgument]; ok {
+		return r
+	}
+
+	// the schema maps can be shared by several resources and data sources
+	s := make(map[string]*schema.Schema, len(r.Schema)+1)
+	for k, v := range r.Schema {
+		s[k] = v
+	}
+	s[OrgArgument] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    !isDataSource,
+		Description: orgArgumentDescription,
+	}
+	r.Schema = s
+
+	if original := r.CreateContext; original != nil {
+		r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			return original(ctx, d, org)
+		}
+	}
+	if original := r.ReadContext; original != nil {
+		r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			return original(ctx, d, org)
+		}
+	}
+	if original := r.UpdateContext; original != nil {
+		r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			return original(ctx, d, org)
+		}
+	}
+	if original := r.DeleteContext; original != nil {
+		r.DeleteContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			return original(ctx, d, org)
+		}
+	}
+	if original := r.Create; original != nil {
+		r.Create = func(d *schema.ResourceData, meta interface{}) error {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return err
+			}
+			return original(d, org)
+		}
+	}
+	if original := r.Read; original != nil {
+		r.Read = func(d *schema.ResourceData, meta interface{}) error {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return err
+			}
+			return original(d, org)
+		}
+	}
+	if original := r.Update; original != nil {
+		r.Update = func(d *schema.ResourceData, meta interface{}) error {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return err
+			}
+			return original(d, org)
+		}
+	}
+	if original := r.Delete; original != nil {
+		r.Delete = func(d *schema.ResourceData, meta interface{}) error {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return err
+			}
+			return original(d, org)
+		}
+	}
+	if original := r.CustomizeDiff; original != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			org, err := orgMeta(d, meta)
+			if err != nil {
+				return err
+			}
+			return original(ctx, d, org)
+		}
+	}
+	if r.Importer != nil {
+		importer := *r.Importer
+		if original := importer.State; original != nil {
+			importer.State = func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				org, err := importOrg(d, meta)
+				if err != nil {
+					return nil, err
+				}
+				return original(d, org)
+			}
+		}
+		if original := importer.StateContext; original != nil {
+			importer.StateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				org, err := importOrg(d, meta)
+				if err != nil {
+					return nil, err
+				}
+				return original(ctx, d, org)
+			}
+		}
+		r.Importer = &importer
+	}
+	return r
+}
+
+// orgMeta returns the configuration of the org selected by the org argument.
+func orgMeta(d interface{ Get(string) interface{} }, meta interface{}) (interface{}, error) {
+	cfg, ok := meta.(*config.Config)
+	if !ok {
+		return meta, nil
+	}
+	name, _ := d.Get(OrgArgument).(string)
+	return cfg.Org(name)
+}
+
+// importOrg selects the org of an imported resource, given as a prefix of the
+// import ID, e.g. "prod:00u1". IDs without the prefix of a declared org are
+// imported in the org of the provider.
+func importOrg(d *schema.ResourceData, meta interface{}) (interface{}, error) {
+	cfg, ok := meta.(*config.Config)
+	if !ok {
+		return meta, nil
+	}
+	name, id, found := splitImportID(cfg, d.Id())
+	if !found {
+		return cfg, nil
+	}
+	d.SetId(id)
+	if err := d.Set(OrgArgument, name); err != nil {
+		return nil, err
+	}
+	return cfg.Org(name)
+}
+
+// splitImportID splits an import ID prefixed with the name of a declared org,
+// e.g. "prod:00u1", into the org and the ID of the object.
+func splitImportID(cfg *config.Config, importID string) (org, id string, found bool) {
+	org, id, found = strings.Cut(importID, orgImportSeparator)
+	if !found || cfg == nil {
+		return "", importID, false
+	}
+	if _, ok := cfg.Orgs[org]; !ok {
+		return "", importID, false
+	}
+	return org, id, true
+}
@@ -0,0 +1,145 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+// orgSchema converts the values of a framework resource or data source, whose
+// schema the safe wrapper extends with the org argument, to values of the
+// schema of the underlying resource or data source, and back. The models of
+// the underlying resources don't have the org argument, the framework fails
+// to read values with attributes they don't have.
+type orgSchema struct {
+	// schema carries the schema without the org argument, the interface of
+	// the framework schemas is internal
+	schema tfsdk.State
+	typ    tftypes.Object
+	// orgType is the type of the schema with the org argument
+	orgType tftypes.Object
+}
+
+func newOrgSchema(ctx context.Context, schema tfsdk.State) *orgSchema {
+	typ, _ := schema.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := make(map[string]tftypes.Type, len(typ.AttributeTypes)+1)
+	for k, v := range typ.AttributeTypes {
+		attributes[k] = v
+	}
+	attributes[OrgArgument] = tftypes.String
+	return &orgSchema{
+		schema:  tfsdk.State{Schema: schema.Schema},
+		typ:     typ,
+		orgType: tftypes.Object{AttributeTypes: attributes},
+	}
+}
+
+// split returns v, a value of the schema with the org argument, without the
+// argument, and the argument.
+func (o *orgSchema) split(v tftypes.Value, diags *diag.Diagnostics) (tftypes.Value, tftypes.Value) {
+	org := tftypes.NewValue(tftypes.String, nil)
+	switch {
+	case v.Type() == nil:
+		return v, org
+	case !v.IsKnown():
+		return tftypes.NewValue(o.typ, tftypes.UnknownValue), tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	case v.IsNull():
+		return tftypes.NewValue(o.typ, nil), org
+	}
+	var values map[string]tftypes.Value
+	if err := v.As(&values); err != nil {
+		diags.AddError("Failed to read the org argument", err.Error())
+		return v, org
+	}
+	// As shares the map of v, which must not change
+	attributes := make(map[string]tftypes.Value, len(values))
+	for k, value := range values {
+		if k == OrgArgument {
+			org = value
+			continue
+		}
+		attributes[k] = value
+	}
+	if err := tftypes.ValidateValue(o.typ, attributes); err != nil {
+		diags.AddError("Failed to read the org argument", err.Error())
+		return v, org
+	}
+	return tftypes.NewValue(o.typ, attributes), org
+}
+
+// join returns v, a value of the schema without the org argument, with the
+// argument set to org.
+func (o *orgSchema) join(v, org tftypes.Value, diags *diag.Diagnostics) tftypes.Value {
+	switch {
+	case v.Type() == nil:
+		return v
+	case !v.IsKnown():
+		return tftypes.NewValue(o.orgType, tftypes.UnknownValue)
+	case v.IsNull():
+		return tftypes.NewValue(o.orgType, nil)
+	}
+	var values map[string]tftypes.Value
+	if err := v.As(&values); err != nil {
+		diags.AddError("Failed to set the org argument", err.Error())
+		return v
+	}
+	attributes := make(map[string]tftypes.Value, len(values)+1)
+	for k, value := range values {
+		attributes[k] = value
+	}
+	attributes[OrgArgument] = org
+	if err := tftypes.ValidateValue(o.orgType, attributes); err != nil {
+		diags.AddError("Failed to set the org argument", err.Error())
+		return v
+	}
+	return tftypes.NewValue(o.orgType, attributes)
+}
+
+// state returns the state without the org argument, and the argument.
+func (o *orgSchema) state(state tfsdk.State, diags *diag.Diagnostics) (tfsdk.State, tftypes.Value) {
+	raw, org := o.split(state.Raw, diags)
+	return tfsdk.State{Schema: o.schema.Schema, Raw: raw}, org
+}
+
+// plan returns the plan without the org argument, and the argument.
+func (o *orgSchema) plan(plan tfsdk.Plan, diags *diag.Diagnostics) (tfsdk.Plan, tftypes.Value) {
+	raw, org := o.split(plan.Raw, diags)
+	return tfsdk.Plan{Schema: o.schema.Schema, Raw: raw}, org
+}
+
+// config returns the configuration without the org argument, and the
+// argument.
+func (o *orgSchema) config(config tfsdk.Config, diags *diag.Diagnostics) (tfsdk.Config, tftypes.Value) {
+	raw, org := o.split(config.Raw, diags)
+	return tfsdk.Config{Schema: o.schema.Schema, Raw: raw}, org
+}
+
+// orgConfig returns the configuration of the org selected by the org
+// argument, nil when the argument is unknown.
+func orgConfig(providerConfig *config.Config, org tftypes.Value) (*config.Config, error) {
+	if providerConfig == nil || !org.IsKnown() {
+		return nil, nil
+	}
+	var name string
+	if err := org.As(&name); err != nil {
+		return nil, err
+	}
+	return providerConfig.Org(name)
+}
+
+// rawStateOrg returns the org argument of a state saved with an earlier
+// version of the schema.
+func rawStateOrg(raw *tfprotov6.RawState) tftypes.Value {
+	var state struct {
+		Org *string `json:"org"`
+	}
+	if raw == nil || json.Unmarshal(raw.JSON, &state) != nil || state.Org == nil {
+		return tftypes.NewValue(tftypes.String, nil)
+	}
+	return tftypes.NewValue(tftypes.String, *state.Org)
+}
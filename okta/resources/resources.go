@@ -19,11 +19,15 @@ const (
 	OktaIDaaSAppMetadataSaml                          = "okta_app_metadata_saml"
 	OktaIDaaSAppOAuth                                 = "okta_app_oauth"
 	OktaIDaaSAppOAuthAPIScope                         = "okta_app_oauth_api_scope"
+	OktaIDaaSAppOAuthAccessToken                      = "okta_app_oauth_access_token"
+	OktaIDaaSAppOAuthClientSecret                     = "okta_app_oauth_client_secret"
+	OktaIDaaSAppOAuthJWK                              = "okta_app_oauth_jwk"
 	OktaIDaaSAppOAuthPostLogoutRedirectURI            = "okta_app_oauth_post_logout_redirect_uri"
 	OktaIDaaSAppOAuthRedirectURI                      = "okta_app_oauth_redirect_uri"
 	OktaIDaaSAppOAuthRoleAssignment                   = "okta_app_oauth_role_assignment"
 	OktaIDaaSAppSaml                                  = "okta_app_saml"
 	OktaIDaaSAppSamlAppSettings                       = "okta_app_saml_app_settings"
+	OktaIDaaSAppSamlKeyRotation                       = "okta_app_saml_key_rotation"
 	OktaIDaaSAppSecurePasswordStore                   = "okta_app_secure_password_store"
 	OktaIDaaSAppSharedCredentials                     = "okta_app_shared_credentials"
 	OktaIDaaSAppSignOnPolicy                          = "okta_app_signon_policy"
@@ -42,6 +46,7 @@ const (
 	OktaIDaaSAuthServerClaimDefault                   = "okta_auth_server_claim_default"
 	OktaIDaaSAuthServerClaims                         = "okta_auth_server_claims"
 	OktaIDaaSAuthServerClients                        = "okta_auth_server_clients"
+	OktaIDaaSAuthServerKeyRotation                    = "okta_auth_server_key_rotation"
 	OktaIDaaSAuthServerKeys                           = "okta_auth_server_keys"
 	OktaIDaaSAuthServerDefault                        = "okta_auth_server_default"
 	OktaIDaaSAuthServerPolicy                         = "okta_auth_server_policy"
@@ -91,7 +96,9 @@ const (
 	OktaIDaaSLinkValue                                = "okta_link_value"
 	OktaIDaaSLogStream                                = "okta_log_stream"
 	OktaIDaaSNetworkZone                              = "okta_network_zone"
+	OktaIDaaSNetworkZoneOverlaps                      = "okta_network_zone_overlaps"
 	OktaIDaaSOrgConfiguration                         = "okta_org_configuration"
+	OktaIDaaSOrgDiff                                  = "okta_org_diff"
 	OktaIDaaSOrgSupport                               = "okta_org_support"
 	OktaIDaaSPolicy                                   = "okta_policy"
 	OktaIDaaSPolicyMfa                                = "okta_policy_mfa"
@@ -106,6 +113,7 @@ const (
 	OktaIDaaSPolicyRuleProfileEnrollment              = "okta_policy_rule_profile_enrollment"
 	OktaIDaaSPolicyRuleSignOn                         = "okta_policy_rule_signon"
 	OktaIDaaSPolicySignOn                             = "okta_policy_signon"
+	OktaIDaaSPolicyWithRules                          = "okta_policy_with_rules"
 	OktaIDaaSProfileMapping                           = "okta_profile_mapping"
 	OktaIDaaSPrincipalRateLimits                      = "okta_principal_rate_limits"
 	OktaIDaaSPushProvider                             = "okta_push_provider"
@@ -116,7 +124,9 @@ const (
 	OktaIDaaSRealmAssignment                          = "okta_realm_assignment"
 	OktaIDaaSResourceSet                              = "okta_resource_set"
 	OktaIDaaSRoleSubscription                         = "okta_role_subscription"
+	OktaIDaaSSamlExpiringCertificates                 = "okta_saml_expiring_certificates"
 	OktaIDaaSSecurityNotificationEmails               = "okta_security_notification_emails"
+	OktaIDaaSSystemLogChanges                         = "okta_system_log_changes"
 	OktaIDaaSTemplateSms                              = "okta_template_sms"
 	OktaIDaaSTheme                                    = "okta_theme"
 	OktaIDaaSThemes                                   = "okta_themes"
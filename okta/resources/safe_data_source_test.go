@@ -10,6 +10,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/config"
 )
 
 type mockDataSource struct {
@@ -177,3 +181,77 @@ func TestWrapDataSources(t *testing.T) {
 		}
 	}
 }
+
+// orgMockDataSource reads its configuration into a model without the org
+// argument
+type orgMockDataSource struct {
+	config *config.Config
+}
+
+func (m *orgMockDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mock"
+}
+
+func (m *orgMockDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":   schema.StringAttribute{Computed: true},
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+
+func (m *orgMockDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	m.config, _ = req.ProviderData.(*config.Config)
+}
+
+func (m *orgMockDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model orgMockModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	model.ID = types.StringValue(m.config.OrgName)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// TestSafeDataSource_Org tests that the org argument selects the
+// configuration of the underlying data source
+func TestSafeDataSource_Org(t *testing.T) {
+	ctx := context.Background()
+	cfg, _ := testOrgsConfig(t)
+	schemaResp := &datasource.SchemaResponse{}
+	NewSafeDataSource(&orgMockDataSource{}).Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+	if _, ok := schemaResp.Schema.Attributes[OrgArgument]; !ok {
+		t.Fatalf("expected the org argument, got %+v", schemaResp.Schema.Attributes)
+	}
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	for org, want := range map[interface{}]string{nil: "acme", "dev": "acme-dev", "prod": ""} {
+		d := NewSafeDataSource(&orgMockDataSource{})
+		d.(datasource.DataSourceWithConfigure).Configure(ctx, datasource.ConfigureRequest{ProviderData: cfg}, &datasource.ConfigureResponse{})
+		config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, map[string]tftypes.Value{
+			"id":        tftypes.NewValue(tftypes.String, nil),
+			"name":      tftypes.NewValue(tftypes.String, "test"),
+			OrgArgument: tftypes.NewValue(tftypes.String, org),
+		})}
+		resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: config.Raw}}
+		d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+		if want == "" {
+			if !resp.Diagnostics.HasError() {
+				t.Errorf("expected an error for org %v that isn't declared", org)
+			}
+			continue
+		}
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("org %v: %v", org, resp.Diagnostics)
+		}
+		var got struct {
+			orgMockModel
+			Org types.String `tfsdk:"org"`
+		}
+		if diags := resp.State.Get(ctx, &got); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if got.ID.ValueString() != want || got.Org.ValueString() != stringOrEmpty(org) {
+			t.Errorf("expected org %v to be read from %s, got %+v", org, want, got)
+		}
+	}
+}
@@ -9,7 +9,11 @@ import (
 	"sync/atomic"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/config"
 )
 
 // Ensure SafeDataSource implements all required interfaces
@@ -23,6 +27,12 @@ type SafeDataSource struct {
 	underlying     datasource.DataSource
 	nameOnce       sync.Once
 	dataSourceName atomic.Value // string
+	providerConfig *config.Config
+	// orgConfig is the configuration of the org the underlying data source
+	// is configured with
+	orgConfig *config.Config
+	orgOnce   sync.Once
+	org       *orgSchema
 }
 
 // NewSafeDataSource creates a new SafeDataSource wrapper around the given data source
@@ -78,20 +88,85 @@ func (s *SafeDataSource) Metadata(ctx context.Context, req datasource.MetadataRe
 	}
 }
 
-// Schema delegates to the underlying data source
+// Schema delegates to the underlying data source, and adds the org argument
+// to its schema
 func (s *SafeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	s.underlying.Schema(ctx, req, resp)
+	if _, ok := resp.Schema.Attributes[OrgArgument]; ok || resp.Diagnostics.HasError() {
+		return
+	}
+	attributes := make(map[string]datasourceschema.Attribute, len(resp.Schema.Attributes)+1)
+	for k, v := range resp.Schema.Attributes {
+		attributes[k] = v
+	}
+	attributes[OrgArgument] = datasourceschema.StringAttribute{
+		Optional:    true,
+		Description: orgArgumentDescription,
+	}
+	resp.Schema.Attributes = attributes
+}
+
+// orgSchema returns the converter of the values of the data source when the
+// wrapper adds the org argument to its schema, nil when the schema of the
+// underlying data source has it.
+func (s *SafeDataSource) orgSchema(ctx context.Context) *orgSchema {
+	s.orgOnce.Do(func() {
+		resp := &datasource.SchemaResponse{}
+		s.underlying.Schema(ctx, datasource.SchemaRequest{}, resp)
+		if _, ok := resp.Schema.Attributes[OrgArgument]; ok || resp.Diagnostics.HasError() {
+			return
+		}
+		s.org = newOrgSchema(ctx, tfsdk.State{Schema: resp.Schema})
+	})
+	return s.org
+}
+
+// configureOrg configures the underlying data source with the configuration
+// of the org selected by the org argument.
+func (s *SafeDataSource) configureOrg(ctx context.Context, org tftypes.Value, diags *diag.Diagnostics) {
+	cfg, err := orgConfig(s.providerConfig, org)
+	if err != nil {
+		diags.AddError("Invalid org", err.Error())
+		return
+	}
+	if cfg == nil || cfg == s.orgConfig {
+		return
+	}
+	s.orgConfig = cfg
+	if dc, ok := s.underlying.(datasource.DataSourceWithConfigure); ok {
+		resp := &datasource.ConfigureResponse{}
+		dc.Configure(ctx, datasource.ConfigureRequest{ProviderData: cfg}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
 }
 
 // Read wraps the underlying Read with panic recovery
 func (s *SafeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "Read")
+	o := s.orgSchema(ctx)
+	if o == nil {
+		s.underlying.Read(ctx, req, resp)
+		return
+	}
+	var org tftypes.Value
+	req.Config, org = o.config(req.Config, &resp.Diagnostics)
+	if s.configureOrg(ctx, org, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
+	}
+	state := resp.State
+	resp.State, _ = o.state(resp.State, &resp.Diagnostics)
 	s.underlying.Read(ctx, req, resp)
+	state.Raw = o.join(resp.State.Raw, org, &resp.Diagnostics)
+	resp.State = state
 }
 
 // Configure delegates to the underlying data source if it implements DataSourceWithConfigure
 func (s *SafeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "Configure")
+	if providerConfig, ok := req.ProviderData.(*config.Config); ok {
+		s.providerConfig = providerConfig
+		s.orgConfig = providerConfig
+	}
 	if dc, ok := s.underlying.(datasource.DataSourceWithConfigure); ok {
 		dc.Configure(ctx, req, resp)
 	}
@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+)
+
+// Ensure SafeEphemeralResource implements all required interfaces
+var (
+	_ ephemeral.EphemeralResource              = &SafeEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &SafeEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithRenew     = &SafeEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &SafeEphemeralResource{}
+)
+
+// SafeEphemeralResource wraps an ephemeral resource with panic recovery to
+// prevent provider crashes
+type SafeEphemeralResource struct {
+	underlying   ephemeral.EphemeralResource
+	nameOnce     sync.Once
+	resourceName atomic.Value // string
+}
+
+// NewSafeEphemeralResource creates a new SafeEphemeralResource wrapper around
+// the given ephemeral resource
+func NewSafeEphemeralResource(e ephemeral.EphemeralResource) ephemeral.EphemeralResource {
+	return &SafeEphemeralResource{underlying: e}
+}
+
+// WrapEphemeralResources wraps multiple ephemeral resource constructors with
+// SafeEphemeralResource
+func WrapEphemeralResources(constructors []func() ephemeral.EphemeralResource) []func() ephemeral.EphemeralResource {
+	wrapped := make([]func() ephemeral.EphemeralResource, len(constructors))
+	for i, constructor := range constructors {
+		c := constructor // capture loop variable
+		wrapped[i] = func() ephemeral.EphemeralResource {
+			return NewSafeEphemeralResource(c())
+		}
+	}
+	return wrapped
+}
+
+// recoverPanic handles panic recovery and adds appropriate diagnostics
+func (s *SafeEphemeralResource) recoverPanic(diags *diag.Diagnostics, operation string) {
+	if r := recover(); r != nil {
+		stackTrace := string(debug.Stack())
+		resourceName, _ := s.resourceName.Load().(string)
+		if resourceName == "" && s.underlying != nil {
+			resourceName = typeBaseName(reflect.TypeOf(s.underlying))
+		}
+		if resourceName == "" {
+			resourceName = "unknown"
+		}
+
+		diags.AddError(
+			fmt.Sprintf("Provider Crash in %s operation of ephemeral resource %s", operation, resourceName),
+			fmt.Sprintf(
+				"The Terraform Provider Okta crashed during the %s operation of ephemeral resource %s.\n\n"+
+					"Please check if this issue has already been reported on\n"+
+					"https://github.com/okta/terraform-provider-okta/issues\n"+
+					"or create a new issue with this stack trace.\n"+
+					"Error: %v\n\nStack trace:\n%s\n\n",
+				operation, resourceName, r, stackTrace,
+			),
+		)
+	}
+}
+
+// Metadata delegates to the underlying ephemeral resource
+func (s *SafeEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	s.underlying.Metadata(ctx, req, resp)
+	if resp.TypeName != "" {
+		s.nameOnce.Do(func() {
+			s.resourceName.Store(resp.TypeName)
+		})
+	}
+}
+
+// Schema delegates to the underlying ephemeral resource
+func (s *SafeEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	s.underlying.Schema(ctx, req, resp)
+}
+
+// Open wraps the underlying Open with panic recovery
+func (s *SafeEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	defer s.recoverPanic(&resp.Diagnostics, "Open")
+	s.underlying.Open(ctx, req, resp)
+}
+
+// Configure delegates to the underlying ephemeral resource if it implements
+// EphemeralResourceWithConfigure
+func (s *SafeEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	defer s.recoverPanic(&resp.Diagnostics, "Configure")
+	if ec, ok := s.underlying.(ephemeral.EphemeralResourceWithConfigure); ok {
+		ec.Configure(ctx, req, resp)
+	}
+}
+
+// Renew delegates to the underlying ephemeral resource if it implements
+// EphemeralResourceWithRenew
+func (s *SafeEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	defer s.recoverPanic(&resp.Diagnostics, "Renew")
+	if er, ok := s.underlying.(ephemeral.EphemeralResourceWithRenew); ok {
+		er.Renew(ctx, req, resp)
+	}
+}
+
+// Close delegates to the underlying ephemeral resource if it implements
+// EphemeralResourceWithClose
+func (s *SafeEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	defer s.recoverPanic(&resp.Diagnostics, "Close")
+	if ec, ok := s.underlying.(ephemeral.EphemeralResourceWithClose); ok {
+		ec.Close(ctx, req, resp)
+	}
+}
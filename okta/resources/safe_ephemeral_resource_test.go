@@ -0,0 +1,91 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+)
+
+type mockEphemeralResource struct {
+	panicOnOpen bool
+	closed      bool
+}
+
+func (m *mockEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mock"
+}
+
+func (m *mockEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{Computed: true},
+		},
+	}
+}
+
+func (m *mockEphemeralResource) Open(_ context.Context, _ ephemeral.OpenRequest, _ *ephemeral.OpenResponse) {
+	if m.panicOnOpen {
+		var x *string
+		_ = *x // nil pointer dereference causes panic
+	}
+}
+
+func (m *mockEphemeralResource) Close(_ context.Context, _ ephemeral.CloseRequest, _ *ephemeral.CloseResponse) {
+	m.closed = true
+}
+
+func TestSafeEphemeralResource_Open_PanicRecovery(t *testing.T) {
+	safe := NewSafeEphemeralResource(&mockEphemeralResource{panicOnOpen: true})
+	safe.Metadata(context.Background(), ephemeral.MetadataRequest{ProviderTypeName: "okta"}, &ephemeral.MetadataResponse{})
+	resp := &ephemeral.OpenResponse{
+		Diagnostics: diag.Diagnostics{},
+	}
+	safe.Open(context.Background(), ephemeral.OpenRequest{}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected diagnostics to have error after panic")
+	}
+
+	summary := resp.Diagnostics.Errors()[0].Summary()
+	if !strings.Contains(summary, "Provider Crash in Open") || !strings.Contains(summary, "okta_mock") {
+		t.Fatalf("Expected error summary to name the Open operation and okta_mock, got '%s'", summary)
+	}
+}
+
+func TestSafeEphemeralResource_Close_PassesThrough(t *testing.T) {
+	mock := &mockEphemeralResource{}
+	safe := NewSafeEphemeralResource(mock)
+	resp := &ephemeral.CloseResponse{
+		Diagnostics: diag.Diagnostics{},
+	}
+	safe.(ephemeral.EphemeralResourceWithClose).Close(context.Background(), ephemeral.CloseRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Expected no diagnostics error, got: %v", resp.Diagnostics)
+	}
+	if !mock.closed {
+		t.Fatal("Expected Close to be delegated to the underlying ephemeral resource")
+	}
+}
+
+func TestWrapEphemeralResources(t *testing.T) {
+	constructors := []func() ephemeral.EphemeralResource{
+		func() ephemeral.EphemeralResource { return &mockEphemeralResource{} },
+		func() ephemeral.EphemeralResource { return &mockEphemeralResource{} },
+	}
+
+	wrapped := WrapEphemeralResources(constructors)
+
+	if len(wrapped) != len(constructors) {
+		t.Errorf("Expected %d wrapped constructors, got %d", len(constructors), len(wrapped))
+	}
+
+	for i, constructor := range wrapped {
+		e := constructor()
+		if _, ok := e.(*SafeEphemeralResource); !ok {
+			t.Errorf("Constructor %d did not return a SafeEphemeralResource", i)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure SafeListResource implements all required interfaces
+var (
+	_ list.ListResource                 = &SafeListResource{}
+	_ list.ListResourceWithConfigure    = &SafeListResource{}
+	_ list.ListResourceWithRawV5Schemas = &SafeListResource{}
+)
+
+// SafeListResource wraps a list resource with panic recovery to prevent
+// provider crashes
+type SafeListResource struct {
+	underlying   list.ListResource
+	nameOnce     sync.Once
+	resourceName atomic.Value // string
+}
+
+// NewSafeListResource creates a new SafeListResource wrapper around the given
+// list resource
+func NewSafeListResource(l list.ListResource) list.ListResource {
+	return &SafeListResource{underlying: l}
+}
+
+// WrapListResources wraps multiple list resource constructors with
+// SafeListResource
+func WrapListResources(constructors []func() list.ListResource) []func() list.ListResource {
+	wrapped := make([]func() list.ListResource, len(constructors))
+	for i, constructor := range constructors {
+		c := constructor // capture loop variable
+		wrapped[i] = func() list.ListResource {
+			return NewSafeListResource(c())
+		}
+	}
+	return wrapped
+}
+
+// recoverPanic handles panic recovery and adds appropriate diagnostics
+func (s *SafeListResource) recoverPanic(diags *diag.Diagnostics, operation string) {
+	if r := recover(); r != nil {
+		stackTrace := string(debug.Stack())
+		resourceName, _ := s.resourceName.Load().(string)
+		if resourceName == "" && s.underlying != nil {
+			resourceName = typeBaseName(reflect.TypeOf(s.underlying))
+		}
+		if resourceName == "" {
+			resourceName = "unknown"
+		}
+
+		diags.AddError(
+			fmt.Sprintf("Provider Crash in %s operation of list resource %s", operation, resourceName),
+			fmt.Sprintf(
+				"The Terraform Provider Okta crashed during the %s operation of list resource %s.\n\n"+
+					"Please check if this issue has already been reported on\n"+
+					"https://github.com/okta/terraform-provider-okta/issues\n"+
+					"or create a new issue with this stack trace.\n"+
+					"Error: %v\n\nStack trace:\n%s\n\n",
+				operation, resourceName, r, stackTrace,
+			),
+		)
+	}
+}
+
+// Metadata delegates to the underlying list resource
+func (s *SafeListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	s.underlying.Metadata(ctx, req, resp)
+	if resp.TypeName != "" {
+		s.nameOnce.Do(func() {
+			s.resourceName.Store(resp.TypeName)
+		})
+	}
+}
+
+// ListResourceConfigSchema delegates to the underlying list resource
+func (s *SafeListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	s.underlying.ListResourceConfigSchema(ctx, req, resp)
+}
+
+// List wraps the underlying List with panic recovery, both when the results
+// are requested and while they are streamed
+func (s *SafeListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var diags diag.Diagnostics
+	func() {
+		defer s.recoverPanic(&diags, "List")
+		s.list(ctx, req, stream)
+	}()
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	results := stream.Results
+	if results == nil {
+		return
+	}
+	stream.Results = func(push func(list.ListResult) bool) {
+		var diags diag.Diagnostics
+		func() {
+			defer s.recoverPanic(&diags, "List")
+			results(push)
+		}()
+		if diags.HasError() {
+			push(list.ListResult{Diagnostics: diags})
+		}
+	}
+}
+
+// list lists the objects of the underlying list resource. The objects of a
+// framework resource, whose schema SafeResource extends with the org
+// argument, are listed without it, and are objects of the org of the
+// provider. It doesn't handle the org argument of plugin SDK resources, whose
+// list resources hand over raw v5 schemas: they have to build the schema and
+// the state from the resource as WrapSDKResources serves it, org argument
+// included.
+func (s *SafeListResource) list(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	schema, ok := req.ResourceSchema.(resourceschema.Schema)
+	if _, found := schema.Attributes[OrgArgument]; !ok || !found {
+		s.underlying.List(ctx, req, stream)
+		return
+	}
+	attributes := make(map[string]resourceschema.Attribute, len(schema.Attributes))
+	for k, v := range schema.Attributes {
+		if k != OrgArgument {
+			attributes[k] = v
+		}
+	}
+	schema.Attributes = attributes
+	o := newOrgSchema(ctx, tfsdk.State{Schema: schema})
+	resourceSchema := req.ResourceSchema
+	req.ResourceSchema = schema
+	s.underlying.List(ctx, req, stream)
+	results := stream.Results
+	if results == nil {
+		return
+	}
+	org := tftypes.NewValue(tftypes.String, nil)
+	stream.Results = func(push func(list.ListResult) bool) {
+		results(func(result list.ListResult) bool {
+			if result.Resource != nil {
+				wrapped := tfsdk.Resource{Schema: resourceSchema}
+				wrapped.Raw = o.join(result.Resource.Raw, org, &result.Diagnostics)
+				result.Resource = &wrapped
+			}
+			return push(result)
+		})
+	}
+}
+
+// Configure delegates to the underlying list resource if it implements
+// ListResourceWithConfigure
+func (s *SafeListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	defer s.recoverPanic(&resp.Diagnostics, "Configure")
+	if lc, ok := s.underlying.(list.ListResourceWithConfigure); ok {
+		lc.Configure(ctx, req, resp)
+	}
+}
+
+// RawV5Schemas delegates to the underlying list resource if it implements
+// ListResourceWithRawV5Schemas
+func (s *SafeListResource) RawV5Schemas(ctx context.Context, req list.RawV5SchemaRequest, resp *list.RawV5SchemaResponse) {
+	if ls, ok := s.underlying.(list.ListResourceWithRawV5Schemas); ok {
+		ls.RawV5Schemas(ctx, req, resp)
+	}
+}
@@ -0,0 +1,132 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type mockListResource struct {
+	panicOnList   bool
+	panicOnResult bool
+}
+
+func (m *mockListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mock"
+}
+
+func (m *mockListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+
+func (m *mockListResource) List(_ context.Context, _ list.ListRequest, stream *list.ListResultsStream) {
+	if m.panicOnList {
+		var x *string
+		_ = *x // nil pointer dereference causes panic
+	}
+	stream.Results = func(push func(list.ListResult) bool) {
+		if !push(list.ListResult{DisplayName: "first"}) {
+			return
+		}
+		if m.panicOnResult {
+			var x *string
+			_ = *x // nil pointer dereference causes panic
+		}
+	}
+}
+
+func collectListResults(stream *list.ListResultsStream) []list.ListResult {
+	var results []list.ListResult
+	for result := range stream.Results {
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestSafeListResource_List_PanicRecovery(t *testing.T) {
+	safe := NewSafeListResource(&mockListResource{panicOnList: true})
+	safe.Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "okta"}, &resource.MetadataResponse{})
+	stream := &list.ListResultsStream{}
+	safe.List(context.Background(), list.ListRequest{}, stream)
+
+	results := collectListResults(stream)
+	if len(results) != 1 || !results[0].Diagnostics.HasError() {
+		t.Fatalf("Expected a single result with an error after panic, got %+v", results)
+	}
+	summary := results[0].Diagnostics.Errors()[0].Summary()
+	if !strings.Contains(summary, "Provider Crash in List") || !strings.Contains(summary, "okta_mock") {
+		t.Fatalf("Expected error summary to name the List operation and okta_mock, got '%s'", summary)
+	}
+}
+
+func TestSafeListResource_Results_PanicRecovery(t *testing.T) {
+	safe := NewSafeListResource(&mockListResource{panicOnResult: true})
+	stream := &list.ListResultsStream{}
+	safe.List(context.Background(), list.ListRequest{}, stream)
+
+	results := collectListResults(stream)
+	if len(results) != 2 {
+		t.Fatalf("Expected the first result and an error, got %+v", results)
+	}
+	if results[0].DisplayName != "first" || results[0].Diagnostics.HasError() {
+		t.Fatalf("Expected the first result to pass through, got %+v", results[0])
+	}
+	if !results[1].Diagnostics.HasError() {
+		t.Fatal("Expected diagnostics to have error after panic")
+	}
+}
+
+func TestSafeListResource_RawV5Schemas_NotImplemented(t *testing.T) {
+	safe := NewSafeListResource(&mockListResource{})
+	resp := &list.RawV5SchemaResponse{}
+	safe.(list.ListResourceWithRawV5Schemas).RawV5Schemas(context.Background(), list.RawV5SchemaRequest{}, resp)
+	if resp.ProtoV5Schema != nil || resp.ProtoV5IdentitySchema != nil {
+		t.Fatal("Expected no schemas for a list resource of a framework resource")
+	}
+}
+
+// orgMockListResource sets the resources it lists from a model without the
+// org argument
+type orgMockListResource struct {
+	mockListResource
+}
+
+func (m *orgMockListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	stream.Results = func(push func(list.ListResult) bool) {
+		result := req.NewListResult(ctx)
+		result.Diagnostics.Append(result.Resource.Set(ctx, &orgMockModel{ID: types.StringValue("00g1"), Name: types.StringValue("test")})...)
+		push(result)
+	}
+}
+
+func TestSafeListResource_Org(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &resource.SchemaResponse{}
+	NewSafeResource(&orgMockResource{}).Schema(ctx, resource.SchemaRequest{}, schemaResp)
+
+	stream := &list.ListResultsStream{}
+	NewSafeListResource(&orgMockListResource{}).List(ctx, list.ListRequest{
+		IncludeResource:        true,
+		ResourceSchema:         schemaResp.Schema,
+		ResourceIdentitySchema: identityschema.Schema{Attributes: map[string]identityschema.Attribute{"id": identityschema.StringAttribute{}}},
+	}, stream)
+	results := collectListResults(stream)
+	if len(results) != 1 || results[0].Diagnostics.HasError() {
+		t.Fatalf("Expected a single result, got %+v", results)
+	}
+	want := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+		"id":        tftypes.NewValue(tftypes.String, "00g1"),
+		"name":      tftypes.NewValue(tftypes.String, "test"),
+		OrgArgument: tftypes.NewValue(tftypes.String, nil),
+	})
+	if !results[0].Resource.Raw.Equal(want) {
+		t.Errorf("Expected the resource of the org of the provider, got %v", results[0].Resource.Raw)
+	}
+}
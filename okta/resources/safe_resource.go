@@ -5,12 +5,20 @@ import (
 	"fmt"
 	"reflect"
 	"runtime/debug"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
 )
 
 // typeBaseName strips pointer prefixes and package qualifiers from a reflect type string.
@@ -36,27 +44,57 @@ var (
 	_ resource.ResourceWithValidateConfig = &SafeResource{}
 	_ resource.ResourceWithModifyPlan     = &SafeResource{}
 	_ resource.ResourceWithUpgradeState   = &SafeResource{}
+	_ resource.ResourceWithIdentity       = &SafeResourceWithIdentity{}
 )
 
+// ResourceWithRequiredCapabilities is a resource that can only be created in
+// orgs with some capabilities, e.g. Okta Identity Engine or a separately
+// licensed product. The capabilities may depend on the planned values.
+// SafeResource fails the plans creating it in orgs without them.
+type ResourceWithRequiredCapabilities interface {
+	resource.Resource
+	RequiredCapabilities(ctx context.Context, plan tfsdk.Plan) []config.Capability
+}
+
 // SafeResource wraps a resource with panic recovery to prevent provider crashes
 type SafeResource struct {
 	underlying   resource.Resource
 	nameOnce     sync.Once
 	resourceName atomic.Value // string
+	// capabilities are required by the resource in addition to those it
+	// declares itself
+	capabilities   []config.Capability
+	providerConfig *config.Config
+	// orgConfig is the configuration of the org the underlying resource is
+	// configured with
+	orgConfig *config.Config
+	orgOnce   sync.Once
+	org       *orgSchema
 }
 
-// NewSafeResource creates a new SafeResource wrapper around the given resource
-func NewSafeResource(r resource.Resource) resource.Resource {
-	return &SafeResource{underlying: r}
+// SafeResourceWithIdentity wraps a resource that supports resource identity,
+// only those resources may implement ResourceWithIdentity
+type SafeResourceWithIdentity struct {
+	*SafeResource
 }
 
-// WrapResources wraps multiple resource constructors with SafeResource
-func WrapResources(constructors []func() resource.Resource) []func() resource.Resource {
+// NewSafeResource creates a new SafeResource wrapper around the given
+// resource, which can only be created in orgs with capabilities.
+func NewSafeResource(r resource.Resource, capabilities ...config.Capability) resource.Resource {
+	if _, ok := r.(resource.ResourceWithIdentity); ok {
+		return &SafeResourceWithIdentity{&SafeResource{underlying: r, capabilities: capabilities}}
+	}
+	return &SafeResource{underlying: r, capabilities: capabilities}
+}
+
+// WrapResources wraps multiple resource constructors with SafeResource. The
+// resources can only be created in orgs with capabilities.
+func WrapResources(constructors []func() resource.Resource, capabilities ...config.Capability) []func() resource.Resource {
 	wrapped := make([]func() resource.Resource, len(constructors))
 	for i, constructor := range constructors {
 		c := constructor // capture loop variable
 		wrapped[i] = func() resource.Resource {
-			return NewSafeResource(c())
+			return NewSafeResource(c(), capabilities...)
 		}
 	}
 	return wrapped
@@ -98,74 +136,311 @@ func (s *SafeResource) Metadata(ctx context.Context, req resource.MetadataReques
 	}
 }
 
-// Schema delegates to the underlying resource
+// Schema delegates to the underlying resource, and adds the org argument to
+// its schema. The org of a resource can't change, objects don't move between
+// orgs.
 func (s *SafeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	s.underlying.Schema(ctx, req, resp)
+	if _, ok := resp.Schema.Attributes[OrgArgument]; ok || resp.Diagnostics.HasError() {
+		return
+	}
+	attributes := make(map[string]resourceschema.Attribute, len(resp.Schema.Attributes)+1)
+	for k, v := range resp.Schema.Attributes {
+		attributes[k] = v
+	}
+	attributes[OrgArgument] = resourceschema.StringAttribute{
+		Optional:      true,
+		Description:   orgArgumentDescription,
+		PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	resp.Schema.Attributes = attributes
+}
+
+// orgSchema returns the converter of the values of the resource when the
+// wrapper adds the org argument to its schema, nil when the schema of the
+// underlying resource has it, e.g. when it is a SafeResource itself.
+func (s *SafeResource) orgSchema(ctx context.Context) *orgSchema {
+	s.orgOnce.Do(func() {
+		resp := &resource.SchemaResponse{}
+		s.underlying.Schema(ctx, resource.SchemaRequest{}, resp)
+		if _, ok := resp.Schema.Attributes[OrgArgument]; ok || resp.Diagnostics.HasError() {
+			return
+		}
+		s.org = newOrgSchema(ctx, tfsdk.State{Schema: resp.Schema})
+	})
+	return s.org
+}
+
+// configureOrg configures the underlying resource with the configuration of
+// the org selected by the org argument. It keeps the configuration when the
+// argument is unknown.
+func (s *SafeResource) configureOrg(ctx context.Context, org tftypes.Value, diags *diag.Diagnostics) {
+	cfg, err := orgConfig(s.providerConfig, org)
+	if err != nil {
+		diags.AddError("Invalid org", err.Error())
+		return
+	}
+	if cfg == nil || cfg == s.orgConfig {
+		return
+	}
+	s.orgConfig = cfg
+	if rc, ok := s.underlying.(resource.ResourceWithConfigure); ok {
+		resp := &resource.ConfigureResponse{}
+		rc.Configure(ctx, resource.ConfigureRequest{ProviderData: cfg}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
 }
 
 // Create wraps the underlying Creation with panic recovery
 func (s *SafeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "Create")
+	ctx = apimutex.WithPriority(ctx, apimutex.PriorityMutate)
+	o := s.orgSchema(ctx)
+	if o == nil {
+		s.underlying.Create(ctx, req, resp)
+		return
+	}
+	var org tftypes.Value
+	req.Plan, org = o.plan(req.Plan, &resp.Diagnostics)
+	req.Config, _ = o.config(req.Config, &resp.Diagnostics)
+	if s.configureOrg(ctx, org, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
+	}
+	state := resp.State
+	resp.State, _ = o.state(resp.State, &resp.Diagnostics)
 	s.underlying.Create(ctx, req, resp)
+	state.Raw = o.join(resp.State.Raw, org, &resp.Diagnostics)
+	resp.State = state
 }
 
-// Read wraps the underlying Read with panic recovery
+// Read wraps the underlying Read with panic recovery. API requests made while
+// refreshing are scheduled behind those of creates, updates and deletes.
 func (s *SafeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "Read")
+	ctx = apimutex.WithPriority(ctx, apimutex.PriorityRefresh)
+	o := s.orgSchema(ctx)
+	if o == nil {
+		s.underlying.Read(ctx, req, resp)
+		return
+	}
+	var org tftypes.Value
+	req.State, org = o.state(req.State, &resp.Diagnostics)
+	if s.configureOrg(ctx, org, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
+	}
+	state := resp.State
+	resp.State, _ = o.state(resp.State, &resp.Diagnostics)
 	s.underlying.Read(ctx, req, resp)
+	state.Raw = o.join(resp.State.Raw, org, &resp.Diagnostics)
+	resp.State = state
 }
 
 // Update wraps the underlying Update with panic recovery
 func (s *SafeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "Update")
+	ctx = apimutex.WithPriority(ctx, apimutex.PriorityMutate)
+	o := s.orgSchema(ctx)
+	if o == nil {
+		s.underlying.Update(ctx, req, resp)
+		return
+	}
+	var org tftypes.Value
+	req.Plan, org = o.plan(req.Plan, &resp.Diagnostics)
+	req.Config, _ = o.config(req.Config, &resp.Diagnostics)
+	req.State, _ = o.state(req.State, &resp.Diagnostics)
+	if s.configureOrg(ctx, org, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
+	}
+	state := resp.State
+	resp.State, _ = o.state(resp.State, &resp.Diagnostics)
 	s.underlying.Update(ctx, req, resp)
+	state.Raw = o.join(resp.State.Raw, org, &resp.Diagnostics)
+	resp.State = state
 }
 
 // Delete wraps the underlying Delete with panic recovery
 func (s *SafeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "Delete")
+	ctx = apimutex.WithPriority(ctx, apimutex.PriorityMutate)
+	o := s.orgSchema(ctx)
+	if o == nil {
+		s.underlying.Delete(ctx, req, resp)
+		return
+	}
+	var org tftypes.Value
+	req.State, org = o.state(req.State, &resp.Diagnostics)
+	if s.configureOrg(ctx, org, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
+	}
+	state := resp.State
+	resp.State, _ = o.state(resp.State, &resp.Diagnostics)
 	s.underlying.Delete(ctx, req, resp)
+	state.Raw = o.join(resp.State.Raw, org, &resp.Diagnostics)
+	resp.State = state
 }
 
 // Configure delegates to the underlying resource if it implements ResourceWithConfigure
 func (s *SafeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "Configure")
+	if providerConfig, ok := req.ProviderData.(*config.Config); ok {
+		s.providerConfig = providerConfig
+		s.orgConfig = providerConfig
+	}
 	if rc, ok := s.underlying.(resource.ResourceWithConfigure); ok {
 		rc.Configure(ctx, req, resp)
 	}
 }
 
-// ImportState delegates to the underlying resource if it implements ResourceWithImportState
+// ImportState delegates to the underlying resource if it implements
+// ResourceWithImportState. An import ID prefixed with the name of a declared
+// org, e.g. "prod:00u1", imports the object of that org.
 func (s *SafeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "ImportState")
-	if ri, ok := s.underlying.(resource.ResourceWithImportState); ok {
+	ri, ok := s.underlying.(resource.ResourceWithImportState)
+	if !ok {
+		// If not implemented, the Framework handles this — do not add an error here.
+		return
+	}
+	o := s.orgSchema(ctx)
+	if o == nil {
 		ri.ImportState(ctx, req, resp)
+		return
+	}
+	org := tftypes.NewValue(tftypes.String, nil)
+	if name, id, found := splitImportID(s.providerConfig, req.ID); found {
+		org, req.ID = tftypes.NewValue(tftypes.String, name), id
 	}
-	// If not implemented, the Framework handles this — do not add an error here.
+	if s.configureOrg(ctx, org, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
+	}
+	state := resp.State
+	resp.State, _ = o.state(resp.State, &resp.Diagnostics)
+	ri.ImportState(ctx, req, resp)
+	state.Raw = o.join(resp.State.Raw, org, &resp.Diagnostics)
+	resp.State = state
 }
 
 // ValidateConfig delegates to the underlying resource if it implements ResourceWithValidateConfig
 func (s *SafeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "ValidateConfig")
-	if rv, ok := s.underlying.(resource.ResourceWithValidateConfig); ok {
-		rv.ValidateConfig(ctx, req, resp)
+	rv, ok := s.underlying.(resource.ResourceWithValidateConfig)
+	if !ok {
+		return
 	}
+	if o := s.orgSchema(ctx); o != nil {
+		if req.Config, _ = o.config(req.Config, &resp.Diagnostics); resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	rv.ValidateConfig(ctx, req, resp)
 }
 
-// ModifyPlan delegates to the underlying resource if it implements ResourceWithModifyPlan
+// ModifyPlan fails plans creating the resource in orgs without the
+// capabilities it requires, otherwise delegates to the underlying resource if
+// it implements ResourceWithModifyPlan
 func (s *SafeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
 	defer s.recoverPanic(&resp.Diagnostics, "ModifyPlan")
+	o := s.orgSchema(ctx)
+	if o == nil {
+		s.modifyPlan(ctx, req, resp, true)
+		return
+	}
+	var org, stateOrg tftypes.Value
+	req.Plan, org = o.plan(req.Plan, &resp.Diagnostics)
+	req.State, stateOrg = o.state(req.State, &resp.Diagnostics)
+	req.Config, _ = o.config(req.Config, &resp.Diagnostics)
+	if req.Plan.Raw.IsNull() {
+		org = stateOrg
+	}
+	if s.configureOrg(ctx, org, &resp.Diagnostics); resp.Diagnostics.HasError() {
+		return
+	}
+	plan := resp.Plan
+	resp.Plan, _ = o.plan(resp.Plan, &resp.Diagnostics)
+	// the capabilities of an org that isn't known yet can't be checked
+	s.modifyPlan(ctx, req, resp, org.IsKnown())
+	plan.Raw = o.join(resp.Plan.Raw, org, &resp.Diagnostics)
+	resp.Plan = plan
+}
+
+func (s *SafeResource) modifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, checkCapabilities bool) {
+	if checkCapabilities {
+		if s.checkCapabilities(ctx, req, resp); resp.Diagnostics.HasError() {
+			return
+		}
+	}
 	if rm, ok := s.underlying.(resource.ResourceWithModifyPlan); ok {
 		rm.ModifyPlan(ctx, req, resp)
 	}
 }
 
+// checkCapabilities adds an error when the plan creates the resource in an org
+// that is known not to have the capabilities it requires. Capabilities that
+// can't be probed are assumed to be there.
+func (s *SafeResource) checkCapabilities(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if s.orgConfig == nil || !req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	required := s.capabilities
+	if rc, ok := s.underlying.(ResourceWithRequiredCapabilities); ok {
+		required = append(slices.Clone(required), rc.RequiredCapabilities(ctx, req.Plan)...)
+	}
+	if len(required) == 0 {
+		return
+	}
+	name, _ := s.resourceName.Load().(string)
+	if name == "" {
+		name = typeBaseName(reflect.TypeOf(s.underlying))
+	}
+	missing, err := s.orgConfig.Capabilities.Missing(ctx, required...)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to check the capabilities %s requires", name), err.Error())
+		return
+	}
+	if len(missing) > 0 {
+		resp.Diagnostics.AddError(config.MissingCapabilitiesError(name, missing))
+	}
+}
+
 // UpgradeState delegates to the underlying resource if it implements ResourceWithUpgradeState.
 // NOTE: panic recovery is not possible here because the method returns a value (not a *Response),
 // so there is no Diagnostics field to write to. A panic here will propagate to the Framework.
 func (s *SafeResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
-	if ru, ok := s.underlying.(resource.ResourceWithUpgradeState); ok {
-		return ru.UpgradeState(ctx)
+	ru, ok := s.underlying.(resource.ResourceWithUpgradeState)
+	if !ok {
+		return nil
 	}
-	return nil
+	upgraders := ru.UpgradeState(ctx)
+	o := s.orgSchema(ctx)
+	if o == nil {
+		return upgraders
+	}
+	wrapped := make(map[int64]resource.StateUpgrader, len(upgraders))
+	for version, upgrader := range upgraders {
+		wrapped[version] = resource.StateUpgrader{
+			PriorSchema: upgrader.PriorSchema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				state := resp.State
+				resp.State = tfsdk.State{Schema: o.schema.Schema}
+				upgrader.StateUpgrader(ctx, req, resp)
+				if resp.DynamicValue != nil {
+					raw, err := resp.DynamicValue.Unmarshal(o.typ)
+					if err != nil {
+						resp.Diagnostics.AddError("Failed to read the upgraded state", err.Error())
+						return
+					}
+					resp.State.Raw, resp.DynamicValue = raw, nil
+				}
+				state.Raw = o.join(resp.State.Raw, rawStateOrg(req.RawState), &resp.Diagnostics)
+				resp.State = state
+			},
+		}
+	}
+	return wrapped
+}
+
+// IdentitySchema delegates to the underlying resource
+func (s *SafeResourceWithIdentity) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	defer s.recoverPanic(&resp.Diagnostics, "IdentitySchema")
+	s.underlying.(resource.ResourceWithIdentity).IdentitySchema(ctx, req, resp)
 }
@@ -11,9 +11,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/config"
 )
 
 type mockResource struct {
@@ -244,6 +250,36 @@ func TestWrapResources(t *testing.T) {
 	}
 }
 
+type mockResourceWithIdentity struct {
+	mockResource
+}
+
+func (m *mockResourceWithIdentity) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{RequiredForImport: true},
+		},
+	}
+}
+
+func TestSafeResource_Identity(t *testing.T) {
+	if _, ok := NewSafeResource(&mockResource{}).(resource.ResourceWithIdentity); ok {
+		t.Fatal("Expected a resource without identity not to implement ResourceWithIdentity")
+	}
+
+	// resources are wrapped by both the services and the provider
+	safe := NewSafeResource(NewSafeResource(&mockResourceWithIdentity{}))
+	r, ok := safe.(resource.ResourceWithIdentity)
+	if !ok {
+		t.Fatal("Expected a resource with identity to implement ResourceWithIdentity")
+	}
+	resp := &resource.IdentitySchemaResponse{}
+	r.IdentitySchema(context.Background(), resource.IdentitySchemaRequest{}, resp)
+	if _, ok := resp.IdentitySchema.Attributes["id"]; !ok {
+		t.Fatalf("Expected the identity schema of the underlying resource, got %+v", resp.IdentitySchema)
+	}
+}
+
 // TestSafeResource_Create_GoroutineWithChannel tests resource panic recovery in goroutine
 func TestSafeResource_Create_GoroutineWithChannel(t *testing.T) {
 	mock := &mockResource{
@@ -272,3 +308,203 @@ func TestSafeResource_Create_GoroutineWithChannel(t *testing.T) {
 		t.Fatal("Test timed out - panic may not have been recovered")
 	}
 }
+
+type mockResourceWithCapabilities struct {
+	mockResource
+	capabilities []config.Capability
+}
+
+func (m *mockResourceWithCapabilities) RequiredCapabilities(_ context.Context, _ tfsdk.Plan) []config.Capability {
+	return m.capabilities
+}
+
+func TestSafeResource_ModifyPlan_Capabilities(t *testing.T) {
+	ctx := context.Background()
+	providerConfig := &config.Config{
+		Capabilities: config.NewCapabilities(map[config.Capability]config.CapabilityProbe{
+			config.CapabilityIdentityEngine: func(context.Context) (bool, error) { return true, nil },
+			config.CapabilityGovernance:     func(context.Context) (bool, error) { return false, nil },
+		}),
+	}
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"id": tftypes.String}}
+	null := tftypes.NewValue(objectType, nil)
+	object := tftypes.NewValue(objectType, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "1")})
+
+	tests := []struct {
+		name      string
+		resource  resource.Resource
+		wrapped   []config.Capability
+		state     tftypes.Value
+		plan      tftypes.Value
+		wantError bool
+	}{
+		{"create with capabilities", &mockResourceWithCapabilities{capabilities: []config.Capability{config.CapabilityIdentityEngine}}, nil, null, object, false},
+		{"create without capabilities", &mockResourceWithCapabilities{capabilities: []config.Capability{config.CapabilityGovernance}}, nil, null, object, true},
+		{"create without wrapped capabilities", &mockResource{}, []config.Capability{config.CapabilityGovernance}, null, object, true},
+		{"update without capabilities", &mockResourceWithCapabilities{capabilities: []config.Capability{config.CapabilityGovernance}}, nil, object, object, false},
+		{"destroy without capabilities", &mockResource{}, []config.Capability{config.CapabilityGovernance}, object, null, false},
+		{"unknown capability", &mockResource{}, []config.Capability{config.CapabilityRealms}, null, object, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			safe := NewSafeResource(test.resource, test.wrapped...).(resource.ResourceWithModifyPlan)
+			safe.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: providerConfig}, &resource.ConfigureResponse{})
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Raw: test.state},
+				Plan:  tfsdk.Plan{Raw: test.plan},
+			}
+			resp := &resource.ModifyPlanResponse{}
+			safe.ModifyPlan(ctx, req, resp)
+			if resp.Diagnostics.HasError() != test.wantError {
+				t.Errorf("ModifyPlan() returned errors %v, want error %t", resp.Diagnostics, test.wantError)
+			}
+		})
+	}
+}
+
+// testOrgsConfig returns a provider configuration with the org dev declared
+// in an orgs block.
+func testOrgsConfig(t *testing.T) (cfg, dev *config.Config) {
+	cfg = &config.Config{OrgName: "acme", Logger: hclog.NewNullLogger()}
+	if err := cfg.LoadOrgs([]interface{}{
+		map[string]interface{}{"name": "dev", "org_name": "acme-dev", "api_token": "token"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dev, _ = cfg.Org("dev")
+	return cfg, dev
+}
+
+type orgMockModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+// orgMockResource reads its values into a model without the org argument,
+// which fails when it gets values with it
+type orgMockResource struct {
+	config *config.Config
+}
+
+func (m *orgMockResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mock"
+}
+
+func (m *orgMockResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = resourceschema.Schema{
+		Attributes: map[string]resourceschema.Attribute{
+			"id":   resourceschema.StringAttribute{Computed: true},
+			"name": resourceschema.StringAttribute{Required: true},
+		},
+	}
+}
+
+func (m *orgMockResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	m.config, _ = req.ProviderData.(*config.Config)
+}
+
+func (m *orgMockResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model orgMockModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	model.ID = types.StringValue(m.config.OrgName)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (m *orgMockResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model orgMockModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	model.ID = types.StringValue(m.config.OrgName)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (m *orgMockResource) Update(context.Context, resource.UpdateRequest, *resource.UpdateResponse) {}
+
+func (m *orgMockResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {}
+
+func (m *orgMockResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.Set(ctx, &orgMockModel{ID: types.StringValue(req.ID), Name: types.StringValue(m.config.OrgName)})...)
+}
+
+// TestSafeResource_Org tests that the org argument selects the configuration
+// of the underlying resource, which gets its values without the argument
+func TestSafeResource_Org(t *testing.T) {
+	ctx := context.Background()
+	cfg, _ := testOrgsConfig(t)
+
+	// resources are wrapped by both the services and the provider
+	safe := NewSafeResource(NewSafeResource(&orgMockResource{}))
+	schemaResp := &resource.SchemaResponse{}
+	safe.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	org, ok := schemaResp.Schema.Attributes[OrgArgument].(resourceschema.StringAttribute)
+	if !ok || !org.Optional || len(org.PlanModifiers) != 1 {
+		t.Fatalf("expected an optional org argument replacing the resource, got %+v", schemaResp.Schema.Attributes[OrgArgument])
+	}
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx)
+	value := func(id, org interface{}) tftypes.Value {
+		return tftypes.NewValue(schemaType, map[string]tftypes.Value{
+			"id":        tftypes.NewValue(tftypes.String, id),
+			"name":      tftypes.NewValue(tftypes.String, "test"),
+			OrgArgument: tftypes.NewValue(tftypes.String, org),
+		})
+	}
+	var got struct {
+		orgMockModel
+		Org types.String `tfsdk:"org"`
+	}
+
+	for org, want := range map[interface{}]string{nil: "acme", "dev": "acme-dev"} {
+		r := NewSafeResource(NewSafeResource(&orgMockResource{}))
+		r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: cfg}, &resource.ConfigureResponse{})
+		plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: value(tftypes.UnknownValue, org)}
+		resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, nil)}}
+		r.Create(ctx, resource.CreateRequest{Plan: plan, Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: value(nil, org)}}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("org %v: %v", org, resp.Diagnostics)
+		}
+		if diags := resp.State.Get(ctx, &got); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if got.ID.ValueString() != want || got.Org.ValueString() != stringOrEmpty(org) {
+			t.Errorf("expected org %v to be created in %s, got %+v", org, want, got)
+		}
+
+		readResp := &resource.ReadResponse{State: resp.State}
+		r.Read(ctx, resource.ReadRequest{State: resp.State}, readResp)
+		if readResp.Diagnostics.HasError() {
+			t.Fatalf("org %v: %v", org, readResp.Diagnostics)
+		}
+		if !readResp.State.Raw.Equal(resp.State.Raw) {
+			t.Errorf("expected the read of org %v not to change the state, got %v", org, readResp.State.Raw)
+		}
+	}
+
+	r := NewSafeResource(&orgMockResource{})
+	r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: cfg}, &resource.ConfigureResponse{})
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: value("acme", "prod")}
+	readResp := &resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, readResp)
+	if !readResp.Diagnostics.HasError() {
+		t.Error("expected an error for an org that isn't declared")
+	}
+
+	for id, want := range map[string][2]string{"dev:00g1": {"00g1", "dev"}, "00g1": {"00g1", ""}, "other:00g1": {"other:00g1", ""}} {
+		r := NewSafeResource(&orgMockResource{})
+		r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: cfg}, &resource.ConfigureResponse{})
+		resp := &resource.ImportStateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, nil)}}
+		r.(resource.ResourceWithImportState).ImportState(ctx, resource.ImportStateRequest{ID: id}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatal(resp.Diagnostics)
+		}
+		if diags := resp.State.Get(ctx, &got); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if got.ID.ValueString() != want[0] || got.Org.ValueString() != want[1] {
+			t.Errorf("expected import ID %q to import %s of org %q, got %+v", id, want[0], want[1], got)
+		}
+	}
+}
+
+func stringOrEmpty(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -32,11 +32,12 @@ func wrapSDKDataSourceWithName(d *schema.Resource, dataSourceName string) *schem
 	return d
 }
 
-// WrapSDKDataSources wraps all SDK data sources in a map with panic recovery.
+// WrapSDKDataSources wraps all SDK data sources in a map with panic recovery
+// and adds the org argument selecting one of the orgs of the provider.
 func WrapSDKDataSources(dataSources map[string]*schema.Resource) map[string]*schema.Resource {
 	wrapped := make(map[string]*schema.Resource, len(dataSources))
 	for name, d := range dataSources {
-		wrapped[name] = wrapSDKDataSourceWithName(d, name)
+		wrapped[name] = wrapSDKDataSourceWithName(withOrgArgument(d, true), name)
 	}
 	return wrapped
 }
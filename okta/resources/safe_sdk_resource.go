@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
 )
 
 // WrapSDKResource wraps a terraform-plugin-sdk/v2 resource with panic recovery.
@@ -53,11 +54,12 @@ func wrapSDKResourceWithName(r *schema.Resource, resourceName string) *schema.Re
 	return r
 }
 
-// WrapSDKResources wraps all SDK resources in a map with panic recovery.
+// WrapSDKResources wraps all SDK resources in a map with panic recovery and
+// adds the org argument selecting one of the orgs of the provider.
 func WrapSDKResources(resources map[string]*schema.Resource) map[string]*schema.Resource {
 	wrapped := make(map[string]*schema.Resource, len(resources))
 	for name, r := range resources {
-		wrapped[name] = wrapSDKResourceWithName(r, name)
+		wrapped[name] = wrapSDKResourceWithName(withOrgArgument(r, false), name)
 	}
 	return wrapped
 }
@@ -98,7 +100,9 @@ func panicRecoveryError(operation, resName string, r interface{}, stackTrace str
 	)
 }
 
-// Context-aware function wrappers (each is a distinct type in the SDK)
+// Context-aware function wrappers (each is a distinct type in the SDK). They
+// also set the rate limit scheduling priority of the API requests made by the
+// wrapped function.
 
 func wrapSDKCreateContextFunc(fn schema.CreateContextFunc, resourceName string) schema.CreateContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) (diagResult diag.Diagnostics) {
@@ -108,7 +112,7 @@ func wrapSDKCreateContextFunc(fn schema.CreateContextFunc, resourceName string)
 				diagResult = resourcePanicRecoveryDiagnostic("Create", resourceName, r, stackTrace)
 			}
 		}()
-		return fn(ctx, d, meta)
+		return fn(apimutex.WithPriority(ctx, apimutex.PriorityMutate), d, meta)
 	}
 }
 
@@ -120,7 +124,7 @@ func wrapSDKReadContextFunc(fn schema.ReadContextFunc, resourceName string) sche
 				diagResult = resourcePanicRecoveryDiagnostic("Read", resourceName, r, stackTrace)
 			}
 		}()
-		return fn(ctx, d, meta)
+		return fn(apimutex.WithPriority(ctx, apimutex.PriorityRefresh), d, meta)
 	}
 }
 
@@ -132,7 +136,7 @@ func wrapSDKUpdateContextFunc(fn schema.UpdateContextFunc, resourceName string)
 				diagResult = resourcePanicRecoveryDiagnostic("Update", resourceName, r, stackTrace)
 			}
 		}()
-		return fn(ctx, d, meta)
+		return fn(apimutex.WithPriority(ctx, apimutex.PriorityMutate), d, meta)
 	}
 }
 
@@ -144,7 +148,7 @@ func wrapSDKDeleteContextFunc(fn schema.DeleteContextFunc, resourceName string)
 				diagResult = resourcePanicRecoveryDiagnostic("Delete", resourceName, r, stackTrace)
 			}
 		}()
-		return fn(ctx, d, meta)
+		return fn(apimutex.WithPriority(ctx, apimutex.PriorityMutate), d, meta)
 	}
 }
 
@@ -5,8 +5,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
 )
 
 // TestWrapSDKResource_CreateContext_PanicRecovery tests that CreateContext panics are recovered
@@ -339,3 +341,60 @@ func TestWrapSDKResource_PreservesDiagnostics(t *testing.T) {
 		t.Errorf("expected summary %q, got %q", expectedDiag.Summary, diags[0].Summary)
 	}
 }
+
+// TestWrapSDKResources_Org tests that the org argument selects the
+// configuration the resource functions get
+func TestWrapSDKResources_Org(t *testing.T) {
+	cfg := &config.Config{OrgName: "acme", Logger: hclog.NewNullLogger()}
+	if err := cfg.LoadOrgs([]interface{}{
+		map[string]interface{}{"name": "dev", "org_name": "acme-dev", "api_token": "token"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dev, _ := cfg.Org("dev")
+
+	var got interface{}
+	wrapped := WrapSDKResources(map[string]*schema.Resource{
+		"okta_resource": {
+			Schema: map[string]*schema.Schema{
+				"name": {Type: schema.TypeString, Optional: true},
+			},
+			ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				got = meta
+				return nil
+			},
+			Importer: &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext},
+		},
+	})["okta_resource"]
+
+	for org, want := range map[string]*config.Config{"": cfg, "dev": dev} {
+		d := wrapped.TestResourceData()
+		_ = d.Set(OrgArgument, org)
+		if diags := wrapped.ReadContext(context.Background(), d, cfg); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if got != want {
+			t.Errorf("expected the read of org %q to get its configuration", org)
+		}
+	}
+
+	d := wrapped.TestResourceData()
+	_ = d.Set(OrgArgument, "prod")
+	if diags := wrapped.ReadContext(context.Background(), d, cfg); !diags.HasError() {
+		t.Error("expected an error for an org that isn't declared")
+	}
+
+	for id, want := range map[string]string{"dev:00g1": "dev", "00g1": "", "other:00g1": ""} {
+		d := wrapped.TestResourceData()
+		d.SetId(id)
+		if _, err := wrapped.Importer.StateContext(context.Background(), d, cfg); err != nil {
+			t.Fatal(err)
+		}
+		if d.Get(OrgArgument).(string) != want {
+			t.Errorf("expected import ID %q to be imported in org %q, got %q", id, want, d.Get(OrgArgument))
+		}
+		if want != "" && d.Id() != "00g1" {
+			t.Errorf("expected the org to be removed from import ID %q, got %q", id, d.Id())
+		}
+	}
+}
@@ -22,8 +22,9 @@ func FWProviderResources() []func() resource.Resource {
 		newEndUserMyRequestsResource,
 		newEntitlementBundleResource,
 	}
-	// Wrap all resources with SafeResource for panic recovery
-	return resources.WrapResources(rawResources)
+	// Wrap all resources with SafeResource for panic recovery, all of them
+	// need Okta Identity Governance
+	return resources.WrapResources(rawResources, config.CapabilityGovernance)
 }
 
 func FWProviderDataSources() []func() datasource.DataSource {
@@ -0,0 +1,139 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	v6okta "github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/stretchr/testify/require"
+)
+
+// testTransport sends the requests to a test server, the v6 client drops the
+// port of the org URL.
+type testTransport struct {
+	host string
+}
+
+func (t *testTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Host = t.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestV6Client returns a v6 client of an API served by handler.
+func newTestV6Client(t *testing.T, handler http.HandlerFunc) *v6okta.APIClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	cfg, err := v6okta.NewConfiguration(
+		v6okta.WithOrgUrl(server.URL),
+		v6okta.WithHttpClientPtr(&http.Client{Transport: &testTransport{host: strings.TrimPrefix(server.URL, "http://")}}),
+		v6okta.WithToken("token"),
+		v6okta.WithTestingDisableHttpsCheck(true),
+		v6okta.WithCache(false),
+		v6okta.WithRateLimitMaxRetries(0),
+	)
+	require.NoError(t, err)
+	return v6okta.NewAPIClient(cfg)
+}
+
+func TestValidateAppOAuthClientSecretWO(t *testing.T) {
+	require.NoError(t, validateAppOAuthClientSecretWO(types.StringNull()))
+	require.NoError(t, validateAppOAuthClientSecretWO(types.StringValue("a-valid-client-secret")))
+	require.ErrorContains(t, validateAppOAuthClientSecretWO(types.StringValue("too-short")), "14 to 100 characters long, got 9")
+	require.ErrorContains(t, validateAppOAuthClientSecretWO(types.StringValue(strings.Repeat("a", 101))), "got 101")
+	require.ErrorContains(t, validateAppOAuthClientSecretWO(types.StringValue("a client secret with spaces")), "must not contain spaces")
+}
+
+func TestRotateAppOAuthClientSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		secretWO    types.String
+		gracePeriod time.Duration
+		createFails bool
+		requests    []string
+		err         string
+	}{
+		{
+			name:        "grace period",
+			secretWO:    types.StringNull(),
+			gracePeriod: time.Hour,
+			requests: []string{
+				"GET /api/v1/apps/0oa1/credentials/secrets/ocs0",
+				"POST /api/v1/apps/0oa1/credentials/secrets/ocs0/lifecycle/deactivate",
+				"DELETE /api/v1/apps/0oa1/credentials/secrets/ocs0",
+				"POST /api/v1/apps/0oa1/credentials/secrets",
+			},
+		},
+		{
+			name:     "no grace period",
+			secretWO: types.StringValue("a-valid-client-secret"),
+			requests: []string{
+				"GET /api/v1/apps/0oa1/credentials/secrets/ocs0",
+				"POST /api/v1/apps/0oa1/credentials/secrets/ocs0/lifecycle/deactivate",
+				"DELETE /api/v1/apps/0oa1/credentials/secrets/ocs0",
+				"GET /api/v1/apps/0oa1/credentials/secrets/ocs1",
+				"POST /api/v1/apps/0oa1/credentials/secrets/ocs1/lifecycle/deactivate",
+				"DELETE /api/v1/apps/0oa1/credentials/secrets/ocs1",
+				"POST /api/v1/apps/0oa1/credentials/secrets",
+			},
+		},
+		{
+			name:     "invalid value",
+			secretWO: types.StringValue("too-short"),
+			err:      "invalid client_secret_wo",
+		},
+		{
+			name:        "creation fails without grace period",
+			secretWO:    types.StringValue("a-rejected-client-secret"),
+			createFails: true,
+			requests: []string{
+				"GET /api/v1/apps/0oa1/credentials/secrets/ocs0",
+				"POST /api/v1/apps/0oa1/credentials/secrets/ocs0/lifecycle/deactivate",
+				"DELETE /api/v1/apps/0oa1/credentials/secrets/ocs0",
+				"GET /api/v1/apps/0oa1/credentials/secrets/ocs1",
+				"POST /api/v1/apps/0oa1/credentials/secrets/ocs1/lifecycle/deactivate",
+				"DELETE /api/v1/apps/0oa1/credentials/secrets/ocs1",
+				"POST /api/v1/apps/0oa1/credentials/secrets",
+			},
+			err: "The current client secret ocs1 was deleted before",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			client := newTestV6Client(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodDelete:
+					w.WriteHeader(http.StatusNoContent)
+					return
+				case r.Method == http.MethodPost && r.URL.Path == "/api/v1/apps/0oa1/credentials/secrets" && tt.createFails:
+					w.WriteHeader(http.StatusBadRequest)
+					_ = json.NewEncoder(w).Encode(map[string]any{"errorCode": "E0000001", "errorSummary": "Api validation failed: client_secret"})
+					return
+				}
+				id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/apps/0oa1/credentials/secrets/"), "/lifecycle/deactivate")
+				if strings.HasSuffix(r.URL.Path, "/credentials/secrets") {
+					id = "ocs2"
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "status": StatusActive})
+			})
+			state := &appOAuthClientSecretModel{ID: types.StringValue("ocs1"), PreviousSecretID: types.StringValue("ocs0")}
+			secret, err := rotateAppOAuthClientSecret(context.Background(), client, "0oa1", state, tt.secretWO, StatusActive, tt.gracePeriod)
+			require.Equal(t, tt.requests, requests)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "ocs2", secret.GetId())
+		})
+	}
+}
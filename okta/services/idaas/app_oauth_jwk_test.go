@@ -0,0 +1,152 @@
+package idaas
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppOAuthJWKFromInput(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	encodePEM := func(blockType string, b []byte) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: b}))
+	}
+	pkixPEM := func(pub any) string {
+		b, err := x509.MarshalPKIXPublicKey(pub)
+		require.NoError(t, err)
+		return encodePEM("PUBLIC KEY", b)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &rsaKey.PublicKey, rsaKey)
+	require.NoError(t, err)
+	marshalJWK := func(key jose.JSONWebKey) string {
+		b, err := key.MarshalJSON()
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	tests := []struct {
+		name         string
+		publicKeyPEM string
+		jwk          string
+		kty          string
+		kid          string
+		use          string
+		alg          string
+		err          string
+	}{
+		{name: "PUBLIC KEY", publicKeyPEM: pkixPEM(&rsaKey.PublicKey), kty: "RSA", use: "sig"},
+		{name: "RSA PUBLIC KEY", publicKeyPEM: encodePEM("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)), kty: "RSA", use: "sig"},
+		{name: "CERTIFICATE", publicKeyPEM: encodePEM("CERTIFICATE", cert), kty: "RSA", use: "sig"},
+		{name: "EC PUBLIC KEY", publicKeyPEM: pkixPEM(&ecKey.PublicKey), kty: "EC", use: "sig"},
+		{
+			name: "EC JWK",
+			jwk:  marshalJWK(jose.JSONWebKey{Key: &ecKey.PublicKey, KeyID: "ec-key", Algorithm: "ES256", Use: "enc"}),
+			kty:  "EC", kid: "ec-key", use: "enc", alg: "ES256",
+		},
+		{name: "private JWK", jwk: marshalJWK(jose.JSONWebKey{Key: rsaKey, KeyID: "private"}), err: "jwk is a private key"},
+		{name: "unsupported key type", publicKeyPEM: pkixPEM(edKey), err: "only RSA and EC public keys are supported"},
+		{name: "unsupported PEM block", publicKeyPEM: encodePEM("PRIVATE KEY", []byte("key")), err: `unsupported PEM block "PRIVATE KEY"`},
+		{name: "not PEM", publicKeyPEM: "key", err: "doesn't contain a PEM block"},
+		{name: "no key", err: "one of public_key_pem and jwk must be set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := appOAuthJWKFromInput(tt.publicKeyPEM, tt.jwk)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, key.IsPublic())
+			require.Equal(t, tt.kty, appOAuthJWKKty(key))
+			require.Equal(t, tt.use, key.Use)
+			require.Equal(t, tt.alg, key.Algorithm)
+			require.Empty(t, key.Certificates)
+			if tt.kid != "" {
+				require.Equal(t, tt.kid, key.KeyID)
+				return
+			}
+			// the RFC 7638 thumbprint
+			require.Len(t, key.KeyID, 43)
+		})
+	}
+}
+
+func TestAppOAuthJWKCheckNotLastActiveKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		authMethod string
+		keys       []appOAuthJWK
+		err        string
+	}{
+		{
+			name:       "other active key",
+			authMethod: "private_key_jwt",
+			keys:       []appOAuthJWK{{ID: "pks1", Status: StatusActive}, {ID: "pks2", Status: StatusActive, Use: "sig"}},
+		},
+		{
+			name:       "last active key",
+			authMethod: "private_key_jwt",
+			keys:       []appOAuthJWK{{ID: "pks1", Status: StatusActive}, {ID: "pks2", Status: StatusInactive}, {ID: "pks3", Status: StatusActive, Use: "enc"}},
+			err:        "key pks1 is the last active signing key of OAuth application 0oa1",
+		},
+		{
+			name:       "client secret",
+			authMethod: "client_secret_basic",
+			keys:       []appOAuthJWK{{ID: "pks1", Status: StatusActive}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body any
+				switch r.URL.Path {
+				case "/api/v1/apps/0oa1":
+					body = map[string]any{"credentials": map[string]any{"oauthClient": map[string]any{"token_endpoint_auth_method": tt.authMethod}}}
+				case "/api/v1/apps/0oa1/credentials/jwks":
+					body = tt.keys
+				default:
+					t.Errorf("unexpected request %s", r.URL)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(body)
+			})
+			r := &appOAuthJWKResource{Config: &config.Config{OktaIDaaSClient: &testIDaaSClient{client: client}}}
+			err := r.checkNotLastActiveKey(context.Background(), "0oa1", "pks1")
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package idaas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppSamlSigningKeys(t *testing.T) {
+	created := func(days int) *time.Time {
+		at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+		return &at
+	}
+	keys := []*sdk.JsonWebKey{
+		{Kid: "active", Created: created(0)},
+		{Kid: "generated", Created: created(10)},
+		// created after the key of the resource, e.g. by the okta_app_saml
+		// resource of the application
+		{Kid: "other", Created: created(20)},
+	}
+	app := sdk.NewSamlApplication()
+	app.Credentials = &sdk.ApplicationCredentials{Signing: &sdk.ApplicationCredentialsSigning{Kid: "active"}}
+
+	tests := []struct {
+		name      string
+		activeKid string
+		nextKeyID string
+		active    string
+		next      string
+	}{
+		{name: "no rotation", activeKid: "active", active: "active"},
+		{name: "generated key", activeKid: "active", nextKeyID: "generated", active: "active", next: "generated"},
+		{name: "generated key gone", activeKid: "active", nextKeyID: "deleted", active: "active"},
+		{name: "generated key active", activeKid: "generated", nextKeyID: "generated", active: "generated"},
+		{name: "no active key", activeKid: "deleted", nextKeyID: "generated", next: "generated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.Credentials.Signing.Kid = tt.activeKid
+			active, next := appSamlSigningKeys(app, keys, tt.nextKeyID)
+			kid := func(key *sdk.JsonWebKey) string {
+				if key == nil {
+					return ""
+				}
+				return key.Kid
+			}
+			require.Equal(t, tt.active, kid(active))
+			require.Equal(t, tt.next, kid(next))
+		})
+	}
+}
+
+func TestAppSamlKeyRotationImport(t *testing.T) {
+	tests := []struct {
+		id        string
+		nextKeyID string
+		err       string
+	}{
+		{id: "0oa1"},
+		{id: "0oa1/kid2", nextKeyID: "kid2"},
+		{id: "0oa1/kid2/kid3", err: "invalid resource import specifier"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			d := resourceAppSamlKeyRotation().Data(nil)
+			d.SetId(tt.id)
+			result, err := appSamlKeyRotationImport(context.Background(), d, nil)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			require.Equal(t, "0oa1", result[0].Id())
+			require.Equal(t, tt.nextKeyID, result[0].Get("next_key_id"))
+			require.Equal(t, 2, result[0].Get("key_years_valid"))
+			require.Equal(t, 60, result[0].Get("rotate_days_before_expiry"))
+			require.Equal(t, 14, result[0].Get("activate_days_after_publish"))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/crc32"
+	"iter"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -87,53 +88,34 @@ func dataSourceGroups() *schema.Resource {
 }
 
 func dataSourceGroupsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := getOktaV5ClientFromMetadata(meta)
-
-	apiRequest := client.GroupAPI.ListGroups(ctx)
-	apiRequest = apiRequest.Limit(int32(utils.DefaultPaginationLimit))
+	search := groupSearch{Limit: int32(utils.DefaultPaginationLimit)}
 	qp := &query.Params{Limit: utils.DefaultPaginationLimit}
 
 	if groupType, ok := d.GetOk("type"); ok {
-		filter := fmt.Sprintf("type eq \"%s\"", groupType.(string))
-		apiRequest = apiRequest.Filter(filter)
-		qp.Filter = filter
+		search.Type = groupType.(string)
+		qp.Filter = search.filter()
 	}
 
 	if limit, ok := d.GetOk("limit"); ok {
 		// override default page_size if user specified a custom limit value
-		apiRequest = apiRequest.Limit(int32(limit.(int)))
+		search.Limit = int32(limit.(int))
 	}
 
 	if q, ok := d.GetOk("q"); ok {
 		qp.Limit = 10000 // keeping this here to avoid potentially changing datasource ID generation behavior
-		apiRequest = apiRequest.Q(q.(string))
+		search.Q = q.(string)
 		qp.Q = q.(string)
 	}
 
-	if search, ok := d.GetOk("search"); ok {
-		apiRequest = apiRequest.Search(search.(string))
-		qp.Search = search.(string)
+	if s, ok := d.GetOk("search"); ok {
+		search.Search = s.(string)
+		qp.Search = s.(string)
 	}
 
-	okta_groups, resp, err := apiRequest.Execute()
+	okta_groups, err := searchGroups(ctx, getOktaV5ClientFromMetadata(meta), search)
 	if err != nil {
 		d.SetId("")
-		return diag.Errorf("failed to list groups: %v", err)
-	}
-
-	// handle pagination
-	for {
-		if !resp.HasNextPage() {
-			break
-		}
-		var moreGroups []v5okta.Group
-		var err error
-		resp, err = resp.Next(&moreGroups)
-		if err != nil {
-			return diag.Errorf("failed to get next page of groups: %v", err)
-		}
-		okta_groups = append(okta_groups, moreGroups...)
-
+		return diag.FromErr(err)
 	}
 
 	// generate a unique ID for the data source based on the query parameters
@@ -169,3 +151,67 @@ func dataSourceGroupsRead(ctx context.Context, d *schema.ResourceData, meta inte
 	_ = d.Set("groups", arr)
 	return nil
 }
+
+// groupSearch holds the parameters of a search of the org's groups.
+type groupSearch struct {
+	Q      string
+	Search string
+	Type   string
+	Limit  int32
+}
+
+func (s groupSearch) filter() string {
+	if s.Type == "" {
+		return ""
+	}
+	return fmt.Sprintf("type eq \"%s\"", s.Type)
+}
+
+// searchGroups returns all of the groups matching the search, Limit being
+// the page size.
+func searchGroups(ctx context.Context, client *v5okta.APIClient, s groupSearch) ([]v5okta.Group, error) {
+	var groups []v5okta.Group
+	for page, err := range searchGroupPages(ctx, client, s) {
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, page...)
+	}
+	return groups, nil
+}
+
+// searchGroupPages yields the groups matching s a page at a time. The next
+// page is only fetched when the previous one was consumed.
+func searchGroupPages(ctx context.Context, client *v5okta.APIClient, s groupSearch) iter.Seq2[[]v5okta.Group, error] {
+	return func(yield func([]v5okta.Group, error) bool) {
+		apiRequest := client.GroupAPI.ListGroups(ctx).Limit(s.Limit)
+		if filter := s.filter(); filter != "" {
+			apiRequest = apiRequest.Filter(filter)
+		}
+		if s.Q != "" {
+			apiRequest = apiRequest.Q(s.Q)
+		}
+		if s.Search != "" {
+			apiRequest = apiRequest.Search(s.Search)
+		}
+		groups, resp, err := apiRequest.Execute()
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to list groups: %v", err))
+			return
+		}
+		if !yield(groups, nil) {
+			return
+		}
+		for resp.HasNextPage() {
+			var moreGroups []v5okta.Group
+			resp, err = resp.Next(&moreGroups)
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to get next page of groups: %v", err))
+				return
+			}
+			if !yield(moreGroups, nil) {
+				return
+			}
+		}
+	}
+}
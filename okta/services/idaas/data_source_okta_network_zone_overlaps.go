@@ -0,0 +1,200 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/internal/iprange"
+)
+
+var (
+	_ datasource.DataSource              = &networkZoneOverlapsDataSource{}
+	_ datasource.DataSourceWithConfigure = &networkZoneOverlapsDataSource{}
+)
+
+func newNetworkZoneOverlapsDataSource() datasource.DataSource {
+	return &networkZoneOverlapsDataSource{}
+}
+
+type networkZoneOverlapsDataSource struct {
+	*config.Config
+}
+
+type networkZoneOverlapsDataSourceModel struct {
+	ID               types.String              `tfsdk:"id"`
+	ZoneIDs          []types.String            `tfsdk:"zone_ids"`
+	Overlaps         []networkZoneOverlapModel `tfsdk:"overlaps"`
+	Ranges           []types.String            `tfsdk:"ranges"`
+	IPv4AddressCount types.Int64               `tfsdk:"ipv4_address_count"`
+	IPv6AddressCount types.String              `tfsdk:"ipv6_address_count"`
+}
+
+type networkZoneOverlapModel struct {
+	ZoneID       types.String `tfsdk:"zone_id"`
+	ZoneName     types.String `tfsdk:"zone_name"`
+	Gateway      types.String `tfsdk:"gateway"`
+	OtherZoneID  types.String `tfsdk:"other_zone_id"`
+	OtherZone    types.String `tfsdk:"other_zone_name"`
+	OtherGateway types.String `tfsdk:"other_gateway"`
+	Addresses    types.String `tfsdk:"addresses"`
+}
+
+// networkZoneGateways are the gateways of an IP zone, parsed.
+type networkZoneGateways struct {
+	id       string
+	name     string
+	gateways []string
+	ranges   []iprange.Range
+}
+
+func (d *networkZoneOverlapsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_zone_overlaps"
+}
+
+func (d *networkZoneOverlapsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports the gateways that IP network zones have in common, and the addresses the zones cover together.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The IDs of the zones, comma separated.",
+			},
+			"zone_ids": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "IDs of the IP network zones to analyze.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"overlaps": schema.ListAttribute{
+				Computed:    true,
+				Description: "The gateways of different zones that have addresses in common.",
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"zone_id":         types.StringType,
+						"zone_name":       types.StringType,
+						"gateway":         types.StringType,
+						"other_zone_id":   types.StringType,
+						"other_zone_name": types.StringType,
+						"other_gateway":   types.StringType,
+						"addresses":       types.StringType,
+					},
+				},
+			},
+			"ranges": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The addresses covered by the gateways of the zones, as sorted CIDRs or ranges with overlapping and adjacent gateways merged.",
+			},
+			"ipv4_address_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of distinct IPv4 addresses covered by the gateways of the zones.",
+			},
+			"ipv6_address_count": schema.StringAttribute{
+				Computed:    true,
+				Description: "Number of distinct IPv6 addresses covered by the gateways of the zones, in decimal. It is a string as it can exceed the range of a number.",
+			},
+		},
+	}
+}
+
+func (d *networkZoneOverlapsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.Config = dataSourceConfiguration(req, resp)
+}
+
+func (d *networkZoneOverlapsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state networkZoneOverlapsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ids []string
+	for _, id := range state.ZoneIDs {
+		ids = append(ids, id.ValueString())
+	}
+	slices.Sort(ids)
+	zones := make([]networkZoneGateways, 0, len(ids))
+	for _, id := range ids {
+		zone, err := d.getNetworkZoneGateways(ctx, id)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to read Okta Network Zone", err.Error())
+			return
+		}
+		zones = append(zones, zone)
+	}
+
+	state.ID = types.StringValue(strings.Join(ids, ","))
+	state.Overlaps = networkZoneOverlaps(zones)
+	var all []iprange.Range
+	for _, zone := range zones {
+		all = append(all, zone.ranges...)
+	}
+	state.Ranges = []types.String{}
+	for _, r := range iprange.Merge(all) {
+		state.Ranges = append(state.Ranges, types.StringValue(r.String()))
+	}
+	ipv4, ipv6 := iprange.Coverage(all)
+	state.IPv4AddressCount = types.Int64Value(ipv4.Int64())
+	state.IPv6AddressCount = types.StringValue(ipv6.String())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// networkZoneOverlaps returns the addresses the gateways of each pair of zones
+// have in common. The gateways of a zone overlapping each other aren't
+// reported.
+func networkZoneOverlaps(zones []networkZoneGateways) []networkZoneOverlapModel {
+	overlaps := []networkZoneOverlapModel{}
+	for i, zone := range zones {
+		for _, other := range zones[i+1:] {
+			for j, r := range zone.ranges {
+				for k, o := range other.ranges {
+					overlap, ok := r.Intersect(o)
+					if !ok {
+						continue
+					}
+					overlaps = append(overlaps, networkZoneOverlapModel{
+						ZoneID:       types.StringValue(zone.id),
+						ZoneName:     types.StringValue(zone.name),
+						Gateway:      types.StringValue(zone.gateways[j]),
+						OtherZoneID:  types.StringValue(other.id),
+						OtherZone:    types.StringValue(other.name),
+						OtherGateway: types.StringValue(other.gateways[k]),
+						Addresses:    types.StringValue(overlap.String()),
+					})
+				}
+			}
+		}
+	}
+	return overlaps
+}
+
+func (d *networkZoneOverlapsDataSource) getNetworkZoneGateways(ctx context.Context, id string) (networkZoneGateways, error) {
+	zone, _, err := d.OktaIDaaSClient.OktaSDKClientV6().NetworkZoneAPI.GetNetworkZone(ctx, id).Execute()
+	if err != nil {
+		return networkZoneGateways{}, fmt.Errorf("failed to get network zone %s: %v", id, err)
+	}
+	if zone.IPNetworkZone == nil {
+		return networkZoneGateways{}, fmt.Errorf("network zone %s is not an IP zone, only IP zones have gateways", id)
+	}
+	result := networkZoneGateways{id: id, name: zone.IPNetworkZone.GetName()}
+	for _, gateway := range zone.IPNetworkZone.GetGateways() {
+		r, err := iprange.Parse(gateway.GetValue())
+		if err != nil {
+			return networkZoneGateways{}, fmt.Errorf("failed to parse gateway of network zone %s: %v", id, err)
+		}
+		result.gateways = append(result.gateways, gateway.GetValue())
+		result.ranges = append(result.ranges, r)
+	}
+	return result, nil
+}
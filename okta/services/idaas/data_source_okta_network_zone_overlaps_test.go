@@ -0,0 +1,40 @@
+package idaas_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+func TestAccDataSourceOktaNetworkZoneOverlaps_read(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the test checks the gateways as Okta stores them, a replay only
+		// checks the recorded responses, run it against an org.
+		// TestNetworkZoneOverlaps covers the detection itself
+		return
+	}
+	mgr := newFixtureManager("data-sources", resources.OktaIDaaSNetworkZoneOverlaps, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+	dataSourceName := "data.okta_network_zone_overlaps.test"
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "overlaps.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "overlaps.0.addresses", "10.0.255.0/24"),
+					resource.TestCheckResourceAttr(dataSourceName, "ranges.#", "3"),
+					resource.TestCheckResourceAttr(dataSourceName, "ranges.0", "10.0.0.0-10.1.0.255"),
+					resource.TestCheckResourceAttr(dataSourceName, "ipv4_address_count", "65793"),
+					resource.TestCheckResourceAttr(dataSourceName, "ipv6_address_count", "1208925819614629174706176"),
+				),
+			},
+		},
+	})
+}
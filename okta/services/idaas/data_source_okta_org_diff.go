@@ -0,0 +1,748 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	schema_sdk "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+var (
+	_ datasource.DataSource              = &orgDiffDataSource{}
+	_ datasource.DataSourceWithConfigure = &orgDiffDataSource{}
+)
+
+// The object types the org diff compares.
+const (
+	orgDiffPolicies                  = "policies"
+	orgDiffPolicyRules               = "policy_rules"
+	orgDiffAuthorizationServers      = "authorization_servers"
+	orgDiffAuthorizationServerClaims = "authorization_server_claims"
+	orgDiffAuthorizationServerScopes = "authorization_server_scopes"
+	orgDiffNetworkZones              = "network_zones"
+	orgDiffApps                      = "apps"
+	orgDiffGroupRules                = "group_rules"
+)
+
+var orgDiffObjectTypes = []string{
+	orgDiffPolicies,
+	orgDiffPolicyRules,
+	orgDiffAuthorizationServers,
+	orgDiffAuthorizationServerClaims,
+	orgDiffAuthorizationServerScopes,
+	orgDiffNetworkZones,
+	orgDiffApps,
+	orgDiffGroupRules,
+}
+
+// orgDiffPolicyTypes are the types of the policies and policy rules compared.
+// The types an org doesn't support are skipped.
+var orgDiffPolicyTypes = []string{
+	"OKTA_SIGN_ON",
+	"PASSWORD",
+	"MFA_ENROLL",
+	"IDP_DISCOVERY",
+	"ACCESS_POLICY",
+	"PROFILE_ENROLLMENT",
+}
+
+// orgDiffIgnoredKeys are the properties of the objects that differ between
+// orgs whatever their configuration.
+var orgDiffIgnoredKeys = []string{
+	"_embedded",
+	"_links",
+	"created",
+	"id",
+	"kid",
+	"lastMembershipUpdated",
+	"lastUpdated",
+}
+
+// oktaIDRegexp matches the IDs of Okta objects, which are 20 letters and
+// digits, e.g. 00g1abcdefghijklmno7.
+var oktaIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9]*[0-9][a-zA-Z0-9]*$`)
+
+// orgDiffRedactedID replaces the IDs in the compared objects.
+const orgDiffRedactedID = "<id>"
+
+func newOrgDiffDataSource() datasource.DataSource {
+	return &orgDiffDataSource{}
+}
+
+type orgDiffDataSource struct {
+	*config.Config
+}
+
+type orgDiffDataSourceModel struct {
+	ID          types.String             `tfsdk:"id"`
+	SourceOrg   types.String             `tfsdk:"source_org"`
+	TargetOrg   types.String             `tfsdk:"target_org"`
+	ObjectTypes []types.String           `tfsdk:"object_types"`
+	Identical   types.Bool               `tfsdk:"identical"`
+	Differences []orgDiffDifferenceModel `tfsdk:"differences"`
+}
+
+type orgDiffDifferenceModel struct {
+	ObjectType types.String   `tfsdk:"object_type"`
+	Name       types.String   `tfsdk:"name"`
+	Change     types.String   `tfsdk:"change"`
+	Attributes []types.String `tfsdk:"attributes"`
+	Source     types.String   `tfsdk:"source"`
+	Target     types.String   `tfsdk:"target"`
+}
+
+func (d *orgDiffDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_org_diff"
+}
+
+func (d *orgDiffDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Compares the configuration of two orgs of the provider, e.g. before promoting the changes of a preview org to production. " +
+			"Objects are matched by name, and compared by the arguments of the resources managing them, with the IDs of the objects they reference resolved to names.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The compared orgs.",
+			},
+			"source_org": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the org of the provider's `orgs` blocks to compare, usually the one changes are promoted from. Defaults to the org of the provider.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"target_org": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the org of the provider's `orgs` blocks to compare the source org with. Defaults to the org of the provider.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"object_types": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: fmt.Sprintf("Types of the objects to compare, defaults to all of them: `%s`. Apps are matched by label, claims and scopes by authorization server and name, policy rules by policy and name.", strings.Join(orgDiffObjectTypes, "`, `")),
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(orgDiffObjectTypes...)),
+				},
+			},
+			"identical": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the objects of the orgs are the same.",
+			},
+			"differences": schema.ListAttribute{
+				Computed: true,
+				Description: "The objects that differ between the orgs, sorted by type and name. `change` is `only_in_source`, `only_in_target` or `changed`, " +
+					"`attributes` are the paths of the values that changed, and `source` and `target` are the compared arguments as JSON.",
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"object_type": types.StringType,
+						"name":        types.StringType,
+						"change":      types.StringType,
+						"attributes":  types.ListType{ElemType: types.StringType},
+						"source":      types.StringType,
+						"target":      types.StringType,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *orgDiffDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.Config = dataSourceConfiguration(req, resp)
+}
+
+func (d *orgDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state orgDiffDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	objectTypes := orgDiffObjectTypes
+	if len(state.ObjectTypes) > 0 {
+		objectTypes = nil
+		for _, objectType := range state.ObjectTypes {
+			objectTypes = append(objectTypes, objectType.ValueString())
+		}
+	}
+	source, err := d.Config.Org(state.SourceOrg.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid source_org", err.Error())
+		return
+	}
+	target, err := d.Config.Org(state.TargetOrg.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid target_org", err.Error())
+		return
+	}
+	sourceObjects, err := readOrgDiffObjects(ctx, source, objectTypes)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read the objects of the source org", err.Error())
+		return
+	}
+	targetObjects, err := readOrgDiffObjects(ctx, target, objectTypes)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read the objects of the target org", err.Error())
+		return
+	}
+
+	state.ID = types.StringValue(fmt.Sprintf("%s..%s", orgDiffName(state.SourceOrg), orgDiffName(state.TargetOrg)))
+	state.Differences = []orgDiffDifferenceModel{}
+	for _, objectType := range orgDiffObjectTypes {
+		if !slices.Contains(objectTypes, objectType) {
+			continue
+		}
+		differences, err := diffOrgObjects(objectType, sourceObjects[objectType], targetObjects[objectType])
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to compare the objects of the orgs", err.Error())
+			return
+		}
+		state.Differences = append(state.Differences, differences...)
+	}
+	state.Identical = types.BoolValue(len(state.Differences) == 0)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func orgDiffName(org types.String) string {
+	if org.ValueString() == "" {
+		return "provider"
+	}
+	return org.ValueString()
+}
+
+// diffOrgObjects returns the differences between the objects of a type of two
+// orgs, keyed by name.
+func diffOrgObjects(objectType string, source, target map[string]interface{}) ([]orgDiffDifferenceModel, error) {
+	names := make([]string, 0, len(source)+len(target))
+	for name := range source {
+		names = append(names, name)
+	}
+	for name := range target {
+		if _, ok := source[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var differences []orgDiffDifferenceModel
+	for _, name := range names {
+		s, inSource := source[name]
+		t, inTarget := target[name]
+		difference := orgDiffDifferenceModel{
+			ObjectType: types.StringValue(objectType),
+			Name:       types.StringValue(name),
+			Attributes: []types.String{},
+			Source:     types.StringValue(""),
+			Target:     types.StringValue(""),
+		}
+		if inSource {
+			b, err := marshalOrgDiffObject(s)
+			if err != nil {
+				return nil, err
+			}
+			difference.Source = types.StringValue(b)
+		}
+		if inTarget {
+			b, err := marshalOrgDiffObject(t)
+			if err != nil {
+				return nil, err
+			}
+			difference.Target = types.StringValue(b)
+		}
+		switch {
+		case !inTarget:
+			difference.Change = types.StringValue("only_in_source")
+		case !inSource:
+			difference.Change = types.StringValue("only_in_target")
+		default:
+			attributes := changedAttributes(s, t)
+			if len(attributes) == 0 {
+				continue
+			}
+			difference.Change = types.StringValue("changed")
+			for _, attribute := range attributes {
+				difference.Attributes = append(difference.Attributes, types.StringValue(attribute))
+			}
+		}
+		differences = append(differences, difference)
+	}
+	return differences, nil
+}
+
+// marshalOrgDiffObject returns the JSON of an object, without escaping the
+// redacted IDs.
+func marshalOrgDiffObject(v interface{}) (string, error) {
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// changedAttributes returns the paths of the values that differ between two
+// objects, e.g. "conditions.network.include[0]".
+func changedAttributes(source, target interface{}) []string {
+	s, t := map[string]string{}, map[string]string{}
+	flattenOrgDiffObject("", source, s)
+	flattenOrgDiffObject("", target, t)
+	var paths []string
+	for path, value := range s {
+		if other, ok := t[path]; !ok || other != value {
+			paths = append(paths, path)
+		}
+	}
+	for path := range t {
+		if _, ok := s[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func flattenOrgDiffObject(path string, v interface{}, out map[string]string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			out[path] = "{}"
+		}
+		for k, value := range v {
+			if path == "" {
+				flattenOrgDiffObject(k, value, out)
+			} else {
+				flattenOrgDiffObject(path+"."+k, value, out)
+			}
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			out[path] = "[]"
+		}
+		for i, value := range v {
+			flattenOrgDiffObject(fmt.Sprintf("%s[%d]", path, i), value, out)
+		}
+	default:
+		out[path], _ = marshalOrgDiffObject(v)
+	}
+}
+
+// orgDiffObject is an object of an org to compare.
+type orgDiffObject struct {
+	id string
+	// resource reads the object, nil when no resource of the provider manages
+	// objects of its kind, which are then compared as listed
+	resource func() *schema_sdk.Resource
+	// arguments are set before reading the object, e.g. the policy of a rule
+	arguments map[string]string
+	listed    map[string]interface{}
+}
+
+// orgDiffSet is the value of a set argument, whose elements are sorted once
+// the IDs they reference are resolved.
+type orgDiffSet []interface{}
+
+// readOrgDiffObjects returns the normalized objects of the types of an org,
+// by type and name.
+func readOrgDiffObjects(ctx context.Context, cfg *config.Config, objectTypes []string) (map[string]map[string]interface{}, error) {
+	objects, names, err := listOrgDiffObjects(ctx, cfg, objectTypes)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]map[string]interface{}{}
+	for objectType, byName := range objects {
+		result[objectType] = map[string]interface{}{}
+		for name, object := range byName {
+			v, ok, err := readOrgDiffObject(ctx, cfg, object)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s %s: %v", strings.ReplaceAll(objectType, "_", " "), name, err)
+			}
+			if ok {
+				result[objectType][name] = normalizeOrgDiffObject(v, names)
+			}
+		}
+	}
+	return result, nil
+}
+
+// readOrgDiffObject returns the arguments of an object as read by its
+// resource, false when the object is gone.
+func readOrgDiffObject(ctx context.Context, cfg *config.Config, object *orgDiffObject) (interface{}, bool, error) {
+	if object.resource == nil {
+		return object.listed, true, nil
+	}
+	res := object.resource()
+	d := res.Data(nil)
+	d.SetId(object.id)
+	for k, v := range object.arguments {
+		if err := d.Set(k, v); err != nil {
+			return nil, false, err
+		}
+	}
+	for _, readDiag := range res.ReadContext(ctx, d, cfg) {
+		if readDiag.Severity == diag.Error {
+			return nil, false, fmt.Errorf("%s %s", readDiag.Summary, readDiag.Detail)
+		}
+	}
+	if d.Id() == "" {
+		return nil, false, nil
+	}
+	return orgDiffArguments(res.SchemaMap(), d.Get), true, nil
+}
+
+// orgDiffArguments returns the values of the arguments of a schema. The
+// computed attributes that can't be configured and the sensitive ones are left
+// out.
+func orgDiffArguments(s map[string]*schema_sdk.Schema, get func(string) interface{}) map[string]interface{} {
+	arguments := map[string]interface{}{}
+	for k, attribute := range s {
+		if k == "id" || attribute.Sensitive || (!attribute.Optional && !attribute.Required) {
+			continue
+		}
+		arguments[k] = orgDiffValue(attribute, get(k))
+	}
+	return arguments
+}
+
+func orgDiffValue(attribute *schema_sdk.Schema, v interface{}) interface{} {
+	switch v := v.(type) {
+	case *schema_sdk.Set:
+		return orgDiffSet(orgDiffList(attribute, v.List()))
+	case []interface{}:
+		return orgDiffList(attribute, v)
+	default:
+		return v
+	}
+}
+
+func orgDiffList(attribute *schema_sdk.Schema, v []interface{}) []interface{} {
+	block, _ := attribute.Elem.(*schema_sdk.Resource)
+	values := make([]interface{}, len(v))
+	for i, value := range v {
+		m, ok := value.(map[string]interface{})
+		if !ok || block == nil {
+			values[i] = value
+			continue
+		}
+		values[i] = orgDiffArguments(block.SchemaMap(), func(k string) interface{} { return m[k] })
+	}
+	return values
+}
+
+// normalizeOrgDiffObject removes the properties of an object that differ
+// between orgs whatever their configuration, and replaces the IDs of the
+// objects it references with their names in the org, e.g. "groups:Admins".
+// names are the names of the objects of the org by ID, the IDs of other
+// objects are replaced with <id>.
+func normalizeOrgDiffObject(v interface{}, names map[string]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			if slices.Contains(orgDiffIgnoredKeys, k) {
+				continue
+			}
+			normalized[k] = normalizeOrgDiffObject(value, names)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, value := range v {
+			normalized[i] = normalizeOrgDiffObject(value, names)
+		}
+		return normalized
+	case orgDiffSet:
+		normalized := make([]interface{}, len(v))
+		keys := make(map[int]string, len(v))
+		for i, value := range v {
+			normalized[i] = normalizeOrgDiffObject(value, names)
+			keys[i], _ = marshalOrgDiffObject(normalized[i])
+		}
+		order := make([]int, len(v))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return keys[order[i]] < keys[order[j]] })
+		sorted := make([]interface{}, len(v))
+		for i, j := range order {
+			sorted[i] = normalized[j]
+		}
+		return sorted
+	case string:
+		if len(v) != 20 || !oktaIDRegexp.MatchString(v) {
+			return v
+		}
+		if name, ok := names[v]; ok {
+			return name
+		}
+		return orgDiffRedactedID
+	default:
+		return v
+	}
+}
+
+// listOrgDiffObjects returns the objects of the types of an org by type and
+// name, and the names of the objects listed, the groups included, by ID.
+func listOrgDiffObjects(ctx context.Context, cfg *config.Config, objectTypes []string) (map[string]map[string]*orgDiffObject, map[string]string, error) {
+	result := map[string]map[string]*orgDiffObject{}
+	names := map[string]string{}
+	add := func(objectType, name string, object *orgDiffObject) {
+		objects, ok := result[objectType]
+		if !ok {
+			objects = map[string]*orgDiffObject{}
+			result[objectType] = objects
+		}
+		key := name
+		for i := 2; ; i++ {
+			if _, ok := objects[key]; !ok {
+				break
+			}
+			key = fmt.Sprintf("%s (%d)", name, i)
+		}
+		objects[key] = object
+		names[object.id] = objectType + ":" + key
+	}
+	wants := func(objectTypes []string, wanted ...string) bool {
+		for _, objectType := range wanted {
+			if slices.Contains(objectTypes, objectType) {
+				return true
+			}
+		}
+		return false
+	}
+
+	groups, err := listOrgDiffJSON(ctx, cfg, "/api/v1/groups"+(&query.Params{Limit: utils.DefaultPaginationLimit}).String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list groups: %v", err)
+	}
+	for _, group := range groups {
+		profile, _ := group["profile"].(map[string]interface{})
+		names[fmt.Sprintf("%v", group["id"])] = fmt.Sprintf("groups:%v", profile["name"])
+	}
+
+	if wants(objectTypes, orgDiffPolicies, orgDiffPolicyRules) {
+		for _, policyType := range orgDiffPolicyTypes {
+			policies, err := listOrgDiffJSON(ctx, cfg, "/api/v1/policies"+(&query.Params{Type: policyType}).String())
+			if isUnsupportedPolicyType(err) {
+				continue
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to list %s policies: %v", policyType, err)
+			}
+			for _, policy := range policies {
+				policyID := fmt.Sprintf("%v", policy["id"])
+				policyName := fmt.Sprintf("%s/%v", policyType, policy["name"])
+				add(orgDiffPolicies, policyName, &orgDiffObject{id: policyID, resource: orgDiffPolicyResource(policyType), listed: policy})
+				if !slices.Contains(objectTypes, orgDiffPolicyRules) {
+					continue
+				}
+				rules, err := listOrgDiffJSON(ctx, cfg, fmt.Sprintf("/api/v1/policies/%s/rules", policyID))
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to list the rules of policy %s: %v", policyName, err)
+				}
+				for _, rule := range rules {
+					add(orgDiffPolicyRules, fmt.Sprintf("%s/%v", policyName, rule["name"]), &orgDiffObject{
+						id:        fmt.Sprintf("%v", rule["id"]),
+						resource:  orgDiffPolicyRuleResource(policyType),
+						arguments: map[string]string{"policy_id": policyID},
+						listed:    rule,
+					})
+				}
+			}
+		}
+	}
+
+	if wants(objectTypes, orgDiffAuthorizationServers, orgDiffAuthorizationServerClaims, orgDiffAuthorizationServerScopes) {
+		servers, err := listOrgDiffJSON(ctx, cfg, "/api/v1/authorizationServers"+(&query.Params{Limit: utils.DefaultPaginationLimit}).String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list authorization servers: %v", err)
+		}
+		for _, server := range servers {
+			serverID := fmt.Sprintf("%v", server["id"])
+			serverName := fmt.Sprintf("%v", server["name"])
+			add(orgDiffAuthorizationServers, serverName, &orgDiffObject{id: serverID, resource: resourceAuthServer, listed: server})
+			if slices.Contains(objectTypes, orgDiffAuthorizationServerClaims) {
+				claims, err := listOrgDiffJSON(ctx, cfg, fmt.Sprintf("/api/v1/authorizationServers/%s/claims", serverID))
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to list the claims of authorization server %s: %v", serverName, err)
+				}
+				for _, claim := range claims {
+					add(orgDiffAuthorizationServerClaims, fmt.Sprintf("%s/%v/%v", serverName, claim["claimType"], claim["name"]), &orgDiffObject{
+						id:        fmt.Sprintf("%v", claim["id"]),
+						resource:  resourceAuthServerClaim,
+						arguments: map[string]string{"auth_server_id": serverID},
+						listed:    claim,
+					})
+				}
+			}
+			if slices.Contains(objectTypes, orgDiffAuthorizationServerScopes) {
+				scopes, err := listOrgDiffJSON(ctx, cfg, fmt.Sprintf("/api/v1/authorizationServers/%s/scopes", serverID))
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to list the scopes of authorization server %s: %v", serverName, err)
+				}
+				for _, scope := range scopes {
+					add(orgDiffAuthorizationServerScopes, fmt.Sprintf("%s/%v", serverName, scope["name"]), &orgDiffObject{
+						id:        fmt.Sprintf("%v", scope["id"]),
+						resource:  resourceAuthServerScope,
+						arguments: map[string]string{"auth_server_id": serverID},
+						listed:    scope,
+					})
+				}
+			}
+		}
+	}
+
+	for _, list := range []struct {
+		objectType string
+		path       string
+		name       string
+		resource   func(map[string]interface{}) func() *schema_sdk.Resource
+	}{
+		{orgDiffNetworkZones, "/api/v1/zones", "name", func(map[string]interface{}) func() *schema_sdk.Resource { return resourceNetworkZone }},
+		{orgDiffApps, "/api/v1/apps", "label", orgDiffAppResource},
+		{orgDiffGroupRules, "/api/v1/groups/rules", "name", func(map[string]interface{}) func() *schema_sdk.Resource { return resourceGroupRule }},
+	} {
+		if !slices.Contains(objectTypes, list.objectType) {
+			continue
+		}
+		objects, err := listOrgDiffJSON(ctx, cfg, list.path+(&query.Params{Limit: utils.DefaultPaginationLimit}).String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list %s: %v", strings.ReplaceAll(list.objectType, "_", " "), err)
+		}
+		for _, object := range objects {
+			add(list.objectType, fmt.Sprintf("%v", object[list.name]), &orgDiffObject{
+				id:       fmt.Sprintf("%v", object["id"]),
+				resource: list.resource(object),
+				listed:   object,
+			})
+		}
+	}
+
+	// the policies are listed for their rules, and the authorization servers
+	// for their claims and scopes, their names resolve the IDs all the same
+	for objectType := range result {
+		if !slices.Contains(objectTypes, objectType) {
+			delete(result, objectType)
+		}
+	}
+	return result, names, nil
+}
+
+// orgDiffPolicyResource returns the resource of the policies of a type, nil
+// for the types the resources of the provider don't manage.
+func orgDiffPolicyResource(policyType string) func() *schema_sdk.Resource {
+	switch policyType {
+	case sdk.SignOnPolicyType:
+		return resourcePolicySignOn
+	case sdk.PasswordPolicyType:
+		return resourcePolicyPassword
+	case sdk.MfaPolicyType:
+		return resourcePolicyMfa
+	case sdk.ProfileEnrollmentPolicyType:
+		return resourcePolicyProfileEnrollment
+	default:
+		return nil
+	}
+}
+
+// orgDiffPolicyRuleResource returns the resource of the rules of the policies
+// of a type.
+func orgDiffPolicyRuleResource(policyType string) func() *schema_sdk.Resource {
+	switch policyType {
+	case sdk.SignOnPolicyType:
+		return resourcePolicySignOnRule
+	case sdk.PasswordPolicyType:
+		return resourcePolicyPasswordRule
+	case sdk.MfaPolicyType:
+		return resourcePolicyMfaRule
+	case sdk.IdpDiscoveryType:
+		return resourcePolicyRuleIdpDiscovery
+	case sdk.AccessPolicyType:
+		return resourceAppSignOnPolicyRule
+	case sdk.ProfileEnrollmentPolicyType:
+		return resourcePolicyProfileEnrollmentRule
+	default:
+		return nil
+	}
+}
+
+// orgDiffAppResource returns the resource of an app by its sign on mode, nil
+// for the modes the resources of the provider don't manage.
+func orgDiffAppResource(app map[string]interface{}) func() *schema_sdk.Resource {
+	switch app["signOnMode"] {
+	case "OPENID_CONNECT":
+		return resourceAppOAuth
+	case "SAML_2_0", "SAML_1_1":
+		return resourceAppSaml
+	case "AUTO_LOGIN":
+		return resourceAppAutoLogin
+	case "BASIC_AUTH":
+		return resourceAppBasicAuth
+	case "BOOKMARK":
+		return resourceAppBookmark
+	case "SECURE_PASSWORD_STORE":
+		return resourceAppSecurePasswordStore
+	case "BROWSER_PLUGIN":
+		if app["name"] == "template_swa3field" {
+			return resourceAppThreeField
+		}
+		return resourceAppSwa
+	default:
+		return nil
+	}
+}
+
+// listOrgDiffJSON returns all of the pages of the objects of a list endpoint.
+func listOrgDiffJSON(ctx context.Context, cfg *config.Config, path string) ([]map[string]interface{}, error) {
+	re := cfg.OktaIDaaSClient.OktaSDKClientV2().CloneRequestExecutor()
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]interface{}
+	resp, err := re.Do(ctx, req, &objects)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var page []map[string]interface{}
+		if resp, err = resp.Next(ctx, &page); err != nil {
+			return nil, err
+		}
+		objects = append(objects, page...)
+	}
+	return objects, nil
+}
+
+// isUnsupportedPolicyType reports whether listing policies failed because the
+// org doesn't support the policy type, e.g. ACCESS_POLICY in Classic orgs.
+func isUnsupportedPolicyType(err error) bool {
+	var oktaErr *sdk.Error
+	if !errors.As(err, &oktaErr) {
+		return false
+	}
+	// invalid request or feature not enabled
+	return oktaErr.ErrorCode == "E0000001" || oktaErr.ErrorCode == "E0000015"
+}
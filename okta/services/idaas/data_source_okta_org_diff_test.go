@@ -0,0 +1,32 @@
+package idaas_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+func TestAccDataSourceOktaOrgDiff_read(t *testing.T) {
+	mgr := newFixtureManager("data-sources", resources.OktaIDaaSOrgDiff, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+	dataSourceName := "data.okta_org_diff.test"
+
+	// the org of the provider compared with itself
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "provider..provider"),
+					resource.TestCheckResourceAttr(dataSourceName, "identical", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "differences.#", "0"),
+				),
+			},
+		},
+	})
+}
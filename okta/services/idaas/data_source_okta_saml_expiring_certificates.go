@@ -0,0 +1,192 @@
+package idaas
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+func dataSourceSamlExpiringCertificates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceSamlExpiringCertificatesRead,
+		Schema: map[string]*schema.Schema{
+			"within_days": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Certificates expiring within this many days are listed, along with the expired ones.",
+			},
+			"certificates": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The certificates expiring within `within_days` days, the soonest first.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "`APP` for the signing keys of SAML applications, `IDP` for the keys of SAML identity providers.",
+						},
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the application or identity provider, empty for IdP keys no identity provider uses.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Label of the application or name of the identity provider.",
+						},
+						"kid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Key ID.",
+						},
+						"active": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the application signs with the key, or the identity provider trusts it.",
+						},
+						"expires_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Expiration date of the certificate.",
+						},
+						"days_remaining": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Days before the certificate expires, negative once it expired.",
+						},
+						"x5t_s256": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "base64url-encoded SHA-256 thumbprint of the DER encoding of the certificate.",
+						},
+					},
+				},
+			},
+		},
+		Description: "Lists the certificates of the SAML applications and SAML identity providers expiring within a number of days.",
+	}
+}
+
+func dataSourceSamlExpiringCertificatesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	days := d.Get("within_days").(int)
+	now := time.Now()
+	limit := now.AddDate(0, 0, days)
+	expiring := func(key *sdk.JsonWebKey) bool {
+		return key.ExpiresAt != nil && key.ExpiresAt.Before(limit)
+	}
+	certificate := func(certType, id, name string, active bool, key *sdk.JsonWebKey) map[string]interface{} {
+		return map[string]interface{}{
+			"type":           certType,
+			"id":             id,
+			"name":           name,
+			"kid":            key.Kid,
+			"active":         active,
+			"expires_at":     formatKeyTime(key.ExpiresAt),
+			"days_remaining": int(math.Floor(key.ExpiresAt.Sub(now).Hours() / 24)),
+			"x5t_s256":       key.X5tS256,
+		}
+	}
+
+	client := getOktaClientFromMetadata(meta)
+	var certificates []map[string]interface{}
+	apps, err := ListAppsV2(ctx, client, nil, utils.DefaultPaginationLimit)
+	if err != nil {
+		return diag.Errorf("failed to list applications: %v", err)
+	}
+	for _, app := range apps {
+		if app.SignOnMode != "SAML_2_0" && app.SignOnMode != "SAML_1_1" {
+			continue
+		}
+		keys, err := fetchAppKeys(ctx, meta, app.Id)
+		if err != nil {
+			return diag.Errorf("failed to load keys of SAML application %s: %v", app.Id, err)
+		}
+		var activeKid string
+		if app.Credentials != nil && app.Credentials.Signing != nil {
+			activeKid = app.Credentials.Signing.Kid
+		}
+		for _, key := range keys {
+			if expiring(key) {
+				certificates = append(certificates, certificate("APP", app.Id, app.Label, key.Kid == activeKid, key))
+			}
+		}
+	}
+
+	idps, err := listSamlIdps(ctx, client)
+	if err != nil {
+		return diag.Errorf("failed to list SAML identity providers: %v", err)
+	}
+	keys, err := listIdpKeys(ctx, client)
+	if err != nil {
+		return diag.Errorf("failed to list identity provider keys: %v", err)
+	}
+	for _, key := range keys {
+		if !expiring(key) {
+			continue
+		}
+		used := false
+		for _, idp := range idps {
+			if idp.Protocol != nil && idp.Protocol.Credentials != nil && idp.Protocol.Credentials.Trust != nil &&
+				idp.Protocol.Credentials.Trust.Kid == key.Kid {
+				certificates = append(certificates, certificate("IDP", idp.Id, idp.Name, true, key))
+				used = true
+			}
+		}
+		if !used {
+			certificates = append(certificates, certificate("IDP", "", "", false, key))
+		}
+	}
+
+	sort.SliceStable(certificates, func(i, j int) bool {
+		return certificates[i]["expires_at"].(string) < certificates[j]["expires_at"].(string)
+	})
+	d.SetId(strconv.Itoa(days))
+	if err := d.Set("certificates", certificates); err != nil {
+		return diag.Errorf("failed to set certificates: %v", err)
+	}
+	return nil
+}
+
+func listSamlIdps(ctx context.Context, client *sdk.Client) ([]*sdk.IdentityProvider, error) {
+	idps, resp, err := client.IdentityProvider.ListIdentityProviders(ctx, &query.Params{Type: Saml2Idp, Limit: utils.DefaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var moreIdps []*sdk.IdentityProvider
+		resp, err = resp.Next(ctx, &moreIdps)
+		if err != nil {
+			return nil, err
+		}
+		idps = append(idps, moreIdps...)
+	}
+	return idps, nil
+}
+
+func listIdpKeys(ctx context.Context, client *sdk.Client) ([]*sdk.JsonWebKey, error) {
+	keys, resp, err := client.IdentityProvider.ListIdentityProviderKeys(ctx, &query.Params{Limit: utils.DefaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var moreKeys []*sdk.JsonWebKey
+		resp, err = resp.Next(ctx, &moreKeys)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, moreKeys...)
+	}
+	return keys, nil
+}
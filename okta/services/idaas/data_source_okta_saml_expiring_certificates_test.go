@@ -0,0 +1,45 @@
+package idaas_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+func TestAccDataSourceOktaSamlExpiringCertificates_read(t *testing.T) {
+	mgr := newFixtureManager("data-sources", resources.OktaIDaaSSamlExpiringCertificates, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+	dataSourceName := fmt.Sprintf("data.%s.test", resources.OktaIDaaSSamlExpiringCertificates)
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "within_days", "3700"),
+					func(s *terraform.State) error {
+						app := s.RootModule().Resources["okta_app_saml.test"].Primary
+						attributes := s.RootModule().Resources[dataSourceName].Primary.Attributes
+						for i := 0; attributes[fmt.Sprintf("certificates.%d.kid", i)] != ""; i++ {
+							prefix := fmt.Sprintf("certificates.%d.", i)
+							if attributes[prefix+"id"] == app.ID && attributes[prefix+"kid"] == app.Attributes["key_id"] {
+								if attributes[prefix+"type"] != "APP" || attributes[prefix+"active"] != "true" {
+									return fmt.Errorf("unexpected certificate of the app: %s %s", attributes[prefix+"type"], attributes[prefix+"active"])
+								}
+								return nil
+							}
+						}
+						return fmt.Errorf("the certificate of app %s isn't listed", app.ID)
+					},
+				),
+			},
+		},
+	})
+}
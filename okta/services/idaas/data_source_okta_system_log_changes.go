@@ -0,0 +1,311 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+// systemLogChangesDefaultLimit is the number of events returned when limit
+// isn't set, it is also the largest page the System Log API returns.
+const systemLogChangesDefaultLimit = 1000
+
+// systemLogTargetIDRegexp matches the IDs of Okta objects, letters and digits
+// only, so they can be quoted in a System Log filter as they are.
+var systemLogTargetIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+var (
+	_ datasource.DataSource              = &systemLogChangesDataSource{}
+	_ datasource.DataSourceWithConfigure = &systemLogChangesDataSource{}
+)
+
+func newSystemLogChangesDataSource() datasource.DataSource {
+	return &systemLogChangesDataSource{}
+}
+
+type systemLogChangesDataSource struct {
+	*config.Config
+}
+
+type systemLogChangesDataSourceModel struct {
+	ID              types.String          `tfsdk:"id"`
+	TargetIDs       []types.String        `tfsdk:"target_ids"`
+	Since           types.String          `tfsdk:"since"`
+	Until           types.String          `tfsdk:"until"`
+	Filter          types.String          `tfsdk:"filter"`
+	ExcludeActorIDs []types.String        `tfsdk:"exclude_actor_ids"`
+	SortOrder       types.String          `tfsdk:"sort_order"`
+	Limit           types.Int64           `tfsdk:"limit"`
+	Events          []systemLogEventModel `tfsdk:"events"`
+}
+
+type systemLogEventModel struct {
+	UUID           types.String           `tfsdk:"uuid"`
+	Published      types.String           `tfsdk:"published"`
+	EventType      types.String           `tfsdk:"event_type"`
+	DisplayMessage types.String           `tfsdk:"display_message"`
+	Severity       types.String           `tfsdk:"severity"`
+	Actor          systemLogEntityModel   `tfsdk:"actor"`
+	Outcome        systemLogOutcomeModel  `tfsdk:"outcome"`
+	Targets        []systemLogEntityModel `tfsdk:"targets"`
+}
+
+type systemLogEntityModel struct {
+	ID          types.String `tfsdk:"id"`
+	Type        types.String `tfsdk:"type"`
+	AlternateID types.String `tfsdk:"alternate_id"`
+	DisplayName types.String `tfsdk:"display_name"`
+}
+
+type systemLogOutcomeModel struct {
+	Result types.String `tfsdk:"result"`
+	Reason types.String `tfsdk:"reason"`
+}
+
+var systemLogEntityType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"id":           types.StringType,
+		"type":         types.StringType,
+		"alternate_id": types.StringType,
+		"display_name": types.StringType,
+	},
+}
+
+func (d *systemLogChangesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_system_log_changes"
+}
+
+func (d *systemLogChangesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports the System Log events that targeted a set of objects, e.g. to flag changes made outside of Terraform and who made them.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The IDs of the targets, comma separated.",
+			},
+			"target_ids": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "IDs of the objects the events targeted, e.g. the IDs of the groups or apps managed by Terraform. IDs are letters and digits only.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(systemLogTargetIDRegexp, "must be the ID of an Okta object, letters and digits only")),
+				},
+			},
+			"since": schema.StringAttribute{
+				Optional:    true,
+				Description: "RFC 3339 timestamp of the earliest event. Defaults to seven days ago.",
+			},
+			"until": schema.StringAttribute{
+				Optional:    true,
+				Description: "RFC 3339 timestamp of the latest event. Defaults to now.",
+			},
+			"filter": schema.StringAttribute{
+				Optional:    true,
+				Description: "System Log filter expression the events also have to match, e.g. `eventType sw \"group.\"`.",
+			},
+			"exclude_actor_ids": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "IDs of actors whose events are left out, e.g. the ID of the user or service app Terraform runs as, so that only changes made outside of Terraform are reported.",
+			},
+			"sort_order": schema.StringAttribute{
+				Optional:    true,
+				Description: "Order of the events by publication time, `ASCENDING` or `DESCENDING`. Defaults to `ASCENDING`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("ASCENDING", "DESCENDING"),
+				},
+			},
+			"limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Maximum number of events returned. Defaults to %d.", systemLogChangesDefaultLimit),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"events": schema.ListAttribute{
+				Computed:    true,
+				Description: "The events that targeted the objects.",
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"uuid":            types.StringType,
+						"published":       types.StringType,
+						"event_type":      types.StringType,
+						"display_message": types.StringType,
+						"severity":        types.StringType,
+						"actor":           systemLogEntityType,
+						"outcome": types.ObjectType{
+							AttrTypes: map[string]attr.Type{
+								"result": types.StringType,
+								"reason": types.StringType,
+							},
+						},
+						"targets": types.ListType{ElemType: systemLogEntityType},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *systemLogChangesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.Config = dataSourceConfiguration(req, resp)
+}
+
+func (d *systemLogChangesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state systemLogChangesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, attribute := range []struct {
+		name  string
+		value types.String
+	}{{"since", state.Since}, {"until", state.Until}} {
+		if v := attribute.value.ValueString(); v != "" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root(attribute.name), "Invalid timestamp", fmt.Sprintf("%q is not an RFC 3339 timestamp: %v", v, err))
+			}
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	limit := int64(systemLogChangesDefaultLimit)
+	if !state.Limit.IsNull() {
+		limit = state.Limit.ValueInt64()
+	}
+	var targetIDs []string
+	for _, id := range state.TargetIDs {
+		targetIDs = append(targetIDs, id.ValueString())
+	}
+	slices.Sort(targetIDs)
+	var excludeActorIDs []string
+	for _, id := range state.ExcludeActorIDs {
+		excludeActorIDs = append(excludeActorIDs, id.ValueString())
+	}
+
+	qp := &query.Params{
+		Filter:    systemLogChangesFilter(targetIDs, state.Filter.ValueString()),
+		Since:     state.Since.ValueString(),
+		Until:     state.Until.ValueString(),
+		SortOrder: state.SortOrder.ValueString(),
+		Limit:     min(limit, systemLogChangesDefaultLimit),
+	}
+	events, err := listSystemLogEvents(ctx, d.OktaIDaaSClient.OktaSDKClientV2(), qp, limit, excludeActorIDs)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read System Log events", err.Error())
+		return
+	}
+
+	state.ID = types.StringValue(strings.Join(targetIDs, ","))
+	state.Events = make([]systemLogEventModel, 0, len(events))
+	for _, event := range events {
+		state.Events = append(state.Events, systemLogEventToModel(event))
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// systemLogChangesFilter returns the System Log filter matching the events
+// that targeted any of the objects and, when it is set, the filter of the
+// user.
+func systemLogChangesFilter(targetIDs []string, filter string) string {
+	conditions := make([]string, len(targetIDs))
+	for i, id := range targetIDs {
+		conditions[i] = fmt.Sprintf(`target.id eq "%s"`, id)
+	}
+	targets := strings.Join(conditions, " or ")
+	if filter == "" {
+		return targets
+	}
+	return fmt.Sprintf("(%s) and (%s)", targets, filter)
+}
+
+// listSystemLogEvents follows the Link header of the System Log API up to
+// limit events. Without an until timestamp the API keeps returning a next
+// link for polling, so an empty page also ends the listing.
+func listSystemLogEvents(ctx context.Context, client *sdk.Client, qp *query.Params, limit int64, excludeActorIDs []string) ([]*sdk.LogEvent, error) {
+	page, resp, err := client.LogEvent.GetLogs(ctx, qp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list System Log events: %v", err)
+	}
+	var events []*sdk.LogEvent
+	for {
+		for _, event := range page {
+			if event.Actor != nil && slices.Contains(excludeActorIDs, event.Actor.Id) {
+				continue
+			}
+			events = append(events, event)
+			if int64(len(events)) >= limit {
+				return events, nil
+			}
+		}
+		if len(page) == 0 || !resp.HasNextPage() {
+			return events, nil
+		}
+		page = nil
+		resp, err = resp.Next(ctx, &page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page of System Log events: %v", err)
+		}
+	}
+}
+
+func systemLogEventToModel(event *sdk.LogEvent) systemLogEventModel {
+	m := systemLogEventModel{
+		UUID:           types.StringValue(event.Uuid),
+		Published:      types.StringNull(),
+		EventType:      types.StringValue(event.EventType),
+		DisplayMessage: types.StringValue(event.DisplayMessage),
+		Severity:       types.StringValue(event.Severity),
+		Actor:          systemLogEntityModel{ID: types.StringNull(), Type: types.StringNull(), AlternateID: types.StringNull(), DisplayName: types.StringNull()},
+		Outcome:        systemLogOutcomeModel{Result: types.StringNull(), Reason: types.StringNull()},
+		Targets:        []systemLogEntityModel{},
+	}
+	if event.Published != nil {
+		m.Published = types.StringValue(event.Published.Format(time.RFC3339))
+	}
+	if event.Actor != nil {
+		m.Actor = systemLogEntityModel{
+			ID:          types.StringValue(event.Actor.Id),
+			Type:        types.StringValue(event.Actor.Type),
+			AlternateID: types.StringValue(event.Actor.AlternateId),
+			DisplayName: types.StringValue(event.Actor.DisplayName),
+		}
+	}
+	if event.Outcome != nil {
+		m.Outcome = systemLogOutcomeModel{
+			Result: types.StringValue(event.Outcome.Result),
+			Reason: types.StringValue(event.Outcome.Reason),
+		}
+	}
+	for _, target := range event.Target {
+		if target == nil {
+			continue
+		}
+		m.Targets = append(m.Targets, systemLogEntityModel{
+			ID:          types.StringValue(target.Id),
+			Type:        types.StringValue(target.Type),
+			AlternateID: types.StringValue(target.AlternateId),
+			DisplayName: types.StringValue(target.DisplayName),
+		})
+	}
+	return m
+}
@@ -0,0 +1,59 @@
+package idaas_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+func TestAccDataSourceOktaSystemLogChanges_read(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the cassettes would hold the System Log events of the org, with the
+		// login and the IP address of the admin running the test, run it
+		// against an org
+		return
+	}
+	mgr := newFixtureManager("data-sources", resources.OktaIDaaSSystemLogChanges, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.okta_system_log_changes.test", "id", "okta_group.test", "id"),
+					resource.TestCheckResourceAttrSet("data.okta_system_log_changes.test", "events.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceOktaSystemLogChanges_invalidTargetID(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the configuration is rejected before any request, there is nothing
+		// to record
+		return
+	}
+	mgr := newFixtureManager("data-sources", resources.OktaIDaaSSystemLogChanges, t.Name())
+	config := mgr.GetFixtures("datasource_invalid_target_id.tf", t)
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be the ID of an Okta object`),
+			},
+		},
+	})
+}
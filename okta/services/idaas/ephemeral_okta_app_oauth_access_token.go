@@ -0,0 +1,231 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &appOAuthAccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &appOAuthAccessTokenEphemeralResource{}
+)
+
+type appOAuthAccessTokenEphemeralResource struct {
+	*config.Config
+}
+
+func newAppOAuthAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &appOAuthAccessTokenEphemeralResource{}
+}
+
+type appOAuthAccessTokenEphemeralModel struct {
+	ClientID              types.String `tfsdk:"client_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	PrivateKey            types.String `tfsdk:"private_key"`
+	PrivateKeyID          types.String `tfsdk:"private_key_id"`
+	AuthorizationServerID types.String `tfsdk:"authorization_server_id"`
+	Scopes                types.Set    `tfsdk:"scopes"`
+	AccessToken           types.String `tfsdk:"access_token"`
+	TokenType             types.String `tfsdk:"token_type"`
+	Scope                 types.String `tfsdk:"scope"`
+	ExpiresIn             types.Int64  `tfsdk:"expires_in"`
+	ExpiresAt             types.String `tfsdk:"expires_at"`
+}
+
+func (e *appOAuthAccessTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	e.Config = ephemeralResourceConfiguration(req, resp)
+}
+
+func (e *appOAuthAccessTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_oauth_access_token"
+}
+
+func (e *appOAuthAccessTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Requests an access token for a service application with the OAuth 2.0 client credentials grant. The token is never written to plan or state.",
+		Attributes: map[string]schema.Attribute{
+			"client_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The client ID of the service application.",
+			},
+			"client_secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The client secret of the service application. Conflicts with `private_key`.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("client_secret"), path.MatchRoot("private_key")),
+				},
+			},
+			"private_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded private key the client assertion is signed with when the service application uses `private_key_jwt` client authentication. Conflicts with `client_secret`.",
+			},
+			"private_key_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "The key ID of `private_key`, sent as the `kid` header of the client assertion.",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("private_key")),
+				},
+			},
+			"authorization_server_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "The ID of the custom authorization server to request the token from. The org authorization server is used when it is not set.",
+			},
+			"scopes": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "The scopes to request.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"access_token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The access token.",
+			},
+			"token_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "The type of the access token.",
+			},
+			"scope": schema.StringAttribute{
+				Computed:    true,
+				Description: "The space separated scopes granted to the access token.",
+			},
+			"expires_in": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The lifetime of the access token in seconds.",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "The RFC3339 timestamp when the access token expires.",
+			},
+		},
+	}
+}
+
+func (e *appOAuthAccessTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data appOAuthAccessTokenEphemeralModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var scopes []string
+	resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tokenURL := oauthTokenEndpoint(e.OrgURL(), data.AuthorizationServerID.ValueString())
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", strings.Join(scopes, " "))
+	if data.PrivateKey.ValueString() != "" {
+		assertion, err := signedClientAssertion(tokenURL, data.ClientID.ValueString(), data.PrivateKey.ValueString(), data.PrivateKeyID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("failed to create client assertion", err.Error())
+			return
+		}
+		form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		form.Set("client_assertion", assertion)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		resp.Diagnostics.AddError("failed to create access token request", err.Error())
+		return
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if data.ClientSecret.ValueString() != "" {
+		httpReq.SetBasicAuth(url.QueryEscape(data.ClientID.ValueString()), url.QueryEscape(data.ClientSecret.ValueString()))
+	}
+
+	token, err := requestAccessToken(e.OktaIDaaSClient.HTTPClient(), httpReq)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to request access token", err.Error())
+		return
+	}
+
+	data.AccessToken = types.StringValue(token.AccessToken)
+	data.TokenType = types.StringValue(token.TokenType)
+	data.Scope = types.StringValue(token.Scope)
+	data.ExpiresIn = types.Int64Value(int64(token.ExpiresIn))
+	data.ExpiresAt = types.StringValue(time.Now().Add(time.Duration(token.ExpiresIn) * time.Second).UTC().Format(time.RFC3339))
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// oauthTokenEndpoint returns the token endpoint of the org authorization
+// server, or of the custom authorization server authServerID when it is set.
+func oauthTokenEndpoint(orgURL, authServerID string) string {
+	if authServerID == "" {
+		return orgURL + "/oauth2/v1/token"
+	}
+	return fmt.Sprintf("%s/oauth2/%s/v1/token", orgURL, url.PathEscape(authServerID))
+}
+
+// signedClientAssertion returns a client assertion JWT for the private_key_jwt
+// client authentication method whose audience is tokenURL.
+func signedClientAssertion(tokenURL, clientID, privateKey, privateKeyID string) (string, error) {
+	signer, err := sdk.CreateKeySigner(privateKey, privateKeyID)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := sdk.ClientAssertionClaims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: tokenURL,
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		ID:       uuid.New().String(),
+	}
+	return jwt.Signed(signer).Claims(claims).Serialize()
+}
+
+func requestAccessToken(client *http.Client, req *http.Request) (*sdk.RequestAccessToken, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			return nil, fmt.Errorf("%s: %s", oauthErr.Error, oauthErr.ErrorDescription)
+		}
+		return nil, fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+	var token sdk.RequestAccessToken
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -0,0 +1,40 @@
+package idaas_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+// TestAccEphemeralOktaAppOAuthAccessToken_basic requests an access token with
+// the client secret of a service app. The echo provider copies the ephemeral
+// values into state so they can be checked.
+func TestAccEphemeralOktaAppOAuthAccessToken_basic(t *testing.T) {
+	mgr := newFixtureManager("ephemeral-resources", resources.OktaIDaaSAppOAuthAccessToken, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"echo": echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("echo.test", "data.access_token"),
+					resource.TestCheckResourceAttr("echo.test", "data.token_type", "Bearer"),
+					resource.TestCheckResourceAttr("echo.test", "data.scope", "okta.users.read"),
+					resource.TestCheckResourceAttrSet("echo.test", "data.expires_in"),
+					resource.TestCheckResourceAttrSet("echo.test", "data.expires_at"),
+				),
+			},
+		},
+	})
+}
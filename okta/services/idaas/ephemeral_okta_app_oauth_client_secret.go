@@ -0,0 +1,148 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &appOAuthClientSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &appOAuthClientSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &appOAuthClientSecretEphemeralResource{}
+)
+
+// appOAuthClientSecretEphemeralPrivateKey is the private data key the secret
+// created by Open is handed to Close with, when it is deleted on close.
+const appOAuthClientSecretEphemeralPrivateKey = "client_secret"
+
+type appOAuthClientSecretEphemeralPrivate struct {
+	AppID    string `json:"app_id"`
+	SecretID string `json:"secret_id"`
+}
+
+type appOAuthClientSecretEphemeralResource struct {
+	*config.Config
+}
+
+func newAppOAuthClientSecretEphemeralResource() ephemeral.EphemeralResource {
+	return &appOAuthClientSecretEphemeralResource{}
+}
+
+type appOAuthClientSecretEphemeralModel struct {
+	ID            types.String `tfsdk:"id"`
+	AppID         types.String `tfsdk:"app_id"`
+	DeleteOnClose types.Bool   `tfsdk:"delete_on_close"`
+	ClientSecret  types.String `tfsdk:"client_secret"`
+	SecretHash    types.String `tfsdk:"secret_hash"`
+	Status        types.String `tfsdk:"status"`
+	Created       types.String `tfsdk:"created"`
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	e.Config = ephemeralResourceConfiguration(req, resp)
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_oauth_client_secret"
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates a client secret for an OAuth application without writing it to plan or state. A new secret is created every time the ephemeral resource is opened, during plan as well as apply, and it is kept unless `delete_on_close` is set, in which case it only lives for the duration of the Terraform operation. An application can have at most two client secrets so the application must have fewer than two when the ephemeral resource is opened. To hand a long lived secret to e.g. Vault or Kubernetes without it reaching state, generate it there and manage it with the write-only `client_secret_wo` argument of `okta_app_oauth_client_secret`, which keeps one secret across runs.",
+		Attributes: map[string]schema.Attribute{
+			"app_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the OAuth application to create the client secret for.",
+			},
+			"delete_on_close": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Deactivates and deletes the client secret when Terraform is done with it, at the end of the plan or apply that opened it. A secret handed to another system is dead by then, only use it for secrets used within the run, e.g. to request an access token. Defaults to `false`.",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the client secret.",
+			},
+			"client_secret": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The client secret.",
+			},
+			"secret_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "The hash of the client secret.",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "The status of the client secret.",
+			},
+			"created": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the client secret was created.",
+			},
+		},
+	}
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data appOAuthClientSecretEphemeralModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID := data.AppID.ValueString()
+	client := e.OktaIDaaSClient.OktaSDKClientV6()
+	secret, _, err := client.ApplicationSSOPublicKeysAPI.CreateOAuth2ClientSecret(ctx, appID).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError("failed to create OAuth client secret", err.Error())
+		return
+	}
+
+	// Close only deletes the secret when asked to, a secret handed to another
+	// system has to outlive the run
+	if data.DeleteOnClose.ValueBool() {
+		private, err := json.Marshal(appOAuthClientSecretEphemeralPrivate{AppID: appID, SecretID: secret.GetId()})
+		if err != nil {
+			resp.Diagnostics.AddError("failed to save OAuth client secret ID", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, appOAuthClientSecretEphemeralPrivateKey, private)...)
+	}
+
+	data.ID = types.StringValue(secret.GetId())
+	data.ClientSecret = types.StringValue(secret.GetClientSecret())
+	data.SecretHash = types.StringValue(secret.GetSecretHash())
+	data.Status = types.StringValue(secret.GetStatus())
+	data.Created = types.StringValue(secret.GetCreated())
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (e *appOAuthClientSecretEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	raw, diags := req.Private.GetKey(ctx, appOAuthClientSecretEphemeralPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || raw == nil {
+		return
+	}
+	var private appOAuthClientSecretEphemeralPrivate
+	if err := json.Unmarshal(raw, &private); err != nil {
+		resp.Diagnostics.AddError("failed to read OAuth client secret ID", err.Error())
+		return
+	}
+
+	client := e.OktaIDaaSClient.OktaSDKClientV6()
+	_, _, err := client.ApplicationSSOPublicKeysAPI.DeactivateOAuth2ClientSecret(ctx, private.AppID, private.SecretID).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("failed to deactivate OAuth client secret %s", private.SecretID), err.Error())
+		return
+	}
+	_, err = client.ApplicationSSOPublicKeysAPI.DeleteOAuth2ClientSecret(ctx, private.AppID, private.SecretID).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("failed to delete OAuth client secret %s", private.SecretID), err.Error())
+	}
+}
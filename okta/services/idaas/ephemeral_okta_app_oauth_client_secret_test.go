@@ -0,0 +1,43 @@
+package idaas_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+// TestAccEphemeralOktaAppOAuthClientSecret_basic creates a temporary client
+// secret for a service app and exchanges it for an access token. The echo
+// provider copies the ephemeral values into state so they can be checked.
+func TestAccEphemeralOktaAppOAuthClientSecret_basic(t *testing.T) {
+	mgr := newFixtureManager("ephemeral-resources", resources.OktaIDaaSAppOAuthClientSecret, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	appResourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSAppOAuth)
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"echo": echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(appResourceName, "type", "service"),
+					resource.TestCheckResourceAttrSet("echo.test", "data.secret_id"),
+					resource.TestCheckResourceAttrSet("echo.test", "data.client_secret"),
+					resource.TestCheckResourceAttr("echo.test", "data.status", "ACTIVE"),
+					resource.TestCheckResourceAttrSet("echo.test", "data.access_token"),
+					resource.TestCheckResourceAttr("echo.test", "data.token_type", "Bearer"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,57 @@
+package idaas_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/acctest/fakeokta"
+)
+
+// TestAccProviderOktaFakeOrg_configure configures the provider against the
+// fake Okta API, which verifies the credentials with GET /api/v1/users/me,
+// and probes the capabilities of the fake org: it is an Okta Identity Engine
+// org without realms. CI runs it with make test-fake-acc.
+func TestAccProviderOktaFakeOrg_configure(t *testing.T) {
+	if !acctest.IsFakeOktaEnabled() {
+		t.Skip("the test only runs against the fake Okta API, set ENV var OKTA_FAKE_TF_ACC")
+	}
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "okta_user" "admin" {
+  user_id     = %q
+  skip_groups = true
+  skip_roles  = true
+}`, fakeokta.AdminUserID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.okta_user.admin", "id", fakeokta.AdminUserID),
+					resource.TestCheckResourceAttr("data.okta_user.admin", "login", "admin@example.com"),
+				),
+			},
+			{
+				// the Okta Identity Engine probe finds the idx pipeline
+				Config: `
+resource "okta_policy_profile_enrollment" "test" {
+  name = "testAcc"
+}`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// the realms probe gets a 404
+				Config: `
+resource "okta_realm" "test" {
+  name = "testAcc"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`requires Realms`),
+			},
+		},
+	})
+}
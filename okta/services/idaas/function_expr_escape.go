@@ -0,0 +1,49 @@
+package idaas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+)
+
+var _ function.Function = &exprEscapeFunction{}
+
+type exprEscapeFunction struct{}
+
+func newExprEscapeFunction() function.Function {
+	return &exprEscapeFunction{}
+}
+
+func (f *exprEscapeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "expr_escape"
+}
+
+func (f *exprEscapeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Quotes a string for use in an Okta Expression Language expression.",
+		Description: "Returns the string as a double quoted Okta Expression Language string literal. Double quotes in the string are escaped by doubling them.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "value",
+				Description: "The string to quote.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *exprEscapeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+	resp.Error = req.Arguments.Get(ctx, &value)
+	if resp.Error != nil {
+		return
+	}
+
+	quoted := expression.Quote(value)
+	if err := expression.Validate(quoted); err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, quoted)
+}
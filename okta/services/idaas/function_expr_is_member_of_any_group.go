@@ -0,0 +1,51 @@
+package idaas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+)
+
+var _ function.Function = &exprIsMemberOfAnyGroupFunction{}
+
+type exprIsMemberOfAnyGroupFunction struct{}
+
+func newExprIsMemberOfAnyGroupFunction() function.Function {
+	return &exprIsMemberOfAnyGroupFunction{}
+}
+
+func (f *exprIsMemberOfAnyGroupFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "expr_is_member_of_any_group"
+}
+
+func (f *exprIsMemberOfAnyGroupFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Builds an isMemberOfAnyGroup Okta Expression Language expression.",
+		Description: "Returns an Okta Expression Language expression that is true when the user is a member of any of the given groups, e.g. for `okta_group_rule.expression_value`.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "group_ids",
+				Description: "The IDs of the groups.",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *exprIsMemberOfAnyGroupFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var groupIDs []string
+	resp.Error = req.Arguments.Get(ctx, &groupIDs)
+	if resp.Error != nil {
+		return
+	}
+
+	expr, err := expression.IsMemberOfAnyGroup(groupIDs)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, expr)
+}
@@ -0,0 +1,71 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &parseImportIDFunction{}
+
+type parseImportIDFunction struct{}
+
+func newParseImportIDFunction() function.Function {
+	return &parseImportIDFunction{}
+}
+
+func (f *parseImportIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_import_id"
+}
+
+func (f *parseImportIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Splits a nested resource import ID into its parts.",
+		Description: "Splits an import ID of a nested resource, e.g. `auth_server_id/policy_id/id`, into the list of its parts. Every part must be non-empty and without surrounding whitespace.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "id",
+				Description: "The import ID.",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *parseImportIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	resp.Error = req.Arguments.Get(ctx, &id)
+	if resp.Error != nil {
+		return
+	}
+
+	parts, err := parseImportID(id)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, parts)
+}
+
+// parseImportID splits an import ID on "/" the same way the nested resource
+// importers do.
+func parseImportID(id string) ([]string, error) {
+	if id == "" {
+		return nil, fmt.Errorf("import ID is empty")
+	}
+	parts := strings.Split(id, "/")
+	for i, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("part %d of import ID %q is empty", i, id)
+		}
+		if strings.TrimSpace(part) != part {
+			return nil, fmt.Errorf("part %d of import ID %q has surrounding whitespace", i, id)
+		}
+	}
+	return parts, nil
+}
@@ -2,8 +2,19 @@ package idaas
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+
+	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/okta/terraform-provider-okta/sdk/query"
@@ -43,32 +54,258 @@ func listGroupUserIDs(ctx context.Context, m interface{}, id string) ([]string,
 	return resUsers, nil
 }
 
-// Group Primary Key Operations (Use when # groups < # users in operations)
-func addGroupMembers(ctx context.Context, client *sdk.Client, groupId string, users []string) error {
-	for _, user := range users {
-		resp, err := client.Group.AddUserToGroup(ctx, groupId, user)
+// groupMembershipParallelism returns the number of membership requests made
+// at once, the parallelism of the provider.
+func groupMembershipParallelism(meta interface{}) int {
+	if cfg, ok := meta.(*config.Config); ok && cfg.Parallelism > 0 {
+		return cfg.Parallelism
+	}
+	return 1
+}
+
+// groupUsersCount returns the number of users of a group from its stats, ok is
+// false when the group isn't found or has no stats.
+func groupUsersCount(ctx context.Context, client *sdk.Client, groupId string) (count int, ok bool, err error) {
+	groups, _, err := client.Group.ListGroups(ctx, &query.Params{Search: fmt.Sprintf("id eq \"%s\"", groupId), Expand: "stats"})
+	if err != nil || len(groups) != 1 {
+		return 0, false, err
+	}
+	embedded, _ := groups[0].Embedded.(map[string]interface{})
+	stats, _ := embedded["stats"].(map[string]interface{})
+	usersCount, ok := stats["usersCount"].(float64)
+	return int(usersCount), ok, nil
+}
+
+// isGroupMember reports whether a user is a member of a group from the groups
+// of the user. A user that doesn't exist isn't a member.
+func isGroupMember(ctx context.Context, client *sdk.Client, groupId, userId string) (bool, error) {
+	groups, resp, err := client.User.ListUserGroups(ctx, userId)
+	if utils.Is404(resp) {
+		return false, nil
+	}
+	for {
 		if err != nil {
-			return fmt.Errorf("failed to add user (%s) to group (%s): %w", user, groupId, err)
+			return false, err
 		}
-		exists, err := utils.DoesResourceExist(resp, err)
-		if !exists {
-			return fmt.Errorf("targeted object does not exist: %s", err)
+		for _, group := range groups {
+			if group.Id == groupId {
+				return true, nil
+			}
+		}
+		if !resp.HasNextPage() {
+			return false, nil
 		}
+		groups = nil
+		resp, err = resp.Next(ctx, &groups)
 	}
-	return nil
 }
 
-func removeGroupMembers(ctx context.Context, client *sdk.Client, groupId string, users []string) error {
+// groupNonMembers returns the users that aren't members of a group, checking
+// the groups of each user with up to parallelism requests at once.
+func groupNonMembers(ctx context.Context, client *sdk.Client, groupId string, users []string, parallelism int) ([]string, error) {
+	var (
+		lock       sync.Mutex
+		nonMembers = map[string]bool{}
+	)
+	failures := changeGroupMembersOnce(users, max(parallelism, 1), func(user string) (*sdk.Response, error) {
+		member, err := isGroupMember(ctx, client, groupId, user)
+		if err == nil && !member {
+			lock.Lock()
+			nonMembers[user] = true
+			lock.Unlock()
+		}
+		return nil, err
+	})
+	var (
+		result []string
+		errs   []error
+	)
 	for _, user := range users {
+		if err, failed := failures[user]; failed {
+			errs = append(errs, fmt.Errorf("failed to list groups of user (%s): %w", user, err))
+		} else if nonMembers[user] {
+			result = append(result, user)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// groupMembershipRetries is the number of times the membership changes that
+// failed with a retryable error are tried again.
+const groupMembershipRetries = 3
+
+// groupMembershipError reports the users whose membership of a group couldn't
+// be changed, by user ID.
+type groupMembershipError struct {
+	groupID  string
+	add      bool
+	failures map[string]error
+}
+
+func (e *groupMembershipError) Error() string {
+	return fmt.Sprintf("failed to %s %d user(s) (%s) %s group (%s)", e.verb(), len(e.failures), strings.Join(e.userIDs(), ", "), e.preposition(), e.groupID)
+}
+
+func (e *groupMembershipError) verb() string {
+	if e.add {
+		return "add"
+	}
+	return "remove"
+}
+
+func (e *groupMembershipError) preposition() string {
+	if e.add {
+		return "to"
+	}
+	return "from"
+}
+
+// userIDs returns the IDs of the users that failed, sorted.
+func (e *groupMembershipError) userIDs() []string {
+	ids := make([]string, 0, len(e.failures))
+	for id := range e.failures {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// diagnostics returns one diagnostic for the failures, naming every user that
+// failed and why.
+func (e *groupMembershipError) diagnostics() diag.Diagnostics {
+	var detail strings.Builder
+	for _, id := range e.userIDs() {
+		fmt.Fprintf(&detail, "%s: %v\n", id, e.failures[id])
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       fmt.Sprintf("Failed to %s %d user(s) %s group %s", e.verb(), len(e.failures), e.preposition(), e.groupID),
+		Detail:        detail.String(),
+		AttributePath: cty.GetAttrPath("users"),
+	}}
+}
+
+// Group Primary Key Operations (Use when # groups < # users in operations)
+
+// addGroupMembers adds users to a group with up to parallelism requests at
+// once. The requests go through the API rate limit governor of the client, so
+// parallelism only bounds the burst. Users that fail with a retryable error
+// are retried, the users that still fail are returned in a
+// *groupMembershipError.
+func addGroupMembers(ctx context.Context, client *sdk.Client, groupId string, users []string, parallelism int) error {
+	return changeGroupMembers(ctx, groupId, users, true, parallelism, func(user string) (*sdk.Response, error) {
+		resp, err := client.Group.AddUserToGroup(ctx, groupId, user)
+		if utils.Is404(resp) {
+			return resp, fmt.Errorf("the user or the group does not exist: %w", err)
+		}
+		return resp, err
+	})
+}
+
+// removeGroupMembers removes users from a group the same way addGroupMembers
+// adds them. Users that aren't members are ignored.
+func removeGroupMembers(ctx context.Context, client *sdk.Client, groupId string, users []string, parallelism int) error {
+	return changeGroupMembers(ctx, groupId, users, false, parallelism, func(user string) (*sdk.Response, error) {
 		resp, err := client.Group.RemoveUserFromGroup(ctx, groupId, user)
-		err = utils.SuppressErrorOn404(resp, err)
-		if err != nil {
-			return fmt.Errorf("failed to remove user (%s) from group (%s): %v", user, groupId, err)
+		return resp, utils.SuppressErrorOn404(resp, err)
+	})
+}
+
+func changeGroupMembers(ctx context.Context, groupId string, users []string, add bool, parallelism int, change func(user string) (*sdk.Response, error)) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	failures := map[string]error{}
+	pending := users
+	bOff := backoff.NewExponentialBackOff()
+	for attempt := 0; len(pending) > 0; attempt++ {
+		var retry []string
+		for user, err := range changeGroupMembersOnce(pending, parallelism, change) {
+			failures[user] = err
+			if attempt < groupMembershipRetries && isRetryableMembershipError(err) {
+				retry = append(retry, user)
+			}
+		}
+		if len(retry) == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return &groupMembershipError{groupID: groupId, add: add, failures: failures}
+		case <-time.After(bOff.NextBackOff()):
+		}
+		for _, user := range retry {
+			delete(failures, user)
 		}
+		pending = retry
+	}
+	if len(failures) > 0 {
+		return &groupMembershipError{groupID: groupId, add: add, failures: failures}
 	}
 	return nil
 }
 
+// changeGroupMembersOnce applies change to every user with parallelism
+// workers and returns the errors by user ID.
+func changeGroupMembersOnce(users []string, parallelism int, change func(user string) (*sdk.Response, error)) map[string]error {
+	queue := make(chan string)
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		failures = map[string]error{}
+	)
+	for i := 0; i < min(parallelism, len(users)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for user := range queue {
+				resp, err := change(user)
+				if err != nil {
+					lock.Lock()
+					failures[user] = &membershipChangeError{resp: resp, err: err}
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+	for _, user := range users {
+		queue <- user
+	}
+	close(queue)
+	wg.Wait()
+	return failures
+}
+
+// membershipChangeError keeps the response of a failed membership change to
+// tell whether it is worth retrying.
+type membershipChangeError struct {
+	resp *sdk.Response
+	err  error
+}
+
+func (e *membershipChangeError) Error() string { return e.err.Error() }
+
+func (e *membershipChangeError) Unwrap() error { return e.err }
+
+// isRetryableMembershipError reports whether a membership change failed for
+// a reason that may go away: a network error, a rate limit or a server error.
+// The client already retries rate limits, but a busy org can exhaust those
+// retries.
+func isRetryableMembershipError(err error) bool {
+	var changeErr *membershipChangeError
+	if !errors.As(err, &changeErr) {
+		return false
+	}
+	if changeErr.resp == nil || changeErr.resp.Response == nil {
+		return !errors.Is(changeErr.err, context.Canceled) && !errors.Is(changeErr.err, context.DeadlineExceeded)
+	}
+	status := changeErr.resp.StatusCode
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
 // User Primary Key Operations (use when # users < # groups in operations)
 func addUserToGroups(ctx context.Context, client *sdk.Client, userId string, groups []string) error {
 	for _, group := range groups {
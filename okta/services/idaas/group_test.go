@@ -0,0 +1,160 @@
+package idaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffGroupMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  []string
+		users    []string
+		toAdd    []string
+		toRemove []string
+	}{
+		{name: "empty group", users: []string{"u1", "u2"}, toAdd: []string{"u1", "u2"}},
+		{name: "no users", members: []string{"u1", "u2"}, toRemove: []string{"u1", "u2"}},
+		{name: "same users", members: []string{"u1", "u2"}, users: []string{"u2", "u1"}},
+		{name: "some changed", members: []string{"u1", "u2", "u3"}, users: []string{"u2", "u4"}, toAdd: []string{"u4"}, toRemove: []string{"u1", "u3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toAdd, toRemove := diffGroupMembers(tt.members, tt.users)
+			require.Equal(t, tt.toAdd, toAdd)
+			require.Equal(t, tt.toRemove, toRemove)
+		})
+	}
+}
+
+func TestChangeGroupMembers(t *testing.T) {
+	status := func(code int) *sdk.Response {
+		return &sdk.Response{Response: &http.Response{StatusCode: code}}
+	}
+	var (
+		lock  sync.Mutex
+		calls = map[string]int{}
+	)
+	err := changeGroupMembers(context.Background(), "00g1", []string{"ok", "throttled", "server", "bad", "down"}, true, 2, func(user string) (*sdk.Response, error) {
+		lock.Lock()
+		calls[user]++
+		call := calls[user]
+		lock.Unlock()
+		switch {
+		case user == "throttled" && call == 1:
+			return status(http.StatusTooManyRequests), errors.New("rate limited")
+		case user == "server" && call < 3:
+			return status(http.StatusInternalServerError), errors.New("internal error")
+		case user == "bad":
+			return status(http.StatusBadRequest), errors.New("invalid user")
+		case user == "down":
+			return status(http.StatusServiceUnavailable), errors.New("unavailable")
+		}
+		return status(http.StatusNoContent), nil
+	})
+
+	var membershipErr *groupMembershipError
+	require.ErrorAs(t, err, &membershipErr)
+	require.Equal(t, []string{"bad", "down"}, membershipErr.userIDs())
+	require.EqualError(t, err, "failed to add 2 user(s) (bad, down) to group (00g1)")
+	require.Equal(t, map[string]int{
+		"ok":        1,
+		"throttled": 2,
+		"server":    3,
+		"bad":       1,
+		"down":      groupMembershipRetries + 1,
+	}, calls)
+
+	diags := groupMembershipDiagnostics(err)
+	require.Len(t, diags, 1)
+	require.Equal(t, "Failed to add 2 user(s) to group 00g1", diags[0].Summary)
+	require.Equal(t, "bad: invalid user\ndown: unavailable\n", diags[0].Detail)
+}
+
+func TestChangeGroupMembersSucceeds(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		changed []string
+	)
+	err := changeGroupMembers(context.Background(), "00g1", []string{"u1", "u2", "u3"}, false, 0, func(user string) (*sdk.Response, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		changed = append(changed, user)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u1", "u2", "u3"}, changed)
+}
+
+func TestGroupMembershipParallelism(t *testing.T) {
+	require.Equal(t, 4, groupMembershipParallelism(&config.Config{Parallelism: 4}))
+	require.Equal(t, 1, groupMembershipParallelism(&config.Config{}))
+	require.Equal(t, 1, groupMembershipParallelism(nil))
+}
+
+// newTestSDKClient returns a client of the Okta API served by handler.
+func newTestSDKClient(t *testing.T, handler http.HandlerFunc) *sdk.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	_, client, err := sdk.NewClient(context.Background(),
+		sdk.WithOrgUrl(server.URL),
+		sdk.WithToken("token"),
+		sdk.WithTestingDisableHttpsCheck(true),
+		sdk.WithCache(false),
+		sdk.WithRateLimitMaxRetries(0),
+	)
+	require.NoError(t, err)
+	return client
+}
+
+func TestCheckIfUsersHaveBeenRemovedFromGroup(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		usersCount int
+		listed     bool
+	}{
+		{name: "large group", usersCount: 5000},
+		{name: "small group", usersCount: 150, listed: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var listed bool
+			client := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/api/v1/groups":
+					assert.Equal(t, `id eq "00g1"`, r.URL.Query().Get("search"))
+					assert.Equal(t, "stats", r.URL.Query().Get("expand"))
+					fmt.Fprintf(w, `[{"id":"00g1","_embedded":{"stats":{"usersCount":%d}}}]`, tt.usersCount)
+				case r.URL.Path == "/api/v1/groups/00g1/users":
+					listed = true
+					fmt.Fprint(w, `[{"id":"u1"},{"id":"u4"}]`)
+				case r.URL.Path == "/api/v1/users/u1/groups":
+					fmt.Fprint(w, `[{"id":"00g2"},{"id":"00g1"}]`)
+				case r.URL.Path == "/api/v1/users/u2/groups":
+					fmt.Fprint(w, `[{"id":"00g2"}]`)
+				case strings.HasPrefix(r.URL.Path, "/api/v1/users/"):
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"errorCode":"E0000007"}`)
+				default:
+					t.Errorf("unexpected request %s", r.URL)
+				}
+			})
+			changed, users, err := checkIfUsersHaveBeenRemovedFromGroup(context.Background(), client, "00g1", []string{"u1", "u2", "u3"}, 2)
+			require.NoError(t, err)
+			require.True(t, changed)
+			require.Equal(t, []string{"u1"}, *users)
+			require.Equal(t, tt.listed, listed)
+		})
+	}
+}
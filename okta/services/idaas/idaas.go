@@ -3,11 +3,15 @@ package idaas
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -25,26 +29,52 @@ import (
 var oktaMutexKV = mutexkv.NewMutexKV()
 
 func listAppsV5(ctx context.Context, config *config.Config, filters *AppFilters, limit int64) ([]oktav5sdk.ListApplications200ResponseInner, error) {
-	req := config.OktaIDaaSClient.OktaSDKClientV5().ApplicationAPI.ListApplications(ctx).Limit(int32(limit))
-	if filters != nil {
-		req = req.Filter(filters.Status)
-		req = req.Q(filters.GetQ())
-	}
-	apps, resp, err := req.Execute()
-	if err != nil {
-		return nil, err
-	}
-	for resp.HasNextPage() {
-		var nextApps []oktav5sdk.ListApplications200ResponseInner
-		resp, err = resp.Next(&nextApps)
+	var apps []oktav5sdk.ListApplications200ResponseInner
+	for page, err := range appPagesV5(ctx, config, filters, limit) {
 		if err != nil {
 			return nil, err
 		}
-		apps = append(apps, nextApps...)
+		apps = append(apps, page...)
 	}
 	return apps, nil
 }
 
+// appPagesV5 yields the apps of the org matching filters a page of at most
+// limit apps at a time. The next page is only fetched when the previous one
+// was consumed.
+func appPagesV5(ctx context.Context, config *config.Config, filters *AppFilters, limit int64) iter.Seq2[[]oktav5sdk.ListApplications200ResponseInner, error] {
+	return func(yield func([]oktav5sdk.ListApplications200ResponseInner, error) bool) {
+		req := config.OktaIDaaSClient.OktaSDKClientV5().ApplicationAPI.ListApplications(ctx).Limit(int32(limit))
+		if filters != nil {
+			if filters.Status != "" {
+				req = req.Filter(filters.Status)
+			}
+			if q := filters.GetQ(); q != "" {
+				req = req.Q(q)
+			}
+		}
+		apps, resp, err := req.Execute()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if !yield(apps, nil) {
+			return
+		}
+		for resp.HasNextPage() {
+			var nextApps []oktav5sdk.ListApplications200ResponseInner
+			resp, err = resp.Next(&nextApps)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(nextApps, nil) {
+				return
+			}
+		}
+	}
+}
+
 func getOktaClientFromMetadata(meta interface{}) *sdk.Client {
 	return meta.(*config.Config).OktaIDaaSClient.OktaSDKClientV2()
 }
@@ -84,6 +114,26 @@ func providerIsClassicOrg(ctx context.Context, m interface{}) bool {
 	return false
 }
 
+// requireCapabilities fails the plans creating the resource name in orgs
+// without capabilities.
+func requireCapabilities(name string, capabilities ...config.Capability) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		cfg, ok := meta.(*config.Config)
+		if !ok || d.Id() != "" {
+			return nil
+		}
+		missing, err := cfg.Capabilities.Missing(ctx, capabilities...)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			summary, detail := config.MissingCapabilitiesError(name, missing)
+			return fmt.Errorf("%s: %s", summary, detail)
+		}
+		return nil
+	}
+}
+
 func FWProviderResources() []func() resource.Resource {
 	rawResources := []func() resource.Resource{
 		newAppAccessPolicyAssignmentResource,
@@ -91,20 +141,11 @@ func FWProviderResources() []func() resource.Resource {
 		newTrustedServerResource,
 		newBrandResource,
 		newLogStreamResource,
-		newPolicyDeviceAssuranceAndroidResource,
-		newPolicyDeviceAssuranceChromeOSResource,
-		newPolicyDeviceAssuranceIOSResource,
-		newPolicyDeviceAssuranceMacOSResource,
-		newPolicyDeviceAssuranceWindowsResource,
 		newCustomizedSigninResource,
 		newPreviewSigninResource,
-		newGroupOwnerResource,
-		newGroupOwnersResource,
 		newAppSignOnPolicyResource,
 		newEmailTemplateSettingsResource,
 		newFeaturesResource,
-		newRealmResource,
-		newRealmAssignmentResource,
 		newRateLimitResource,
 		newRateLimitAdminNotificationSettingsResource,
 		newRateLimitWarningThresholdPercentageResource,
@@ -125,11 +166,76 @@ func FWProviderResources() []func() resource.Resource {
 		newPushGroupResource,
 		newUserRiskResource,
 		newPostAuthSessionPolicyRuleResource,
+		newPolicyWithRulesResource,
+		newAuthServerKeyRotationResource,
+		newAppOAuthClientSecretResource,
+		newAppOAuthJWKResource,
+	}
+	// Wrap all resources with SafeResource for panic recovery, those that can
+	// only be created in orgs with some capabilities declare them
+	wrapped := resources.WrapResources(rawResources)
+	wrapped = append(wrapped, resources.WrapResources([]func() resource.Resource{
+		newPolicyDeviceAssuranceAndroidResource,
+		newPolicyDeviceAssuranceChromeOSResource,
+		newPolicyDeviceAssuranceIOSResource,
+		newPolicyDeviceAssuranceMacOSResource,
+		newPolicyDeviceAssuranceWindowsResource,
+	}, config.CapabilityIdentityEngine)...)
+	wrapped = append(wrapped, resources.WrapResources([]func() resource.Resource{
+		newGroupOwnerResource,
+		newGroupOwnersResource,
+	}, config.CapabilityGovernance)...)
+	wrapped = append(wrapped, resources.WrapResources([]func() resource.Resource{
+		newRealmResource,
+		newRealmAssignmentResource,
+	}, config.CapabilityRealms)...)
+	wrapped = append(wrapped, resources.WrapResources([]func() resource.Resource{
 		newEntityRiskPolicyRuleResource,
 		newSessionViolationPolicyRuleResource,
+	}, config.CapabilityIdentityThreatProtection)...)
+	return wrapped
+}
+
+// FWProviderEphemeralResources returns the ephemeral resources of the IDaaS
+// service. Ephemeral resources are never persisted to plan or state.
+func FWProviderEphemeralResources() []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newAppOAuthAccessTokenEphemeralResource,
+		newAppOAuthClientSecretEphemeralResource,
+	}
+}
+
+// FWProviderListResources returns the list resources of the IDaaS service,
+// used by terraform query to find the existing objects of the org.
+func FWProviderListResources() []func() list.ListResource {
+	return []func() list.ListResource{
+		newAppOAuthListResource,
+		newAppSamlListResource,
+		newGroupListResource,
+		newHookKeyListResource,
+		newLogStreamListResource,
+		newNetworkZoneListResource,
+		newPolicyMfaListResource,
+		newPolicyPasswordListResource,
+		newPolicyDeviceAssuranceAndroidListResource,
+		newPolicyDeviceAssuranceChromeOSListResource,
+		newPolicyDeviceAssuranceIOSListResource,
+		newPolicyDeviceAssuranceMacOSListResource,
+		newPolicyDeviceAssuranceWindowsListResource,
+		newPolicyProfileEnrollmentListResource,
+		newPolicySignOnListResource,
+		newRealmListResource,
+	}
+}
+
+// FWProviderFunctions returns the provider defined functions of the IDaaS
+// service.
+func FWProviderFunctions() []func() function.Function {
+	return []func() function.Function{
+		newExprEscapeFunction,
+		newExprIsMemberOfAnyGroupFunction,
+		newParseImportIDFunction,
 	}
-	// Wrap all resources with SafeResource for panic recovery
-	return resources.WrapResources(rawResources)
 }
 
 func FWProviderDataSources() []func() datasource.DataSource {
@@ -168,6 +274,9 @@ func FWProviderDataSources() []func() datasource.DataSource {
 		newPostAuthSessionPolicyDataSource,
 		newEntityRiskPolicyDataSource,
 		newSessionViolationPolicyDataSource,
+		newSystemLogChangesDataSource,
+		newNetworkZoneOverlapsDataSource,
+		newOrgDiffDataSource,
 	}
 }
 
@@ -188,6 +297,7 @@ func ProviderResources() map[string]*schema.Resource {
 		resources.OktaIDaaSAppOAuthRedirectURI:           resourceAppOAuthRedirectURI(),
 		resources.OktaIDaaSAppSaml:                       resourceAppSaml(),
 		resources.OktaIDaaSAppSamlAppSettings:            resourceAppSamlAppSettings(),
+		resources.OktaIDaaSAppSamlKeyRotation:            resourceAppSamlKeyRotation(),
 		resources.OktaIDaaSAppSecurePasswordStore:        resourceAppSecurePasswordStore(),
 		resources.OktaIDaaSAppSharedCredentials:          resourceAppSharedCredentials(),
 		resources.OktaIDaaSAppSignOnPolicyRule:           resourceAppSignOnPolicyRule(),
@@ -305,6 +415,7 @@ func ProviderDataSources() map[string]*schema.Resource {
 		resources.OktaIDaaSPolicy:                   dataSourcePolicy(),
 		resources.OktaIDaaSPolicyRulePassword:       dataSourcePolicyRulePassword(),
 		resources.OktaIDaaSRoleSubscription:         dataSourceRoleSubscription(),
+		resources.OktaIDaaSSamlExpiringCertificates: dataSourceSamlExpiringCertificates(),
 		resources.OktaIDaaSTheme:                    dataSourceTheme(),
 		resources.OktaIDaaSThemes:                   dataSourceThemes(),
 		resources.OktaIDaaSTrustedOrigins:           dataSourceTrustedOrigins(),
@@ -387,6 +498,23 @@ func resourceConfiguration(req resource.ConfigureRequest, resp *resource.Configu
 	return p
 }
 
+func ephemeralResourceConfiguration(req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) *config.Config {
+	if req.ProviderData == nil {
+		return nil
+	}
+
+	p, ok := req.ProviderData.(*config.Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *config.Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return nil
+	}
+
+	return p
+}
+
 func frameworkResourceOIEOnlyFeatureError(name string) fwdiag.Diagnostics {
 	return frameworkOIEOnlyFeatureError("resources", name)
 }
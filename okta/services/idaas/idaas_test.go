@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	schema_sdk "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -117,6 +118,23 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+// TestProviderSchema gets the schemas of the muxed provider, which fails when
+// a framework schema can't be served over protocol version 5, e.g. because it
+// has nested attributes.
+func TestProviderSchema(t *testing.T) {
+	server, err := acctest.ProvidersForTest(t.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp, err := server().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+}
+
 func TestProvider_impl(t *testing.T) {
 	_ = provider.Provider()
 }
@@ -0,0 +1,104 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+)
+
+var _ list.ListResourceWithRawV5Schemas = &appListResource{}
+
+// appListResource lists the apps of the org with a given sign on mode.
+type appListResource struct {
+	sdkListResource
+	signOnMode string
+}
+
+type appListModel struct {
+	Label       types.String `tfsdk:"label"`
+	LabelPrefix types.String `tfsdk:"label_prefix"`
+	ActiveOnly  types.Bool   `tfsdk:"active_only"`
+}
+
+func newAppOAuthListResource() list.ListResource {
+	return newAppListResource("_app_oauth", providerResource(resources.OktaIDaaSAppOAuth), "OPENID_CONNECT")
+}
+
+func newAppSamlListResource() list.ListResource {
+	return newAppListResource("_app_saml", providerResource(resources.OktaIDaaSAppSaml), "SAML_2_0")
+}
+
+func newAppListResource(typeName string, resource func() *schema.Resource, signOnMode string) list.ListResource {
+	return &appListResource{
+		sdkListResource: sdkListResource{typeName: typeName, resource: resource},
+		signOnMode:      signOnMode,
+	}
+}
+
+func (r *appListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: fmt.Sprintf("Lists the apps of the org with the %s sign on mode.", r.signOnMode),
+		Attributes: map[string]listschema.Attribute{
+			"label": listschema.StringAttribute{
+				Optional:    true,
+				Description: "The label of the apps.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("label_prefix")),
+				},
+			},
+			"label_prefix": listschema.StringAttribute{
+				Optional:    true,
+				Description: "The prefix of the label of the apps.",
+			},
+			"active_only": listschema.BoolAttribute{
+				Optional:    true,
+				Description: "Lists only the active apps.",
+			},
+		},
+	}
+}
+
+func (r *appListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data appListModel
+	if diags := req.Config.Get(ctx, &data); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	filters := &AppFilters{Label: data.Label.ValueString(), LabelPrefix: data.LabelPrefix.ValueString()}
+	if data.ActiveOnly.ValueBool() {
+		filters.Status = fmt.Sprintf(`status eq "%s"`, StatusActive)
+	}
+	pages := func(yield func([]listedObject, error) bool) {
+		for apps, err := range appPagesV5(ctx, r.Config, filters, utils.DefaultPaginationLimit) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			var objects []listedObject
+			for _, a := range apps {
+				app, ok := a.GetActualInstance().(OktaApp)
+				if !ok || app.GetSignOnMode() != r.signOnMode {
+					continue
+				}
+				// q matches the start of the label, the label has to be checked
+				if filters.Label != "" && app.GetLabel() != filters.Label {
+					continue
+				}
+				objects = append(objects, listedObject{ID: app.GetId(), DisplayName: app.GetLabel()})
+			}
+			if !yield(objects, nil) {
+				return
+			}
+		}
+	}
+	stream.Results = r.results(ctx, req, pages, "failed to list apps")
+}
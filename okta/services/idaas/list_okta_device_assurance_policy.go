@@ -0,0 +1,147 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/okta-sdk-golang/v4/okta"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+var (
+	_ list.ListResource              = &deviceAssuranceListResource{}
+	_ list.ListResourceWithConfigure = &deviceAssuranceListResource{}
+)
+
+// deviceAssuranceListResource lists the device assurance policies of the org
+// for one platform.
+type deviceAssuranceListResource struct {
+	*config.Config
+	typeName string
+	platform string
+	// state maps a device assurance policy of the platform to the state of
+	// its managed resource
+	state func(*okta.ListDeviceAssurancePolicies200ResponseInner) (any, diag.Diagnostics)
+}
+
+func newPolicyDeviceAssuranceAndroidListResource() list.ListResource {
+	return &deviceAssuranceListResource{
+		typeName: "_policy_device_assurance_android",
+		platform: "ANDROID",
+		state: func(policy *okta.ListDeviceAssurancePolicies200ResponseInner) (any, diag.Diagnostics) {
+			var state policyDeviceAssuranceAndroidResourceModel
+			return &state, mapDeviceAssuranceAndroidToState(policy, &state)
+		},
+	}
+}
+
+func newPolicyDeviceAssuranceChromeOSListResource() list.ListResource {
+	return &deviceAssuranceListResource{
+		typeName: "_policy_device_assurance_chromeos",
+		platform: "CHROMEOS",
+		state: func(policy *okta.ListDeviceAssurancePolicies200ResponseInner) (any, diag.Diagnostics) {
+			var state policyDeviceAssuranceChromeOSResourceModel
+			return &state, mapDeviceAssuranceChromeOSToState(policy, &state)
+		},
+	}
+}
+
+func newPolicyDeviceAssuranceIOSListResource() list.ListResource {
+	return &deviceAssuranceListResource{
+		typeName: "_policy_device_assurance_ios",
+		platform: "IOS",
+		state: func(policy *okta.ListDeviceAssurancePolicies200ResponseInner) (any, diag.Diagnostics) {
+			var state policyDeviceAssuranceIOSResourceModel
+			return &state, mapDeviceAssuranceIOSToState(policy, &state)
+		},
+	}
+}
+
+func newPolicyDeviceAssuranceMacOSListResource() list.ListResource {
+	return &deviceAssuranceListResource{
+		typeName: "_policy_device_assurance_macos",
+		platform: "MACOS",
+		state: func(policy *okta.ListDeviceAssurancePolicies200ResponseInner) (any, diag.Diagnostics) {
+			var state policyDeviceAssuranceMacOSResourceModel
+			return &state, mapDeviceAssuranceMacOSToState(policy, &state)
+		},
+	}
+}
+
+func newPolicyDeviceAssuranceWindowsListResource() list.ListResource {
+	return &deviceAssuranceListResource{
+		typeName: "_policy_device_assurance_windows",
+		platform: "WINDOWS",
+		state: func(policy *okta.ListDeviceAssurancePolicies200ResponseInner) (any, diag.Diagnostics) {
+			var state policyDeviceAssuranceWindowsResourceModel
+			return &state, mapDeviceAssuranceWindowsToState(policy, &state)
+		},
+	}
+}
+
+func (r *deviceAssuranceListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.typeName
+}
+
+func (r *deviceAssuranceListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *deviceAssuranceListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: fmt.Sprintf("Lists the device assurance policies of the org for the %s platform.", r.platform),
+	}
+}
+
+func (r *deviceAssuranceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	apiRequest := r.OktaIDaaSClient.OktaSDKClientV3().DeviceAssuranceAPI.ListDeviceAssurancePolicies(ctx)
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var pushed int64
+		policies, resp, err := apiRequest.Execute()
+		for {
+			if err != nil {
+				push(listResultError("failed to list device assurance policies", err))
+				return
+			}
+			for i := range policies {
+				policy, ok := policies[i].GetActualInstance().(interface {
+					GetId() string
+					GetName() string
+					GetPlatform() string
+				})
+				if !ok || policy.GetPlatform() != r.platform {
+					continue
+				}
+				result := req.NewListResult(ctx)
+				result.DisplayName = policy.GetName()
+				result.Diagnostics.Append(result.Identity.Set(ctx, idIdentityModel{ID: types.StringValue(policy.GetId())})...)
+				if req.IncludeResource {
+					state, diags := r.state(&policies[i])
+					result.Diagnostics.Append(diags...)
+					if !diags.HasError() {
+						result.Diagnostics.Append(result.Resource.Set(ctx, state)...)
+					}
+				}
+				if !push(result) {
+					return
+				}
+				pushed++
+				if listLimitReached(req, pushed) {
+					return
+				}
+			}
+			if !resp.HasNextPage() {
+				return
+			}
+			var morePolicies []okta.ListDeviceAssurancePolicies200ResponseInner
+			resp, err = resp.Next(&morePolicies)
+			policies = morePolicies
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package idaas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+)
+
+var _ list.ListResourceWithRawV5Schemas = &groupListResource{}
+
+type groupListResource struct {
+	sdkListResource
+}
+
+type groupListModel struct {
+	Q      types.String `tfsdk:"q"`
+	Search types.String `tfsdk:"search"`
+	Type   types.String `tfsdk:"type"`
+}
+
+func newGroupListResource() list.ListResource {
+	return &groupListResource{sdkListResource{typeName: "_group", resource: providerResource(resources.OktaIDaaSGroup)}}
+}
+
+func (r *groupListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: "Lists the groups of the org.",
+		Attributes: map[string]listschema.Attribute{
+			"q": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Searches the name property of groups for matching value.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("search")),
+				},
+			},
+			"search": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Searches for groups with a supported filtering expression for all attributes except for '_embedded', '_links', and 'objectClass'.",
+			},
+			"type": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Type of the groups, `OKTA_GROUP` by default as groups of other types can't be managed with `okta_group`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("OKTA_GROUP", "APP_GROUP", "BUILT_IN"),
+					stringvalidator.ConflictsWith(path.MatchRoot("search")),
+				},
+			},
+		},
+	}
+}
+
+func (r *groupListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data groupListModel
+	if diags := req.Config.Get(ctx, &data); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	search := groupSearch{
+		Q:      data.Q.ValueString(),
+		Search: data.Search.ValueString(),
+		Type:   data.Type.ValueString(),
+		Limit:  int32(utils.DefaultPaginationLimit),
+	}
+	if search.Type == "" && search.Search == "" {
+		search.Type = "OKTA_GROUP"
+	}
+	pages := func(yield func([]listedObject, error) bool) {
+		for groups, err := range searchGroupPages(ctx, r.OktaIDaaSClient.OktaSDKClientV5(), search) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			objects := make([]listedObject, len(groups))
+			for i, group := range groups {
+				objects[i] = listedObject{ID: group.GetId(), DisplayName: group.Profile.GetName()}
+			}
+			if !yield(objects, nil) {
+				return
+			}
+		}
+	}
+	stream.Results = r.results(ctx, req, pages, "failed to list groups")
+}
@@ -0,0 +1,77 @@
+package idaas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	v5okta "github.com/okta/okta-sdk-golang/v5/okta"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+var (
+	_ list.ListResource              = &hookKeyListResource{}
+	_ list.ListResourceWithConfigure = &hookKeyListResource{}
+)
+
+type hookKeyListResource struct {
+	*config.Config
+}
+
+func newHookKeyListResource() list.ListResource {
+	return &hookKeyListResource{}
+}
+
+func (r *hookKeyListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hook_key"
+}
+
+func (r *hookKeyListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *hookKeyListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: "Lists the inline hook keys of the org.",
+	}
+}
+
+func (r *hookKeyListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	apiRequest := r.OktaIDaaSClient.OktaSDKClientV5().HookKeyAPI.ListHookKeys(ctx)
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var pushed int64
+		hookKeys, resp, err := apiRequest.Execute()
+		for {
+			if err != nil {
+				push(listResultError("failed to list hook keys", err))
+				return
+			}
+			for i := range hookKeys {
+				result := req.NewListResult(ctx)
+				result.DisplayName = hookKeys[i].GetName()
+				result.Diagnostics.Append(result.Identity.Set(ctx, idIdentityModel{ID: types.StringValue(hookKeys[i].GetId())})...)
+				if req.IncludeResource {
+					var state hookKeyModel
+					applyHookKeyToState(&state, &hookKeys[i])
+					result.Diagnostics.Append(result.Resource.Set(ctx, &state)...)
+				}
+				if !push(result) {
+					return
+				}
+				pushed++
+				if listLimitReached(req, pushed) {
+					return
+				}
+			}
+			if !resp.HasNextPage() {
+				return
+			}
+			var moreHookKeys []v5okta.HookKey
+			resp, err = resp.Next(&moreHookKeys)
+			hookKeys = moreHookKeys
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/okta-sdk-golang/v4/okta"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+var (
+	_ list.ListResource              = &logStreamListResource{}
+	_ list.ListResourceWithConfigure = &logStreamListResource{}
+)
+
+type logStreamListResource struct {
+	*config.Config
+}
+
+type logStreamListModel struct {
+	Type types.String `tfsdk:"type"`
+}
+
+func newLogStreamListResource() list.ListResource {
+	return &logStreamListResource{}
+}
+
+func (r *logStreamListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_log_stream"
+}
+
+func (r *logStreamListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *logStreamListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: "Lists the log streams of the org.",
+		Attributes: map[string]listschema.Attribute{
+			"type": listschema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Type of the log streams, %q or %q.", logStreamTypeEventBridge, logStreamTypeSplunk),
+				Validators: []validator.String{
+					stringvalidator.OneOf(logStreamTypeEventBridge, logStreamTypeSplunk),
+				},
+			},
+		},
+	}
+}
+
+func (r *logStreamListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data logStreamListModel
+	if diags := req.Config.Get(ctx, &data); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	apiRequest := r.OktaIDaaSClient.OktaSDKClientV3().LogStreamAPI.ListLogStreams(ctx)
+	if _type := data.Type.ValueString(); _type != "" {
+		apiRequest = apiRequest.Filter(fmt.Sprintf(`type eq "%s"`, _type))
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var pushed int64
+		logStreams, resp, err := apiRequest.Execute()
+		for {
+			if err != nil {
+				push(listResultError("failed to list log streams", err))
+				return
+			}
+			for i := range logStreams {
+				result := req.NewListResult(ctx)
+				logStream, err := normalizeLogSteamResponse(&logStreams[i])
+				if err != nil {
+					result.Diagnostics.AddError("failed to normalize log stream", err.Error())
+					push(result)
+					return
+				}
+				result.DisplayName = logStream.Name
+				result.Diagnostics.Append(result.Identity.Set(ctx, idIdentityModel{ID: types.StringValue(logStream.Id)})...)
+				if req.IncludeResource {
+					var state logStreamModel
+					applyLogStreamToState(ctx, logStream, &state)
+					result.Diagnostics.Append(result.Resource.Set(ctx, &state)...)
+				}
+				if !push(result) {
+					return
+				}
+				pushed++
+				if listLimitReached(req, pushed) {
+					return
+				}
+			}
+			if !resp.HasNextPage() {
+				return
+			}
+			var moreLogStreams []okta.ListLogStreams200ResponseInner
+			resp, err = resp.Next(&moreLogStreams)
+			logStreams = moreLogStreams
+		}
+	}
+}
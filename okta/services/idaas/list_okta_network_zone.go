@@ -0,0 +1,85 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	v6okta "github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+var _ list.ListResourceWithRawV5Schemas = &networkZoneListResource{}
+
+// networkZoneListResource lists the network zones of the org. The system
+// zones are left out as they can't be created or deleted.
+type networkZoneListResource struct {
+	sdkListResource
+}
+
+type networkZoneListModel struct {
+	Usage types.String `tfsdk:"usage"`
+}
+
+func newNetworkZoneListResource() list.ListResource {
+	return &networkZoneListResource{sdkListResource{typeName: "_network_zone", resource: providerResource(resources.OktaIDaaSNetworkZone)}}
+}
+
+func (r *networkZoneListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: "Lists the network zones of the org, other than the system zones.",
+		Attributes: map[string]listschema.Attribute{
+			"usage": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Usage of the network zones.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("POLICY", "BLOCKLIST"),
+				},
+			},
+		},
+	}
+}
+
+func (r *networkZoneListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data networkZoneListModel
+	if diags := req.Config.Get(ctx, &data); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	apiRequest := r.OktaIDaaSClient.OktaSDKClientV6().NetworkZoneAPI.ListNetworkZones(ctx)
+	if usage := data.Usage.ValueString(); usage != "" {
+		apiRequest = apiRequest.Filter(fmt.Sprintf(`usage eq "%s"`, usage))
+	}
+	pages := func(yield func([]listedObject, error) bool) {
+		zones, resp, err := apiRequest.Execute()
+		for {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			var objects []listedObject
+			for _, z := range zones {
+				zone, ok := z.GetActualInstance().(interface {
+					GetId() string
+					GetName() string
+					GetSystem() bool
+				})
+				if !ok || zone.GetSystem() {
+					continue
+				}
+				objects = append(objects, listedObject{ID: zone.GetId(), DisplayName: zone.GetName()})
+			}
+			if !yield(objects, nil) || !resp.HasNextPage() {
+				return
+			}
+			var moreZones []v6okta.ListNetworkZones200ResponseInner
+			resp, err = resp.Next(&moreZones)
+			zones = moreZones
+		}
+	}
+	stream.Results = r.results(ctx, req, pages, "failed to list network zones")
+}
@@ -0,0 +1,99 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+var _ list.ListResourceWithRawV5Schemas = &policyListResource{}
+
+// policyListResource lists the policies of the org of a given type. The
+// system policies are left out, they are managed by the matching _default
+// resources.
+type policyListResource struct {
+	sdkListResource
+	policyType string
+}
+
+type policyListModel struct {
+	Status types.String `tfsdk:"status"`
+}
+
+func newPolicyMfaListResource() list.ListResource {
+	return newPolicyListResource("_policy_mfa", providerResource(resources.OktaIDaaSPolicyMfa), sdk.MfaPolicyType)
+}
+
+func newPolicyPasswordListResource() list.ListResource {
+	return newPolicyListResource("_policy_password", providerResource(resources.OktaIDaaSPolicyPassword), sdk.PasswordPolicyType)
+}
+
+func newPolicyProfileEnrollmentListResource() list.ListResource {
+	return newPolicyListResource("_policy_profile_enrollment", providerResource(resources.OktaIDaaSPolicyProfileEnrollment), sdk.ProfileEnrollmentPolicyType)
+}
+
+func newPolicySignOnListResource() list.ListResource {
+	return newPolicyListResource("_policy_signon", providerResource(resources.OktaIDaaSPolicySignOn), sdk.SignOnPolicyType)
+}
+
+func newPolicyListResource(typeName string, resource func() *schema.Resource, policyType string) list.ListResource {
+	return &policyListResource{
+		sdkListResource: sdkListResource{typeName: typeName, resource: resource},
+		policyType:      policyType,
+	}
+}
+
+func (r *policyListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: fmt.Sprintf("Lists the %s policies of the org, other than the default policy.", r.policyType),
+		Attributes: map[string]listschema.Attribute{
+			"status": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Status of the policies.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(StatusActive, StatusInactive),
+				},
+			},
+		},
+	}
+}
+
+func (r *policyListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data policyListModel
+	if diags := req.Config.Get(ctx, &data); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	client := getOktaClientFromMetadata(r.Config)
+	pages := func(yield func([]listedObject, error) bool) {
+		policies, resp, err := client.Policy.ListPolicies(ctx, &query.Params{Type: r.policyType, Status: data.Status.ValueString()})
+		for {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			var objects []listedObject
+			for _, p := range policies {
+				policy := p.(*sdk.Policy)
+				if policy.System != nil && *policy.System {
+					continue
+				}
+				objects = append(objects, listedObject{ID: policy.Id, DisplayName: policy.Name})
+			}
+			if !yield(objects, nil) || !resp.HasNextPage() {
+				return
+			}
+			resp, err = resp.Next(ctx, &policies)
+		}
+	}
+	stream.Results = r.results(ctx, req, pages, "failed to list policies")
+}
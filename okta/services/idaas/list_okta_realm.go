@@ -0,0 +1,101 @@
+package idaas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	v5okta "github.com/okta/okta-sdk-golang/v5/okta"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+var (
+	_ list.ListResource              = &realmListResource{}
+	_ list.ListResourceWithConfigure = &realmListResource{}
+)
+
+// realmListResource lists the realms of the org. The default realm is left
+// out as it can't be created or deleted.
+type realmListResource struct {
+	*config.Config
+}
+
+type realmListModel struct {
+	Search types.String `tfsdk:"search"`
+}
+
+func newRealmListResource() list.ListResource {
+	return &realmListResource{}
+}
+
+func (r *realmListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_realm"
+}
+
+func (r *realmListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *realmListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: "Lists the realms of the org, other than the default realm.",
+		Attributes: map[string]listschema.Attribute{
+			"search": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Searches for realms with a supported filtering expression, e.g. `profile.name sw \"Partners\"`.",
+			},
+		},
+	}
+}
+
+func (r *realmListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data realmListModel
+	if diags := req.Config.Get(ctx, &data); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	apiRequest := r.OktaIDaaSClient.OktaSDKClientV5().RealmAPI.ListRealms(ctx)
+	if search := data.Search.ValueString(); search != "" {
+		apiRequest = apiRequest.Search(search)
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var pushed int64
+		realms, resp, err := apiRequest.Execute()
+		for {
+			if err != nil {
+				push(listResultError("failed to list realms", err))
+				return
+			}
+			for i := range realms {
+				if realms[i].GetIsDefault() {
+					continue
+				}
+				result := req.NewListResult(ctx)
+				result.DisplayName = realms[i].Profile.Name
+				result.Diagnostics.Append(result.Identity.Set(ctx, idIdentityModel{ID: types.StringValue(realms[i].GetId())})...)
+				if req.IncludeResource {
+					var state realmModel
+					result.Diagnostics.Append(mapRealmResourceToState(&realms[i], &state)...)
+					state.RealmType = types.StringNull()
+					result.Diagnostics.Append(result.Resource.Set(ctx, &state)...)
+				}
+				if !push(result) {
+					return
+				}
+				pushed++
+				if listLimitReached(req, pushed) {
+					return
+				}
+			}
+			if !resp.HasNextPage() {
+				return
+			}
+			var moreRealms []v5okta.Realm
+			resp, err = resp.Next(&moreRealms)
+			realms = moreRealms
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+// listedObject is an Okta object found by a list resource.
+type listedObject struct {
+	ID          string
+	DisplayName string
+}
+
+// sdkListResource implements the parts of list.ListResource shared by the
+// list resources of managed resources implemented with the plugin SDK. The
+// managed resource must have been given an identity with withIDIdentity.
+type sdkListResource struct {
+	*config.Config
+	typeName string
+	// resource returns the managed resource as the provider serves it, see
+	// providerResource
+	resource func() *schema.Resource
+}
+
+// providerResources are the SDK resources of ProviderResources, the ones the
+// provider serves, built once for the list resources.
+var providerResources = sync.OnceValue(ProviderResources)
+
+// providerResource returns the SDK resource name as ProviderResources serves
+// it, with the arguments its wrappers add, e.g. the org argument. The schema
+// and the state of the list results have to match the schema the provider
+// serves for the resource exactly.
+func providerResource(name string) func() *schema.Resource {
+	return func() *schema.Resource {
+		return providerResources()[name]
+	}
+}
+
+func (r *sdkListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.typeName
+}
+
+func (r *sdkListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *sdkListResource) RawV5Schemas(ctx context.Context, _ list.RawV5SchemaRequest, resp *list.RawV5SchemaResponse) {
+	res := r.resource()
+	resp.ProtoV5Schema = res.ProtoSchema(ctx)()
+	resp.ProtoV5IdentitySchema = res.ProtoIdentitySchema(ctx)()
+}
+
+// listedPages yields the objects found by a list resource a page at a time.
+// Pages are only fetched while the list results are streamed so that listing
+// stops once the limit of the request is reached.
+type listedPages = iter.Seq2[[]listedObject, error]
+
+// results streams a list result for each of the objects of pages, up to the
+// limit of the request. When the request includes the resource the managed
+// resource is read, objects that are gone by then are skipped.
+func (r *sdkListResource) results(ctx context.Context, req list.ListRequest, pages listedPages, errSummary string) iter.Seq[list.ListResult] {
+	return func(push func(list.ListResult) bool) {
+		var pushed int64
+		for objects, err := range pages {
+			if err != nil {
+				push(listResultError(errSummary, err))
+				return
+			}
+			for _, object := range objects {
+				result, ok := r.result(ctx, req, object)
+				if !ok {
+					continue
+				}
+				if !push(result) {
+					return
+				}
+				pushed++
+				if listLimitReached(req, pushed) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *sdkListResource) result(ctx context.Context, req list.ListRequest, object listedObject) (list.ListResult, bool) {
+	result := req.NewListResult(ctx)
+	result.DisplayName = object.DisplayName
+
+	res := r.resource()
+	d := res.Data(nil)
+	d.SetId(object.ID)
+	identity, err := d.Identity()
+	if err == nil {
+		err = identity.Set("id", object.ID)
+	}
+	if err != nil {
+		result.Diagnostics.AddError(fmt.Sprintf("failed to set identity of %s", object.ID), err.Error())
+		return result, true
+	}
+
+	// the org argument is left null, the objects are read from the org of the
+	// provider
+	if req.IncludeResource {
+		diags := res.ReadContext(ctx, d, r.Config)
+		for _, readDiag := range diags {
+			if readDiag.Severity == diag.Error {
+				result.Diagnostics.AddError(readDiag.Summary, readDiag.Detail)
+			} else {
+				result.Diagnostics.AddWarning(readDiag.Summary, readDiag.Detail)
+			}
+		}
+		if result.Diagnostics.HasError() {
+			return result, true
+		}
+		if d.Id() == "" {
+			return result, false
+		}
+		state, err := d.TfTypeResourceState()
+		if err != nil {
+			result.Diagnostics.AddError(fmt.Sprintf("failed to read state of %s", object.ID), err.Error())
+			return result, true
+		}
+		result.Resource.Raw = *state
+	}
+
+	identityState, err := d.TfTypeIdentityState()
+	if err != nil {
+		result.Diagnostics.AddError(fmt.Sprintf("failed to read identity of %s", object.ID), err.Error())
+		return result, true
+	}
+	result.Identity.Raw = *identityState
+	return result, true
+}
+
+// listResultError returns a list result reporting err.
+func listResultError(summary string, err error) list.ListResult {
+	var diags fwdiag.Diagnostics
+	diags.AddError(summary, err.Error())
+	return list.ListResult{Diagnostics: diags}
+}
+
+// listLimitReached reports whether pushed results are all the results the
+// request asked for.
+func listLimitReached(req list.ListRequest, pushed int64) bool {
+	return req.Limit > 0 && pushed >= req.Limit
+}
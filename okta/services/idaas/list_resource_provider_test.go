@@ -0,0 +1,112 @@
+package idaas_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+	"github.com/okta/terraform-provider-okta/okta/services/idaas"
+	"github.com/stretchr/testify/require"
+)
+
+// nullConfig returns a configuration of schema with every attribute null.
+func nullConfig(t *testing.T, schema *tfprotov5.Schema) *tfprotov5.DynamicValue {
+	t.Helper()
+	objectType := schema.ValueType().(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	config, err := tfprotov5.NewDynamicValue(objectType, tftypes.NewValue(objectType, values))
+	require.NoError(t, err)
+	return &config
+}
+
+// configureProviderServer configures the provider server from the OKTA_*
+// environment variables and returns its schemas.
+func configureProviderServer(t *testing.T, server tfprotov5.ProviderServer) *tfprotov5.GetProviderSchemaResponse {
+	t.Helper()
+	ctx := context.Background()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	require.Empty(t, schemas.Diagnostics)
+	configured, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: "1.14.0",
+		Config:           nullConfig(t, schemas.Provider),
+	})
+	require.NoError(t, err)
+	for _, d := range configured.Diagnostics {
+		require.NotEqual(t, tfprotov5.DiagnosticSeverityError, d.Severity, "%s: %s", d.Summary, d.Detail)
+	}
+	return schemas
+}
+
+// TestListResourceOktaGroup_includeResource lists the groups of the fake org
+// with their resources through the provider server the provider binary
+// serves. The state of a plugin SDK resource has to decode with the resource
+// schema the provider serves, which includes the org argument.
+func TestListResourceOktaGroup_includeResource(t *testing.T) {
+	ctx := context.Background()
+	server, org := acctest.FakeOktaProviderServer(t)
+	_, err := org.Create("groups", map[string]any{"profile": map[string]any{"name": "Engineering", "description": "Builds things"}})
+	require.NoError(t, err)
+	schemas := configureProviderServer(t, server)
+
+	stream, err := server.ListResource(ctx, &tfprotov5.ListResourceRequest{
+		TypeName:        resources.OktaIDaaSGroup,
+		Config:          nullConfig(t, schemas.ListResourceSchemas[resources.OktaIDaaSGroup]),
+		IncludeResource: true,
+	})
+	require.NoError(t, err)
+	var names []string
+	for result := range stream.Results {
+		require.Empty(t, result.Diagnostics)
+		require.NotNil(t, result.Resource)
+		value, err := result.Resource.Unmarshal(schemas.ResourceSchemas[resources.OktaIDaaSGroup].ValueType())
+		require.NoError(t, err)
+		var attributes map[string]tftypes.Value
+		require.NoError(t, value.As(&attributes))
+		var name, description string
+		require.NoError(t, attributes["name"].As(&name))
+		require.NoError(t, attributes["description"].As(&description))
+		require.Equal(t, "Builds things", description)
+		require.True(t, attributes[resources.OrgArgument].IsNull(), "the groups are read from the org of the provider")
+		names = append(names, name)
+	}
+	require.Equal(t, []string{"Engineering"}, names)
+}
+
+// TestListResourceRawV5Schemas checks that the list resources of plugin SDK
+// resources hand over the schemas the provider serves for the resources, the
+// framework decodes the listed state with them.
+func TestListResourceRawV5Schemas(t *testing.T) {
+	ctx := context.Background()
+	server := acctest.ProviderServer(t)
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	identities, err := server.GetResourceIdentitySchemas(ctx, &tfprotov5.GetResourceIdentitySchemasRequest{})
+	require.NoError(t, err)
+
+	var checked int
+	for _, newListResource := range idaas.FWProviderListResources() {
+		listResource, ok := newListResource().(list.ListResourceWithRawV5Schemas)
+		if !ok {
+			continue
+		}
+		var metadata resource.MetadataResponse
+		listResource.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "okta"}, &metadata)
+		t.Run(metadata.TypeName, func(t *testing.T) {
+			var resp list.RawV5SchemaResponse
+			listResource.RawV5Schemas(ctx, list.RawV5SchemaRequest{}, &resp)
+			require.Equal(t, schemas.ResourceSchemas[metadata.TypeName], resp.ProtoV5Schema)
+			require.Equal(t, identities.IdentitySchemas[metadata.TypeName], resp.ProtoV5IdentitySchema)
+		})
+		checked++
+	}
+	require.NotZero(t, checked)
+}
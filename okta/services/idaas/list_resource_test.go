@@ -0,0 +1,308 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/okta/terraform-provider-okta/okta/api"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedAPI serves the pages of a collection of the Okta API, linking each
+// page to the next one like Okta does. It records the requests it was sent.
+type pagedAPI struct {
+	pages [][]map[string]any
+
+	lock     sync.Mutex
+	requests []*http.Request
+}
+
+func (a *pagedAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.lock.Lock()
+	a.requests = append(a.requests, r)
+	a.lock.Unlock()
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("after"))
+	if page < len(a.pages)-1 {
+		next := *r.URL
+		query := next.Query()
+		query.Set("after", strconv.Itoa(page+1))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	}
+	objects := []map[string]any{}
+	if page < len(a.pages) {
+		objects = a.pages[page]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(objects)
+}
+
+func (a *pagedAPI) query(i int, key string) string {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.requests[i].URL.Query().Get(key)
+}
+
+func (a *pagedAPI) requestCount() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return len(a.requests)
+}
+
+// listTest runs a list resource against a fake Okta API.
+type listTest struct {
+	resource list.ListResource
+	// managed is the managed resource of framework list resources, the
+	// results include its state when set
+	managed resource.Resource
+	config  map[string]tftypes.Value
+	limit   int64
+}
+
+// listTestResult is a list result reduced to what the tests check.
+type listTestResult struct {
+	ID          string
+	DisplayName string
+	Resource    *tfsdk.Resource
+}
+
+func (lt listTest) run(t *testing.T, handler http.Handler) []listTestResult {
+	t.Helper()
+	ctx := context.Background()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := api.NewOktaIDaaSAPIClient(&api.OktaAPIConfig{
+		ApiToken:       "token",
+		HttpProxy:      server.URL,
+		Logger:         hclog.NewNullLogger(),
+		MaxAPICapacity: 100,
+		OrgName:        "test",
+		Domain:         "okta.com",
+		RequestTimeout: 10,
+	})
+	require.NoError(t, err)
+	c := &config.Config{OktaIDaaSClient: client, Logger: hclog.NewNullLogger()}
+
+	var configureResp resource.ConfigureResponse
+	lt.resource.(list.ListResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: c}, &configureResp)
+	require.False(t, configureResp.Diagnostics.HasError())
+
+	var schemaResp list.ListResourceSchemaResponse
+	lt.resource.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &schemaResp)
+	configType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	configValues := map[string]tftypes.Value{}
+	for name, attributeType := range configType.AttributeTypes {
+		configValues[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := lt.config[name]; ok {
+			configValues[name] = value
+		}
+	}
+
+	req := list.ListRequest{
+		Config:                 tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(configType, configValues)},
+		Limit:                  lt.limit,
+		ResourceSchema:         resourceschema.Schema{},
+		ResourceIdentitySchema: idIdentitySchema(),
+	}
+	if lt.managed != nil {
+		var resourceSchemaResp resource.SchemaResponse
+		lt.managed.Schema(ctx, resource.SchemaRequest{}, &resourceSchemaResp)
+		req.ResourceSchema = resourceSchemaResp.Schema
+		req.IncludeResource = true
+	}
+	var stream list.ListResultsStream
+	lt.resource.List(ctx, req, &stream)
+
+	var results []listTestResult
+	for result := range stream.Results {
+		require.False(t, result.Diagnostics.HasError(), "list result has errors: %v", result.Diagnostics)
+		var id string
+		require.False(t, result.Identity.GetAttribute(ctx, path.Root("id"), &id).HasError())
+		tr := listTestResult{ID: id, DisplayName: result.DisplayName}
+		if req.IncludeResource {
+			tr.Resource = result.Resource
+		}
+		results = append(results, tr)
+	}
+	return results
+}
+
+func resourceStringAttribute(t *testing.T, r *tfsdk.Resource, name string) string {
+	t.Helper()
+	var value string
+	require.False(t, r.GetAttribute(context.Background(), path.Root(name), &value).HasError())
+	return value
+}
+
+func listTestIDs(results []listTestResult) []string {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+	return ids
+}
+
+func testApp(id, label, signOnMode string) map[string]any {
+	return map[string]any{
+		"id":          id,
+		"label":       label,
+		"name":        "test_app",
+		"signOnMode":  signOnMode,
+		"status":      StatusActive,
+		"credentials": map[string]any{},
+		"settings":    map[string]any{},
+	}
+}
+
+func TestAppListResource(t *testing.T) {
+	apps := &pagedAPI{pages: [][]map[string]any{
+		{testApp("0oa1", "Payroll", "OPENID_CONNECT"), testApp("0oa2", "Payroll SAML", "SAML_2_0")},
+		{testApp("0oa3", "Payroll Admin", "OPENID_CONNECT")},
+	}}
+	results := listTest{resource: newAppOAuthListResource()}.run(t, apps)
+	require.Equal(t, []string{"0oa1", "0oa3"}, listTestIDs(results))
+	require.Equal(t, "Payroll", results[0].DisplayName)
+
+	results = listTest{resource: newAppSamlListResource()}.run(t, apps)
+	require.Equal(t, []string{"0oa2"}, listTestIDs(results))
+
+	// q matches the start of the label, only the exact label is listed
+	results = listTest{
+		resource: newAppOAuthListResource(),
+		config:   map[string]tftypes.Value{"label": tftypes.NewValue(tftypes.String, "Payroll")},
+	}.run(t, apps)
+	require.Equal(t, []string{"0oa1"}, listTestIDs(results))
+	require.Equal(t, "Payroll", apps.query(apps.requestCount()-2, "q"))
+}
+
+func TestAppListResourceStopsAtLimit(t *testing.T) {
+	apps := &pagedAPI{pages: [][]map[string]any{
+		{testApp("0oa1", "one", "OPENID_CONNECT"), testApp("0oa2", "two", "OPENID_CONNECT")},
+		{testApp("0oa3", "three", "OPENID_CONNECT")},
+	}}
+	results := listTest{resource: newAppOAuthListResource(), limit: 2}.run(t, apps)
+	require.Equal(t, []string{"0oa1", "0oa2"}, listTestIDs(results))
+	require.Equal(t, 1, apps.requestCount(), "the second page shouldn't be fetched once the limit is reached")
+}
+
+func TestGroupListResource(t *testing.T) {
+	groups := &pagedAPI{pages: [][]map[string]any{
+		{{"id": "00g1", "type": "OKTA_GROUP", "profile": map[string]any{"name": "Engineering"}}},
+		{{"id": "00g2", "type": "OKTA_GROUP", "profile": map[string]any{"name": "Sales"}}},
+	}}
+	results := listTest{resource: newGroupListResource()}.run(t, groups)
+	require.Equal(t, []string{"00g1", "00g2"}, listTestIDs(results))
+	require.Equal(t, "Sales", results[1].DisplayName)
+	require.Equal(t, `type eq "OKTA_GROUP"`, groups.query(0, "filter"))
+}
+
+func TestNetworkZoneListResource(t *testing.T) {
+	zones := &pagedAPI{pages: [][]map[string]any{{
+		{"id": "nzo1", "name": "LegacyIpZone", "type": "IP", "usage": "POLICY", "system": true},
+		{"id": "nzo2", "name": "Office", "type": "IP", "usage": "POLICY", "system": false},
+	}}}
+	results := listTest{
+		resource: newNetworkZoneListResource(),
+		config:   map[string]tftypes.Value{"usage": tftypes.NewValue(tftypes.String, "POLICY")},
+	}.run(t, zones)
+	require.Equal(t, []string{"nzo2"}, listTestIDs(results))
+	require.Equal(t, `usage eq "POLICY"`, zones.query(0, "filter"))
+}
+
+func TestPolicyListResource(t *testing.T) {
+	policies := &pagedAPI{pages: [][]map[string]any{
+		{{"id": "00p1", "name": "Default Policy", "type": "PASSWORD", "system": true}},
+		{{"id": "00p2", "name": "Contractors", "type": "PASSWORD", "system": false}},
+	}}
+	results := listTest{resource: newPolicyPasswordListResource()}.run(t, policies)
+	require.Equal(t, []string{"00p2"}, listTestIDs(results))
+	require.Equal(t, "Contractors", results[0].DisplayName)
+	require.Equal(t, "PASSWORD", policies.query(0, "type"))
+}
+
+func TestLogStreamListResource(t *testing.T) {
+	logStreams := &pagedAPI{pages: [][]map[string]any{{{
+		"id":     "0oa1",
+		"name":   "Splunk",
+		"type":   logStreamTypeSplunk,
+		"status": StatusActive,
+		"settings": map[string]any{
+			"edition": "aws",
+			"host":    "acme.splunkcloud.com",
+		},
+	}}}}
+	results := listTest{
+		resource: newLogStreamListResource(),
+		managed:  newLogStreamResource(),
+		config:   map[string]tftypes.Value{"type": tftypes.NewValue(tftypes.String, logStreamTypeSplunk)},
+	}.run(t, logStreams)
+	require.Equal(t, []string{"0oa1"}, listTestIDs(results))
+	require.Equal(t, "Splunk", resourceStringAttribute(t, results[0].Resource, "name"))
+	require.Equal(t, fmt.Sprintf(`type eq "%s"`, logStreamTypeSplunk), logStreams.query(0, "filter"))
+}
+
+func TestRealmListResource(t *testing.T) {
+	realms := &pagedAPI{pages: [][]map[string]any{
+		{{"id": "guo1", "isDefault": true, "profile": map[string]any{"name": "Default Realm"}}},
+		{{"id": "guo2", "isDefault": false, "profile": map[string]any{"name": "Partners"}}},
+	}}
+	results := listTest{resource: newRealmListResource(), managed: newRealmResource()}.run(t, realms)
+	require.Equal(t, []string{"guo2"}, listTestIDs(results))
+	require.Equal(t, "Partners", resourceStringAttribute(t, results[0].Resource, "name"))
+}
+
+func TestHookKeyListResource(t *testing.T) {
+	hookKeys := &pagedAPI{pages: [][]map[string]any{{{
+		"id":          "HKY1",
+		"keyId":       "7fbc27fd-e3df-4522-86bf-1930110256ad",
+		"name":        "inline hooks",
+		"isUsed":      true,
+		"created":     "2024-01-01T00:00:00Z",
+		"lastUpdated": "2024-01-01T00:00:00Z",
+	}}}}
+	results := listTest{resource: newHookKeyListResource(), managed: newHookKeyResource()}.run(t, hookKeys)
+	require.Equal(t, []string{"HKY1"}, listTestIDs(results))
+	require.Equal(t, "inline hooks", results[0].DisplayName)
+	require.Equal(t, "7fbc27fd-e3df-4522-86bf-1930110256ad", resourceStringAttribute(t, results[0].Resource, "key_id"))
+}
+
+func TestDeviceAssuranceListResource(t *testing.T) {
+	policies := &pagedAPI{pages: [][]map[string]any{{
+		{"id": "dae1", "name": "Android", "platform": "ANDROID", "jailbreak": false},
+		{"id": "dae2", "name": "iOS", "platform": "IOS", "jailbreak": false},
+		{"id": "dae3", "name": "Mac", "platform": "MACOS"},
+	}}}
+	tests := []struct {
+		resource list.ListResource
+		managed  resource.Resource
+		id       string
+	}{
+		{newPolicyDeviceAssuranceAndroidListResource(), newPolicyDeviceAssuranceAndroidResource(), "dae1"},
+		{newPolicyDeviceAssuranceIOSListResource(), newPolicyDeviceAssuranceIOSResource(), "dae2"},
+		{newPolicyDeviceAssuranceMacOSListResource(), newPolicyDeviceAssuranceMacOSResource(), "dae3"},
+	}
+	for _, test := range tests {
+		results := listTest{resource: test.resource, managed: test.managed}.run(t, policies)
+		require.Equal(t, []string{test.id}, listTestIDs(results))
+		require.Equal(t, test.id, resourceStringAttribute(t, results[0].Resource, "id"))
+	}
+	results := listTest{resource: newPolicyDeviceAssuranceWindowsListResource()}.run(t, policies)
+	require.Empty(t, results)
+	results = listTest{resource: newPolicyDeviceAssuranceChromeOSListResource()}.run(t, policies)
+	require.Empty(t, results)
+}
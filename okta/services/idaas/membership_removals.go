@@ -0,0 +1,65 @@
+package idaas
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+)
+
+// membershipRemovalsSchema are the arguments of the resources whose changes
+// can remove group memberships or app assignments in bulk, limiting how many
+// one change may remove.
+var membershipRemovalsSchema = map[string]*schema.Schema{
+	"max_membership_removals": {
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateDiagFunc: utils.ValidateRemovalLimit,
+		Description:      "Largest number of memberships, like `100`, or percentage of the existing memberships, like `10%`, that one change of the resource may remove. The plan fails when a change removes more. Defaults to the provider's `max_membership_removals`, unlimited when neither is set.",
+	},
+	"override_max_membership_removals": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Apply changes that remove more memberships than `max_membership_removals` allows. To destroy the resource past the limit, set it and apply before destroying.",
+	},
+}
+
+// checkMembershipRemovals returns an error when removing removals of total
+// memberships exceeds the limit of the resource. get reads the arguments of
+// the resource, from the plan or the state. what describes the memberships,
+// e.g. "users of group 00g1".
+func checkMembershipRemovals(get func(string) interface{}, meta interface{}, what string, removals, total int) error {
+	if removals == 0 {
+		return nil
+	}
+	setting, source := membershipRemovalLimit(get, meta)
+	if setting == "" {
+		return nil
+	}
+	limit, err := utils.ParseRemovalLimit(setting)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", source, err)
+	}
+	if limit.Allows(removals, total) {
+		return nil
+	}
+	return fmt.Errorf("the change removes %d of the %d %s, more than the %s allows (%s). Set override_max_membership_removals = true to apply it anyway",
+		removals, total, what, source, limit)
+}
+
+// membershipRemovalLimit returns the max_membership_removals of the resource,
+// or of the provider when the resource doesn't set it, and where it is set.
+// It is empty when there is no limit or the resource overrides it.
+func membershipRemovalLimit(get func(string) interface{}, meta interface{}) (setting, source string) {
+	if get("override_max_membership_removals").(bool) {
+		return "", ""
+	}
+	if setting := get("max_membership_removals").(string); setting != "" {
+		return setting, "max_membership_removals of the resource"
+	}
+	if cfg, ok := meta.(*config.Config); ok && cfg.MaxMembershipRemovals != "" {
+		return cfg.MaxMembershipRemovals, "max_membership_removals of the provider"
+	}
+	return "", ""
+}
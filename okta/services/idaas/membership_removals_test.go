@@ -0,0 +1,140 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/okta/api"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+// testRemovalArgs returns a get func reading the removal limit arguments of a
+// resource.
+func testRemovalArgs(limit string, override bool) func(string) interface{} {
+	args := map[string]interface{}{
+		"max_membership_removals":          limit,
+		"override_max_membership_removals": override,
+	}
+	return func(key string) interface{} { return args[key] }
+}
+
+func TestCheckMembershipRemovals(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         string
+		override      bool
+		providerLimit string
+		removals      int
+		total         int
+		err           string
+	}{
+		{name: "no limit", removals: 100, total: 100},
+		{name: "nothing removed", limit: "0", total: 100},
+		{name: "count within", limit: "10", removals: 10, total: 20},
+		{name: "count exceeded", limit: "10", removals: 11, total: 200, err: "the change removes 11 of the 200 users of group 00g1, more than the max_membership_removals of the resource allows (10)"},
+		{name: "percent within", limit: "10%", removals: 5, total: 50},
+		{name: "percent exceeded", limit: "10%", removals: 6, total: 50, err: "removes 6 of the 50"},
+		{name: "percent of empty group", limit: "10%", removals: 1, err: "removes 1 of the 0"},
+		{name: "destroy with percent", limit: "99%", removals: 50, total: 50, err: "removes 50 of the 50"},
+		{name: "destroy with all", limit: "100%", removals: 50, total: 50},
+		{name: "provider limit", providerLimit: "5", removals: 6, total: 50, err: "more than the max_membership_removals of the provider allows (5)"},
+		{name: "resource limit over provider", limit: "10", providerLimit: "5", removals: 6, total: 50},
+		{name: "override", limit: "1", override: true, providerLimit: "1", removals: 6, total: 50},
+		{name: "invalid", limit: "ten", removals: 1, total: 1, err: "invalid max_membership_removals of the resource"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := &config.Config{MaxMembershipRemovals: tt.providerLimit}
+			err := checkMembershipRemovals(testRemovalArgs(tt.limit, tt.override), meta, groupMembershipsDescription("00g1"), tt.removals, tt.total)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+// testIDaaSClient serves the v2 client of an API served by a test server.
+type testIDaaSClient struct {
+	api.OktaIDaaSClient
+	client *sdk.Client
+}
+
+func (c *testIDaaSClient) OktaSDKClientV2() *sdk.Client { return c.client }
+
+func TestCheckGroupMembershipRemovals(t *testing.T) {
+	var listed int
+	client := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/groups/00g1/users" {
+			t.Errorf("unexpected request %s", r.URL)
+			return
+		}
+		listed++
+		members := make([]string, 10)
+		for i := range members {
+			members[i] = fmt.Sprintf(`{"id":"u%d"}`, i)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(members, ","))
+	})
+	meta := &config.Config{OktaIDaaSClient: &testIDaaSClient{client: client}}
+	users := func(ids ...int) []string {
+		var result []string
+		for _, id := range ids {
+			result = append(result, fmt.Sprintf("u%d", id))
+		}
+		return result
+	}
+	ctx := context.Background()
+
+	require.NoError(t, checkGroupMembershipRemovals(ctx, testRemovalArgs("", false), meta, "00g1", users(0, 1, 2)))
+	require.NoError(t, checkGroupMembershipRemovals(ctx, testRemovalArgs("20%", false), meta, "00g1", nil))
+	require.Zero(t, listed, "the group is listed without removals or a limit")
+
+	// the base is the 10 members of the group
+	require.NoError(t, checkGroupMembershipRemovals(ctx, testRemovalArgs("20%", false), meta, "00g1", users(0, 1)))
+	require.ErrorContains(t, checkGroupMembershipRemovals(ctx, testRemovalArgs("20%", false), meta, "00g1", users(0, 1, 2)), "removes 3 of the 10")
+	// users that aren't members aren't removed
+	require.NoError(t, checkGroupMembershipRemovals(ctx, testRemovalArgs("20%", false), meta, "00g1", users(0, 1, 20, 21)))
+	// destroying the resource removes all its users
+	require.ErrorContains(t, checkGroupMembershipRemovals(ctx, testRemovalArgs("50%", false), meta, "00g1", users(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)), "removes 10 of the 10")
+	require.NoError(t, checkGroupMembershipRemovals(ctx, testRemovalArgs("50%", false), meta, "00g1", users(0, 1, 2, 3, 4)))
+	require.Equal(t, 5, listed)
+}
+
+func TestCheckGroupRuleRemovals(t *testing.T) {
+	var listed int
+	client := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+		listed++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/groups/00g1/users":
+			fmt.Fprint(w, `[{"id":"u0"},{"id":"u1"},{"id":"u2"}]`)
+		case "/api/v1/groups/00g2/users":
+			fmt.Fprint(w, `[{"id":"u3"},{"id":"u4"}]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+	meta := &config.Config{OktaIDaaSClient: &testIDaaSClient{client: client}}
+	ctx := context.Background()
+	groups := []string{"00g1", "00g2"}
+
+	require.NoError(t, checkGroupRuleRemovals(ctx, testRemovalArgs("", false), meta, groups))
+	// the share of the members the rule assigned is unknown
+	require.NoError(t, checkGroupRuleRemovals(ctx, testRemovalArgs("10%", false), meta, groups))
+	require.ErrorContains(t, checkGroupRuleRemovals(ctx, testRemovalArgs("ten", false), meta, groups), "invalid max_membership_removals of the resource")
+	require.Zero(t, listed, "the groups are listed without a limit on the number of memberships")
+
+	// every member of the groups counts
+	require.NoError(t, checkGroupRuleRemovals(ctx, testRemovalArgs("5", false), meta, groups))
+	require.ErrorContains(t, checkGroupRuleRemovals(ctx, testRemovalArgs("4", false), meta, groups), "removes 5 of the 5")
+	require.NoError(t, checkGroupRuleRemovals(ctx, testRemovalArgs("4", true), meta, groups))
+	require.Equal(t, 4, listed)
+}
@@ -0,0 +1,88 @@
+package idaas
+
+import (
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/iprange"
+	"github.com/stretchr/testify/require"
+)
+
+// testNetworkZone returns an IP zone with gateways.
+func testNetworkZone(t *testing.T, id string, gateways ...string) networkZoneGateways {
+	zone := networkZoneGateways{id: id, name: "zone " + id}
+	for _, gateway := range gateways {
+		r, err := iprange.Parse(gateway)
+		require.NoError(t, err)
+		zone.gateways = append(zone.gateways, gateway)
+		zone.ranges = append(zone.ranges, r)
+	}
+	return zone
+}
+
+func TestNetworkZoneOverlaps(t *testing.T) {
+	// overlap describes an overlap as zone/gateway other_zone/other_gateway addresses
+	type overlap [5]string
+	tests := []struct {
+		name     string
+		zones    []networkZoneGateways
+		expected []overlap
+	}{
+		{
+			name: "no overlap",
+			zones: []networkZoneGateways{
+				testNetworkZone(t, "nzo1", "10.0.0.0/24", "2001:db8::/48"),
+				testNetworkZone(t, "nzo2", "10.0.1.0/24", "2001:db9::/48"),
+			},
+		},
+		{
+			name: "overlap",
+			zones: []networkZoneGateways{
+				testNetworkZone(t, "nzo1", "10.0.0.0/16", "2001:db8::/48"),
+				testNetworkZone(t, "nzo2", "10.0.255.0-10.1.0.255", "192.168.0.1"),
+			},
+			expected: []overlap{{"nzo1", "10.0.0.0/16", "nzo2", "10.0.255.0-10.1.0.255", "10.0.255.0/24"}},
+		},
+		{
+			name: "IPv6 and IPv4-mapped addresses",
+			zones: []networkZoneGateways{
+				testNetworkZone(t, "nzo1", "2001:db8::/32", "::ffff:192.168.0.1"),
+				testNetworkZone(t, "nzo2", "2001:db8:1::/48", "192.168.0.0/24"),
+			},
+			expected: []overlap{
+				{"nzo1", "2001:db8::/32", "nzo2", "2001:db8:1::/48", "2001:db8:1::/48"},
+				{"nzo1", "::ffff:192.168.0.1", "nzo2", "192.168.0.0/24", "192.168.0.1/32"},
+			},
+		},
+		{
+			name: "gateways of a zone overlapping each other",
+			zones: []networkZoneGateways{
+				testNetworkZone(t, "nzo1", "10.0.0.0/8", "10.1.0.0/16"),
+				testNetworkZone(t, "nzo2", "192.168.0.0/16"),
+			},
+		},
+		{
+			name: "three zones",
+			zones: []networkZoneGateways{
+				testNetworkZone(t, "nzo1", "10.0.0.0/8"),
+				testNetworkZone(t, "nzo2", "10.1.0.0/16"),
+				testNetworkZone(t, "nzo3", "10.1.2.3"),
+			},
+			expected: []overlap{
+				{"nzo1", "10.0.0.0/8", "nzo2", "10.1.0.0/16", "10.1.0.0/16"},
+				{"nzo1", "10.0.0.0/8", "nzo3", "10.1.2.3", "10.1.2.3/32"},
+				{"nzo2", "10.1.0.0/16", "nzo3", "10.1.2.3", "10.1.2.3/32"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var overlaps []overlap
+			for _, o := range networkZoneOverlaps(tt.zones) {
+				require.Equal(t, "zone "+o.ZoneID.ValueString(), o.ZoneName.ValueString())
+				require.Equal(t, "zone "+o.OtherZoneID.ValueString(), o.OtherZone.ValueString())
+				overlaps = append(overlaps, overlap{o.ZoneID.ValueString(), o.Gateway.ValueString(), o.OtherZoneID.ValueString(), o.OtherGateway.ValueString(), o.Addresses.ValueString()})
+			}
+			require.Equal(t, tt.expected, overlaps)
+		})
+	}
+}
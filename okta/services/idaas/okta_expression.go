@@ -0,0 +1,99 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+// validateOktaExpression checks the syntax and the function calls of an Okta
+// Expression Language expression.
+func validateOktaExpression(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %s to be string", k)
+	}
+	if err := expression.Validate(v); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// oktaExpressionCustomizeDiff validates the Okta Expression Language
+// expression in field at plan time, including that the user attributes it
+// reads are part of the user profile. Custom attributes are looked up in the
+// user schema properties managed in the same configuration that were planned
+// before the expression and in the org's user schemas. isExpression, if set,
+// reports whether field holds an expression at all.
+func oktaExpressionCustomizeDiff(field string, isExpression func(d *schema.ResourceDiff) bool) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !d.NewValueKnown(field) || (isExpression != nil && !isExpression(d)) {
+			return nil
+		}
+		value := d.Get(field).(string)
+		if err := expression.Validate(value); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		c, ok := meta.(*config.Config)
+		if !ok {
+			return nil
+		}
+		node, _ := expression.Parse(value)
+		for _, attribute := range expression.UserAttributes(node) {
+			if expression.IsBaseUserAttribute(attribute.Name) {
+				continue
+			}
+			known, checked := c.UserSchemaProperties.Has(ctx, attribute.Name, func(ctx context.Context) ([]string, error) {
+				return listUserSchemaPropertyIndexes(ctx, meta)
+			})
+			if !checked {
+				logger(meta).Warn("unable to check user attributes of expression, the org's user schemas could not be read", "field", field)
+				return nil
+			}
+			if !known {
+				return fmt.Errorf("%s: invalid expression at position %d: unknown user attribute %q, if it is added by an %s resource of this configuration add that resource to depends_on", field, attribute.Pos(), attribute.Name, resources.OktaIDaaSUserSchemaProperty)
+			}
+		}
+		return nil
+	}
+}
+
+// registerUserSchemaProperty records the index of a custom user schema
+// property managed in the configuration so expressions planned after it may
+// read it.
+func registerUserSchemaProperty(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if c, ok := meta.(*config.Config); ok && d.NewValueKnown("index") {
+		c.UserSchemaProperties.Add(d.Get("index").(string))
+	}
+	return nil
+}
+
+// listUserSchemaPropertyIndexes returns the indexes of the custom properties
+// of the user schemas of all of the org's user types.
+func listUserSchemaPropertyIndexes(ctx context.Context, meta interface{}) ([]string, error) {
+	client := getOktaClientFromMetadata(meta)
+	userTypes, _, err := client.UserType.ListUserTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []string
+	for _, userType := range userTypes {
+		s, _, err := client.UserSchema.GetUserSchema(ctx, UserTypeSchemaID(userType))
+		if err != nil {
+			return nil, err
+		}
+		if s.Definitions == nil || s.Definitions.Custom == nil {
+			continue
+		}
+		for index := range s.Definitions.Custom.Properties {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
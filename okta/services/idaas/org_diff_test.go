@@ -0,0 +1,193 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/stretchr/testify/require"
+)
+
+// testOktaID pads prefix to an Okta ID.
+func testOktaID(prefix string) string {
+	return prefix + strings.Repeat("0", 20-len(prefix))
+}
+
+func TestDiffOrgObjects(t *testing.T) {
+	source := map[string]interface{}{
+		"Default":  map[string]interface{}{"status": "ACTIVE", "priority": 1},
+		"Legacy":   map[string]interface{}{"status": "INACTIVE"},
+		"Internal": map[string]interface{}{"status": "ACTIVE", "groups": []interface{}{"groups:Admins"}},
+	}
+	target := map[string]interface{}{
+		"Default":  map[string]interface{}{"status": "ACTIVE", "priority": 1},
+		"Internal": map[string]interface{}{"status": "INACTIVE", "groups": []interface{}{"groups:Everyone"}},
+		"New":      map[string]interface{}{"status": "ACTIVE"},
+	}
+	differences, err := diffOrgObjects(orgDiffPolicies, source, target)
+	require.NoError(t, err)
+	require.Equal(t, []orgDiffDifferenceModel{
+		{
+			ObjectType: types.StringValue(orgDiffPolicies),
+			Name:       types.StringValue("Internal"),
+			Change:     types.StringValue("changed"),
+			Attributes: []types.String{types.StringValue("groups[0]"), types.StringValue("status")},
+			Source:     types.StringValue(`{"groups":["groups:Admins"],"status":"ACTIVE"}`),
+			Target:     types.StringValue(`{"groups":["groups:Everyone"],"status":"INACTIVE"}`),
+		},
+		{
+			ObjectType: types.StringValue(orgDiffPolicies),
+			Name:       types.StringValue("Legacy"),
+			Change:     types.StringValue("only_in_source"),
+			Attributes: []types.String{},
+			Source:     types.StringValue(`{"status":"INACTIVE"}`),
+			Target:     types.StringValue(""),
+		},
+		{
+			ObjectType: types.StringValue(orgDiffPolicies),
+			Name:       types.StringValue("New"),
+			Change:     types.StringValue("only_in_target"),
+			Attributes: []types.String{},
+			Source:     types.StringValue(""),
+			Target:     types.StringValue(`{"status":"ACTIVE"}`),
+		},
+	}, differences)
+
+	differences, err = diffOrgObjects(orgDiffPolicies, source, source)
+	require.NoError(t, err)
+	require.Empty(t, differences)
+}
+
+func TestChangedAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   interface{}
+		target   interface{}
+		expected []string
+	}{
+		{name: "same", source: map[string]interface{}{"a": "x"}, target: map[string]interface{}{"a": "x"}},
+		{name: "changed", source: map[string]interface{}{"a": "x", "b": true}, target: map[string]interface{}{"a": "y", "b": true}, expected: []string{"a"}},
+		{name: "only in source", source: map[string]interface{}{"a": "x", "b": "y"}, target: map[string]interface{}{"a": "x"}, expected: []string{"b"}},
+		{name: "only in target", source: map[string]interface{}{"a": "x"}, target: map[string]interface{}{"a": "x", "b": "y"}, expected: []string{"b"}},
+		{
+			name:     "nested",
+			source:   map[string]interface{}{"conditions": map[string]interface{}{"network": map[string]interface{}{"include": []interface{}{"a", "b"}}}},
+			target:   map[string]interface{}{"conditions": map[string]interface{}{"network": map[string]interface{}{"include": []interface{}{"a"}}}},
+			expected: []string{"conditions.network.include[1]"},
+		},
+		{name: "emptied", source: map[string]interface{}{"a": []interface{}{"x"}}, target: map[string]interface{}{"a": []interface{}{}}, expected: []string{"a", "a[0]"}},
+		{name: "type", source: map[string]interface{}{"a": "1"}, target: map[string]interface{}{"a": 1}, expected: []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, changedAttributes(tt.source, tt.target))
+		})
+	}
+}
+
+func TestNormalizeOrgDiffObject(t *testing.T) {
+	admins, everyone, unknown := testOktaID("00gadmins"), testOktaID("00geveryone"), testOktaID("00gunknown")
+	names := map[string]string{admins: "groups:Admins", everyone: "groups:Everyone"}
+	object := map[string]interface{}{
+		"id":          testOktaID("00p"),
+		"lastUpdated": "2024-01-01T00:00:00.000Z",
+		"_links":      map[string]interface{}{"self": "https://example.okta.com"},
+		"name":        "Internal",
+		"description": "a name of twenty chr",
+		"groups":      []interface{}{everyone, admins, unknown},
+		"users":       orgDiffSet{everyone, admins, unknown},
+		"nested":      map[string]interface{}{"group": admins, "count": 2},
+	}
+	require.Equal(t, map[string]interface{}{
+		"name":        "Internal",
+		"description": "a name of twenty chr",
+		"groups":      []interface{}{"groups:Everyone", "groups:Admins", orgDiffRedactedID},
+		"users":       []interface{}{orgDiffRedactedID, "groups:Admins", "groups:Everyone"},
+		"nested":      map[string]interface{}{"group": "groups:Admins", "count": 2},
+	}, normalizeOrgDiffObject(object, names))
+}
+
+func TestReadOrgDiffObjects(t *testing.T) {
+	// testOrg serves the groups and group rules of an org, with its own IDs
+	testOrg := func(prefix string, rules map[string]string) *config.Config {
+		groupID := func(name string) string { return testOktaID("00g" + prefix + name) }
+		client := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+			var body interface{}
+			switch {
+			case r.URL.Path == "/api/v1/groups":
+				var groups []interface{}
+				for _, name := range []string{"admins", "eng"} {
+					groups = append(groups, map[string]interface{}{"id": groupID(name), "profile": map[string]interface{}{"name": name}})
+				}
+				body = groups
+			case r.URL.Path == "/api/v1/groups/rules":
+				var list []interface{}
+				for name := range rules {
+					list = append(list, map[string]interface{}{"id": testOktaID("0pr" + prefix + name), "name": name})
+				}
+				body = list
+			case strings.HasPrefix(r.URL.Path, "/api/v1/groups/rules/"):
+				for name, group := range rules {
+					if r.URL.Path != "/api/v1/groups/rules/"+testOktaID("0pr"+prefix+name) {
+						continue
+					}
+					body = map[string]interface{}{
+						"id":     testOktaID("0pr" + prefix + name),
+						"name":   name,
+						"status": "ACTIVE",
+						"conditions": map[string]interface{}{
+							"expression": map[string]interface{}{"type": "urn:okta:expression:1.0", "value": "user.department==\"" + name + "\""},
+						},
+						"actions": map[string]interface{}{
+							"assignUserToGroups": map[string]interface{}{"groupIds": []string{groupID(group)}},
+						},
+					}
+				}
+			}
+			if body == nil {
+				t.Errorf("unexpected request %s", r.URL)
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(body)
+		})
+		return &config.Config{OktaIDaaSClient: &testIDaaSClient{client: client}}
+	}
+
+	source, err := readOrgDiffObjects(context.Background(), testOrg("src", map[string]string{"eng": "eng", "ops": "admins", "sales": "eng"}), []string{orgDiffGroupRules})
+	require.NoError(t, err)
+	target, err := readOrgDiffObjects(context.Background(), testOrg("tgt", map[string]string{"eng": "eng", "ops": "eng", "support": "admins"}), []string{orgDiffGroupRules})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"name":                             "eng",
+		"status":                           "ACTIVE",
+		"expression_type":                  "urn:okta:expression:1.0",
+		"expression_value":                 `user.department=="eng"`,
+		"group_assignments":                []interface{}{"groups:eng"},
+		"users_excluded":                   []interface{}{},
+		"remove_assigned_users":            false,
+		"max_membership_removals":          "",
+		"override_max_membership_removals": false,
+	}, source[orgDiffGroupRules]["eng"])
+
+	differences, err := diffOrgObjects(orgDiffGroupRules, source[orgDiffGroupRules], target[orgDiffGroupRules])
+	require.NoError(t, err)
+	var changes []string
+	for _, difference := range differences {
+		var attributes []string
+		for _, attribute := range difference.Attributes {
+			attributes = append(attributes, attribute.ValueString())
+		}
+		changes = append(changes, difference.Name.ValueString()+" "+difference.Change.ValueString()+" "+strings.Join(attributes, ","))
+	}
+	require.Equal(t, []string{
+		"ops changed group_assignments[0]",
+		"sales only_in_source ",
+		"support only_in_target ",
+	}, changes)
+}
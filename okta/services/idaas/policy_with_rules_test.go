@@ -0,0 +1,230 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileRules(t *testing.T) {
+	// the rules as the API lists them, out of priority order: b is above a
+	// and stale isn't planned anymore
+	current := []map[string]any{
+		{"id": "0pr3", "name": "stale", "priority": 2, "status": "ACTIVE"},
+		{"id": "0pr1", "name": "a", "priority": 3, "status": "ACTIVE", "actions": map[string]any{"signon": map[string]any{"access": "DENY"}}},
+		{"id": "0prd", "name": "Default Rule", "priority": 99, "status": "ACTIVE", "system": true},
+		{"id": "0pr2", "name": "b", "priority": 1, "status": "ACTIVE", "actions": map[string]any{"signon": map[string]any{"access": "ALLOW", "requireFactor": false}}},
+	}
+	var requests []string
+	client := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			require.NoError(t, json.NewEncoder(w).Encode(current))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/policies/00p1/rules":
+			_, _ = w.Write([]byte(`{"id":"0pr4","name":"c","status":"ACTIVE"}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	r := &policyWithRulesResource{Config: &config.Config{OktaIDaaSClient: &testIDaaSClient{client: client}}}
+	rule := func(name, status string) policyWithRulesRule {
+		return policyWithRulesRule{Name: types.StringValue(name), Status: types.StringValue(status)}
+	}
+	plan := policyWithRulesModel{Rules: []policyWithRulesRule{rule("a", "ACTIVE"), rule("b", "ACTIVE"), rule("c", "INACTIVE")}}
+	bodies := []map[string]any{
+		{"name": "a", "priority": 1, "actions": map[string]any{"signon": map[string]any{"access": "DENY"}}},
+		{"name": "b", "priority": 2, "actions": map[string]any{"signon": map[string]any{"access": "ALLOW"}}},
+		{"name": "c", "priority": 3, "actions": map[string]any{"signon": map[string]any{"access": "ALLOW"}}},
+	}
+
+	require.NoError(t, r.reconcileRules(context.Background(), "00p1", plan, bodies))
+	// stale is deleted first, a is moved above b, which is then in place and
+	// up to date, and c is created below them and deactivated
+	require.Equal(t, []string{
+		"GET /api/v1/policies/00p1/rules",
+		"DELETE /api/v1/policies/00p1/rules/0pr3",
+		"PUT /api/v1/policies/00p1/rules/0pr1",
+		"POST /api/v1/policies/00p1/rules",
+		"POST /api/v1/policies/00p1/rules/0pr4/lifecycle/deactivate",
+	}, requests)
+}
+
+func TestPolicyWithRulesRuleUpToDate(t *testing.T) {
+	current := map[string]any{
+		"name":     "rule",
+		"priority": 1,
+		"conditions": map[string]any{
+			"network": map[string]any{"connection": "ANYWHERE"},
+			"people":  map[string]any{"users": map[string]any{"exclude": []any{"00u1"}}},
+		},
+		"actions": map[string]any{"signon": map[string]any{"access": "ALLOW", "requireFactor": false}},
+	}
+	for _, tt := range []struct {
+		name     string
+		body     map[string]any
+		upToDate bool
+	}{
+		{
+			name:     "same values",
+			body:     map[string]any{"conditions": current["conditions"], "actions": current["actions"]},
+			upToDate: true,
+		},
+		{
+			name: "defaults added by the API",
+			body: map[string]any{
+				"conditions": map[string]any{"network": map[string]any{"connection": "ANYWHERE"}},
+				"actions":    map[string]any{"signon": map[string]any{"access": "ALLOW"}},
+			},
+			upToDate: true,
+		},
+		{
+			name: "other priority and name",
+			body: map[string]any{
+				"name":     "renamed",
+				"priority": 2,
+				"actions":  map[string]any{"signon": map[string]any{"access": "ALLOW"}},
+			},
+			upToDate: true,
+		},
+		{
+			name:     "profile enrollment rule without conditions",
+			body:     map[string]any{"actions": current["actions"]},
+			upToDate: true,
+		},
+		{
+			name:     "changed action",
+			body:     map[string]any{"actions": map[string]any{"signon": map[string]any{"access": "DENY"}}},
+			upToDate: false,
+		},
+		{
+			name: "changed condition",
+			body: map[string]any{
+				"conditions": map[string]any{"people": map[string]any{"users": map[string]any{"exclude": []any{"00u2"}}}},
+			},
+			upToDate: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.upToDate, policyWithRulesRuleUpToDate(tt.body, current))
+		})
+	}
+}
+
+func TestJSONKeepingConfigured(t *testing.T) {
+	value := map[string]any{"signon": map[string]any{"access": "ALLOW", "requireFactor": false}}
+	for _, tt := range []struct {
+		name       string
+		configured types.String
+		value      any
+		expected   types.String
+	}{
+		{
+			name:       "configured values kept by the API",
+			configured: types.StringValue(`{"signon": {"access": "ALLOW"}}`),
+			value:      value,
+			expected:   types.StringValue(`{"signon": {"access": "ALLOW"}}`),
+		},
+		{
+			name:       "configured value changed",
+			configured: types.StringValue(`{"signon": {"access": "DENY"}}`),
+			value:      value,
+			expected:   types.StringValue(`{"signon":{"access":"ALLOW","requireFactor":false}}`),
+		},
+		{
+			name:       "not configured",
+			configured: types.StringNull(),
+			value:      value,
+			expected:   types.StringValue(`{"signon":{"access":"ALLOW","requireFactor":false}}`),
+		},
+		{
+			name:       "unknown",
+			configured: types.StringUnknown(),
+			value:      value,
+			expected:   types.StringValue(`{"signon":{"access":"ALLOW","requireFactor":false}}`),
+		},
+		{
+			name:       "no value",
+			configured: types.StringValue(`{"signon": {"access": "ALLOW"}}`),
+			value:      nil,
+			expected:   types.StringValue(`{"signon": {"access": "ALLOW"}}`),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, jsonKeepingConfigured(tt.configured, tt.value))
+		})
+	}
+}
+
+func TestValidateProfileEnrollmentPolicyWithRules(t *testing.T) {
+	rule := policyWithRulesRule{
+		Name:              types.StringValue("rule"),
+		UsersExcluded:     types.SetNull(types.StringType),
+		NetworkConnection: types.StringNull(),
+		NetworkIncludes:   types.ListNull(types.StringType),
+		NetworkExcludes:   types.ListNull(types.StringType),
+	}
+	for _, tt := range []struct {
+		name  string
+		rules []policyWithRulesRule
+		err   string
+	}{
+		{name: "one rule", rules: []policyWithRulesRule{rule}},
+		{name: "no rule", err: "got 0 rules"},
+		{name: "two rules", rules: []policyWithRulesRule{rule, rule}, err: "got 2 rules"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateProfileEnrollmentPolicyWithRules(policyWithRulesModel{
+				GroupsIncluded: types.SetNull(types.StringType),
+				Rules:          tt.rules,
+			}, &diags)
+			if tt.err == "" {
+				require.False(t, diags.HasError(), "%v", diags)
+				return
+			}
+			require.True(t, diags.HasError())
+			require.Contains(t, diags.Errors()[0].Detail(), tt.err)
+		})
+	}
+}
+
+func TestReconcileProfileEnrollmentRule(t *testing.T) {
+	var requests []string
+	var updated map[string]any
+	client := newTestSDKClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[{"id":"0pr1","name":"Catch-all Rule","priority":99,"status":"ACTIVE","system":true,"actions":{"profileEnrollment":{"access":"DENY"}}}]`))
+		case http.MethodPut:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	r := &policyWithRulesResource{Config: &config.Config{OktaIDaaSClient: &testIDaaSClient{client: client}}}
+	ctx := context.Background()
+
+	// the plan is checked before the rules are listed
+	err := r.reconcileProfileEnrollmentRule(ctx, "00p1", policyWithRulesModel{}, nil)
+	require.EqualError(t, err, "a PROFILE_ENROLLMENT policy has exactly one rule, 0 rules are planned")
+	require.Empty(t, requests)
+
+	plan := policyWithRulesModel{Rules: []policyWithRulesRule{{Name: types.StringValue("enroll"), Status: types.StringValue("ACTIVE")}}}
+	bodies := []map[string]any{{"name": "enroll", "priority": 1, "actions": map[string]any{"profileEnrollment": map[string]any{"access": "ALLOW"}}}}
+	require.NoError(t, r.reconcileProfileEnrollmentRule(ctx, "00p1", plan, bodies))
+	require.Equal(t, []string{"GET /api/v1/policies/00p1/rules", "PUT /api/v1/policies/00p1/rules/0pr1"}, requests)
+	// the rule keeps the priority Okta gave it
+	require.EqualValues(t, 99, updated["priority"])
+	require.Equal(t, "enroll", updated["name"])
+}
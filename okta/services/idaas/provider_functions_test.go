@@ -0,0 +1,113 @@
+package idaas_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+	"github.com/okta/terraform-provider-okta/okta/services/idaas"
+)
+
+func runProviderFunction(t *testing.T, name string, result attr.Value, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+	for _, newFunction := range idaas.FWProviderFunctions() {
+		f := newFunction()
+		metadata := &function.MetadataResponse{}
+		f.Metadata(ctx, function.MetadataRequest{}, metadata)
+		if metadata.Name != name {
+			continue
+		}
+		resp := &function.RunResponse{Result: function.NewResultData(result)}
+		f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+		return resp.Result.Value(), resp.Error
+	}
+	t.Fatalf("provider function %q not found", name)
+	return nil, nil
+}
+
+func TestProviderFunctionExprEscape(t *testing.T) {
+	result, err := runProviderFunction(t, "expr_escape", types.StringUnknown(), types.StringValue(`say "hi"`))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if expected := types.StringValue(`"say ""hi"""`); !result.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestProviderFunctionExprEscapeMapArgument(t *testing.T) {
+	// the escaped value is used as a value of the map argument of the
+	// user.isMemberOf and user.getGroups methods
+	for _, value := range []string{`Everyone`, `West & East`, `say "hi"`, `{'a': 'b'}`, `it's`} {
+		result, err := runProviderFunction(t, "expr_escape", types.StringUnknown(), types.StringValue(value))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		escaped := result.(types.String).ValueString()
+		for _, expr := range []string{
+			fmt.Sprintf(`user.isMemberOf({'group.profile.name': %s, 'operator': 'EXACT'})`, escaped),
+			fmt.Sprintf(`user.getGroups({'group.profile.name': %s, 'operator': 'STARTS_WITH'}).![name]`, escaped),
+		} {
+			if err := expression.Validate(expr); err != nil {
+				t.Errorf("expected %s to be valid, got %+v", expr, err)
+			}
+		}
+	}
+
+	// unescaped values or malformed maps aren't valid
+	for _, expr := range []string{
+		`user.isMemberOf({'group.profile.name': "say "hi"", 'operator': 'EXACT'})`,
+		`user.isMemberOf({'group.profile.name' "Everyone"})`,
+		`user.isMemberOf({'group.profile.name': "Everyone", 'operator'})`,
+		`user.getGroups({'group.profile.name': "Everyone"}).![name`,
+	} {
+		if err := expression.Validate(expr); err == nil {
+			t.Errorf("expected %s to be invalid", expr)
+		}
+	}
+}
+
+func TestProviderFunctionExprIsMemberOfAnyGroup(t *testing.T) {
+	ids := types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("00g1234567890abcdefg"),
+		types.StringValue("00g1234567890abcdefh"),
+	})
+	result, err := runProviderFunction(t, "expr_is_member_of_any_group", types.StringUnknown(), ids)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if expected := types.StringValue(`isMemberOfAnyGroup("00g1234567890abcdefg","00g1234567890abcdefh")`); !result.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, result)
+	}
+
+	empty := types.ListValueMust(types.StringType, []attr.Value{})
+	if _, err := runProviderFunction(t, "expr_is_member_of_any_group", types.StringUnknown(), empty); err == nil {
+		t.Fatal("expected an error for an empty list of group IDs")
+	}
+}
+
+func TestProviderFunctionParseImportID(t *testing.T) {
+	result, err := runProviderFunction(t, "parse_import_id", types.ListUnknown(types.StringType), types.StringValue("aus1234567890abcdefg/00p1234567890abcdefg/0pr1234567890abcdefg"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	expected := types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("aus1234567890abcdefg"),
+		types.StringValue("00p1234567890abcdefg"),
+		types.StringValue("0pr1234567890abcdefg"),
+	})
+	if !result.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, result)
+	}
+
+	for _, id := range []string{"", "aus1234567890abcdefg/", "aus1234567890abcdefg// 00p1234567890abcdefg"} {
+		if _, err := runProviderFunction(t, "parse_import_id", types.ListUnknown(types.StringType), types.StringValue(id)); err == nil {
+			t.Errorf("expected an error for import ID %q", id)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package idaas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const identityIDDescription = "The ID of the Okta object."
+
+// idIdentityModel is the resource identity of framework resources identified
+// by their Okta ID.
+type idIdentityModel struct {
+	ID types.String `tfsdk:"id"`
+}
+
+// idIdentitySchema is the resource identity schema of framework resources
+// identified by their Okta ID.
+func idIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       identityIDDescription,
+			},
+		},
+	}
+}
+
+// withIDIdentity gives a plugin SDK resource identified by its Okta ID a
+// resource identity, so it can be imported by identity and be listed with
+// terraform query. The identity is set whenever the resource is created, read
+// or updated.
+func withIDIdentity(r *schema.Resource) *schema.Resource {
+	r.Identity = &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			return map[string]*schema.Schema{
+				"id": {
+					Type:              schema.TypeString,
+					RequiredForImport: true,
+					Description:       identityIDDescription,
+				},
+			}
+		},
+	}
+	r.CreateContext = setIDIdentity(r.CreateContext)
+	r.ReadContext = setIDIdentity(r.ReadContext)
+	r.UpdateContext = setIDIdentity(r.UpdateContext)
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		importer := r.Importer.StateContext
+		r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			if _, err := schema.ImportStatePassthroughWithIdentity("id")(ctx, d, meta); err != nil {
+				return nil, err
+			}
+			return importer(ctx, d, meta)
+		}
+	}
+	return r
+}
+
+// sdkCRUDFunc is the signature shared by the create, read and update
+// functions of plugin SDK resources.
+type sdkCRUDFunc = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+func setIDIdentity(f sdkCRUDFunc) sdkCRUDFunc {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		diags := f(ctx, d, meta)
+		if diags.HasError() || d.Id() == "" {
+			return diags
+		}
+		identity, err := d.Identity()
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+		if err := identity.Set("id", d.Id()); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+		return diags
+	}
+}
@@ -55,7 +55,7 @@ resource "okta_app_group_assignments" "this" {
 ` + "```" + `
 
 Note: Using ` + "`for_each`" + ` on this resource is safe when each instance targets a **different** ` + "`app_id`" + `, for example when assigning the same group to multiple applications.`,
-		Schema: map[string]*schema.Schema{
+		Schema: utils.BuildSchema(map[string]*schema.Schema{
 			"app_id": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -96,7 +96,8 @@ Note: Using ` + "`for_each`" + ` on this resource is safe when each instance tar
 					},
 				},
 			},
-		},
+		}, membershipRemovalsSchema),
+		CustomizeDiff: appGroupAssignmentsCustomizeDiff,
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(1 * time.Hour),
 			Read:   schema.DefaultTimeout(1 * time.Hour),
@@ -105,6 +106,43 @@ Note: Using ` + "`for_each`" + ` on this resource is safe when each instance tar
 	}
 }
 
+// appGroupAssignmentsCustomizeDiff fails the plan when it unassigns more
+// groups from the app than max_membership_removals allows.
+func appGroupAssignmentsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.NewValueKnown("group") {
+		return nil
+	}
+	oldAppId, _ := d.GetChange("app_id")
+	oldGroups, newGroups := d.GetChange("group")
+	for i := range newGroups.([]interface{}) {
+		if !d.NewValueKnown(fmt.Sprintf("group.%d.id", i)) {
+			return nil
+		}
+	}
+	oldIDs, newIDs := assignedGroupIDs(oldGroups.([]interface{})), assignedGroupIDs(newGroups.([]interface{}))
+	removed := 0
+	for id := range oldIDs {
+		if !newIDs[id] || d.HasChange("app_id") {
+			removed++
+		}
+	}
+	return checkMembershipRemovals(d.Get, meta, appGroupAssignmentsDescription(oldAppId.(string)), removed, len(oldIDs))
+}
+
+func assignedGroupIDs(groups []interface{}) map[string]bool {
+	ids := map[string]bool{}
+	for _, rawGroup := range groups {
+		if group, ok := rawGroup.(map[string]interface{}); ok {
+			ids[group["id"].(string)] = true
+		}
+	}
+	return ids
+}
+
+func appGroupAssignmentsDescription(appID string) string {
+	return fmt.Sprintf("group assignments of app %s", appID)
+}
+
 func resourceAppGroupAssignmentsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getOktaClientFromMetadata(meta)
 	assignments := tfGroupsToGroupAssignments(d)
@@ -171,6 +209,11 @@ func resourceAppGroupAssignmentsUpdate(ctx context.Context, d *schema.ResourceDa
 	if err != nil {
 		return diag.Errorf("failed to discern group assignment splits: %v", err)
 	}
+	oldGroups, _ := d.GetChange("group")
+	if err := checkMembershipRemovals(d.Get, meta, appGroupAssignmentsDescription(appID), len(toRemove), len(assignedGroupIDs(oldGroups.([]interface{})))); err != nil {
+		d.Partial(true)
+		return diag.FromErr(err)
+	}
 	err = deleteGroupAssignments(
 		client.Application.DeleteApplicationGroupAssignment,
 		ctx,
@@ -193,8 +236,12 @@ func resourceAppGroupAssignmentsUpdate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceAppGroupAssignmentsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	groups := d.Get("group").([]interface{})
+	if err := checkMembershipRemovals(d.Get, meta, appGroupAssignmentsDescription(d.Get("app_id").(string)), len(groups), len(groups)); err != nil {
+		return diag.FromErr(err)
+	}
 	client := getOktaClientFromMetadata(meta)
-	for _, rawGroup := range d.Get("group").([]interface{}) {
+	for _, rawGroup := range groups {
 		group := rawGroup.(map[string]interface{})
 		resp, err := client.Application.DeleteApplicationGroupAssignment(
 			ctx,
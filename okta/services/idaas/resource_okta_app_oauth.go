@@ -123,7 +123,7 @@ var appRequirementsByType = map[string]*applicationMap{
 }
 
 func resourceAppOAuth() *schema.Resource {
-	return &schema.Resource{
+	return withIDIdentity(&schema.Resource{
 		CreateContext: resourceAppOAuthCreate,
 		ReadContext:   resourceAppOAuthRead,
 		UpdateContext: resourceAppOAuthUpdate,
@@ -374,7 +374,7 @@ other arguments that changed will be applied.`,
 			"jwks": {
 				Type:        schema.TypeList,
 				Optional:    true,
-				Description: "JSON Web Key Set (JWKS) for application. Note: Inline JWKS may have compatibility issues with v6 SDK. Consider using jwks_uri instead.",
+				Description: "JSON Web Key Set (JWKS) for application. Note: Inline JWKS may have compatibility issues with v6 SDK. Consider using jwks_uri instead, or leave it unset and manage the keys with `okta_app_oauth_jwk`.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"kid": {
@@ -489,7 +489,7 @@ other arguments that changed will be applied.`,
 			Read:   schema.DefaultTimeout(1 * time.Hour),
 			Update: schema.DefaultTimeout(1 * time.Hour),
 		},
-	}
+	})
 }
 
 var groupsClaimResource = &schema.Resource{
@@ -867,8 +867,10 @@ func setOAuthClientSettingsV6(d *schema.ResourceData, oauthClient *v6okta.OpenId
 		}
 	}
 
+	// keys added with okta_app_oauth_jwk are left out of jwks when it isn't
+	// configured
 	jwk, ok := oauthClient.GetJwksOk()
-	if ok {
+	if _, configured := d.GetOk("jwks"); ok && configured {
 		jwks := jwk.Keys
 		arr := make([]map[string]interface{}, len(jwks))
 		for i, j := range jwks {
@@ -952,6 +954,10 @@ func resourceAppOAuthUpdate(ctx context.Context, d *schema.ResourceData, meta in
 		}
 	}
 
+	if err = keepAppOAuthJWKS(ctx, d, client, app); err != nil {
+		return diag.Errorf("failed to update OAuth application: %v", err)
+	}
+
 	appResp, _, err := client.ApplicationAPI.ReplaceApplication(ctx, d.Id()).Application(app).Execute()
 	if err != nil {
 		return diag.Errorf("failed to update OAuth application: %v", err)
@@ -1002,6 +1008,29 @@ func resourceAppOAuthDelete(ctx context.Context, d *schema.ResourceData, meta in
 	return nil
 }
 
+// keepAppOAuthJWKS copies the JWKS of the application to app when jwks isn't
+// configured, so that replacing the application keeps the keys added with
+// okta_app_oauth_jwk.
+func keepAppOAuthJWKS(ctx context.Context, d *schema.ResourceData, client *v6okta.APIClient, app v6okta.ListApplications200ResponseInner) error {
+	if _, ok := d.GetOk("jwks"); ok || d.Get("jwks_uri").(string) != "" || app.OpenIdConnectApplication == nil {
+		return nil
+	}
+	currentResp, _, err := client.ApplicationAPI.GetApplication(ctx, d.Id()).Execute()
+	if err != nil {
+		return err
+	}
+	current, err := verifyOidcAppTypeV6(*currentResp)
+	if err != nil {
+		return err
+	}
+	jwks, ok := current.Settings.OauthClient.GetJwksOk()
+	if !ok || len(jwks.Keys) == 0 {
+		return nil
+	}
+	app.OpenIdConnectApplication.Settings.OauthClient.SetJwks(*jwks)
+	return nil
+}
+
 func buildAppOAuthV6(d *schema.ResourceData, isNew bool) (v6okta.ListApplications200ResponseInner, error) {
 	app := v6okta.NewOpenIdConnectApplicationWithDefaults()
 	appType := d.Get("type").(string)
@@ -1361,8 +1390,10 @@ func validateAppOAuth(d *schema.ResourceData, meta interface{}) error {
 	}
 	_, jwks := d.GetOk("jwks")
 	_, jwks_uri := d.GetOk("jwks_uri")
-	if !(jwks || jwks_uri) && d.Get("token_endpoint_auth_method").(string) == "private_key_jwt" {
-		return errors.New("'jwks' or 'jwks_uri' is required when 'token_endpoint_auth_method' is 'private_key_jwt'")
+	// on update the keys can also be added with okta_app_oauth_jwk
+	if !(jwks || jwks_uri) && d.Id() == "" && d.Get("token_endpoint_auth_method").(string) == "private_key_jwt" {
+		return errors.New("'jwks' or 'jwks_uri' is required when 'token_endpoint_auth_method' is 'private_key_jwt'. " +
+			"To manage the keys with okta_app_oauth_jwk, create the application with another 'token_endpoint_auth_method', add the keys, then change it to 'private_key_jwt'")
 	}
 	if d.Get("login_mode").(string) != "DISABLED" {
 		if d.Get("login_uri").(string) == "" {
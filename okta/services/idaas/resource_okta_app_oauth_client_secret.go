@@ -0,0 +1,472 @@
+package idaas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	v6okta "github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/okta/terraform-provider-okta/okta/config"
+)
+
+// defaultClientSecretGracePeriodMinutes is how long the previous client
+// secret stays active after a rotation unless grace_period_minutes is set.
+const defaultClientSecretGracePeriodMinutes = 24 * 60
+
+var (
+	_ resource.Resource                = &appOAuthClientSecretResource{}
+	_ resource.ResourceWithConfigure   = &appOAuthClientSecretResource{}
+	_ resource.ResourceWithImportState = &appOAuthClientSecretResource{}
+	_ resource.ResourceWithModifyPlan  = &appOAuthClientSecretResource{}
+)
+
+type appOAuthClientSecretResource struct {
+	*config.Config
+}
+
+type appOAuthClientSecretModel struct {
+	ID                      types.String `tfsdk:"id"`
+	AppID                   types.String `tfsdk:"app_id"`
+	ClientSecretWO          types.String `tfsdk:"client_secret_wo"`
+	ClientSecretWOVersion   types.Int64  `tfsdk:"client_secret_wo_version"`
+	Status                  types.String `tfsdk:"status"`
+	GracePeriodMinutes      types.Int64  `tfsdk:"grace_period_minutes"`
+	ClientSecret            types.String `tfsdk:"client_secret"`
+	SecretHash              types.String `tfsdk:"secret_hash"`
+	Created                 types.String `tfsdk:"created"`
+	LastUpdated             types.String `tfsdk:"last_updated"`
+	PreviousSecretID        types.String `tfsdk:"previous_secret_id"`
+	PreviousSecretExpiresAt types.String `tfsdk:"previous_secret_expires_at"`
+}
+
+func newAppOAuthClientSecretResource() resource.Resource {
+	return &appOAuthClientSecretResource{}
+}
+
+func (r *appOAuthClientSecretResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_oauth_client_secret"
+}
+
+func (r *appOAuthClientSecretResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *appOAuthClientSecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			"Expected format: app_id/secret_id",
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_id"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("grace_period_minutes"), int64(defaultClientSecretGracePeriodMinutes))...)
+}
+
+func (r *appOAuthClientSecretResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Manages a client secret of an OAuth application, in addition to the secret of the 'okta_app_oauth' resource.
+Changing 'client_secret_wo_version' rotates the secret: a new secret is created and the previous one stays active
+for 'grace_period_minutes', so that the services using it can switch to the new secret without an outage. The
+previous secret is deleted by the first apply after the grace period. An application can have at most two client secrets,
+including the one generated for the 'okta_app_oauth' resource: import that secret to rotate it with this resource.`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the client secret.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"app_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the OAuth application.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"client_secret_wo": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Description: "Write-only value of the client secret for Terraform 1.11+, it isn't persisted in the Terraform state file. Okta generates the secret when it isn't set. Increment `client_secret_wo_version` to use a new value.",
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(14, 100),
+				},
+			},
+			"client_secret_wo_version": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Version number of the client secret. Changing it rotates the secret.",
+			},
+			"status": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(StatusActive),
+				Description: "Status of the client secret: `ACTIVE` or `INACTIVE`. Default is `ACTIVE`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(StatusActive, StatusInactive),
+				},
+			},
+			"grace_period_minutes": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(defaultClientSecretGracePeriodMinutes),
+				Description: "Minutes the previous client secret stays active after a rotation, `0` deletes it right away, before the new secret is created as an application has at most two client secrets. Default is `1440`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"client_secret": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The client secret generated by Okta, stored in the Terraform state file. Null when `client_secret_wo` is set.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "The hash of the client secret.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the client secret was created.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the client secret was updated.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"previous_secret_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the client secret replaced by the last rotation, until it is deleted.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"previous_secret_expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Time after which the previous client secret is deleted.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan marks the attributes of the client secret unknown when it is
+// rotated, and plans the deletion of the previous secret once its grace
+// period is over.
+func (r *appOAuthClientSecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan appOAuthClientSecretModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var secretWO types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("client_secret_wo"), &secretWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	generated := types.StringUnknown()
+	if !secretWO.IsNull() && !secretWO.IsUnknown() {
+		generated = types.StringNull()
+	}
+
+	if req.State.Raw.IsNull() {
+		plan.ClientSecret = generated
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+	var state appOAuthClientSecretModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	switch {
+	case !plan.ClientSecretWOVersion.Equal(state.ClientSecretWOVersion):
+		plan.ID = types.StringUnknown()
+		plan.ClientSecret = generated
+		plan.SecretHash = types.StringUnknown()
+		plan.Created = types.StringUnknown()
+		plan.LastUpdated = types.StringUnknown()
+		plan.PreviousSecretID = types.StringUnknown()
+		plan.PreviousSecretExpiresAt = types.StringUnknown()
+	default:
+		if !plan.Status.Equal(state.Status) {
+			plan.LastUpdated = types.StringUnknown()
+		}
+		if expiresAt, err := time.Parse(time.RFC3339, state.PreviousSecretExpiresAt.ValueString()); err == nil && !time.Now().Before(expiresAt) {
+			plan.PreviousSecretID = types.StringNull()
+			plan.PreviousSecretExpiresAt = types.StringNull()
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *appOAuthClientSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data appOAuthClientSecretModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var secretWO types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("client_secret_wo"), &secretWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := validateAppOAuthClientSecretWO(secretWO); err != nil {
+		resp.Diagnostics.AddError("invalid client_secret_wo", err.Error())
+		return
+	}
+	client := r.OktaIDaaSClient.OktaSDKClientV6()
+	secret, err := createAppOAuthClientSecret(ctx, client, data.AppID.ValueString(), secretWO, data.Status.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("failed to create OAuth client secret", err.Error())
+		return
+	}
+	applyAppOAuthClientSecretToState(&data, secret)
+	data.ClientSecret = generatedAppOAuthClientSecret(secretWO, secret)
+	data.PreviousSecretID = types.StringNull()
+	data.PreviousSecretExpiresAt = types.StringNull()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appOAuthClientSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data appOAuthClientSecretModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	client := r.OktaIDaaSClient.OktaSDKClientV6()
+	appID := data.AppID.ValueString()
+	secret, apiResp, err := client.ApplicationSSOPublicKeysAPI.GetOAuth2ClientSecret(ctx, appID, data.ID.ValueString()).Execute()
+	if apiResp != nil && apiResp.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("failed to read OAuth client secret", err.Error())
+		return
+	}
+	applyAppOAuthClientSecretToState(&data, secret)
+
+	if previousID := data.PreviousSecretID.ValueString(); previousID != "" {
+		_, apiResp, err := client.ApplicationSSOPublicKeysAPI.GetOAuth2ClientSecret(ctx, appID, previousID).Execute()
+		if apiResp != nil && apiResp.StatusCode == http.StatusNotFound {
+			data.PreviousSecretID = types.StringNull()
+			data.PreviousSecretExpiresAt = types.StringNull()
+		} else if err != nil {
+			resp.Diagnostics.AddError("failed to read previous OAuth client secret", err.Error())
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appOAuthClientSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state appOAuthClientSecretModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	client := r.OktaIDaaSClient.OktaSDKClientV6()
+	appID := plan.AppID.ValueString()
+
+	if !plan.ClientSecretWOVersion.Equal(state.ClientSecretWOVersion) {
+		var secretWO types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("client_secret_wo"), &secretWO)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		gracePeriod := time.Duration(plan.GracePeriodMinutes.ValueInt64()) * time.Minute
+		secret, err := rotateAppOAuthClientSecret(ctx, client, appID, &state, secretWO, plan.Status.ValueString(), gracePeriod)
+		if err != nil {
+			resp.Diagnostics.AddError("failed to rotate OAuth client secret", err.Error())
+			return
+		}
+		applyAppOAuthClientSecretToState(&plan, secret)
+		plan.ClientSecret = generatedAppOAuthClientSecret(secretWO, secret)
+		plan.PreviousSecretID = types.StringNull()
+		plan.PreviousSecretExpiresAt = types.StringNull()
+		if gracePeriod > 0 {
+			plan.PreviousSecretID = state.ID
+			plan.PreviousSecretExpiresAt = types.StringValue(time.Now().Add(gracePeriod).UTC().Format(time.RFC3339))
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	if plan.PreviousSecretID.IsNull() && !state.PreviousSecretID.IsNull() {
+		if err := deleteAppOAuthClientSecret(ctx, client, appID, state.PreviousSecretID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("failed to delete previous OAuth client secret", err.Error())
+			return
+		}
+	}
+	var (
+		secret *v6okta.OAuth2ClientSecret
+		err    error
+	)
+	switch {
+	case plan.Status.Equal(state.Status):
+		secret, _, err = client.ApplicationSSOPublicKeysAPI.GetOAuth2ClientSecret(ctx, appID, state.ID.ValueString()).Execute()
+	case plan.Status.ValueString() == StatusActive:
+		secret, _, err = client.ApplicationSSOPublicKeysAPI.ActivateOAuth2ClientSecret(ctx, appID, state.ID.ValueString()).Execute()
+	default:
+		secret, _, err = client.ApplicationSSOPublicKeysAPI.DeactivateOAuth2ClientSecret(ctx, appID, state.ID.ValueString()).Execute()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("failed to update OAuth client secret", err.Error())
+		return
+	}
+	applyAppOAuthClientSecretToState(&plan, secret)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *appOAuthClientSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data appOAuthClientSecretModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	client := r.OktaIDaaSClient.OktaSDKClientV6()
+	for _, id := range []types.String{data.PreviousSecretID, data.ID} {
+		if id.ValueString() == "" {
+			continue
+		}
+		if err := deleteAppOAuthClientSecret(ctx, client, data.AppID.ValueString(), id.ValueString()); err != nil {
+			resp.Diagnostics.AddError("failed to delete OAuth client secret", err.Error())
+			return
+		}
+	}
+}
+
+// rotateAppOAuthClientSecret replaces the client secret of state with a new
+// secret. An application has at most two client secrets: the secret kept by
+// the last rotation makes room for the new one, and so does the current secret
+// when there is no grace period.
+func rotateAppOAuthClientSecret(ctx context.Context, client *v6okta.APIClient, appID string, state *appOAuthClientSecretModel, secretWO types.String, status string, gracePeriod time.Duration) (*v6okta.OAuth2ClientSecret, error) {
+	// nothing is deleted when the new secret is bound to be rejected
+	if err := validateAppOAuthClientSecretWO(secretWO); err != nil {
+		return nil, fmt.Errorf("invalid client_secret_wo: %w", err)
+	}
+	obsoleteIDs := []string{state.PreviousSecretID.ValueString()}
+	if gracePeriod == 0 {
+		obsoleteIDs = append(obsoleteIDs, state.ID.ValueString())
+	}
+	for _, id := range obsoleteIDs {
+		if id == "" {
+			continue
+		}
+		if err := deleteAppOAuthClientSecret(ctx, client, appID, id); err != nil {
+			return nil, fmt.Errorf("failed to delete previous client secret %s: %w", id, err)
+		}
+	}
+	secret, err := createAppOAuthClientSecret(ctx, client, appID, secretWO, status)
+	if err == nil {
+		return secret, nil
+	}
+	if gracePeriod == 0 {
+		return nil, fmt.Errorf("failed to create the new client secret: %w. The current client secret %s was deleted before, "+
+			"as grace_period_minutes is 0: the application has no client secret managed by this resource until the next apply creates one", err, state.ID.ValueString())
+	}
+	return nil, fmt.Errorf("failed to create the new client secret: %w", err)
+}
+
+// validateAppOAuthClientSecretWO checks the value of client_secret_wo when it
+// is applied, a value set by an ephemeral resource is unknown when the
+// configuration is validated.
+func validateAppOAuthClientSecretWO(secretWO types.String) error {
+	if secretWO.IsNull() {
+		return nil
+	}
+	value := secretWO.ValueString()
+	if n := utf8.RuneCountInString(value); n < 14 || n > 100 {
+		return fmt.Errorf("the client secret must be 14 to 100 characters long, got %d", n)
+	}
+	if i := strings.IndexFunc(value, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }); i >= 0 {
+		return errors.New("the client secret must not contain spaces or control characters")
+	}
+	return nil
+}
+
+// createAppOAuthClientSecret creates a client secret with the value secretWO,
+// or a value generated by Okta when it's null.
+func createAppOAuthClientSecret(ctx context.Context, client *v6okta.APIClient, appID string, secretWO types.String, status string) (*v6okta.OAuth2ClientSecret, error) {
+	body := v6okta.OAuth2ClientSecretRequestBody{Status: v6okta.PtrString(status)}
+	if !secretWO.IsNull() {
+		body.ClientSecret = secretWO.ValueStringPointer()
+	}
+	secret, _, err := client.ApplicationSSOPublicKeysAPI.CreateOAuth2ClientSecret(ctx, appID).OAuth2ClientSecretRequestBody(body).Execute()
+	return secret, err
+}
+
+// deleteAppOAuthClientSecret deactivates and deletes a client secret, a secret
+// that doesn't exist anymore is ignored.
+func deleteAppOAuthClientSecret(ctx context.Context, client *v6okta.APIClient, appID, secretID string) error {
+	secret, apiResp, err := client.ApplicationSSOPublicKeysAPI.GetOAuth2ClientSecret(ctx, appID, secretID).Execute()
+	if apiResp != nil && apiResp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if secret.GetStatus() == StatusActive {
+		if _, _, err := client.ApplicationSSOPublicKeysAPI.DeactivateOAuth2ClientSecret(ctx, appID, secretID).Execute(); err != nil {
+			return fmt.Errorf("failed to deactivate client secret %s: %w", secretID, err)
+		}
+	}
+	apiResp, err = client.ApplicationSSOPublicKeysAPI.DeleteOAuth2ClientSecret(ctx, appID, secretID).Execute()
+	if apiResp != nil && apiResp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func applyAppOAuthClientSecretToState(data *appOAuthClientSecretModel, secret *v6okta.OAuth2ClientSecret) {
+	data.ID = types.StringValue(secret.GetId())
+	data.Status = types.StringValue(secret.GetStatus())
+	data.SecretHash = types.StringValue(secret.GetSecretHash())
+	data.Created = types.StringValue(secret.GetCreated())
+	data.LastUpdated = types.StringValue(secret.GetLastUpdated())
+}
+
+// generatedAppOAuthClientSecret is the value of client_secret, only secrets
+// generated by Okta are kept in the state.
+func generatedAppOAuthClientSecret(secretWO types.String, secret *v6okta.OAuth2ClientSecret) types.String {
+	if !secretWO.IsNull() {
+		return types.StringNull()
+	}
+	return types.StringValue(secret.GetClientSecret())
+}
@@ -0,0 +1,88 @@
+package idaas_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+func TestAccResourceOktaAppOAuthClientSecret_crud(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the cassettes would hold the client secrets the test creates, run
+		// it against an org
+		return
+	}
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSAppOAuthClientSecret)
+	mgr := newFixtureManager("resources", resources.OktaIDaaSAppOAuthClientSecret, t.Name())
+	basic := mgr.GetFixtures("basic.tf", t)
+	rotated := mgr.GetFixtures("rotated.tf", t)
+	var secretID string
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", func(value string) error {
+						secretID = value
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "status", "ACTIVE"),
+					resource.TestCheckResourceAttr(resourceName, "client_secret_wo_version", "1"),
+					resource.TestCheckResourceAttr(resourceName, "grace_period_minutes", "1440"),
+					resource.TestCheckNoResourceAttr(resourceName, "client_secret_wo"),
+					resource.TestCheckNoResourceAttr(resourceName, "client_secret"),
+					resource.TestCheckResourceAttrSet(resourceName, "secret_hash"),
+					resource.TestCheckNoResourceAttr(resourceName, "previous_secret_id"),
+				),
+			},
+			{
+				Config: rotated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith(resourceName, "id", func(value string) error {
+						if value == secretID {
+							return fmt.Errorf("expected a new client secret, got %s again", value)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "status", "INACTIVE"),
+					resource.TestCheckResourceAttr(resourceName, "client_secret_wo_version", "2"),
+					resource.TestCheckNoResourceAttr(resourceName, "client_secret_wo"),
+					resource.TestCheckNoResourceAttr(resourceName, "previous_secret_id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceOktaAppOAuthClientSecret_generated(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the cassettes would hold the client secrets the test creates, run
+		// it against an org
+		return
+	}
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSAppOAuthClientSecret)
+	mgr := newFixtureManager("resources", resources.OktaIDaaSAppOAuthClientSecret, t.Name())
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: mgr.GetFixtures("generated.tf", t),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", "ACTIVE"),
+					resource.TestCheckResourceAttrSet(resourceName, "client_secret"),
+					resource.TestCheckResourceAttrSet(resourceName, "secret_hash"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,463 @@
+package idaas
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+var (
+	_ resource.Resource               = &appOAuthJWKResource{}
+	_ resource.ResourceWithConfigure  = &appOAuthJWKResource{}
+	_ resource.ResourceWithModifyPlan = &appOAuthJWKResource{}
+)
+
+type appOAuthJWKResource struct {
+	*config.Config
+}
+
+type appOAuthJWKModel struct {
+	ID           types.String `tfsdk:"id"`
+	AppID        types.String `tfsdk:"app_id"`
+	PublicKeyPEM types.String `tfsdk:"public_key_pem"`
+	JWK          types.String `tfsdk:"jwk"`
+	Kid          types.String `tfsdk:"kid"`
+	Alg          types.String `tfsdk:"alg"`
+	Use          types.String `tfsdk:"use"`
+	Status       types.String `tfsdk:"status"`
+	Kty          types.String `tfsdk:"kty"`
+	Created      types.String `tfsdk:"created"`
+	LastUpdated  types.String `tfsdk:"last_updated"`
+}
+
+// appOAuthJWK is a key of the JWKS of an OAuth application as the API
+// returns it.
+type appOAuthJWK struct {
+	ID          string `json:"id"`
+	Kid         string `json:"kid"`
+	Kty         string `json:"kty"`
+	Alg         string `json:"alg"`
+	Use         string `json:"use"`
+	Status      string `json:"status"`
+	Created     string `json:"created"`
+	LastUpdated string `json:"lastUpdated"`
+}
+
+func newAppOAuthJWKResource() resource.Resource {
+	return &appOAuthJWKResource{}
+}
+
+func (r *appOAuthJWKResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_oauth_jwk"
+}
+
+func (r *appOAuthJWKResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *appOAuthJWKResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Manages a public key of the JWKS of an OAuth application, one resource per key. Don't set 'jwks' on the
+'okta_app_oauth' resource of the application. An application authenticating with 'private_key_jwt' needs its keys
+when it is created: create it with another 'token_endpoint_auth_method', e.g. 'client_secret_basic', add its keys,
+then change the method to 'private_key_jwt'. The last active signing key of such an application is neither deactivated
+nor removed: to roll a key over, add the new key with 'lifecycle { create_before_destroy = true }', or add it
+'INACTIVE' and activate it before removing the old key.`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The Okta ID of the key.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"app_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the OAuth application.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"public_key_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "The public key in PEM format: a `PUBLIC KEY`, `RSA PUBLIC KEY` or `CERTIFICATE` block. Exactly one of `public_key_pem` and `jwk` must be set.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("jwk")),
+				},
+			},
+			"jwk": schema.StringAttribute{
+				Optional:    true,
+				Description: "The public key as a JSON Web Key. Its `kid`, `alg` and `use` are the defaults of the arguments of the same name. Private keys are rejected.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"kid": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Key ID. Defaults to the `kid` of `jwk`, else to the RFC 7638 thumbprint of the key.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"alg": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Algorithm used with the key, e.g. `RS256` or `ES256`. Defaults to the `alg` of `jwk`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"use": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Acceptable use of the key: `sig` or `enc`. Defaults to the `use` of `jwk`, else to `sig`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("sig", "enc"),
+				},
+			},
+			"status": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(StatusActive),
+				Description: "Status of the key: `ACTIVE` or `INACTIVE`. Default is `ACTIVE`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(StatusActive, StatusInactive),
+				},
+			},
+			"kty": schema.StringAttribute{
+				Computed:    true,
+				Description: "Key type: `RSA` or `EC`.",
+			},
+			"created": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the key was created.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the key was updated.",
+			},
+		},
+	}
+}
+
+// ModifyPlan converts the key locally so that kid, alg, use and kty are known
+// at plan time, and an invalid key is reported before anything is applied.
+func (r *appOAuthJWKResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan appOAuthJWKModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.PublicKeyPEM.IsUnknown() || plan.JWK.IsUnknown() {
+		return
+	}
+	key, err := appOAuthJWKFromInput(plan.PublicKeyPEM.ValueString(), plan.JWK.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("invalid public key", err.Error())
+		return
+	}
+	if plan.Kid.IsUnknown() {
+		plan.Kid = types.StringValue(key.KeyID)
+	}
+	if plan.Alg.IsUnknown() {
+		plan.Alg = types.StringValue(key.Algorithm)
+	}
+	if plan.Use.IsUnknown() {
+		plan.Use = types.StringValue(key.Use)
+	}
+	plan.Kty = types.StringValue(appOAuthJWKKty(key))
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *appOAuthJWKResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data appOAuthJWKModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	key, err := appOAuthJWKFromInput(data.PublicKeyPEM.ValueString(), data.JWK.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("invalid public key", err.Error())
+		return
+	}
+	if !data.Kid.IsUnknown() {
+		key.KeyID = data.Kid.ValueString()
+	}
+	if !data.Alg.IsUnknown() {
+		key.Algorithm = data.Alg.ValueString()
+	}
+	if !data.Use.IsUnknown() {
+		key.Use = data.Use.ValueString()
+	}
+	data.Kid = types.StringValue(key.KeyID)
+	data.Alg = types.StringValue(key.Algorithm)
+	data.Use = types.StringValue(key.Use)
+	data.Kty = types.StringValue(appOAuthJWKKty(key))
+	keyJSON, err := key.MarshalJSON()
+	if err != nil {
+		resp.Diagnostics.AddError("failed to encode public key", err.Error())
+		return
+	}
+	body := map[string]any{}
+	if err := json.Unmarshal(keyJSON, &body); err != nil {
+		resp.Diagnostics.AddError("failed to encode public key", err.Error())
+		return
+	}
+	body["status"] = data.Status.ValueString()
+
+	var jwk appOAuthJWK
+	if _, err := r.do(ctx, http.MethodPost, appOAuthJWKsURL(data.AppID.ValueString()), body, &jwk); err != nil {
+		resp.Diagnostics.AddError("failed to add JSON web key", err.Error())
+		return
+	}
+	applyAppOAuthJWKToState(&data, &jwk)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appOAuthJWKResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data appOAuthJWKModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var jwk appOAuthJWK
+	apiResp, err := r.do(ctx, http.MethodGet, appOAuthJWKURL(data.AppID.ValueString(), data.ID.ValueString()), nil, &jwk)
+	if utils.Is404(apiResp) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("failed to read JSON web key", err.Error())
+		return
+	}
+	applyAppOAuthJWKToState(&data, &jwk)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appOAuthJWKResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data appOAuthJWKModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// status is the only argument that doesn't replace the key
+	action := "deactivate"
+	if data.Status.ValueString() == StatusActive {
+		action = "activate"
+	} else if err := r.checkNotLastActiveKey(ctx, data.AppID.ValueString(), data.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("failed to deactivate JSON web key", err.Error())
+		return
+	}
+	var jwk appOAuthJWK
+	url := fmt.Sprintf("%s/lifecycle/%s", appOAuthJWKURL(data.AppID.ValueString(), data.ID.ValueString()), action)
+	if _, err := r.do(ctx, http.MethodPost, url, nil, &jwk); err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("failed to %s JSON web key", action), err.Error())
+		return
+	}
+	applyAppOAuthJWKToState(&data, &jwk)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appOAuthJWKResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data appOAuthJWKModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	appID := data.AppID.ValueString()
+	url := appOAuthJWKURL(appID, data.ID.ValueString())
+	var jwk appOAuthJWK
+	apiResp, err := r.do(ctx, http.MethodGet, url, nil, &jwk)
+	if utils.Is404(apiResp) {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("failed to read JSON web key", err.Error())
+		return
+	}
+	if jwk.Status == StatusActive {
+		if err := r.checkNotLastActiveKey(ctx, appID, jwk.ID); err != nil {
+			resp.Diagnostics.AddError("failed to deactivate JSON web key", err.Error())
+			return
+		}
+		if _, err := r.do(ctx, http.MethodPost, url+"/lifecycle/deactivate", nil, nil); err != nil {
+			resp.Diagnostics.AddError("failed to deactivate JSON web key", err.Error())
+			return
+		}
+	}
+	apiResp, err = r.do(ctx, http.MethodDelete, url, nil, nil)
+	if err := utils.SuppressErrorOn404(apiResp, err); err != nil {
+		resp.Diagnostics.AddError("failed to delete JSON web key", err.Error())
+	}
+}
+
+// checkNotLastActiveKey fails when keyID is the last active signing key of an
+// application authenticating with private_key_jwt, which Okta doesn't
+// deactivate. Checking first reports the order the keys must be rolled over in
+// before anything is changed.
+func (r *appOAuthJWKResource) checkNotLastActiveKey(ctx context.Context, appID, keyID string) error {
+	var app struct {
+		Credentials struct {
+			OauthClient struct {
+				TokenEndpointAuthMethod string `json:"token_endpoint_auth_method"`
+			} `json:"oauthClient"`
+		} `json:"credentials"`
+	}
+	if _, err := r.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/apps/%s", appID), nil, &app); err != nil {
+		return fmt.Errorf("failed to get OAuth application %s: %v", appID, err)
+	}
+	if app.Credentials.OauthClient.TokenEndpointAuthMethod != "private_key_jwt" {
+		return nil
+	}
+	var keys []appOAuthJWK
+	if _, err := r.do(ctx, http.MethodGet, appOAuthJWKsURL(appID), nil, &keys); err != nil {
+		return fmt.Errorf("failed to list the JSON web keys of OAuth application %s: %v", appID, err)
+	}
+	for _, key := range keys {
+		if key.ID != keyID && key.Status == StatusActive && key.Use != "enc" {
+			return nil
+		}
+	}
+	return fmt.Errorf("key %s is the last active signing key of OAuth application %s, which authenticates with private_key_jwt. "+
+		"Add its replacement first, with 'lifecycle { create_before_destroy = true }' or as an active key the change depends on", keyID, appID)
+}
+
+// do sends a request to the JWKS API of an application. The keys are handled
+// as plain JSON because the SDK models drop their alg and use.
+func (r *appOAuthJWKResource) do(ctx context.Context, method, url string, body, v any) (*sdk.Response, error) {
+	re := r.OktaIDaaSClient.OktaSDKClientV2().GetRequestExecutor()
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	return re.Do(ctx, req, v)
+}
+
+func appOAuthJWKsURL(appID string) string {
+	return fmt.Sprintf("/api/v1/apps/%s/credentials/jwks", appID)
+}
+
+func appOAuthJWKURL(appID, keyID string) string {
+	return fmt.Sprintf("%s/%s", appOAuthJWKsURL(appID), keyID)
+}
+
+func applyAppOAuthJWKToState(data *appOAuthJWKModel, jwk *appOAuthJWK) {
+	data.ID = types.StringValue(jwk.ID)
+	data.Status = types.StringValue(jwk.Status)
+	data.Created = types.StringValue(jwk.Created)
+	data.LastUpdated = types.StringValue(jwk.LastUpdated)
+	if jwk.Kid != "" {
+		data.Kid = types.StringValue(jwk.Kid)
+	}
+	if jwk.Kty != "" {
+		data.Kty = types.StringValue(jwk.Kty)
+	}
+}
+
+// appOAuthJWKFromInput converts a PEM encoded public key, or a JWK, to the
+// public JWK sent to Okta. The key ID defaults to the RFC 7638 thumbprint of
+// the key and its use to sig.
+func appOAuthJWKFromInput(publicKeyPEM, jwkJSON string) (*jose.JSONWebKey, error) {
+	var key jose.JSONWebKey
+	switch {
+	case publicKeyPEM != "":
+		block, _ := pem.Decode([]byte(publicKeyPEM))
+		if block == nil {
+			return nil, errors.New("public_key_pem doesn't contain a PEM block")
+		}
+		var (
+			pub any
+			err error
+		)
+		switch block.Type {
+		case "PUBLIC KEY":
+			pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				pub = cert.PublicKey
+			}
+		default:
+			return nil, fmt.Errorf("unsupported PEM block %q, expected a PUBLIC KEY, RSA PUBLIC KEY or CERTIFICATE", block.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public_key_pem: %w", err)
+		}
+		key = jose.JSONWebKey{Key: pub}
+	case jwkJSON != "":
+		if err := key.UnmarshalJSON([]byte(jwkJSON)); err != nil {
+			return nil, fmt.Errorf("failed to parse jwk: %w", err)
+		}
+		if !key.IsPublic() {
+			return nil, errors.New("jwk is a private key, only public keys are accepted")
+		}
+	default:
+		return nil, errors.New("one of public_key_pem and jwk must be set")
+	}
+
+	public := key.Public()
+	if !public.Valid() || appOAuthJWKKty(&public) == "" {
+		return nil, errors.New("only RSA and EC public keys are supported")
+	}
+	// keep only the parameters of the public key
+	public.Certificates = nil
+	public.CertificateThumbprintSHA1 = nil
+	public.CertificateThumbprintSHA256 = nil
+	public.CertificatesURL = nil
+	if public.KeyID == "" {
+		thumbprint, err := public.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute the thumbprint of the key: %w", err)
+		}
+		public.KeyID = base64.RawURLEncoding.EncodeToString(thumbprint)
+	}
+	if public.Use == "" {
+		public.Use = "sig"
+	}
+	return &public, nil
+}
+
+func appOAuthJWKKty(key *jose.JSONWebKey) string {
+	switch key.Key.(type) {
+	case *rsa.PublicKey:
+		return "RSA"
+	case *ecdsa.PublicKey:
+		return "EC"
+	}
+	return ""
+}
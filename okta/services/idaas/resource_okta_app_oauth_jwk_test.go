@@ -0,0 +1,53 @@
+package idaas_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+func TestAccResourceOktaAppOAuthJWK_rollover(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the rollover checks the order in which Okta accepts the keys to be
+		// added and deactivated, which replayed responses don't, run it
+		// against an org
+		return
+	}
+	rsaResourceName := fmt.Sprintf("%s.rsa", resources.OktaIDaaSAppOAuthJWK)
+	ecResourceName := fmt.Sprintf("%s.ec", resources.OktaIDaaSAppOAuthJWK)
+	mgr := newFixtureManager("resources", resources.OktaIDaaSAppOAuthJWK, t.Name())
+	basic := mgr.GetFixtures("basic.tf", t)
+	rollover := mgr.GetFixtures("rollover.tf", t)
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(rsaResourceName, "id"),
+					resource.TestCheckResourceAttrSet(rsaResourceName, "kid"),
+					resource.TestCheckResourceAttr(rsaResourceName, "kty", "RSA"),
+					resource.TestCheckResourceAttr(rsaResourceName, "alg", "RS256"),
+					resource.TestCheckResourceAttr(rsaResourceName, "use", "sig"),
+					resource.TestCheckResourceAttr(rsaResourceName, "status", "ACTIVE"),
+				),
+			},
+			{
+				Config: rollover,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(ecResourceName, "kid", "testAcc-ec-key"),
+					resource.TestCheckResourceAttr(ecResourceName, "kty", "EC"),
+					resource.TestCheckResourceAttr(ecResourceName, "alg", "ES256"),
+					resource.TestCheckResourceAttr(ecResourceName, "status", "ACTIVE"),
+					resource.TestCheckResourceAttr(rsaResourceName, "status", "INACTIVE"),
+				),
+			},
+		},
+	})
+}
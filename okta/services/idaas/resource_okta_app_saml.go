@@ -45,7 +45,7 @@ var (
 )
 
 func resourceAppSaml() *schema.Resource {
-	return &schema.Resource{
+	return withIDIdentity(&schema.Resource{
 		CreateContext: resourceAppSamlCreate,
 		ReadContext:   resourceAppSamlRead,
 		UpdateContext: resourceAppSamlUpdate,
@@ -53,6 +53,7 @@ func resourceAppSaml() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: appImporter,
 		},
+		CustomizeDiff: appSamlMetadataCustomizeDiff,
 		Description: `This resource allows you to create and configure a SAML Application.
 -> During an apply if there is change in 'status' the app will first be
 activated or deactivated in accordance with the 'status' change. Then, all
@@ -221,24 +222,40 @@ request feature flag 'ADVANCED_SSO' be applied to your org.`,
 				Optional:    true,
 				Description: "Identifies a specific application resource in an IDP initiated SSO scenario.",
 			},
+			"sp_metadata_xml": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"sp_metadata_url"},
+				Description:   "SAML metadata of the service provider. The SSO, audience, subject NameID format, ACS endpoints and single logout arguments that aren't set are filled from it, arguments set to other values than the metadata are reported as conflicts during the plan.",
+			},
+			"sp_metadata_url": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"sp_metadata_xml"},
+				Description:   "URL of the SAML metadata of the service provider, fetched during the plan. It fills the arguments like `sp_metadata_xml` does.",
+			},
 			"sso_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true, // Required for SetNew()
 				Description: "Single Sign On URL",
 			},
 			"recipient": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true, // Required for SetNew()
 				Description: "The location where the app may present the SAML assertion",
 			},
 			"destination": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true, // Required for SetNew()
 				Description: "Identifies the location where the SAML response is intended to be sent inside of the SAML assertion",
 			},
 			"audience": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true, // Required for SetNew()
 				Description: "Audience Restriction",
 			},
 			"idp_issuer": {
@@ -260,6 +277,7 @@ request feature flag 'ADVANCED_SSO' be applied to your org.`,
 			"subject_name_id_format": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true, // Required for SetNew()
 				Description: "Identifies the SAML processing rules.",
 			},
 			"response_signed": {
@@ -321,12 +339,14 @@ request feature flag 'ADVANCED_SSO' be applied to your org.`,
 				Type:          schema.TypeList,
 				Elem:          &schema.Schema{Type: schema.TypeString},
 				Optional:      true,
+				Computed:      true, // Required for SetNew()
 				Description:   "An array of ACS endpoints. You can configure a maximum of 100 endpoints.",
 				ConflictsWith: []string{"acs_endpoints_indices"},
 			},
 			"acs_endpoints_indices": {
 				Type:          schema.TypeSet,
 				Optional:      true,
+				Computed:      true, // Required for SetNew()
 				ConflictsWith: []string{"acs_endpoints"},
 				Description:   "ACS endpoints with indices, as a set of maps with `url` and `index` keys.",
 				Elem: &schema.Resource{
@@ -385,18 +405,21 @@ request feature flag 'ADVANCED_SSO' be applied to your org.`,
 			"single_logout_issuer": {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Computed:     true, // Required for SetNew()
 				Description:  "The issuer of the Service Provider that generates the Single Logout request",
 				RequiredWith: []string{"single_logout_url"},
 			},
 			"single_logout_url": {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Computed:     true, // Required for SetNew()
 				Description:  "The location where the logout response is sent",
 				RequiredWith: []string{"single_logout_issuer"},
 			},
 			"single_logout_certificate": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true, // Required for SetNew()
 				Description: "x509 encoded certificate that the Service Provider uses to sign Single Logout requests. Note: should be provided without `-----BEGIN CERTIFICATE-----` and `-----END CERTIFICATE-----`, see [official documentation](https://developer.okta.com/docs/reference/api/apps/#service-provider-certificate).",
 				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 					oldCert, err := utils.CertNormalize(oldValue)
@@ -449,7 +472,7 @@ request feature flag 'ADVANCED_SSO' be applied to your org.`,
 			Read:   schema.DefaultTimeout(1 * time.Hour),
 			Update: schema.DefaultTimeout(1 * time.Hour),
 		},
-	}
+	})
 }
 
 func resourceAppSamlCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -836,3 +859,48 @@ func validateAppSaml(d *schema.ResourceData) error {
 	}
 	return nil
 }
+
+// appSamlMetadataCustomizeDiff fills the arguments described by the SAML
+// metadata of the service provider and reports the configured ones differing
+// from it. The ACS endpoints set in either argument are compared as a whole.
+func appSamlMetadataCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	fields := samlAppMetadataFields
+	acsConfigured := !config.GetAttr("acs_endpoints").IsNull() || !config.GetAttr("acs_endpoints_indices").IsNull()
+	if !acsConfigured {
+		fields = append(append([]string{}, samlAppMetadataFields...), samlAppACSFields...)
+	}
+	metadataXML, metadataURL := config.GetAttr("sp_metadata_xml"), config.GetAttr("sp_metadata_url")
+	if !metadataXML.IsKnown() || !metadataURL.IsKnown() {
+		return setUnconfiguredComputed(d, fields)
+	}
+	var raw, source string
+	switch {
+	case !metadataXML.IsNull():
+		raw, source = metadataXML.AsString(), "sp_metadata_xml"
+	case !metadataURL.IsNull():
+		var err error
+		if raw, err = fetchSAMLMetadata(ctx, metadataURL.AsString()); err != nil {
+			return err
+		}
+		source = "the metadata at sp_metadata_url"
+	default:
+		return clearUnconfigured(d, fields)
+	}
+	entity, err := parseSAMLMetadata(raw)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", source, err)
+	}
+	values, err := samlAppMetadataValues(entity)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", source, err)
+	}
+	if acsConfigured && config.GetAttr("acs_endpoints").IsWhollyKnown() && config.GetAttr("acs_endpoints_indices").IsWhollyKnown() {
+		configured := acsEndpointPairs(d.Get("acs_endpoints"), d.Get("acs_endpoints_indices"))
+		described := acsEndpointPairs(values["acs_endpoints"], values["acs_endpoints_indices"])
+		if len(described) > 0 && !samlMetadataValueEqual(configured, described) {
+			return fmt.Errorf("the arguments set in the configuration conflict with %s, remove them or make them match: the ACS endpoints are %v but the metadata has %v", source, configured, described)
+		}
+	}
+	return setFromSAMLMetadata(d, source, fields, values)
+}
@@ -0,0 +1,343 @@
+package idaas
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+// appSamlKeyRotationKeyFields are the attributes describing the active and
+// the next signing keys, they change when a key is generated or activated.
+var appSamlKeyRotationKeyFields = []string{
+	"active_key_id",
+	"active_key_expires_at",
+	"active_certificate",
+	"next_key_id",
+	"next_key_expires_at",
+	"next_key_activates_at",
+	"next_certificate",
+	"metadata",
+}
+
+func resourceAppSamlKeyRotation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppSamlKeyRotationCreate,
+		ReadContext:   resourceAppSamlKeyRotationRead,
+		UpdateContext: resourceAppSamlKeyRotationUpdate,
+		DeleteContext: utils.ResourceFuncNoOp,
+		Importer: &schema.ResourceImporter{
+			StateContext: appSamlKeyRotationImport,
+		},
+		CustomizeDiff: appSamlKeyRotationCustomizeDiff,
+		Description: `Rotates the signing key of a SAML application ahead of its expiry.
+When the active key expires within 'rotate_days_before_expiry' days a new key is generated, it is published
+with the active key in 'metadata' so that the service provider trusts both. 'activate_days_after_publish'
+days later, or when the active key expires if that comes first, the new key becomes the active key of the
+application. The rotation happens during the plan and apply following these dates, run them on a schedule.
+Only the keys generated by the resource are activated, keys generated otherwise are left as they are.
+-> Don't set 'key_name' on the 'okta_app_saml' resource of the application, it generates keys too.
+Destroying the resource leaves the keys of the application as they are.`,
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the SAML application.",
+			},
+			"key_years_valid": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntBetween(2, 10),
+				Description:  "Number of years the generated keys are valid (2 - 10 years). Default: `2`",
+			},
+			"rotate_days_before_expiry": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "A new key is generated when the active key expires within this many days. Default: `60`",
+			},
+			"activate_days_after_publish": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      14,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The new key becomes the active key this many days after it is generated, giving the service provider time to trust it. Default: `14`",
+			},
+			"active_key_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the key signing the assertions of the application.",
+			},
+			"active_key_expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiration date of the active key.",
+			},
+			"active_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Base64 encoded certificate of the active key.",
+			},
+			"next_key_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the key the resource generated and rotates the application to, empty when no rotation is in progress.",
+			},
+			"next_key_expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiration date of the next key.",
+			},
+			"next_key_activates_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Date from which the next key becomes the active key.",
+			},
+			"next_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Base64 encoded certificate of the next key.",
+			},
+			"metadata": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SAML metadata of the application publishing the certificates of both the active and the next key.",
+			},
+		},
+	}
+}
+
+func resourceAppSamlKeyRotationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	if err := rotateAppSamlKey(ctx, d, meta, appID); err != nil {
+		return diag.Errorf("failed to rotate the signing key of SAML application: %v", err)
+	}
+	d.SetId(appID)
+	return resourceAppSamlKeyRotationRead(ctx, d, meta)
+}
+
+func resourceAppSamlKeyRotationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	app := sdk.NewSamlApplication()
+	err := fetchAppByID(ctx, d.Id(), meta, app)
+	if err != nil {
+		return diag.Errorf("failed to get SAML application: %v", err)
+	}
+	if app.Id == "" {
+		d.SetId("")
+		return nil
+	}
+	keys, err := fetchAppKeys(ctx, meta, app.Id)
+	if err != nil {
+		return diag.Errorf("failed to load existing keys for SAML application: %v", err)
+	}
+	active, next := appSamlSigningKeys(app, keys, d.Get("next_key_id").(string))
+	if active == nil {
+		return diag.Errorf("SAML application %s has no active signing key", app.Id)
+	}
+	_ = d.Set("app_id", app.Id)
+	_ = d.Set("active_key_id", active.Kid)
+	_ = d.Set("active_key_expires_at", formatKeyTime(active.ExpiresAt))
+	_ = d.Set("active_certificate", keyCertificate(active))
+	metadata, metadataRoot, err := getAPISupplementFromMetadata(meta).GetSAMLMetadata(ctx, app.Id, active.Kid)
+	if err != nil {
+		return diag.Errorf("failed to get SAML application's metadata: %v", err)
+	}
+	if next == nil {
+		_ = d.Set("next_key_id", "")
+		_ = d.Set("next_key_expires_at", "")
+		_ = d.Set("next_key_activates_at", "")
+		_ = d.Set("next_certificate", "")
+		_ = d.Set("metadata", string(metadata))
+		return nil
+	}
+	_ = d.Set("next_key_id", next.Kid)
+	_ = d.Set("next_key_expires_at", formatKeyTime(next.ExpiresAt))
+	_ = d.Set("next_key_activates_at", appSamlKeyActivatesAt(active, next, d.Get("activate_days_after_publish").(int)).UTC().Format(time.RFC3339))
+	_ = d.Set("next_certificate", keyCertificate(next))
+	metadata, err = publishSigningCertificate(metadataRoot, keyCertificate(next))
+	if err != nil {
+		return diag.Errorf("failed to add the next certificate to SAML application's metadata: %v", err)
+	}
+	_ = d.Set("metadata", string(metadata))
+	return nil
+}
+
+func resourceAppSamlKeyRotationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := rotateAppSamlKey(ctx, d, meta, d.Id()); err != nil {
+		return diag.Errorf("failed to rotate the signing key of SAML application: %v", err)
+	}
+	return resourceAppSamlKeyRotationRead(ctx, d, meta)
+}
+
+// appSamlKeyRotationCustomizeDiff plans the generation or the activation of a
+// key once their date has come, the keys are known after apply.
+func appSamlKeyRotationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	now := time.Now()
+	due := false
+	if d.Get("next_key_id").(string) == "" {
+		expiresAt, err := time.Parse(time.RFC3339, d.Get("active_key_expires_at").(string))
+		due = err == nil && !now.AddDate(0, 0, d.Get("rotate_days_before_expiry").(int)).Before(expiresAt)
+	} else {
+		activatesAt, err := time.Parse(time.RFC3339, d.Get("next_key_activates_at").(string))
+		due = err == nil && !now.Before(activatesAt)
+	}
+	if !due && !d.HasChange("activate_days_after_publish") {
+		return nil
+	}
+	for _, field := range appSamlKeyRotationKeyFields {
+		if err := d.SetNewComputed(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotateAppSamlKey generates the next signing key of the application when the
+// active one is about to expire, and activates it when its date has come.
+func rotateAppSamlKey(ctx context.Context, d *schema.ResourceData, meta interface{}, appID string) error {
+	app := sdk.NewSamlApplication()
+	err := fetchAppByID(ctx, appID, meta, app)
+	if err != nil {
+		return err
+	}
+	if app.Id == "" {
+		return fmt.Errorf("application with id %s does not exist", appID)
+	}
+	keys, err := fetchAppKeys(ctx, meta, app.Id)
+	if err != nil {
+		return fmt.Errorf("failed to load existing keys: %v", err)
+	}
+	// the next key may be planned as unknown, the key generated before is the
+	// one of the state
+	nextKeyID, _ := d.GetChange("next_key_id")
+	active, next := appSamlSigningKeys(app, keys, nextKeyID.(string))
+	if active == nil || active.ExpiresAt == nil {
+		return errors.New("the application has no active signing key")
+	}
+
+	client := getOktaClientFromMetadata(meta)
+	now := time.Now()
+	if next == nil && !now.AddDate(0, 0, d.Get("rotate_days_before_expiry").(int)).Before(*active.ExpiresAt) {
+		params := &query.Params{ValidityYears: int64(d.Get("key_years_valid").(int))}
+		next, _, err = client.Application.GenerateApplicationKey(ctx, app.Id, params)
+		if err != nil {
+			return fmt.Errorf("failed to generate a new key: %v", err)
+		}
+		if next.Created == nil {
+			next.Created = &now
+		}
+		_ = d.Set("next_key_id", next.Kid)
+	}
+	if next == nil || now.Before(appSamlKeyActivatesAt(active, next, d.Get("activate_days_after_publish").(int))) {
+		return nil
+	}
+	if app.Credentials.Signing == nil {
+		app.Credentials.Signing = &sdk.ApplicationCredentialsSigning{}
+	}
+	app.Credentials.Signing.Kid = next.Kid
+	if err := updateAppByID(ctx, app.Id, meta, app); err != nil {
+		return fmt.Errorf("failed to activate key %s: %v", next.Kid, err)
+	}
+	_ = d.Set("next_key_id", "")
+	return nil
+}
+
+// appSamlKeyRotationImport imports the rotation of an app, <app_id>, or of an
+// app with a key generated by an earlier rotation to activate,
+// <app_id>/<next_key_id>. The arguments are set to their defaults, the import
+// has no configuration.
+func appSamlKeyRotationImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) > 2 {
+		return nil, errors.New("invalid resource import specifier. Use: terraform import <app_id> or <app_id>/<next_key_id>")
+	}
+	d.SetId(parts[0])
+	if len(parts) == 2 {
+		_ = d.Set("next_key_id", parts[1])
+	}
+	resource := resourceAppSamlKeyRotation()
+	for _, field := range []string{"key_years_valid", "rotate_days_before_expiry", "activate_days_after_publish"} {
+		_ = d.Set(field, resource.Schema[field].Default)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// appSamlSigningKeys returns the active signing key of app and the key it is
+// being rotated to, the key nextKeyID generated by the resource. next is nil
+// when the key is gone or already active.
+func appSamlSigningKeys(app *sdk.SamlApplication, keys []*sdk.JsonWebKey, nextKeyID string) (active, next *sdk.JsonWebKey) {
+	if app.Credentials == nil || app.Credentials.Signing == nil {
+		return nil, nil
+	}
+	for _, key := range keys {
+		switch {
+		case key.Kid == app.Credentials.Signing.Kid:
+			active = key
+		case key.Kid == nextKeyID && key.Created != nil:
+			next = key
+		}
+	}
+	return active, next
+}
+
+// appSamlKeyActivatesAt returns when next becomes the active key: days after
+// it was created, or when the active key expires if that comes first. next is
+// one of the keys returned by appSamlSigningKeys.
+func appSamlKeyActivatesAt(active, next *sdk.JsonWebKey, days int) time.Time {
+	activatesAt := next.Created.AddDate(0, 0, days)
+	if active.ExpiresAt != nil && active.ExpiresAt.Before(activatesAt) {
+		return *active.ExpiresAt
+	}
+	return activatesAt
+}
+
+// publishSigningCertificate returns the metadata root with cert as an
+// additional signing certificate of the IdP.
+func publishSigningCertificate(root *saml.EntityDescriptor, cert string) ([]byte, error) {
+	if root == nil || len(root.IDPSSODescriptors) == 0 {
+		return nil, errors.New("the metadata has no IDPSSODescriptor")
+	}
+	root.IDPSSODescriptors[0].KeyDescriptors = append(root.IDPSSODescriptors[0].KeyDescriptors, saml.KeyDescriptor{
+		Use: "signing",
+		KeyInfo: saml.KeyInfo{
+			X509Data: saml.X509Data{
+				X509Certificates: []saml.X509Certificate{{Data: cert}},
+			},
+		},
+	})
+	metadata, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), metadata...), nil
+}
+
+func keyCertificate(key *sdk.JsonWebKey) string {
+	if len(key.X5c) == 0 {
+		return ""
+	}
+	return key.X5c[0]
+}
+
+func formatKeyTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,79 @@
+package idaas_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestAccResourceOktaAppSamlKeyRotation_crud(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the rotation compares the dates of the keys with the current time,
+		// replayed responses plan other rotations as they age, run it against
+		// an org
+		return
+	}
+	mgr := newFixtureManager("resources", resources.OktaIDaaSAppSamlKeyRotation, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	publish := mgr.GetFixtures("publish.tf", t)
+	rotate := mgr.GetFixtures("rotate.tf", t)
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSAppSamlKeyRotation)
+	var activeKeyID, nextKeyID string
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             checkResourceDestroy(resources.OktaIDaaSAppSaml, createDoesAppExist(sdk.NewSamlApplication())),
+		Steps: []resource.TestStep{
+			{
+				// the key of a new app doesn't expire within 60 days
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "app_id", "okta_app_saml.test", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "active_key_id", "okta_app_saml.test", "key_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "active_key_expires_at"),
+					resource.TestCheckResourceAttrSet(resourceName, "metadata"),
+					resource.TestCheckResourceAttr(resourceName, "next_key_id", ""),
+				),
+			},
+			{
+				// the next key is generated and published
+				Config: publish,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "next_key_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "next_key_activates_at"),
+					resource.TestCheckResourceAttrSet(resourceName, "next_certificate"),
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources[resourceName].Primary.Attributes
+						activeKeyID, nextKeyID = attributes["active_key_id"], attributes["next_key_id"]
+						if activeKeyID == nextKeyID {
+							return fmt.Errorf("expected the next key to differ from the active key %s", activeKeyID)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// the next key is activated, and as the new active key expires
+				// within rotate_days_before_expiry the plan generates another one
+				Config: rotate,
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources[resourceName].Primary.Attributes
+						if attributes["active_key_id"] != nextKeyID {
+							return fmt.Errorf("expected the next key %s to be active, got %s", nextKeyID, attributes["active_key_id"])
+						}
+						return nil
+					},
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
@@ -588,6 +588,40 @@ func TestAccResourceOktaAppSaml_Issue2171AcsEndpointsWithIndex(t *testing.T) {
 	})
 }
 
+func TestAccResourceOktaAppSaml_spMetadata(t *testing.T) {
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSAppSaml)
+	mgr := newFixtureManager("resources", resources.OktaIDaaSAppSaml, t.Name())
+	config := mgr.GetFixtures("sp_metadata.tf", t)
+	conflictConfig := mgr.GetFixtures("sp_metadata_conflict.tf", t)
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             checkResourceDestroy(resources.OktaIDaaSAppSaml, createDoesAppExist(sdk.NewSamlApplication())),
+		Steps: []resource.TestStep{
+			{
+				Config:      conflictConfig,
+				ExpectError: regexp.MustCompile(`audience is set to https://sp.example.com/other but the metadata has https://sp.example.com/saml`),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					ensureResourceExists(resourceName, createDoesAppExist(sdk.NewSamlApplication())),
+					resource.TestCheckResourceAttr(resourceName, "sso_url", "https://sp.example.com/saml/acs"),
+					resource.TestCheckResourceAttr(resourceName, "recipient", "https://sp.example.com/saml/acs"),
+					resource.TestCheckResourceAttr(resourceName, "destination", "https://sp.example.com/saml/acs"),
+					resource.TestCheckResourceAttr(resourceName, "audience", "https://sp.example.com/saml"),
+					resource.TestCheckResourceAttr(resourceName, "subject_name_id_format", "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"),
+					resource.TestCheckResourceAttr(resourceName, "single_logout_url", "https://sp.example.com/saml/slo"),
+					resource.TestCheckResourceAttr(resourceName, "single_logout_issuer", "https://sp.example.com/saml"),
+					resource.TestCheckResourceAttr(resourceName, "acs_endpoints.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "acs_endpoints.1", "https://sp.example.com/saml/acs/1"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceOktaAppSaml_skipAuthenticationPolicy(t *testing.T) {
 	mgr := newFixtureManager("resources", resources.OktaIDaaSAppSaml, t.Name())
 	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSAppSaml)
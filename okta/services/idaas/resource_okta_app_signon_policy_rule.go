@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/resources"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 	"github.com/okta/terraform-provider-okta/sdk"
@@ -19,6 +20,7 @@ func resourceAppSignOnPolicyRule() *schema.Resource {
 		ReadContext:   resourceAppSignOnPolicyRuleRead,
 		UpdateContext: resourceAppSignOnPolicyRuleUpdate,
 		DeleteContext: resourceAppSignOnPolicyRuleDelete,
+		CustomizeDiff: requireCapabilities(resources.OktaIDaaSAppSignOnPolicyRule, config.CapabilityIdentityEngine),
 		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
 			func(ctx context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
 				reauthenticateInFreqPresent := !req.RawConfig.GetAttr("re_authentication_frequency").IsNull()
@@ -38,7 +38,7 @@ func resourceAuthServerClaim() *schema.Resource {
 			"value": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The value of the claim.",
+				Description: "The value of the claim. When `value_type` is `EXPRESSION` its syntax, the functions it calls and the user attributes it reads are checked at plan time. An expression reading a user attribute added by an `okta_user_schema_property` of the same configuration must depend on it.",
 			},
 			"value_type": {
 				Type:        schema.TypeString,
@@ -63,6 +63,9 @@ func resourceAuthServerClaim() *schema.Resource {
 				Description: "Specifies the type of group filter if `value_type` is `GROUPS`. Can be set to one of the following `STARTS_WITH`, `EQUALS`, `CONTAINS`, `REGEX`.",
 			},
 		},
+		CustomizeDiff: oktaExpressionCustomizeDiff("value", func(d *schema.ResourceDiff) bool {
+			return d.NewValueKnown("value_type") && d.Get("value_type").(string) == "EXPRESSION"
+		}),
 	}
 }
 
@@ -0,0 +1,293 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	v6okta "github.com/okta/okta-sdk-golang/v6/okta"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+)
+
+// authServerJWKSTimeout is how long a rotation waits for the new keys to be
+// published in the JWKS of the authorization server.
+const authServerJWKSTimeout = 5 * time.Minute
+
+var (
+	_ resource.Resource              = &authServerKeyRotationResource{}
+	_ resource.ResourceWithConfigure = &authServerKeyRotationResource{}
+)
+
+type authServerKeyRotationResource struct {
+	*config.Config
+}
+
+type authServerKeyRotationModel struct {
+	ID              types.String `tfsdk:"id"`
+	AuthServerID    types.String `tfsdk:"auth_server_id"`
+	RotationTrigger types.String `tfsdk:"rotation_trigger"`
+	ActiveKeyID     types.String `tfsdk:"active_key_id"`
+	NextKeyID       types.String `tfsdk:"next_key_id"`
+	Keys            types.List   `tfsdk:"keys"`
+}
+
+type authServerKeyRotationKeyModel struct {
+	Kid    types.String `tfsdk:"kid"`
+	Status types.String `tfsdk:"status"`
+	Alg    types.String `tfsdk:"alg"`
+	Use    types.String `tfsdk:"use"`
+}
+
+var authServerKeyRotationKeyType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"kid":    types.StringType,
+		"status": types.StringType,
+		"alg":    types.StringType,
+		"use":    types.StringType,
+	},
+}
+
+func newAuthServerKeyRotationResource() resource.Resource {
+	return &authServerKeyRotationResource{}
+}
+
+func (r *authServerKeyRotationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_auth_server_key_rotation"
+}
+
+func (r *authServerKeyRotationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *authServerKeyRotationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Rotates the signing keys of an authorization server. The NEXT key becomes the ACTIVE key, the ACTIVE key
+becomes EXPIRED and a new NEXT key is generated. A rotation happens when the resource is created and whenever
+'rotation_trigger' changes, it completes once the new keys are published in the JWKS of the authorization server.
+Destroying the resource doesn't change the keys.`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the authorization server.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"auth_server_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the authorization server.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"rotation_trigger": schema.StringAttribute{
+				Required:    true,
+				Description: "Any value, the keys are rotated whenever it changes, e.g. a date or a `time_rotating` resource.",
+			},
+			"active_key_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the ACTIVE key, signing the tokens of the authorization server.",
+			},
+			"next_key_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the NEXT key, the ACTIVE key after the next rotation.",
+			},
+			"keys": schema.ListAttribute{
+				Computed:    true,
+				Description: "The ACTIVE, NEXT and EXPIRED keys of the authorization server, with their `kid`, `status`, `alg` and `use`.",
+				ElementType: authServerKeyRotationKeyType,
+			},
+		},
+	}
+}
+
+func (r *authServerKeyRotationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data authServerKeyRotationModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = data.AuthServerID
+	issuer, diags := r.rotate(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// the keys are saved before waiting for them to be published so that a
+	// failed wait doesn't rotate them again. An error would taint the
+	// resource, and replacing it would rotate them again, hence the warning.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.waitForKeys(ctx, issuer, &data); err != nil {
+		resp.Diagnostics.AddWarning("the rotated authorization server keys aren't published", err.Error())
+	}
+}
+
+func (r *authServerKeyRotationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data authServerKeyRotationModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	keys, apiResp, err := getOktaV6ClientFromMetadata(r.Config).AuthorizationServerKeysAPI.ListAuthorizationServerKeys(ctx, data.AuthServerID.ValueString()).Execute()
+	if apiResp != nil && apiResp.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("failed to list authorization server keys", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(applyAuthServerKeysToState(ctx, &data, keys)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *authServerKeyRotationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data authServerKeyRotationModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// only rotation_trigger can change, auth_server_id replaces the resource
+	issuer, diags := r.rotate(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// the keys are saved before waiting for them to be published so that a
+	// failed wait doesn't rotate them again
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.waitForKeys(ctx, issuer, &data); err != nil {
+		resp.Diagnostics.AddError("the rotated authorization server keys aren't published", err.Error())
+	}
+}
+
+func (r *authServerKeyRotationResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	// the keys of the authorization server stay as they are
+}
+
+// rotate rotates the keys of the authorization server and returns its
+// issuer.
+func (r *authServerKeyRotationResource) rotate(ctx context.Context, data *authServerKeyRotationModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	client := getOktaV6ClientFromMetadata(r.Config)
+	authServerID := data.AuthServerID.ValueString()
+	authServer, _, err := client.AuthorizationServerAPI.GetAuthorizationServer(ctx, authServerID).Execute()
+	if err != nil {
+		diags.AddError("failed to get authorization server", err.Error())
+		return "", diags
+	}
+	keys, _, err := client.AuthorizationServerKeysAPI.RotateAuthorizationServerKeys(ctx, authServerID).Use(v6okta.JwkUse{Use: v6okta.PtrString("sig")}).Execute()
+	if err != nil {
+		diags.AddError("failed to rotate authorization server keys", err.Error())
+		return "", diags
+	}
+	diags.Append(applyAuthServerKeysToState(ctx, data, keys)...)
+	return authServer.GetIssuer(), diags
+}
+
+// waitForKeys waits for the ACTIVE and NEXT keys of data to be published in
+// the JWKS of the authorization server of issuer.
+func (r *authServerKeyRotationResource) waitForKeys(ctx context.Context, issuer string, data *authServerKeyRotationModel) error {
+	var kids []string
+	for _, kid := range []types.String{data.ActiveKeyID, data.NextKeyID} {
+		if kid.ValueString() != "" {
+			kids = append(kids, kid.ValueString())
+		}
+	}
+	return waitForAuthServerJWKS(ctx, r.OktaIDaaSClient.HTTPClient(), issuer, kids)
+}
+
+func applyAuthServerKeysToState(ctx context.Context, data *authServerKeyRotationModel, keys []v6okta.AuthorizationServerJsonWebKey) diag.Diagnostics {
+	data.ActiveKeyID = types.StringValue("")
+	data.NextKeyID = types.StringValue("")
+	models := make([]authServerKeyRotationKeyModel, len(keys))
+	for i, key := range keys {
+		switch key.GetStatus() {
+		case "ACTIVE":
+			data.ActiveKeyID = types.StringValue(key.GetKid())
+		case "NEXT":
+			data.NextKeyID = types.StringValue(key.GetKid())
+		}
+		models[i] = authServerKeyRotationKeyModel{
+			Kid:    types.StringValue(key.GetKid()),
+			Status: types.StringValue(key.GetStatus()),
+			Alg:    types.StringValue(key.GetAlg()),
+			Use:    types.StringValue(key.GetUse()),
+		}
+	}
+	list, diags := types.ListValueFrom(ctx, authServerKeyRotationKeyType, models)
+	data.Keys = list
+	return diags
+}
+
+// waitForAuthServerJWKS waits until the JWKS published by the authorization
+// server of issuer lists the keys kids.
+func waitForAuthServerJWKS(ctx context.Context, client *http.Client, issuer string, kids []string) error {
+	jwksURL := strings.TrimSuffix(issuer, "/") + "/v1/keys"
+	boc := utils.NewExponentialBackOffWithContext(ctx, authServerJWKSTimeout)
+	return backoff.Retry(func() error {
+		published, err := fetchJWKSKeyIDs(ctx, client, jwksURL)
+		if err != nil {
+			return err
+		}
+		for _, kid := range kids {
+			if !published[kid] {
+				return fmt.Errorf("key %s isn't published at %s", kid, jwksURL)
+			}
+		}
+		return nil
+	}, boc)
+}
+
+// fetchJWKSKeyIDs returns the IDs of the keys of the JWKS at jwksURL. client
+// is the HTTP client of the provider so that its proxy and the VCR recorder
+// of the acceptance tests apply.
+func fetchJWKSKeyIDs(ctx context.Context, client *http.Client, jwksURL string) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, backoff.Permanent(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the JWKS at %s: %w", jwksURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the JWKS at %s: received status code %d", jwksURL, resp.StatusCode)
+	}
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("invalid JWKS at %s: %w", jwksURL, err)
+	}
+	kids := make(map[string]bool, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		kids[key.Kid] = true
+	}
+	return kids, nil
+}
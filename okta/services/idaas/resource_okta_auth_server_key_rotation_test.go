@@ -0,0 +1,58 @@
+package idaas_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+func TestAccResourceOktaAuthServerKeyRotation_crud(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the rotation polls the JWKS of the authorization server until the
+		// new keys are published, which isn't recorded, run it against an org
+		return
+	}
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSAuthServerKeyRotation)
+	mgr := newFixtureManager("resources", resources.OktaIDaaSAuthServerKeyRotation, t.Name())
+	basic := mgr.GetFixtures("basic.tf", t)
+	updated := mgr.GetFixtures("updated.tf", t)
+	var nextKeyID string
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             nil,
+		Steps: []resource.TestStep{
+			{
+				Config: basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "id", "okta_auth_server.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "rotation_trigger", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "active_key_id"),
+					resource.TestCheckResourceAttrWith(resourceName, "next_key_id", func(value string) error {
+						nextKeyID = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrSet(resourceName, "keys.#"),
+				),
+			},
+			{
+				Config: updated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "rotation_trigger", "2"),
+					resource.TestCheckResourceAttrWith(resourceName, "active_key_id", func(value string) error {
+						if value != nextKeyID {
+							return fmt.Errorf("expected the NEXT key %q to become ACTIVE, got %q", nextKeyID, value)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrSet(resourceName, "next_key_id"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "keys.*", map[string]string{"status": "EXPIRED"}),
+				),
+			},
+		},
+	})
+}
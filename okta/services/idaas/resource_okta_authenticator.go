@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/resources"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 	"github.com/okta/terraform-provider-okta/sdk"
@@ -19,6 +20,7 @@ func resourceAuthenticator() *schema.Resource {
 		ReadContext:   resourceAuthenticatorRead,
 		UpdateContext: resourceAuthenticatorUpdate,
 		DeleteContext: resourceAuthenticatorDelete,
+		CustomizeDiff: requireCapabilities(resources.OktaIDaaSAuthenticator, config.CapabilityIdentityEngine),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/resources"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 	"github.com/okta/terraform-provider-okta/sdk"
@@ -16,6 +17,7 @@ func resourceCaptcha() *schema.Resource {
 		ReadContext:   resourceCaptchaRead,
 		UpdateContext: resourceCaptchaUpdate,
 		DeleteContext: resourceCaptchaDelete,
+		CustomizeDiff: requireCapabilities(resources.OktaIDaaSCaptcha, config.CapabilityIdentityEngine),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/resources"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 	"github.com/okta/terraform-provider-okta/sdk"
@@ -16,6 +17,7 @@ func resourceCaptchaOrgWideSettings() *schema.Resource {
 		ReadContext:   resourceCaptchaOrgWideSettingsRead,
 		UpdateContext: resourceCaptchaOrgWideSettingsUpdate,
 		DeleteContext: resourceCaptchaOrgWideSettingsDelete,
+		CustomizeDiff: requireCapabilities(resources.OktaIDaaSCaptchaOrgWideSettings, config.CapabilityIdentityEngine),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &policyDeviceAssuranceAndroidResource{}
 	_ resource.ResourceWithConfigure   = &policyDeviceAssuranceAndroidResource{}
 	_ resource.ResourceWithImportState = &policyDeviceAssuranceAndroidResource{}
+	_ resource.ResourceWithIdentity    = &policyDeviceAssuranceAndroidResource{}
 )
 
 func newPolicyDeviceAssuranceAndroidResource() resource.Resource {
@@ -193,6 +194,7 @@ func (r *policyDeviceAssuranceAndroidResource) Create(ctx context.Context, req r
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -220,6 +222,7 @@ func (r *policyDeviceAssuranceAndroidResource) Read(ctx context.Context, req res
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -273,6 +276,7 @@ func (r *policyDeviceAssuranceAndroidResource) Update(ctx context.Context, req r
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -342,6 +346,10 @@ func mapDeviceAssuranceAndroidToState(data *okta.ListDeviceAssurancePolicies200R
 	return diags
 }
 
+func (r *policyDeviceAssuranceAndroidResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *policyDeviceAssuranceAndroidResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
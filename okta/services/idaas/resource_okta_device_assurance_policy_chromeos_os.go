@@ -19,6 +19,7 @@ var (
 	_ resource.Resource                = &policyDeviceAssuranceChromeOSResource{}
 	_ resource.ResourceWithConfigure   = &policyDeviceAssuranceChromeOSResource{}
 	_ resource.ResourceWithImportState = &policyDeviceAssuranceChromeOSResource{}
+	_ resource.ResourceWithIdentity    = &policyDeviceAssuranceChromeOSResource{}
 )
 
 func newPolicyDeviceAssuranceChromeOSResource() resource.Resource {
@@ -191,6 +192,7 @@ func (r *policyDeviceAssuranceChromeOSResource) Create(ctx context.Context, req
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -218,6 +220,7 @@ func (r *policyDeviceAssuranceChromeOSResource) Read(ctx context.Context, req re
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -271,6 +274,7 @@ func (r *policyDeviceAssuranceChromeOSResource) Update(ctx context.Context, req
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -355,6 +359,10 @@ func mapDeviceAssuranceChromeOSToState(data *okta.ListDeviceAssurancePolicies200
 	return diags
 }
 
+func (r *policyDeviceAssuranceChromeOSResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *policyDeviceAssuranceChromeOSResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &policyDeviceAssuranceIOSResource{}
 	_ resource.ResourceWithConfigure   = &policyDeviceAssuranceIOSResource{}
 	_ resource.ResourceWithImportState = &policyDeviceAssuranceIOSResource{}
+	_ resource.ResourceWithIdentity    = &policyDeviceAssuranceIOSResource{}
 )
 
 func newPolicyDeviceAssuranceIOSResource() resource.Resource {
@@ -156,6 +157,7 @@ func (r *policyDeviceAssuranceIOSResource) Create(ctx context.Context, req resou
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -183,6 +185,7 @@ func (r *policyDeviceAssuranceIOSResource) Read(ctx context.Context, req resourc
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -236,6 +239,7 @@ func (r *policyDeviceAssuranceIOSResource) Update(ctx context.Context, req resou
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -291,6 +295,10 @@ func mapDeviceAssuranceIOSToState(data *okta.ListDeviceAssurancePolicies200Respo
 	return diags
 }
 
+func (r *policyDeviceAssuranceIOSResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *policyDeviceAssuranceIOSResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
@@ -19,6 +19,7 @@ var (
 	_ resource.Resource                = &policyDeviceAssuranceMacOSResource{}
 	_ resource.ResourceWithConfigure   = &policyDeviceAssuranceMacOSResource{}
 	_ resource.ResourceWithImportState = &policyDeviceAssuranceMacOSResource{}
+	_ resource.ResourceWithIdentity    = &policyDeviceAssuranceMacOSResource{}
 )
 
 func newPolicyDeviceAssuranceMacOSResource() resource.Resource {
@@ -214,6 +215,7 @@ func (r *policyDeviceAssuranceMacOSResource) Create(ctx context.Context, req res
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -241,6 +243,7 @@ func (r *policyDeviceAssuranceMacOSResource) Read(ctx context.Context, req resou
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -294,6 +297,7 @@ func (r *policyDeviceAssuranceMacOSResource) Update(ctx context.Context, req res
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -415,6 +419,10 @@ func mapDeviceAssuranceMacOSToState(data *okta.ListDeviceAssurancePolicies200Res
 	return diags
 }
 
+func (r *policyDeviceAssuranceMacOSResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *policyDeviceAssuranceMacOSResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
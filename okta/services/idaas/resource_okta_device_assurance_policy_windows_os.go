@@ -19,6 +19,7 @@ var (
 	_ resource.Resource                = &policyDeviceAssuranceWindowsResource{}
 	_ resource.ResourceWithConfigure   = &policyDeviceAssuranceWindowsResource{}
 	_ resource.ResourceWithImportState = &policyDeviceAssuranceWindowsResource{}
+	_ resource.ResourceWithIdentity    = &policyDeviceAssuranceWindowsResource{}
 )
 
 func newPolicyDeviceAssuranceWindowsResource() resource.Resource {
@@ -244,6 +245,7 @@ func (r *policyDeviceAssuranceWindowsResource) Create(ctx context.Context, req r
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -271,6 +273,7 @@ func (r *policyDeviceAssuranceWindowsResource) Read(ctx context.Context, req res
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -324,6 +327,7 @@ func (r *policyDeviceAssuranceWindowsResource) Update(ctx context.Context, req r
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -458,6 +462,10 @@ func mapDeviceAssuranceWindowsToState(data *okta.ListDeviceAssurancePolicies200R
 	return diags
 }
 
+func (r *policyDeviceAssuranceWindowsResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *policyDeviceAssuranceWindowsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
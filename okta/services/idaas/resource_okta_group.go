@@ -17,7 +17,7 @@ import (
 )
 
 func resourceGroup() *schema.Resource {
-	return &schema.Resource{
+	return withIDIdentity(&schema.Resource{
 		CreateContext: resourceGroupCreate,
 		ReadContext:   resourceGroupRead,
 		UpdateContext: resourceGroupUpdate,
@@ -80,7 +80,7 @@ func resourceGroup() *schema.Resource {
 				},
 			},
 		},
-	}
+	})
 }
 
 func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -44,8 +44,14 @@ user to many groups, please use the 'okta_user_group_memberships' resource.
 state of user ids that are assigned it. This behavior will signal drift only if
 those users stop being part of the group. If the desired behavior is track all
 users that are added/removed from the group make use of the 'track_all_users'
-argument with this resource.`,
-		Schema: map[string]*schema.Schema{
+argument with this resource. Set 'authoritative' to also remove the members of
+the group that aren't in 'users' on apply. A percentage in
+'max_membership_removals' is of the current members of the group, on plan,
+apply and destroy alike.
+Membership changes are made with up to 'parallelism' requests at once, under the
+provider's API rate limit governor, and users whose change failed are reported
+by ID.`,
+		Schema: utils.BuildSchema(map[string]*schema.Schema{
 			"group_id": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -64,13 +70,92 @@ argument with this resource.`,
 				Default:     false,
 				Description: "The resource concerns itself with all users added/deleted to the group; even those managed outside of the resource.",
 			},
-		},
+			"authoritative": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "The users of the group are exactly the users in `users`: members added outside of the resource are removed on apply. Implies `track_all_users`. Default: `false`",
+			},
+		}, membershipRemovalsSchema),
+		CustomizeDiff: groupMembershipsCustomizeDiff,
 	}
 }
 
+// groupMembershipsCustomizeDiff fails the plan when it removes more users
+// from the group than max_membership_removals allows. The users an
+// authoritative resource removes when it is created are only known from the
+// API, the limit is checked again on apply for the users that weren't known
+// when planning. The group is only listed when there is a limit.
+func groupMembershipsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if setting, _ := membershipRemovalLimit(d.Get, meta); setting == "" {
+		return nil
+	}
+	groupId := d.Get("group_id").(string)
+	if !d.NewValueKnown("group_id") || !d.NewValueKnown("users") {
+		return nil
+	}
+	if d.Id() == "" {
+		if !d.Get("authoritative").(bool) {
+			return nil
+		}
+		members, err := listGroupUserIDs(ctx, meta, groupId)
+		if err != nil {
+			return fmt.Errorf("failed to list users of group (%s): %v", groupId, err)
+		}
+		_, usersToRemove := diffGroupMembers(members, utils.ConvertInterfaceToStringSetNullable(d.Get("users")))
+		return checkMembershipRemovals(d.Get, meta, groupMembershipsDescription(groupId), len(usersToRemove), len(members))
+	}
+	oldUsers, newUsers := d.GetChange("users")
+	removed := oldUsers.(*schema.Set).Difference(newUsers.(*schema.Set))
+	if d.HasChange("group_id") {
+		// the users are removed from the old group
+		oldGroupId, _ := d.GetChange("group_id")
+		groupId, removed = oldGroupId.(string), oldUsers.(*schema.Set)
+	}
+	return checkGroupMembershipRemovals(ctx, d.Get, meta, groupId, utils.ConvertInterfaceArrToStringArr(removed.List()))
+}
+
+func groupMembershipsDescription(groupId string) string {
+	return fmt.Sprintf("users of group %s", groupId)
+}
+
+// checkGroupMembershipRemovals checks removing users from a group against
+// max_membership_removals. The removals are the users that are members and
+// the total is the current members of the group, the base every path of the
+// resource uses. The group is only listed when there is a limit.
+func checkGroupMembershipRemovals(ctx context.Context, get func(string) interface{}, meta interface{}, groupId string, users []string) error {
+	if len(users) == 0 {
+		return nil
+	}
+	if setting, _ := membershipRemovalLimit(get, meta); setting == "" {
+		return nil
+	}
+	members, err := listGroupUserIDs(ctx, meta, groupId)
+	if err != nil {
+		return fmt.Errorf("failed to list users of group (%s): %v", groupId, err)
+	}
+	return checkMembershipRemovals(get, meta, groupMembershipsDescription(groupId), countGroupMembers(members, users), len(members))
+}
+
+// countGroupMembers returns how many of users are in members.
+func countGroupMembers(members, users []string) int {
+	isMember := map[string]bool{}
+	for _, id := range members {
+		isMember[id] = true
+	}
+	count := 0
+	for _, id := range users {
+		if isMember[id] {
+			count++
+		}
+	}
+	return count
+}
+
 func resourceGroupMembershipsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	groupId := d.Get("group_id").(string)
 	users := utils.ConvertInterfaceToStringSetNullable(d.Get("users"))
+	authoritative := d.Get("authoritative").(bool)
 	// if read is being called via import "id" will not be blank and "group_id"
 	// will be blank, so set group_id accordingly
 	if d.Id() != "" && groupId == "" {
@@ -80,19 +165,33 @@ func resourceGroupMembershipsCreate(ctx context.Context, d *schema.ResourceData,
 
 	client := getOktaClientFromMetadata(meta)
 
-	if len(users) == 0 {
+	if len(users) == 0 && !authoritative {
 		d.SetId(groupId)
 		return nil
 	}
-	err := addGroupMembers(ctx, client, groupId, users)
-	if err != nil {
-		return diag.FromErr(err)
+	// Adding a user that is already a member is a no-op, the group is only
+	// listed to find the members an authoritative resource removes.
+	usersToAdd, usersToRemove := users, []string(nil)
+	if authoritative {
+		members, err := listGroupUserIDs(ctx, meta, groupId)
+		if err != nil {
+			return diag.Errorf("failed to list users of group (%s): %v", groupId, err)
+		}
+		usersToAdd, usersToRemove = diffGroupMembers(members, users)
+		if err := checkMembershipRemovals(d.Get, meta, groupMembershipsDescription(groupId), len(usersToRemove), len(members)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	d.SetId(groupId)
+	diags := changeGroupMemberships(ctx, d, meta, groupId, users, usersToAdd, usersToRemove)
+	if diags.HasError() || len(usersToAdd) == 0 {
+		return diags
 	}
 	boc := utils.NewExponentialBackOffWithContext(ctx, 10*time.Second)
 	// During create the Okta service can have eventual consistency issues when
 	// adding users to a group. Use a backoff to wait for at list one user to be
 	// associated with the group.
-	err = backoff.Retry(func() error {
+	err := backoff.Retry(func() error {
 		// TODO, should we wait for all users to be added to the group?
 		ok, err := checkIfGroupHasUsers(ctx, client, groupId, users)
 		if doNotRetry(meta, err) {
@@ -109,7 +208,6 @@ func resourceGroupMembershipsCreate(ctx context.Context, d *schema.ResourceData,
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	d.SetId(groupId)
 	return nil
 }
 
@@ -117,7 +215,7 @@ func resourceGroupMembershipsRead(ctx context.Context, d *schema.ResourceData, m
 	client := getOktaClientFromMetadata(meta)
 	groupId := d.Get("group_id").(string)
 	oldUsers := utils.ConvertInterfaceToStringSetNullable(d.Get("users"))
-	trackAllUsers := d.Get("track_all_users").(bool)
+	trackAllUsers := d.Get("track_all_users").(bool) || d.Get("authoritative").(bool)
 
 	// New behavior, tracking all users.
 	if trackAllUsers {
@@ -134,7 +232,7 @@ func resourceGroupMembershipsRead(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	// Legacy behavior is just to check if any users have left the group.
-	changed, newUserIDs, err := checkIfUsersHaveBeenRemoved(ctx, client, groupId, &oldUsers)
+	changed, newUserIDs, err := checkIfUsersHaveBeenRemovedFromGroup(ctx, client, groupId, oldUsers, groupMembershipParallelism(meta))
 	if err != nil {
 		return diag.Errorf("An error occurred checking user ids for group %q, error: %+v", groupId, err)
 	}
@@ -148,37 +246,117 @@ func resourceGroupMembershipsRead(ctx context.Context, d *schema.ResourceData, m
 func resourceGroupMembershipsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	groupId := d.Get("group_id").(string)
 	users := utils.ConvertInterfaceToStringSetNullable(d.Get("users"))
-	client := getOktaClientFromMetadata(meta)
-	err := removeGroupMembers(ctx, client, groupId, users)
-	if err != nil {
+	if err := checkGroupMembershipRemovals(ctx, d.Get, meta, groupId, users); err != nil {
 		return diag.FromErr(err)
 	}
-	return nil
+	err := removeGroupMembers(ctx, getOktaClientFromMetadata(meta), groupId, users, groupMembershipParallelism(meta))
+	return groupMembershipDiagnostics(err)
 }
 
 func resourceGroupMembershipsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	groupId := d.Get("group_id").(string)
-	client := getOktaClientFromMetadata(meta)
-
 	oldUsers, newUsers := d.GetChange("users")
-
-	oldSet := oldUsers.(*schema.Set)
-	newSet := newUsers.(*schema.Set)
-
-	usersToAdd := utils.ConvertInterfaceArrToStringArr(newSet.Difference(oldSet).List())
-	usersToRemove := utils.ConvertInterfaceArrToStringArr(oldSet.Difference(newSet).List())
-
-	err := addGroupMembers(ctx, client, groupId, usersToAdd)
+	users := utils.ConvertInterfaceToStringSetNullable(newUsers)
+
+	// Only the managed users change: Read drops the users that left the group
+	// from the state, so adding the users that are new to the state and
+	// removing the ones that are gone from it is enough. Removing a user that
+	// isn't a member is a no-op.
+	usersToAdd := utils.ConvertInterfaceArrToStringArr(newUsers.(*schema.Set).Difference(oldUsers.(*schema.Set)).List())
+	usersToRemove := utils.ConvertInterfaceArrToStringArr(oldUsers.(*schema.Set).Difference(newUsers.(*schema.Set)).List())
+	var err error
+	if d.Get("authoritative").(bool) {
+		var members []string
+		members, err = listGroupUserIDs(ctx, meta, groupId)
+		if err != nil {
+			return diag.Errorf("failed to list users of group (%s): %v", groupId, err)
+		}
+		usersToAdd, usersToRemove = diffGroupMembers(members, users)
+		err = checkMembershipRemovals(d.Get, meta, groupMembershipsDescription(groupId), len(usersToRemove), len(members))
+	} else {
+		err = checkGroupMembershipRemovals(ctx, d.Get, meta, groupId, usersToRemove)
+	}
 	if err != nil {
-		diag.FromErr(err)
+		d.Partial(true)
+		return diag.FromErr(err)
 	}
+	return changeGroupMemberships(ctx, d, meta, groupId, users, usersToAdd, usersToRemove)
+}
 
-	err = removeGroupMembers(ctx, client, groupId, usersToRemove)
-	if err != nil {
-		diag.FromErr(err)
+// diffGroupMembers returns the users to add to and the members to remove from
+// a group with members for its users to be exactly users.
+func diffGroupMembers(members, users []string) (usersToAdd, usersToRemove []string) {
+	isMember := map[string]bool{}
+	for _, id := range members {
+		isMember[id] = true
+	}
+	isUser := map[string]bool{}
+	for _, id := range users {
+		isUser[id] = true
+		if !isMember[id] {
+			usersToAdd = append(usersToAdd, id)
+		}
+	}
+	for _, id := range members {
+		if !isUser[id] {
+			usersToRemove = append(usersToRemove, id)
+		}
 	}
+	return usersToAdd, usersToRemove
+}
 
-	return nil
+// changeGroupMemberships adds and removes the users of a group. When some
+// changes fail the users are set to the users the group was meant to have,
+// minus the users that couldn't be added and plus the users that couldn't be
+// removed, so that the next plan retries them.
+func changeGroupMemberships(ctx context.Context, d *schema.ResourceData, meta interface{}, groupId string, users, usersToAdd, usersToRemove []string) diag.Diagnostics {
+	client := getOktaClientFromMetadata(meta)
+	parallelism := groupMembershipParallelism(meta)
+	var diags diag.Diagnostics
+	failed := map[string]bool{}
+	for _, change := range []struct {
+		users []string
+		apply func(context.Context, *sdk.Client, string, []string, int) error
+	}{{usersToAdd, addGroupMembers}, {usersToRemove, removeGroupMembers}} {
+		if len(change.users) == 0 {
+			continue
+		}
+		err := change.apply(ctx, client, groupId, change.users, parallelism)
+		var membershipErr *groupMembershipError
+		if errors.As(err, &membershipErr) {
+			for id := range membershipErr.failures {
+				failed[id] = true
+			}
+		}
+		diags = append(diags, groupMembershipDiagnostics(err)...)
+	}
+	if len(failed) == 0 {
+		return diags
+	}
+	var achieved []string
+	for _, id := range users {
+		if !failed[id] {
+			achieved = append(achieved, id)
+		}
+	}
+	for _, id := range usersToRemove {
+		if failed[id] {
+			achieved = append(achieved, id)
+		}
+	}
+	d.Set("users", utils.ConvertStringSliceToSet(achieved))
+	return diags
+}
+
+func groupMembershipDiagnostics(err error) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+	var membershipErr *groupMembershipError
+	if errors.As(err, &membershipErr) {
+		return membershipErr.diagnostics()
+	}
+	return diag.FromErr(err)
 }
 
 // checkIfUsersHaveChanged If the function returns true then users have been
@@ -300,6 +478,39 @@ func checkIfUsersHaveBeenRemoved(ctx context.Context, client *sdk.Client, groupI
 	return true, &newUsers, nil
 }
 
+// checkIfUsersHaveBeenRemovedFromGroup is checkIfUsersHaveBeenRemoved without
+// paging through a large group for a few users: when checking the groups of
+// each user takes fewer requests than listing the group, that is done instead.
+func checkIfUsersHaveBeenRemovedFromGroup(ctx context.Context, client *sdk.Client, groupId string, users []string, parallelism int) (bool, *[]string, error) {
+	noop := []string{}
+	if len(users) == 0 {
+		return false, &noop, nil
+	}
+	count, ok, err := groupUsersCount(ctx, client, groupId)
+	if err != nil {
+		return false, &noop, fmt.Errorf("unable to get the number of users of group (%s) from API, error: %+v", groupId, err)
+	}
+	pages := (int64(count) + utils.DefaultPaginationLimit - 1) / utils.DefaultPaginationLimit
+	if !ok || pages <= int64(len(users)) {
+		return checkIfUsersHaveBeenRemoved(ctx, client, groupId, &users)
+	}
+	removed, err := groupNonMembers(ctx, client, groupId, users, parallelism)
+	if err != nil {
+		return false, &noop, err
+	}
+	if len(removed) == 0 {
+		return false, &noop, nil
+	}
+	isRemoved := toStrIndexedMap(&removed)
+	newUsers := []string{}
+	for _, userId := range users {
+		if _, found := (*isRemoved)[userId]; !found {
+			newUsers = append(newUsers, userId)
+		}
+	}
+	return true, &newUsers, nil
+}
+
 func checkIfGroupHasUsers(ctx context.Context, client *sdk.Client, groupId string, users []string) (bool, error) {
 	groupUsers, resp, err := client.Group.ListGroupUsers(ctx, groupId, &query.Params{Limit: utils.DefaultPaginationLimit})
 	if err := utils.SuppressErrorOn404(resp, err); err != nil {
@@ -1,11 +1,14 @@
 package idaas_test
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/okta/terraform-provider-okta/okta/acctest"
 	"github.com/okta/terraform-provider-okta/okta/resources"
 )
@@ -308,3 +311,103 @@ resource "okta_user" "test-%03d" {
 }
 `, i, i, i, i)
 }
+
+// TestAccResourceOktaGroupMemberships_authoritative adds a member to the group
+// outside of Terraform and expects an authoritative resource to remove it.
+func TestAccResourceOktaGroupMemberships_authoritative(t *testing.T) {
+	mgr := newFixtureManager("resources", resources.OktaIDaaSGroupMemberships, t.Name())
+	start := mgr.GetFixtures("authoritative.tf", t)
+	authoritative := mgr.GetFixtures("authoritative_enabled.tf", t)
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSGroupMemberships)
+	var groupID, unmanagedUserID string
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             checkUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: start,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "users.#", "2"),
+					resource.TestCheckResourceAttrWith("okta_group.test", "id", func(value string) error {
+						groupID = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("okta_user.test3", "id", func(value string) error {
+						unmanagedUserID = value
+						return nil
+					}),
+				),
+			},
+			{
+				PreConfig: func() {
+					client := iDaaSAPIClientForTestUtil.OktaSDKClientV2()
+					if _, err := client.Group.AddUserToGroup(context.Background(), groupID, unmanagedUserID); err != nil {
+						t.Fatalf("failed to add user %s to group %s: %v", unmanagedUserID, groupID, err)
+					}
+				},
+				Config: authoritative,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "authoritative", "true"),
+					resource.TestCheckResourceAttr(resourceName, "users.#", "2"),
+					checkGroupMembersCount(resourceName, 2),
+				),
+			},
+		},
+	})
+}
+
+// TestAccResourceOktaGroupMemberships_maxMembershipRemovals expects a plan
+// removing more users than max_membership_removals to fail unless the limit
+// is overridden.
+func TestAccResourceOktaGroupMemberships_maxMembershipRemovals(t *testing.T) {
+	mgr := newFixtureManager("resources", resources.OktaIDaaSGroupMemberships, t.Name())
+	start := mgr.GetFixtures("removal_limit.tf", t)
+	exceeded := mgr.GetFixtures("removal_limit_exceeded.tf", t)
+	override := mgr.GetFixtures("removal_limit_override.tf", t)
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSGroupMemberships)
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             checkUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: start,
+				Check:  resource.TestCheckResourceAttr(resourceName, "users.#", "4"),
+			},
+			{
+				Config:      exceeded,
+				ExpectError: regexp.MustCompile(`removes 3 of the 4 users of group`),
+			},
+			{
+				Config: override,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "users.#", "1"),
+					checkGroupMembersCount(resourceName, 1),
+				),
+			},
+		},
+	})
+}
+
+func checkGroupMembersCount(name string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+		client := iDaaSAPIClientForTestUtil.OktaSDKClientV2()
+		users, _, err := client.Group.ListGroupUsers(context.Background(), rs.Primary.ID, nil)
+		if err != nil {
+			return err
+		}
+		if len(users) != count {
+			return fmt.Errorf("group %s has %d members, expected %d", rs.Primary.ID, len(users), count)
+		}
+		return nil
+	}
+}
@@ -36,8 +36,13 @@ func resourceGroupRule() *schema.Resource {
 This resource allows you to create and configure an Okta Group Rule.
 -> If the Okta API marks the 'status' of the rule as 'INVALID' the Okta
 Terraform Provider will act in a force/replace manner and call the API to delete
-the underlying rule resource and create a new rule resource.`,
-		Schema: map[string]*schema.Schema{
+the underlying rule resource and create a new rule resource.
+-> With 'remove_assigned_users' set, deleting or replacing the rule counts every
+member of its groups against 'max_membership_removals', as Okta doesn't tell
+which members the rule assigned. For the same reason, a percentage
+'max_membership_removals' like '10%' doesn't apply to group rules, only a number
+of memberships like '100' does.`,
+		Schema: utils.BuildSchema(map[string]*schema.Schema{
 			"name": {
 				Type:             schema.TypeString,
 				Required:         true,
@@ -59,9 +64,10 @@ the underlying rule resource and create a new rule resource.`,
 				Description: "The expression type to use to invoke the rule. The default is `urn:okta:expression:1.0`.",
 			},
 			"expression_value": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The expression value.",
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The expression value. Its syntax, the functions it calls and the user attributes it reads are checked at plan time. An expression reading a user attribute added by an `okta_user_schema_property` of the same configuration must depend on it.",
+				ValidateDiagFunc: validateOktaExpression,
 			},
 			"status": statusSchema,
 			"remove_assigned_users": {
@@ -75,11 +81,59 @@ the underlying rule resource and create a new rule resource.`,
 				Description: "The list of user IDs that would be excluded when rules are processed",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
-		},
-		CustomizeDiff: customdiff.ForceNewIf("status", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
-			return StatusIsInvalidDiffFn(d.Get("status").(string))
-		}),
+		}, membershipRemovalsSchema),
+		CustomizeDiff: customdiff.All(
+			customdiff.ForceNewIf("status", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+				return StatusIsInvalidDiffFn(d.Get("status").(string))
+			}),
+			oktaExpressionCustomizeDiff("expression_value", nil),
+			groupRuleRemovalsCustomizeDiff,
+		),
+	}
+}
+
+// groupRuleRemovalsCustomizeDiff fails the plan when it replaces a rule with
+// remove_assigned_users set whose groups have more members than
+// max_membership_removals allows.
+func groupRuleRemovalsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	removeUsers, _ := d.GetChange("remove_assigned_users")
+	if d.Id() == "" || !removeUsers.(bool) {
+		return nil
+	}
+	if !d.HasChange("group_assignments") && !StatusIsInvalidDiffFn(d.Get("status").(string)) {
+		return nil
+	}
+	groups, _ := d.GetChange("group_assignments")
+	return checkGroupRuleRemovals(ctx, d.Get, meta, utils.ConvertInterfaceToStringSet(groups))
+}
+
+// checkGroupRuleRemovals checks the users that deleting a rule assigning users
+// to groups may remove against max_membership_removals. Okta doesn't tell
+// which members the rule assigned, so every member of the groups counts, and
+// a percentage limit doesn't apply: the share of the members the rule
+// assigned is unknown, and counting all of them would always be 100%.
+func checkGroupRuleRemovals(ctx context.Context, get func(string) interface{}, meta interface{}, groups []string) error {
+	setting, source := membershipRemovalLimit(get, meta)
+	if setting == "" {
+		return nil
+	}
+	limit, err := utils.ParseRemovalLimit(setting)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", source, err)
+	}
+	if limit.Percent {
+		return nil
 	}
+	members := 0
+	for _, group := range groups {
+		users, err := listGroupUserIDs(ctx, meta, group)
+		if err != nil {
+			return fmt.Errorf("failed to list users of group (%s): %v", group, err)
+		}
+		members += len(users)
+	}
+	what := fmt.Sprintf("memberships of the groups the rule assigns (%s), any of which the rule may have assigned", strings.Join(groups, ", "))
+	return checkMembershipRemovals(get, meta, what, members, members)
 }
 
 func StatusIsInvalidDiffFn(status string) bool {
@@ -224,6 +278,12 @@ func hasGroupRuleChange(d *schema.ResourceData) bool {
 }
 
 func resourceGroupRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("remove_assigned_users").(bool) {
+		groups := utils.ConvertInterfaceToStringSet(d.Get("group_assignments"))
+		if err := checkGroupRuleRemovals(ctx, d.Get, meta, groups); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	client := getOktaClientFromMetadata(meta)
 	// If the rule is active, attempt to deactivate it first.
 	if d.Get("status").(string) == StatusActive {
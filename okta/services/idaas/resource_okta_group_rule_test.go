@@ -223,6 +223,43 @@ func TestAccResourceOktaGroupRule_nameLengthVerification_Issue2396(t *testing.T)
 	})
 }
 
+func TestAccResourceOktaGroupRule_invalidExpression(t *testing.T) {
+	mgr := newFixtureManager("resources", "okta_group_rule", t.Name())
+	invalidConfig := mgr.GetFixtures("basic_invalid_expression.tf", t)
+	unknownFunctionConfig := mgr.GetFixtures("basic_unknown_function.tf", t)
+	unknownAttributeConfig := mgr.GetFixtures("basic_unknown_attribute.tf", t)
+	managedAttributeConfig := mgr.GetFixtures("basic_managed_attribute.tf", t)
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		Steps: []resource.TestStep{
+			{
+				Config:      invalidConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`invalid expression at position 39`),
+			},
+			{
+				Config:      unknownFunctionConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`unknown function String.beginsWith`),
+			},
+			{
+				Config:      unknownAttributeConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`unknown user attribute "departmnet"`),
+			},
+			{
+				// the attribute is added by a user schema property the rule
+				// depends on
+				Config:             managedAttributeConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
 func TestAccResourceOktaGroupRule_423ResponseCapture(t *testing.T) {
 	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSGroupRule)
 	mgr := newFixtureManager("resources", "okta_group_rule", t.Name())
@@ -20,6 +20,7 @@ var (
 	_ resource.Resource                = &hookKey{}
 	_ resource.ResourceWithConfigure   = &hookKey{}
 	_ resource.ResourceWithImportState = &hookKey{}
+	_ resource.ResourceWithIdentity    = &hookKey{}
 )
 
 type hookKey struct {
@@ -62,8 +63,12 @@ func (r *hookKey) Configure(_ context.Context, req resource.ConfigureRequest, re
 	r.Config = resourceConfiguration(req, resp)
 }
 
+func (r *hookKey) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *hookKey) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, tfpath.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, tfpath.Root("id"), tfpath.Root("id"), req, resp)
 }
 
 func (r *hookKey) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -123,6 +128,7 @@ func (r *hookKey) Create(ctx context.Context, req resource.CreateRequest, resp *
 
 	applyHookKeyToState(&data, hookKey)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save data into Terraform state
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: data.Id})...)
 }
 
 func (r *hookKey) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -140,6 +146,7 @@ func (r *hookKey) Read(ctx context.Context, req resource.ReadRequest, resp *reso
 	}
 	applyHookKeyToState(&data, hookKey)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: data.Id})...)
 }
 
 func (r *hookKey) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -161,6 +168,7 @@ func (r *hookKey) Update(ctx context.Context, req resource.UpdateRequest, resp *
 
 	applyHookKeyToState(&data, hookKey)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...) // Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: data.Id})...)
 }
 
 func (r *hookKey) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
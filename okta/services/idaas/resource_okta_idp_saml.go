@@ -3,6 +3,7 @@ package idaas
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,11 +16,12 @@ func resourceIdpSaml() *schema.Resource {
 		CreateContext: resourceIdpSamlCreate,
 		ReadContext:   resourceIdpSamlRead,
 		UpdateContext: resourceIdpSamlUpdate,
-		DeleteContext: resourceIdpDelete,
+		DeleteContext: resourceIdpSamlDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
-		Description: "Creates a SAML Identity Provider. This resource allows you to create and configure a SAML Identity Provider.",
+		CustomizeDiff: idpSamlMetadataCustomizeDiff,
+		Description:   "Creates a SAML Identity Provider. This resource allows you to create and configure a SAML Identity Provider.",
 		Schema: buildIdpSchema(map[string]*schema.Schema{
 			"type": {
 				Type:     schema.TypeString,
@@ -35,10 +37,16 @@ func resourceIdpSaml() *schema.Resource {
 				Default:     "INSTANCE",
 				Description: "The type of ACS. It can be `INSTANCE` or `ORG`. Default: `INSTANCE`",
 			},
+			"idp_metadata_xml": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "SAML metadata of the identity provider. The `sso_url`, `issuer`, `subject_format` and `kid` arguments that aren't set are filled from it, the signing certificate is added to the IdP keys unless `kid` is set. Arguments set to other values than the metadata are reported as conflicts during the plan.",
+			},
 			"sso_url": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "URL of binding-specific endpoint to send an AuthnRequest message to IdP.",
+				Optional:    true,
+				Computed:    true, // Required for SetNew()
+				Description: "URL of binding-specific endpoint to send an AuthnRequest message to IdP. Required unless `idp_metadata_xml` is set.",
 			},
 			"sso_binding": {
 				Type:        schema.TypeString,
@@ -66,6 +74,7 @@ func resourceIdpSaml() *schema.Resource {
 				Type:        schema.TypeSet,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Optional:    true,
+				Computed:    true, // Required for SetNew()
 				Description: "The name format.",
 			},
 			"subject_filter": {
@@ -75,8 +84,9 @@ func resourceIdpSaml() *schema.Resource {
 			},
 			"issuer": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "URI that identifies the issuer.",
+				Optional:    true,
+				Computed:    true, // Required for SetNew()
+				Description: "URI that identifies the issuer. Required unless `idp_metadata_xml` is set.",
 			},
 			"issuer_mode": issuerMode,
 			"audience": {
@@ -85,8 +95,14 @@ func resourceIdpSaml() *schema.Resource {
 			},
 			"kid": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The ID of the signing key.",
+				Optional:    true,
+				Computed:    true, // Required for SetNew()
+				Description: "The ID of the signing key. Required unless `idp_metadata_xml` is set.",
+			},
+			"metadata_kid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the IdP key this resource added for the signing certificate of `idp_metadata_xml`. The key is deleted when the certificate changes, when `kid` is set or `idp_metadata_xml` removed, and along with the identity provider. Empty when the key already existed.",
 			},
 			"max_clock_skew": {
 				Type:        schema.TypeInt,
@@ -132,13 +148,21 @@ func resourceIdpSaml() *schema.Resource {
 }
 
 func resourceIdpSamlCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	err := setIdpSamlMetadataKid(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	idp, err := buildIdPSaml(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	respIdp, _, err := getOktaClientFromMetadata(meta).IdentityProvider.CreateIdentityProvider(ctx, idp)
 	if err != nil {
-		return diag.Errorf("failed to create SAML identity provider: %v", err)
+		diags := diag.Errorf("failed to create SAML identity provider: %v", err)
+		if kid := d.Get("metadata_kid").(string); kid != "" {
+			diags = append(diags, deleteIdpSamlMetadataKey(ctx, meta, kid)...)
+		}
+		return diags
 	}
 	d.SetId(respIdp.Id)
 	err = setIdpStatus(ctx, d, getOktaClientFromMetadata(meta), idp.Status)
@@ -243,6 +267,11 @@ func resourceIdpSamlRead(ctx context.Context, d *schema.ResourceData, meta inter
 }
 
 func resourceIdpSamlUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	oldMetadataKid, _ := d.GetChange("metadata_kid")
+	err := setIdpSamlMetadataKid(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	idp, err := buildIdPSaml(d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -255,7 +284,38 @@ func resourceIdpSamlUpdate(ctx context.Context, d *schema.ResourceData, meta int
 	if err != nil {
 		return diag.Errorf("failed to update SAML identity provider's status: %v", err)
 	}
-	return resourceIdpSamlRead(ctx, d, meta)
+	// the key added for the previous signing certificate isn't used anymore
+	var diags diag.Diagnostics
+	if kid := oldMetadataKid.(string); kid != "" && kid != d.Get("kid").(string) && kid != d.Get("metadata_kid").(string) {
+		diags = deleteIdpSamlMetadataKey(ctx, meta, kid)
+	}
+	return append(diags, resourceIdpSamlRead(ctx, d, meta)...)
+}
+
+func resourceIdpSamlDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := resourceIdpDelete(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+	if kid := d.Get("metadata_kid").(string); kid != "" {
+		diags = append(diags, deleteIdpSamlMetadataKey(ctx, meta, kid)...)
+	}
+	return diags
+}
+
+// deleteIdpSamlMetadataKey deletes the IdP key added for the signing
+// certificate of the metadata. A key that can't be deleted, e.g. because
+// another identity provider uses it now, is left in place with a warning.
+func deleteIdpSamlMetadataKey(ctx context.Context, meta interface{}, kid string) diag.Diagnostics {
+	resp, err := getOktaClientFromMetadata(meta).IdentityProvider.DeleteIdentityProviderKey(ctx, kid)
+	if err := utils.SuppressErrorOn404(resp, err); err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("failed to delete identity provider signing key %s added for idp_metadata_xml", kid),
+			Detail:   err.Error(),
+		}}
+	}
+	return nil
 }
 
 func buildIdPSaml(d *schema.ResourceData) (sdk.IdentityProvider, error) {
@@ -332,3 +392,122 @@ func syncIdpSamlAlgo(d *schema.ResourceData, alg *sdk.ProtocolAlgorithms) {
 		}
 	}
 }
+
+// idpSamlMetadataCustomizeDiff fills the arguments described by the SAML
+// metadata of the identity provider and reports the configured ones differing
+// from it. Without metadata sso_url, issuer and kid are required. The key of a
+// new signing certificate is known after apply, the key added for the
+// previous one goes away.
+func idpSamlMetadataCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	metadata := config.GetAttr("idp_metadata_xml")
+	if !metadata.IsKnown() {
+		return setIdpSamlMetadataKidComputed(d)
+	}
+	if metadata.IsNull() {
+		for _, field := range []string{"sso_url", "issuer", "kid"} {
+			if config.GetAttr(field).IsNull() {
+				return fmt.Errorf("%q is required unless idp_metadata_xml is set", field)
+			}
+		}
+		if err := clearMetadataKid(d); err != nil {
+			return err
+		}
+		return clearUnconfigured(d, []string{"subject_format"})
+	}
+	binding := config.GetAttr("sso_binding")
+	if !binding.IsWhollyKnown() {
+		return setIdpSamlMetadataKidComputed(d)
+	}
+	values, cert, err := idpSamlMetadataValues(metadata.AsString(), d.Get("sso_binding").(string))
+	if err != nil {
+		return err
+	}
+	if err := setFromSAMLMetadata(d, "idp_metadata_xml", samlIdpMetadataFields, values); err != nil {
+		return err
+	}
+	if !config.GetAttr("kid").IsNull() {
+		return clearMetadataKid(d)
+	}
+	// the key stays the same as long as the signing certificate does
+	oldMetadata, _ := d.GetChange("idp_metadata_xml")
+	if d.Id() != "" && oldMetadata.(string) != "" {
+		if _, oldCert, err := idpSamlMetadataValues(oldMetadata.(string), d.Get("sso_binding").(string)); err == nil && samlMetadataValueEqual(oldCert, cert) {
+			return nil
+		}
+	}
+	if err := d.SetNewComputed("kid"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("metadata_kid")
+}
+
+// setIdpSamlMetadataKidComputed marks the arguments filled from the metadata
+// and the key added for it as known after apply.
+func setIdpSamlMetadataKidComputed(d *schema.ResourceDiff) error {
+	if err := setUnconfiguredComputed(d, append([]string{"kid"}, samlIdpMetadataFields...)); err != nil {
+		return err
+	}
+	if d.GetRawConfig().GetAttr("kid").IsNull() {
+		return d.SetNewComputed("metadata_kid")
+	}
+	return clearMetadataKid(d)
+}
+
+// clearMetadataKid plans the removal of the key added for the metadata when
+// the signing key isn't taken from it anymore.
+func clearMetadataKid(d *schema.ResourceDiff) error {
+	if d.Get("metadata_kid").(string) == "" {
+		return nil
+	}
+	return d.SetNew("metadata_kid", "")
+}
+
+// idpSamlMetadataValues returns the values of the arguments described by the
+// SAML metadata raw, and its signing certificate.
+func idpSamlMetadataValues(raw, binding string) (map[string]interface{}, string, error) {
+	entity, err := parseSAMLMetadata(raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid idp_metadata_xml: %w", err)
+	}
+	values, cert, err := samlIdpMetadataValues(entity, binding)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid idp_metadata_xml: %w", err)
+	}
+	return values, cert, nil
+}
+
+// setIdpSamlMetadataKid sets kid to the IdP key of the signing certificate of
+// the metadata when kid isn't configured, adding the key if there is none.
+// metadata_kid is set to the key it adds, the keys that already existed are
+// left out so that they aren't deleted with the identity provider.
+func setIdpSamlMetadataKid(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	metadata := d.Get("idp_metadata_xml").(string)
+	if metadata == "" || !d.GetRawConfig().GetAttr("kid").IsNull() {
+		return d.Set("metadata_kid", "")
+	}
+	if d.Get("kid").(string) != "" {
+		return nil
+	}
+	_, cert, err := idpSamlMetadataValues(metadata, d.Get("sso_binding").(string))
+	if err != nil {
+		return err
+	}
+	client := getOktaClientFromMetadata(meta)
+	keys, err := listIdpKeys(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to list identity provider keys: %v", err)
+	}
+	for _, key := range keys {
+		if len(key.X5c) > 0 && samlMetadataValueEqual(key.X5c[0], cert) {
+			_ = d.Set("metadata_kid", "")
+			return d.Set("kid", key.Kid)
+		}
+	}
+	key, _, err := client.IdentityProvider.CreateIdentityProviderKey(ctx, sdk.JsonWebKey{X5c: []string{cert}})
+	if err != nil {
+		return fmt.Errorf("failed to create identity provider signing key: %v", err)
+	}
+	_ = d.Set("metadata_kid", key.Kid)
+	return d.Set("kid", key.Kid)
+}
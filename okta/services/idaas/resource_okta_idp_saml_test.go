@@ -1,8 +1,10 @@
 package idaas_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/okta/terraform-provider-okta/okta/acctest"
 	"github.com/okta/terraform-provider-okta/okta/resources"
+	"github.com/okta/terraform-provider-okta/okta/utils"
 )
 
 func TestAccResourceOktaIdpSaml_crud(t *testing.T) {
@@ -184,3 +187,53 @@ resource "okta_idp_saml" "test" {
 		},
 	})
 }
+
+func TestAccResourceOktaIdpSaml_metadata(t *testing.T) {
+	mgr := newFixtureManager("resources", resources.OktaIDaaSIdpSaml, t.Name())
+	config := mgr.GetFixtures("idp_metadata.tf", t)
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSIdpSaml)
+	// the key added for the signing certificate of the metadata is deleted
+	// along with the identity provider
+	var metadataKid string
+	checkKeyDestroy := func(*terraform.State) error {
+		if metadataKid == "" || os.Getenv("OKTA_VCR_TF_ACC") == "play" {
+			return nil
+		}
+		_, resp, err := iDaaSAPIClientForTestUtil.OktaSDKClientV2().IdentityProvider.GetIdentityProviderKey(context.Background(), metadataKid)
+		exists, err := utils.DoesResourceExist(resp, err)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("identity provider key %s added for idp_metadata_xml still exists", metadataKid)
+		}
+		return nil
+	}
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			checkResourceDestroy(resources.OktaIDaaSIdpSaml, createDoesIdpExist),
+			checkKeyDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", acctest.BuildResourceName(mgr.Seed)),
+					resource.TestCheckResourceAttrPair(resourceName, "issuer", "okta_app_saml.test", "entity_url"),
+					resource.TestCheckResourceAttrPair(resourceName, "sso_url", "okta_app_saml.test", "http_post_binding"),
+					resource.TestCheckResourceAttrSet(resourceName, "subject_format.#"),
+					resource.TestCheckResourceAttrSet(resourceName, "kid"),
+					resource.TestCheckResourceAttrPair(resourceName, "metadata_kid", resourceName, "kid"),
+					resource.TestCheckResourceAttrWith(resourceName, "metadata_kid", func(value string) error {
+						metadataKid = value
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
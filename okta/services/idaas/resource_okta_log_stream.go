@@ -24,6 +24,7 @@ var (
 	_ resource.Resource                = &logStreamResource{}
 	_ resource.ResourceWithConfigure   = &logStreamResource{}
 	_ resource.ResourceWithImportState = &logStreamResource{}
+	_ resource.ResourceWithIdentity    = &logStreamResource{}
 )
 
 const (
@@ -254,6 +255,7 @@ func (r *logStreamResource) Create(ctx context.Context, req resource.CreateReque
 	// need to set the "new" state of the log stream model, TF runtime does
 	// change detection there
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	// don't need to check for error, we are returning already
 }
 
@@ -282,6 +284,7 @@ func (r *logStreamResource) Read(ctx context.Context, req resource.ReadRequest,
 	applyLogStreamToState(ctx, logStream, &data)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: data.ID})...)
 }
 
 func (r *logStreamResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -352,6 +355,7 @@ func (r *logStreamResource) Update(ctx context.Context, req resource.UpdateReque
 	// need to set the "new" state of the log stream model, TF runtime does
 	// change detection there
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{ID: state.ID})...)
 	// don't need to check for error, we are returning already
 }
 
@@ -380,8 +384,12 @@ func (r *logStreamResource) Delete(ctx context.Context, req resource.DeleteReque
 	}
 }
 
+func (r *logStreamResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *logStreamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
 
 func applyLogStreamToState(ctx context.Context, ls *providerLogStream, m *logStreamModel) {
@@ -8,16 +8,25 @@ import (
 
 	v6okta "github.com/okta/okta-sdk-golang/v6/okta"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/okta/terraform-provider-okta/okta/internal/iprange"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 )
 
 const defaultEnhancedDynamicZone = "DefaultEnhancedDynamicZone"
 
+// Maximum number of gateways, and of proxies, of IP zones by kind of zone.
+const (
+	networkZoneMaxEntries          = 150
+	networkZoneBlocklistMaxEntries = 1000
+	networkZoneSystemMaxEntries    = 5000
+)
+
 func resourceNetworkZone() *schema.Resource {
-	return &schema.Resource{
+	return withIDIdentity(&schema.Resource{
 		CreateContext: resourceNetworkZoneCreate,
 		ReadContext:   resourceNetworkZoneRead,
 		UpdateContext: resourceNetworkZoneUpdate,
@@ -25,7 +34,8 @@ func resourceNetworkZone() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
-		Description: "Creates an Okta Network Zone. This resource allows you to create and configure an Okta Network Zone.",
+		Description:   "Creates an Okta Network Zone. This resource allows you to create and configure an Okta Network Zone.",
+		CustomizeDiff: networkZoneEntryLimitsCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"dynamic_locations": {
 				Type:        schema.TypeSet,
@@ -48,8 +58,9 @@ func resourceNetworkZone() *schema.Resource {
 			"gateways": {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "Array of values in CIDR/range form depending on the way it's been declared (i.e. CIDR will contain /suffix). Please check API docs for examples. Use with type `IP`",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Array of values in CIDR/range form depending on the way it's been declared (i.e. CIDR will contain /suffix). Please check API docs for examples. Use with type `IP`. IPv4 and IPv6 addresses are supported, equivalent notations of the same addresses such as `10.0.0.0/8` and `10.0.0.0-10.255.255.255` don't cause a diff",
+				Elem:        networkZoneAddressSchema(),
+				Set:         hashNetworkZoneAddress,
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -59,8 +70,9 @@ func resourceNetworkZone() *schema.Resource {
 			"proxies": {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "Array of values in CIDR/range form depending on the way it's been declared (i.e. CIDR will contain /suffix). Please check API docs for examples. Can not be set if `usage` is set to `BLOCKLIST`. Use with type `IP`",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Array of values in CIDR/range form depending on the way it's been declared (i.e. CIDR will contain /suffix). Please check API docs for examples. Can not be set if `usage` is set to `BLOCKLIST`. Use with type `IP`. IPv4 and IPv6 addresses are supported, equivalent notations of the same addresses such as `10.0.0.0/8` and `10.0.0.0-10.255.255.255` don't cause a diff",
+				Elem:        networkZoneAddressSchema(),
+				Set:         hashNetworkZoneAddress,
 			},
 			"type": {
 				Type:        schema.TypeString,
@@ -108,7 +120,7 @@ func resourceNetworkZone() *schema.Resource {
 				Description: "Indicates a system Network Zone",
 			},
 		},
-	}
+	})
 }
 
 func resourceNetworkZoneCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -323,6 +335,63 @@ func buildAddressObjList(values *schema.Set) []v6okta.NetworkZoneAddress {
 	return addressObjList
 }
 
+// networkZoneAddressSchema is the schema of the gateways and proxies of IP
+// zones: IPv4 or IPv6 addresses, CIDRs and ranges. The sets are hashed with
+// hashNetworkZoneAddress so they are compared by the addresses they cover
+// rather than by their notation.
+func networkZoneAddressSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		ValidateDiagFunc: validateNetworkZoneAddress,
+	}
+}
+
+// validateNetworkZoneAddress warns about addresses that aren't an IP address,
+// CIDR or range, they are left for the Okta API to accept or reject as they
+// were before addresses were checked.
+func validateNetworkZoneAddress(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %s to be string", k)
+	}
+	if _, err := iprange.Parse(v); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Warning,
+			Summary:       "Unrecognized network zone address",
+			Detail:        fmt.Sprintf("%s, it is sent to Okta as is and won't be compared with equivalent notations", err),
+			AttributePath: k,
+		}}
+	}
+	return nil
+}
+
+// hashNetworkZoneAddress hashes the canonical form of an address so that
+// equivalent notations are the same element of the set.
+func hashNetworkZoneAddress(v interface{}) int {
+	return schema.HashString(iprange.Canonical(v.(string)))
+}
+
+// networkZoneEntryLimitsCustomizeDiff fails the plan if IP zones have more
+// gateways or proxies than Okta allows.
+func networkZoneEntryLimitsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("type").(string) != "IP" {
+		return nil
+	}
+	limit, kind := networkZoneMaxEntries, "IP zones"
+	switch {
+	case d.Get("system").(bool):
+		limit, kind = networkZoneSystemMaxEntries, "system IP zones"
+	case d.Get("usage").(string) == "BLOCKLIST":
+		limit, kind = networkZoneBlocklistMaxEntries, "IP blocklist zones"
+	}
+	for _, field := range []string{"gateways", "proxies"} {
+		if n := d.Get(field).(*schema.Set).Len(); n > limit {
+			return fmt.Errorf("%s has %d entries, %s can have at most %d", field, n, kind, limit)
+		}
+	}
+	return nil
+}
+
 func buildLocationList(values *schema.Set) []v6okta.NetworkZoneLocation {
 	var locationsList []v6okta.NetworkZoneLocation
 	for _, value := range values.List() {
@@ -340,15 +409,28 @@ func buildLocationList(values *schema.Set) []v6okta.NetworkZoneLocation {
 	return locationsList
 }
 
-func flattenAddresses(gateways []v6okta.NetworkZoneAddress) interface{} {
+// flattenAddresses returns the addresses of field as set, keeping the notation
+// field already has for the addresses that are equivalent to one of the API,
+// e.g. 10.0.0.0/8 when the API returns 10.0.0.0-10.255.255.255.
+func flattenAddresses(d *schema.ResourceData, field string, gateways []v6okta.NetworkZoneAddress) interface{} {
 	if len(gateways) == 0 {
 		return nil
 	}
+	notations := map[string]string{}
+	if current, ok := d.Get(field).(*schema.Set); ok {
+		for _, v := range current.List() {
+			notations[iprange.Canonical(v.(string))] = v.(string)
+		}
+	}
 	arr := make([]interface{}, len(gateways))
 	for i := range gateways {
-		arr[i] = gateways[i].GetValue()
+		value := gateways[i].GetValue()
+		if notation, ok := notations[iprange.Canonical(value)]; ok {
+			value = notation
+		}
+		arr[i] = value
 	}
-	return schema.NewSet(schema.HashString, arr)
+	return schema.NewSet(hashNetworkZoneAddress, arr)
 }
 
 func flattenDynamicLocations(locations []v6okta.NetworkZoneLocation) interface{} {
@@ -460,8 +542,8 @@ func mapNetworkZoneToState(d *schema.ResourceData, data *v6okta.ListNetworkZones
 		_ = d.Set("usage", v.GetUsage())
 		_ = d.Set("system", v.GetSystem())
 		err = utils.SetNonPrimitives(d, map[string]interface{}{
-			"gateways": flattenAddresses(v.GetGateways()),
-			"proxies":  flattenAddresses(v.GetProxies()),
+			"gateways": flattenAddresses(d, "gateways", v.GetGateways()),
+			"proxies":  flattenAddresses(d, "proxies", v.GetProxies()),
 		})
 	}
 	return err
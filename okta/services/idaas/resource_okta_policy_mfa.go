@@ -15,7 +15,7 @@ import (
 
 // resourcePolicyMfa requires Org Feature Flag OKTA_MFA_POLICY
 func resourcePolicyMfa() *schema.Resource {
-	return &schema.Resource{
+	return withIDIdentity(&schema.Resource{
 		CreateContext: resourcePolicyMfaCreate,
 		ReadContext:   resourcePolicyMfaRead,
 		UpdateContext: resourcePolicyMfaUpdate,
@@ -24,7 +24,7 @@ func resourcePolicyMfa() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: buildMfaPolicySchema(buildFactorSchemaProviders()),
-	}
+	})
 }
 
 func resourcePolicyMfaCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
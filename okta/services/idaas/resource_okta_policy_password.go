@@ -10,7 +10,7 @@ import (
 )
 
 func resourcePolicyPassword() *schema.Resource {
-	return &schema.Resource{
+	return withIDIdentity(&schema.Resource{
 		CreateContext: resourcePolicyPasswordCreate,
 		ReadContext:   resourcePolicyPasswordRead,
 		UpdateContext: resourcePolicyPasswordUpdate,
@@ -167,7 +167,7 @@ func resourcePolicyPassword() *schema.Resource {
 				Default:     false,
 			},
 		}),
-	}
+	})
 }
 
 func resourcePolicyPasswordCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
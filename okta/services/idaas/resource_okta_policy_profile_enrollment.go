@@ -5,16 +5,18 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/resources"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
 
 func resourcePolicyProfileEnrollment() *schema.Resource {
-	return &schema.Resource{
+	return withIDIdentity(&schema.Resource{
 		CreateContext: resourcePolicyProfileEnrollmentCreate,
 		ReadContext:   resourcePolicyProfileEnrollmentRead,
 		UpdateContext: resourcePolicyProfileEnrollmentUpdate,
 		DeleteContext: resourcePolicyProfileEnrollmentDelete,
+		CustomizeDiff: requireCapabilities(resources.OktaIDaaSPolicyProfileEnrollment, config.CapabilityIdentityEngine),
 		Importer:      &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext},
 		Description: `Creates a Profile Enrollment Policy
 		
@@ -33,7 +35,7 @@ This resource allows you to create and configure a Profile Enrollment Policy.`,
 				Default:     StatusActive,
 			},
 		},
-	}
+	})
 }
 
 func resourcePolicyProfileEnrollmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/resources"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 	"github.com/okta/terraform-provider-okta/sdk"
@@ -19,6 +20,7 @@ func resourcePolicyProfileEnrollmentApps() *schema.Resource {
 		ReadContext:   resourcePolicyProfileEnrollmentAppsRead,
 		UpdateContext: resourcePolicyProfileEnrollmentAppsUpdate,
 		DeleteContext: resourcePolicyProfileEnrollmentAppsDelete,
+		CustomizeDiff: requireCapabilities(resources.OktaIDaaSPolicyProfileEnrollmentApps, config.CapabilityIdentityEngine),
 		Importer:      &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext},
 		Description: `Manages Profile Enrollment Policy Apps
 ~> **WARNING:** This feature is only available as a part of the Identity Engine. [Contact support](mailto:dev-inquiries@okta.com) for further information.
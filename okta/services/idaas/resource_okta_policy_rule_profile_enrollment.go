@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/config"
 	"github.com/okta/terraform-provider-okta/okta/resources"
 	"github.com/okta/terraform-provider-okta/okta/utils"
 	"github.com/okta/terraform-provider-okta/sdk"
@@ -17,6 +18,7 @@ func resourcePolicyProfileEnrollmentRule() *schema.Resource {
 		ReadContext:   resourcePolicyProfileEnrollmentRuleRead,
 		UpdateContext: resourcePolicyProfileEnrollmentRuleUpdate,
 		DeleteContext: resourcePolicyProfileEnrollmentRuleDelete,
+		CustomizeDiff: requireCapabilities(resources.OktaIDaaSPolicyRuleProfileEnrollment, config.CapabilityIdentityEngine),
 		Importer:      createPolicyRuleImporter(),
 		Description: `Creates a Profile Enrollment Policy Rule.
 		
@@ -10,7 +10,7 @@ import (
 )
 
 func resourcePolicySignOn() *schema.Resource {
-	return &schema.Resource{
+	return withIDIdentity(&schema.Resource{
 		CreateContext: resourcePolicySignOnCreate,
 		ReadContext:   resourcePolicySignOnRead,
 		UpdateContext: resourcePolicySignOnUpdate,
@@ -20,7 +20,7 @@ func resourcePolicySignOn() *schema.Resource {
 		},
 		Description: "Creates a Sign On Policy. This resource allows you to create and configure a Sign On Policy.",
 		Schema:      basePolicySchema,
-	}
+	})
 }
 
 func resourcePolicySignOnCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -0,0 +1,848 @@
+package idaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// policyWithRulesRuleTypes are the types of the rules of the policy types
+// okta_policy_with_rules supports.
+var policyWithRulesRuleTypes = map[string]string{
+	sdk.SignOnPolicyType:   sdk.SignOnPolicyRuleType,
+	sdk.PasswordPolicyType: "PASSWORD",
+	sdk.MfaPolicyType:      "MFA_ENROLL",
+	// a profile enrollment policy has exactly one rule, the one Okta creates
+	// with the policy, which is updated in place
+	sdk.ProfileEnrollmentPolicyType: sdk.ProfileEnrollmentPolicyType,
+}
+
+var (
+	_ resource.Resource                   = &policyWithRulesResource{}
+	_ resource.ResourceWithConfigure      = &policyWithRulesResource{}
+	_ resource.ResourceWithImportState    = &policyWithRulesResource{}
+	_ resource.ResourceWithValidateConfig = &policyWithRulesResource{}
+	_ resource.ResourceWithModifyPlan     = &policyWithRulesResource{}
+)
+
+func newPolicyWithRulesResource() resource.Resource {
+	return &policyWithRulesResource{}
+}
+
+type policyWithRulesResource struct {
+	*config.Config
+}
+
+type policyWithRulesModel struct {
+	ID             types.String          `tfsdk:"id"`
+	Type           types.String          `tfsdk:"type"`
+	Name           types.String          `tfsdk:"name"`
+	Description    types.String          `tfsdk:"description"`
+	Status         types.String          `tfsdk:"status"`
+	Priority       types.Int64           `tfsdk:"priority"`
+	GroupsIncluded types.Set             `tfsdk:"groups_included"`
+	Settings       types.String          `tfsdk:"settings"`
+	Rules          []policyWithRulesRule `tfsdk:"rule"`
+}
+
+type policyWithRulesRule struct {
+	ID                types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	Status            types.String `tfsdk:"status"`
+	Priority          types.Int64  `tfsdk:"priority"`
+	UsersExcluded     types.Set    `tfsdk:"users_excluded"`
+	NetworkConnection types.String `tfsdk:"network_connection"`
+	NetworkIncludes   types.List   `tfsdk:"network_includes"`
+	NetworkExcludes   types.List   `tfsdk:"network_excludes"`
+	Actions           types.String `tfsdk:"actions"`
+}
+
+func (r *policyWithRulesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy_with_rules"
+}
+
+func (r *policyWithRulesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.Config = resourceConfiguration(req, resp)
+}
+
+func (r *policyWithRulesResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a sign-on, password, MFA enrollment or profile enrollment policy together with all of its rules. " +
+			"The rules are ordered as they are listed: the first rule gets priority 1, the second priority 2 and so on. " +
+			"Rules of the policy that aren't listed are deleted, except for system rules. " +
+			"A profile enrollment policy has exactly one rule, the one Okta creates with the policy, which is updated in place. " +
+			"Priorities are reconciled in a single pass on each apply, so rules don't get shuffled by parallel applies of separate rule resources.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the policy.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "Type of the policy: `OKTA_SIGN_ON`, `PASSWORD`, `MFA_ENROLL` or `PROFILE_ENROLLMENT`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(sdk.SignOnPolicyType, sdk.PasswordPolicyType, sdk.MfaPolicyType, sdk.ProfileEnrollmentPolicyType),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Policy Name",
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "Policy Description",
+			},
+			"status": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(StatusActive),
+				Description: "Policy Status: `ACTIVE` or `INACTIVE`. Default: `ACTIVE`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(StatusActive, StatusInactive),
+				},
+			},
+			"priority": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Policy Priority, this attribute can be set to a valid priority. To avoid endless diff situation we error if an invalid priority is provided. API defaults it to the last (lowest) if not there.",
+			},
+			"groups_included": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "List of Group IDs to Include. Not supported by `PROFILE_ENROLLMENT` policies.",
+			},
+			"settings": schema.StringAttribute{
+				Optional: true,
+				Description: "Settings of the policy as a JSON object, as documented for the policy type in the Okta API, e.g. " +
+					"`jsonencode({ password = { complexity = { minLength = 12 } } })`. Settings the API adds with their default values " +
+					"aren't reported as a diff, only changes to the settings of the object are.",
+				Validators: []validator.String{
+					jsonObjectValidator{},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rule": schema.ListNestedBlock{
+				Description: "Rules of the policy, highest priority first.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the rule.",
+						},
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Policy Rule Name. Must be unique within the policy, rules are matched by name so renaming a rule replaces it.",
+						},
+						"status": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString(StatusActive),
+							Description: "Policy Rule Status: `ACTIVE` or `INACTIVE`. Default: `ACTIVE`",
+							Validators: []validator.String{
+								stringvalidator.OneOf(StatusActive, StatusInactive),
+							},
+						},
+						"priority": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Priority of the rule, its position in the list of rules.",
+						},
+						"users_excluded": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Set of User IDs to Exclude. Not supported by `PROFILE_ENROLLMENT` rules.",
+						},
+						"network_connection": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString("ANYWHERE"),
+							Description: "Network selection mode: `ANYWHERE`, `ZONE`, `ON_NETWORK`, or `OFF_NETWORK`. Default: `ANYWHERE`. Not supported by `PROFILE_ENROLLMENT` rules.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(validNetworkConnections...),
+							},
+						},
+						"network_includes": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Required if `network_connection` = `ZONE`. Indicates the network zones to include. Not supported by `PROFILE_ENROLLMENT` rules.",
+						},
+						"network_excludes": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Required if `network_connection` = `ZONE`. Indicates the network zones to exclude. Not supported by `PROFILE_ENROLLMENT` rules.",
+						},
+						"actions": schema.StringAttribute{
+							Required: true,
+							Description: "Actions of the rule as a JSON object, as documented for the policy type in the Okta API, e.g. " +
+								"`jsonencode({ signon = { access = \"ALLOW\", requireFactor = false } })` or, for a `PROFILE_ENROLLMENT` rule, " +
+								"`jsonencode({ profileEnrollment = { access = \"ALLOW\", unknownUserAction = \"REGISTER\" } })`. Actions the API adds with their default values " +
+								"aren't reported as a diff, only changes to the actions of the object are.",
+							Validators: []validator.String{
+								jsonObjectValidator{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *policyWithRulesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data policyWithRulesModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	seen := make(map[string]int, len(data.Rules))
+	for i, rule := range data.Rules {
+		if rule.Name.IsNull() || rule.Name.IsUnknown() {
+			continue
+		}
+		name := rule.Name.ValueString()
+		if prev, ok := seen[name]; ok {
+			resp.Diagnostics.AddAttributeError(path.Root("rule").AtListIndex(i).AtName("name"), "Duplicate rule name",
+				fmt.Sprintf("Rule name %q is used by both rule[%d] and rule[%d]. Each rule within a policy must have a unique name.", name, prev, i))
+			continue
+		}
+		seen[name] = i
+	}
+	if data.Type.ValueString() == sdk.ProfileEnrollmentPolicyType {
+		validateProfileEnrollmentPolicyWithRules(data, &resp.Diagnostics)
+	}
+}
+
+// validateProfileEnrollmentPolicyWithRules checks the configuration of a
+// profile enrollment policy: it has exactly one rule, and neither the policy
+// nor its rule has people or network conditions.
+func validateProfileEnrollmentPolicyWithRules(data policyWithRulesModel, diags *diag.Diagnostics) {
+	if len(data.Rules) != 1 {
+		diags.AddAttributeError(path.Root("rule"), "Invalid number of rules",
+			fmt.Sprintf("A %s policy has exactly one rule, the one Okta creates with the policy, got %d rules.", sdk.ProfileEnrollmentPolicyType, len(data.Rules)))
+	}
+	if !data.GroupsIncluded.IsNull() {
+		diags.AddAttributeError(path.Root("groups_included"), "Unsupported attribute",
+			fmt.Sprintf("groups_included can't be set on a %s policy.", sdk.ProfileEnrollmentPolicyType))
+	}
+	for i, rule := range data.Rules {
+		for name, value := range map[string]attr.Value{
+			"users_excluded":     rule.UsersExcluded,
+			"network_connection": rule.NetworkConnection,
+			"network_includes":   rule.NetworkIncludes,
+			"network_excludes":   rule.NetworkExcludes,
+		} {
+			if !value.IsNull() {
+				diags.AddAttributeError(path.Root("rule").AtListIndex(i).AtName(name), "Unsupported attribute",
+					fmt.Sprintf("%s can't be set on the rule of a %s policy.", name, sdk.ProfileEnrollmentPolicyType))
+			}
+		}
+	}
+}
+
+// ModifyPlan plans the priority of each rule as its position in the list and
+// the ID of each rule as the ID of the rule of the same name in the state.
+// Rules are matched by name because list elements are matched by position,
+// which would move IDs around when rules are reordered.
+func (r *policyWithRulesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan policyWithRulesModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	stateIDs := map[string]types.String{}
+	if !req.State.Raw.IsNull() {
+		var state policyWithRulesModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for _, rule := range state.Rules {
+			stateIDs[rule.Name.ValueString()] = rule.ID
+		}
+	}
+	for i := range plan.Rules {
+		plan.Rules[i].Priority = types.Int64Value(int64(i + 1))
+		plan.Rules[i].ID = types.StringUnknown()
+		if id, ok := stateIDs[plan.Rules[i].Name.ValueString()]; ok && !plan.Rules[i].Name.IsUnknown() {
+			plan.Rules[i].ID = id
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *policyWithRulesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan policyWithRulesModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	body, diags := r.buildPolicy(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	ruleBodies, diags := buildPolicyWithRulesRules(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var policy map[string]any
+	if _, err := r.do(ctx, http.MethodPost, "/api/v1/policies?activate=false", body, &policy); err != nil {
+		resp.Diagnostics.AddError("Unable to create policy", err.Error())
+		return
+	}
+	policyID, _ := policy["id"].(string)
+	oktaMutexKV.Lock(policyID)
+	defer oktaMutexKV.Unlock(policyID)
+
+	// the policy exists from now on, it is kept in the state even if its
+	// rules can't be created so that the next apply picks up from there
+	plan.ID = types.StringValue(policyID)
+	r.apply(ctx, policyID, plan, ruleBodies, policy, &resp.Diagnostics)
+	r.setState(ctx, plan, &resp.State, &resp.Diagnostics)
+}
+
+func (r *policyWithRulesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state policyWithRulesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy, rules, err := r.readPolicy(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read policy", err.Error())
+		return
+	}
+	if policy == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(r.updateModel(ctx, &state, policy, rules)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *policyWithRulesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan policyWithRulesModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var state policyWithRulesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policyID := state.ID.ValueString()
+	oktaMutexKV.Lock(policyID)
+	defer oktaMutexKV.Unlock(policyID)
+
+	body, diags := r.buildPolicy(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	ruleBodies, diags := buildPolicyWithRulesRules(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var policy map[string]any
+	if _, err := r.do(ctx, http.MethodPut, "/api/v1/policies/"+policyID, body, &policy); err != nil {
+		resp.Diagnostics.AddError("Unable to update policy", err.Error())
+		return
+	}
+	plan.ID = state.ID
+	r.apply(ctx, policyID, plan, ruleBodies, policy, &resp.Diagnostics)
+	r.setState(ctx, plan, &resp.State, &resp.Diagnostics)
+}
+
+func (r *policyWithRulesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state policyWithRulesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policyID := state.ID.ValueString()
+	oktaMutexKV.Lock(policyID)
+	defer oktaMutexKV.Unlock(policyID)
+
+	// the rules of a policy are deleted with it
+	resp2, err := r.OktaIDaaSClient.OktaSDKClientV2().Policy.DeletePolicy(ctx, policyID)
+	if err := utils.SuppressErrorOn404(resp2, err); err != nil {
+		resp.Diagnostics.AddError("Unable to delete policy", err.Error())
+	}
+}
+
+func (r *policyWithRulesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// apply brings the status, priority and rules of the policy in line with
+// plan after the policy itself has been created or updated. ruleBodies are
+// the request bodies of the planned rules, in the order of the plan.
+func (r *policyWithRulesResource) apply(ctx context.Context, policyID string, plan policyWithRulesModel, ruleBodies []map[string]any, policy map[string]any, diags *diag.Diagnostics) {
+	if !plan.Priority.IsNull() {
+		if err := utils.ValidatePriority(plan.Priority.ValueInt64(), jsonInt64(policy["priority"])); err != nil {
+			diags.AddAttributeError(path.Root("priority"), "Invalid policy priority", err.Error())
+		}
+	}
+	if err := r.setPolicyStatus(ctx, policyID, plan.Status.ValueString(), policy["status"]); err != nil {
+		diags.AddError("Unable to change the status of the policy", err.Error())
+		return
+	}
+	reconcile := r.reconcileRules
+	if plan.Type.ValueString() == sdk.ProfileEnrollmentPolicyType {
+		reconcile = r.reconcileProfileEnrollmentRule
+	}
+	if err := reconcile(ctx, policyID, plan, ruleBodies); err != nil {
+		diags.AddError("Unable to reconcile the rules of the policy", err.Error())
+	}
+}
+
+// setState reads the policy back into the state, on top of plan.
+func (r *policyWithRulesResource) setState(ctx context.Context, plan policyWithRulesModel, state *tfsdk.State, diags *diag.Diagnostics) {
+	policy, rules, err := r.readPolicy(ctx, plan.ID.ValueString())
+	if err != nil {
+		diags.AddError("Unable to read policy", err.Error())
+	}
+	if policy != nil {
+		diags.Append(r.updateModel(ctx, &plan, policy, rules)...)
+	}
+	diags.Append(state.Set(ctx, &plan)...)
+}
+
+func (r *policyWithRulesResource) setPolicyStatus(ctx context.Context, policyID, status string, current any) error {
+	if status == current {
+		return nil
+	}
+	client := r.OktaIDaaSClient.OktaSDKClientV2().Policy
+	if status == StatusActive {
+		if _, err := client.ActivatePolicy(ctx, policyID); err != nil {
+			return fmt.Errorf("activation has failed: %v", err)
+		}
+		return nil
+	}
+	if _, err := client.DeactivatePolicy(ctx, policyID); err != nil {
+		return fmt.Errorf("deactivation has failed: %v", err)
+	}
+	return nil
+}
+
+// reconcileRules deletes the rules of the policy that aren't planned and
+// then creates or updates the planned rules in the order of the plan. Okta
+// shifts the rules below a rule when its priority is set, so setting the
+// priorities from the top down leaves every rule in its planned position
+// after a single pass. The position of each rule is tracked along the way so
+// that rules already in place aren't updated.
+func (r *policyWithRulesResource) reconcileRules(ctx context.Context, policyID string, plan policyWithRulesModel, ruleBodies []map[string]any) error {
+	current, err := r.listRules(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	planned := make(map[string]bool, len(plan.Rules))
+	for _, rule := range plan.Rules {
+		planned[rule.Name.ValueString()] = true
+	}
+	existing := map[string]map[string]any{}
+	var order []string
+	for _, rule := range current {
+		name, _ := rule["name"].(string)
+		if system, _ := rule["system"].(bool); system {
+			continue
+		}
+		if !planned[name] {
+			ruleID, _ := rule["id"].(string)
+			resp, err := r.OktaIDaaSClient.OktaSDKClientV2().Policy.DeletePolicyRule(ctx, policyID, ruleID)
+			if err := utils.SuppressErrorOn404(resp, err); err != nil {
+				return fmt.Errorf("failed to delete rule %q: %v", name, err)
+			}
+			continue
+		}
+		existing[name] = rule
+		order = append(order, name)
+	}
+
+	for i, rule := range plan.Rules {
+		name := rule.Name.ValueString()
+		body := ruleBodies[i]
+		var result map[string]any
+		current, ok := existing[name]
+		switch {
+		case !ok:
+			if _, err := r.do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/policies/%s/rules", policyID), body, &result); err != nil {
+				return fmt.Errorf("failed to create rule %q: %v", name, err)
+			}
+			current = map[string]any{"id": result["id"], "status": result["status"]}
+			order = slices.Insert(order, min(i, len(order)), name)
+		case slices.Index(order, name) != i || !policyWithRulesRuleUpToDate(body, current):
+			if _, err := r.do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/policies/%s/rules/%s", policyID, current["id"]), body, &result); err != nil {
+				return fmt.Errorf("failed to update rule %q: %v", name, err)
+			}
+			order = slices.DeleteFunc(order, func(n string) bool { return n == name })
+			order = slices.Insert(order, min(i, len(order)), name)
+		}
+		ruleID, _ := current["id"].(string)
+		if err := r.setRuleStatus(ctx, policyID, ruleID, rule.Status.ValueString(), current["status"]); err != nil {
+			return fmt.Errorf("rule %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// reconcileProfileEnrollmentRule updates the only rule of a profile
+// enrollment policy, the one Okta created with the policy, with the planned
+// rule. It keeps the priority Okta gave it. ValidateConfig rejects plans
+// without exactly one rule.
+func (r *policyWithRulesResource) reconcileProfileEnrollmentRule(ctx context.Context, policyID string, plan policyWithRulesModel, ruleBodies []map[string]any) error {
+	if len(plan.Rules) != 1 {
+		return fmt.Errorf("a %s policy has exactly one rule, %d rules are planned", sdk.ProfileEnrollmentPolicyType, len(plan.Rules))
+	}
+	current, err := r.listRules(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	if len(current) == 0 {
+		return fmt.Errorf("the %s policy has no rule, Okta creates one with the policy", sdk.ProfileEnrollmentPolicyType)
+	}
+	rule, body := current[0], ruleBodies[0]
+	body["priority"] = rule["priority"]
+	if !policyWithRulesRuleUpToDate(body, rule) || jsonString(rule["name"]) != body["name"] {
+		var result map[string]any
+		if _, err := r.do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/policies/%s/rules/%s", policyID, rule["id"]), body, &result); err != nil {
+			return fmt.Errorf("failed to update rule %q: %v", body["name"], err)
+		}
+	}
+	ruleID, _ := rule["id"].(string)
+	if err := r.setRuleStatus(ctx, policyID, ruleID, plan.Rules[0].Status.ValueString(), rule["status"]); err != nil {
+		return fmt.Errorf("rule %q: %v", body["name"], err)
+	}
+	return nil
+}
+
+func (r *policyWithRulesResource) setRuleStatus(ctx context.Context, policyID, ruleID, status string, current any) error {
+	if status == current {
+		return nil
+	}
+	client := r.OktaIDaaSClient.OktaSDKClientV2().Policy
+	if status == StatusActive {
+		if _, err := client.ActivatePolicyRule(ctx, policyID, ruleID); err != nil {
+			return fmt.Errorf("activation has failed: %v", err)
+		}
+		return nil
+	}
+	if _, err := client.DeactivatePolicyRule(ctx, policyID, ruleID); err != nil {
+		return fmt.Errorf("deactivation has failed: %v", err)
+	}
+	return nil
+}
+
+// readPolicy returns the policy and its rules, sorted by priority, or a nil
+// policy if it doesn't exist.
+func (r *policyWithRulesResource) readPolicy(ctx context.Context, policyID string) (map[string]any, []map[string]any, error) {
+	var policy map[string]any
+	resp, err := r.do(ctx, http.MethodGet, "/api/v1/policies/"+policyID, nil, &policy)
+	if err := utils.SuppressErrorOn404(resp, err); err != nil {
+		return nil, nil, fmt.Errorf("failed to get policy: %v", err)
+	}
+	if utils.Is404(resp) {
+		return nil, nil, nil
+	}
+	rules, err := r.listRules(ctx, policyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, rules, nil
+}
+
+func (r *policyWithRulesResource) listRules(ctx context.Context, policyID string) ([]map[string]any, error) {
+	var rules []map[string]any
+	if _, err := r.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/policies/%s/rules", policyID), nil, &rules); err != nil {
+		return nil, fmt.Errorf("failed to list policy rules: %v", err)
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return jsonInt64(rules[i]["priority"]) < jsonInt64(rules[j]["priority"])
+	})
+	return rules, nil
+}
+
+// do sends a request to the policies API with a JSON body. The policies and
+// rules are handled as plain JSON so that settings and actions the SDK
+// doesn't model are kept.
+func (r *policyWithRulesResource) do(ctx context.Context, method, url string, body, v any) (*sdk.Response, error) {
+	re := r.OktaIDaaSClient.OktaSDKClientV2().GetRequestExecutor()
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	return re.Do(ctx, req, v)
+}
+
+func (r *policyWithRulesResource) buildPolicy(ctx context.Context, plan policyWithRulesModel) (map[string]any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	body := map[string]any{
+		"type": plan.Type.ValueString(),
+		"name": plan.Name.ValueString(),
+	}
+	if !plan.Description.IsNull() {
+		body["description"] = plan.Description.ValueString()
+	}
+	if !plan.Priority.IsNull() {
+		body["priority"] = plan.Priority.ValueInt64()
+	}
+	if !plan.GroupsIncluded.IsNull() {
+		var groups []string
+		diags.Append(plan.GroupsIncluded.ElementsAs(ctx, &groups, false)...)
+		body["conditions"] = map[string]any{
+			"people": map[string]any{"groups": map[string]any{"include": groups}},
+		}
+	}
+	if !plan.Settings.IsNull() {
+		var settings any
+		if err := json.Unmarshal([]byte(plan.Settings.ValueString()), &settings); err != nil {
+			diags.AddAttributeError(path.Root("settings"), "Invalid settings", err.Error())
+		}
+		body["settings"] = settings
+	}
+	return body, diags
+}
+
+// buildPolicyWithRulesRules returns the request bodies of the planned rules,
+// in the order of the plan.
+func buildPolicyWithRulesRules(ctx context.Context, plan policyWithRulesModel) ([]map[string]any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	ruleType := policyWithRulesRuleTypes[plan.Type.ValueString()]
+	bodies := make([]map[string]any, len(plan.Rules))
+	for i, rule := range plan.Rules {
+		var d diag.Diagnostics
+		bodies[i], d = buildPolicyWithRulesRule(ctx, path.Root("rule").AtListIndex(i), ruleType, int64(i+1), rule)
+		diags.Append(d...)
+	}
+	return bodies, diags
+}
+
+func buildPolicyWithRulesRule(ctx context.Context, p path.Path, ruleType string, priority int64, rule policyWithRulesRule) (map[string]any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var actions any
+	if err := json.Unmarshal([]byte(rule.Actions.ValueString()), &actions); err != nil {
+		diags.AddAttributeError(p.AtName("actions"), "Invalid actions", err.Error())
+		return nil, diags
+	}
+	body := map[string]any{
+		"type":     ruleType,
+		"name":     rule.Name.ValueString(),
+		"priority": priority,
+		"actions":  actions,
+	}
+	// the rule of a profile enrollment policy has no conditions
+	if ruleType == sdk.ProfileEnrollmentPolicyType {
+		return body, diags
+	}
+
+	network := map[string]any{"connection": rule.NetworkConnection.ValueString()}
+	var includes, excludes, users []string
+	diags.Append(rule.NetworkIncludes.ElementsAs(ctx, &includes, false)...)
+	diags.Append(rule.NetworkExcludes.ElementsAs(ctx, &excludes, false)...)
+	diags.Append(rule.UsersExcluded.ElementsAs(ctx, &users, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if len(includes) > 0 {
+		network["include"] = includes
+	}
+	if len(excludes) > 0 {
+		network["exclude"] = excludes
+	}
+	conditions := map[string]any{"network": network}
+	if len(users) > 0 {
+		conditions["people"] = map[string]any{"users": map[string]any{"exclude": users}}
+	}
+	body["conditions"] = conditions
+	return body, diags
+}
+
+// policyWithRulesRuleUpToDate tells whether the rule the API returned already
+// has the conditions and actions of body. Those body doesn't have, e.g. the
+// conditions of a profile enrollment rule, aren't compared.
+func policyWithRulesRuleUpToDate(body, current map[string]any) bool {
+	for _, key := range []string{"conditions", "actions"} {
+		if _, ok := body[key]; !ok {
+			continue
+		}
+		want, _ := json.Marshal(body[key])
+		got, _ := json.Marshal(current[key])
+		if !utils.JSONIsSubset(string(want), string(got)) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateModel sets m from the policy and rules the API returned. The JSON of
+// settings and actions is kept as configured while the API's JSON still has
+// the same values, the API adds the default values of what isn't configured.
+func (r *policyWithRulesResource) updateModel(ctx context.Context, m *policyWithRulesModel, policy map[string]any, rules []map[string]any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if id, ok := policy["id"].(string); ok {
+		m.ID = types.StringValue(id)
+	}
+	m.Type = types.StringValue(jsonString(policy["type"]))
+	m.Name = types.StringValue(jsonString(policy["name"]))
+	m.Status = types.StringValue(jsonString(policy["status"]))
+	if description := jsonString(policy["description"]); description != "" || !m.Description.IsNull() {
+		m.Description = types.StringValue(description)
+	}
+	if !m.Priority.IsNull() {
+		m.Priority = types.Int64Value(jsonInt64(policy["priority"]))
+	}
+	groups := jsonStrings(jsonPath(policy, "conditions", "people", "groups", "include"))
+	if len(groups) > 0 || !m.GroupsIncluded.IsNull() {
+		var d diag.Diagnostics
+		m.GroupsIncluded, d = types.SetValueFrom(ctx, types.StringType, groups)
+		diags.Append(d...)
+	}
+	if !m.Settings.IsNull() {
+		m.Settings = jsonKeepingConfigured(m.Settings, policy["settings"])
+	}
+
+	configured := make(map[string]policyWithRulesRule, len(m.Rules))
+	for _, rule := range m.Rules {
+		configured[rule.Name.ValueString()] = rule
+	}
+	m.Rules = []policyWithRulesRule{}
+	priority := int64(0)
+	// the only rule of a profile enrollment policy is managed even if it is
+	// a system rule
+	profileEnrollment := m.Type.ValueString() == sdk.ProfileEnrollmentPolicyType
+	for _, rule := range rules {
+		if system, _ := rule["system"].(bool); system && !profileEnrollment {
+			continue
+		}
+		priority++
+		name := jsonString(rule["name"])
+		prior, ok := configured[name]
+		if !ok {
+			prior = policyWithRulesRule{Actions: types.StringNull()}
+		}
+		model := policyWithRulesRule{
+			ID:                types.StringValue(jsonString(rule["id"])),
+			Name:              types.StringValue(name),
+			Status:            types.StringValue(jsonString(rule["status"])),
+			Priority:          types.Int64Value(priority),
+			NetworkConnection: types.StringValue(jsonString(jsonPath(rule, "conditions", "network", "connection"))),
+			Actions:           jsonKeepingConfigured(prior.Actions, rule["actions"]),
+		}
+		if model.NetworkConnection.ValueString() == "" {
+			model.NetworkConnection = types.StringValue("ANYWHERE")
+		}
+		var d diag.Diagnostics
+		model.UsersExcluded, d = nullableStringSet(ctx, jsonStrings(jsonPath(rule, "conditions", "people", "users", "exclude")))
+		diags.Append(d...)
+		model.NetworkIncludes, d = nullableStringList(ctx, jsonStrings(jsonPath(rule, "conditions", "network", "include")))
+		diags.Append(d...)
+		model.NetworkExcludes, d = nullableStringList(ctx, jsonStrings(jsonPath(rule, "conditions", "network", "exclude")))
+		diags.Append(d...)
+		m.Rules = append(m.Rules, model)
+	}
+	return diags
+}
+
+// jsonKeepingConfigured returns configured if the API's value still has
+// everything configured has, otherwise the API's value as JSON.
+func jsonKeepingConfigured(configured types.String, value any) types.String {
+	b, err := json.Marshal(value)
+	if err != nil || value == nil {
+		return configured
+	}
+	if !configured.IsNull() && !configured.IsUnknown() && utils.JSONIsSubset(configured.ValueString(), string(b)) {
+		return configured
+	}
+	return types.StringValue(string(b))
+}
+
+func nullableStringSet(ctx context.Context, values []string) (types.Set, diag.Diagnostics) {
+	if len(values) == 0 {
+		return types.SetNull(types.StringType), nil
+	}
+	return types.SetValueFrom(ctx, types.StringType, values)
+}
+
+func nullableStringList(ctx context.Context, values []string) (types.List, diag.Diagnostics) {
+	if len(values) == 0 {
+		return types.ListNull(types.StringType), nil
+	}
+	return types.ListValueFrom(ctx, types.StringType, values)
+}
+
+func jsonPath(v any, keys ...string) any {
+	for _, key := range keys {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func jsonString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func jsonInt64(v any) int64 {
+	f, _ := v.(float64)
+	return int64(f)
+}
+
+func jsonStrings(v any) []string {
+	values, _ := v.([]any)
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// jsonObjectValidator validates that a string is a JSON object.
+type jsonObjectValidator struct{}
+
+func (v jsonObjectValidator) Description(ctx context.Context) string {
+	return "value must be a JSON object"
+}
+
+func (v jsonObjectValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonObjectValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	var object map[string]any
+	if err := json.Unmarshal([]byte(req.ConfigValue.ValueString()), &object); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid JSON object", fmt.Sprintf("%s must be a JSON object: %v", req.Path, err))
+	}
+}
@@ -0,0 +1,118 @@
+package idaas_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/acctest"
+	"github.com/okta/terraform-provider-okta/okta/resources"
+)
+
+func TestAccResourceOktaPolicyWithRules_crud(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the rules are reordered from the priorities Okta shifts on every
+		// update, there are no recorded responses, run it against an org
+		return
+	}
+	mgr := newFixtureManager("resources", resources.OktaIDaaSPolicyWithRules, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("basic_updated.tf", t)
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSPolicyWithRules)
+	ruleName := func(suffix string) string {
+		return fmt.Sprintf("%s_%s", acctest.BuildResourceName(mgr.Seed), suffix)
+	}
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             checkPolicyDestroy(resources.OktaIDaaSPolicyWithRules),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "name", acctest.BuildResourceName(mgr.Seed)),
+					resource.TestCheckResourceAttr(resourceName, "status", "ACTIVE"),
+					resource.TestCheckResourceAttr(resourceName, "rule.#", "3"),
+					resource.TestCheckResourceAttr(resourceName, "rule.0.name", ruleName("a")),
+					resource.TestCheckResourceAttr(resourceName, "rule.0.priority", "1"),
+					resource.TestCheckResourceAttr(resourceName, "rule.0.network_connection", "OFF_NETWORK"),
+					resource.TestCheckResourceAttr(resourceName, "rule.1.name", ruleName("b")),
+					resource.TestCheckResourceAttr(resourceName, "rule.1.priority", "2"),
+					resource.TestCheckResourceAttr(resourceName, "rule.2.name", ruleName("c")),
+					resource.TestCheckResourceAttr(resourceName, "rule.2.priority", "3"),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", fmt.Sprintf("%s Updated", acctest.BuildResourceName(mgr.Seed))),
+					resource.TestCheckResourceAttr(resourceName, "status", "INACTIVE"),
+					resource.TestCheckResourceAttr(resourceName, "rule.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "rule.0.name", ruleName("c")),
+					resource.TestCheckResourceAttr(resourceName, "rule.0.priority", "1"),
+					resource.TestCheckResourceAttr(resourceName, "rule.1.name", ruleName("a")),
+					resource.TestCheckResourceAttr(resourceName, "rule.1.priority", "2"),
+					resource.TestCheckResourceAttr(resourceName, "rule.1.status", "INACTIVE"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"settings", "rule.0.actions", "rule.1.actions"},
+			},
+		},
+	})
+}
+
+func TestAccResourceOktaPolicyWithRules_profileEnrollment(t *testing.T) {
+	if acctest.SkipVCRTest(t) {
+		// the rule Okta creates with a profile enrollment policy is updated
+		// in place, there are no recorded responses, run it against an org
+		return
+	}
+	mgr := newFixtureManager("resources", resources.OktaIDaaSPolicyWithRules, t.Name())
+	config := mgr.GetFixtures("profile_enrollment.tf", t)
+	updatedConfig := mgr.GetFixtures("profile_enrollment_updated.tf", t)
+	invalidConfig := mgr.GetFixtures("profile_enrollment_invalid.tf", t)
+	resourceName := fmt.Sprintf("%s.test", resources.OktaIDaaSPolicyWithRules)
+	ruleName := func(suffix string) string {
+		return fmt.Sprintf("%s_%s", acctest.BuildResourceName(mgr.Seed), suffix)
+	}
+
+	acctest.OktaResourceTest(t, resource.TestCase{
+		PreCheck:                 acctest.AccPreCheck(t),
+		ErrorCheck:               testAccErrorChecks(t),
+		ProtoV5ProviderFactories: acctest.ProtoV5ProviderFactoriesForTestAcc(t),
+		CheckDestroy:             checkPolicyDestroy(resources.OktaIDaaSPolicyWithRules),
+		Steps: []resource.TestStep{
+			{
+				Config:      invalidConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Invalid number of rules.*Unsupported attribute`),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "type", "PROFILE_ENROLLMENT"),
+					resource.TestCheckResourceAttr(resourceName, "rule.#", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "rule.0.id"),
+					resource.TestCheckResourceAttr(resourceName, "rule.0.name", ruleName("a")),
+					resource.TestCheckResourceAttr(resourceName, "rule.0.priority", "1"),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "rule.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "rule.0.name", ruleName("b")),
+				),
+			},
+		},
+	})
+}
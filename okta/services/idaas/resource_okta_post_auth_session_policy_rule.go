@@ -14,8 +14,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/okta/terraform-provider-okta/okta/config"
+	"github.com/okta/terraform-provider-okta/okta/resources"
 
 	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
 	v6okta "github.com/okta/okta-sdk-golang/v6/okta"
@@ -25,6 +27,8 @@ var (
 	_ resource.Resource                = &postAuthSessionPolicyRuleResource{}
 	_ resource.ResourceWithConfigure   = &postAuthSessionPolicyRuleResource{}
 	_ resource.ResourceWithImportState = &postAuthSessionPolicyRuleResource{}
+
+	_ resources.ResourceWithRequiredCapabilities = &postAuthSessionPolicyRuleResource{}
 )
 
 func newPostAuthSessionPolicyRuleResource() resource.Resource {
@@ -35,6 +39,18 @@ type postAuthSessionPolicyRuleResource struct {
 	*config.Config
 }
 
+// RequiredCapabilities returns the capabilities of the org the resource needs,
+// running a workflow on session violations needs Okta Workflows too.
+func (r *postAuthSessionPolicyRuleResource) RequiredCapabilities(ctx context.Context, plan tfsdk.Plan) []config.Capability {
+	capabilities := []config.Capability{config.CapabilityIdentityThreatProtection}
+	var workflowID types.String
+	plan.GetAttribute(ctx, path.Root("workflow_id"), &workflowID)
+	if !workflowID.IsNull() && !workflowID.IsUnknown() && workflowID.ValueString() != "" {
+		capabilities = append(capabilities, config.CapabilityWorkflows)
+	}
+	return capabilities
+}
+
 type postAuthSessionPolicyRuleResourceModel struct {
 	ID               types.String `tfsdk:"id"`
 	PolicyID         types.String `tfsdk:"policy_id"`
@@ -78,6 +78,8 @@ func resourceUserCustomSchemaProperty() *schema.Resource {
 				},
 			},
 		),
+		// record the property so expressions planned after it can read it
+		CustomizeDiff: registerUserSchemaProperty,
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
 			{
@@ -20,7 +20,7 @@ func resourceUserGroupMemberships() *schema.Resource {
 		DeleteContext: resourceUserGroupMembershipsDelete,
 		Importer:      nil,
 		Description:   "Resource to manage a set of group memberships for a specific user. This resource allows you to bulk manage groups for a single user, independent of the user schema itself. This allows you to manage group membership in terraform without overriding other automatic membership operations performed by group rules and other non-managed actions.",
-		Schema: map[string]*schema.Schema{
+		Schema: utils.BuildSchema(map[string]*schema.Schema{
 			"user_id": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -33,10 +33,31 @@ func resourceUserGroupMemberships() *schema.Resource {
 				Description: "The list of Okta group IDs which the user should have membership managed for.",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
-		},
+		}, membershipRemovalsSchema),
+		CustomizeDiff: userGroupMembershipsCustomizeDiff,
 	}
 }
 
+// userGroupMembershipsCustomizeDiff fails the plan when it removes the user
+// from more groups than max_membership_removals allows.
+func userGroupMembershipsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.NewValueKnown("groups") {
+		return nil
+	}
+	oldUserId, _ := d.GetChange("user_id")
+	oldGroups, newGroups := d.GetChange("groups")
+	removed := oldGroups.(*schema.Set).Difference(newGroups.(*schema.Set)).Len()
+	if d.HasChange("user_id") {
+		// the old user is removed from all of its groups
+		removed = oldGroups.(*schema.Set).Len()
+	}
+	return checkMembershipRemovals(d.Get, meta, userGroupMembershipsDescription(oldUserId.(string)), removed, oldGroups.(*schema.Set).Len())
+}
+
+func userGroupMembershipsDescription(userId string) string {
+	return fmt.Sprintf("group memberships of user %s", userId)
+}
+
 func resourceUserGroupMembershipsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	userId := d.Get("user_id").(string)
 	groups := utils.ConvertInterfaceToStringSetNullable(d.Get("groups"))
@@ -86,6 +107,9 @@ func resourceUserGroupMembershipsRead(ctx context.Context, d *schema.ResourceDat
 func resourceUserGroupMembershipsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	userId := d.Get("user_id").(string)
 	groups := utils.ConvertInterfaceToStringSetNullable(d.Get("groups"))
+	if err := checkMembershipRemovals(d.Get, meta, userGroupMembershipsDescription(userId), len(groups), len(groups)); err != nil {
+		return diag.FromErr(err)
+	}
 	client := getOktaClientFromMetadata(meta)
 	err := removeUserFromGroups(ctx, client, userId, groups)
 	if err != nil {
@@ -104,6 +128,10 @@ func resourceUserGroupMembershipsUpdate(ctx context.Context, d *schema.ResourceD
 
 	groupsToAdd := utils.ConvertInterfaceArrToStringArr(newSet.Difference(oldSet).List())
 	groupsToRemove := utils.ConvertInterfaceArrToStringArr(oldSet.Difference(newSet).List())
+	if err := checkMembershipRemovals(d.Get, meta, userGroupMembershipsDescription(userId), len(groupsToRemove), oldSet.Len()); err != nil {
+		d.Partial(true)
+		return diag.FromErr(err)
+	}
 
 	err := addUserToGroups(ctx, client, userId, groupsToAdd)
 	if err != nil {
@@ -0,0 +1,346 @@
+package idaas
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/utils"
+)
+
+// samlMetadataTimeout is how long fetching the metadata of a SAML service
+// provider from its URL may take.
+const samlMetadataTimeout = 30 * time.Second
+
+var (
+	// samlAppMetadataFields are the arguments of okta_app_saml filled from the
+	// metadata of its service provider
+	samlAppMetadataFields = []string{
+		"sso_url",
+		"recipient",
+		"destination",
+		"audience",
+		"subject_name_id_format",
+		"single_logout_issuer",
+		"single_logout_url",
+		"single_logout_certificate",
+	}
+	// samlAppACSFields are the arguments of okta_app_saml holding the ACS
+	// endpoints, only one of them is set
+	samlAppACSFields = []string{"acs_endpoints", "acs_endpoints_indices"}
+	// samlIdpMetadataFields are the arguments of okta_idp_saml filled from the
+	// metadata of its identity provider, kid aside
+	samlIdpMetadataFields = []string{
+		"sso_url",
+		"issuer",
+		"subject_format",
+	}
+)
+
+// parseSAMLMetadata returns the entity described by the SAML metadata raw. An
+// EntitiesDescriptor must describe exactly one entity.
+func parseSAMLMetadata(raw string) (*saml.EntityDescriptor, error) {
+	var entity saml.EntityDescriptor
+	err := xml.Unmarshal([]byte(raw), &entity)
+	if err == nil {
+		return &entity, nil
+	}
+	var entities saml.EntitiesDescriptor
+	if xml.Unmarshal([]byte(raw), &entities) != nil {
+		return nil, fmt.Errorf("invalid SAML metadata: %w", err)
+	}
+	if len(entities.EntityDescriptors) != 1 {
+		return nil, fmt.Errorf("the SAML metadata describes %d entities, expected one", len(entities.EntityDescriptors))
+	}
+	return &entities.EntityDescriptors[0], nil
+}
+
+// fetchSAMLMetadata returns the SAML metadata published at url.
+func fetchSAMLMetadata(ctx context.Context, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, samlMetadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/samlmetadata+xml, application/xml, text/xml")
+	resp, err := cleanhttp.DefaultClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the SAML metadata at %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch the SAML metadata at %s: received status code %d", url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the SAML metadata at %s: %w", url, err)
+	}
+	return string(body), nil
+}
+
+// signingCertificate returns the base64 encoded certificate signing the
+// messages of a SAML entity, empty when there is none.
+func signingCertificate(keys []saml.KeyDescriptor) string {
+	for _, key := range keys {
+		if key.Use != "" && key.Use != "signing" {
+			continue
+		}
+		for _, cert := range key.KeyInfo.X509Data.X509Certificates {
+			if data := strings.Join(strings.Fields(cert.Data), ""); data != "" {
+				return data
+			}
+		}
+	}
+	return ""
+}
+
+// samlAppMetadataValues returns the values of the okta_app_saml arguments
+// described by the metadata of its service provider.
+func samlAppMetadataValues(entity *saml.EntityDescriptor) (map[string]interface{}, error) {
+	if len(entity.SPSSODescriptors) == 0 {
+		return nil, errors.New("the SAML metadata has no SPSSODescriptor")
+	}
+	sp := entity.SPSSODescriptors[0]
+
+	// Okta only posts assertions
+	var acs []saml.IndexedEndpoint
+	for _, endpoint := range sp.AssertionConsumerServices {
+		if endpoint.Binding == postBinding {
+			acs = append(acs, endpoint)
+		}
+	}
+	if len(acs) == 0 {
+		return nil, errors.New("the SAML metadata has no AssertionConsumerService with the HTTP-POST binding")
+	}
+	sort.SliceStable(acs, func(i, j int) bool { return acs[i].Index < acs[j].Index })
+	ssoURL := acs[0].Location
+	for _, endpoint := range acs {
+		if endpoint.IsDefault != nil && *endpoint.IsDefault {
+			ssoURL = endpoint.Location
+			break
+		}
+	}
+
+	values := map[string]interface{}{
+		"sso_url":     ssoURL,
+		"recipient":   ssoURL,
+		"destination": ssoURL,
+		"audience":    entity.EntityID,
+	}
+	if len(sp.NameIDFormats) > 0 {
+		values["subject_name_id_format"] = string(sp.NameIDFormats[0])
+	}
+	if cert := signingCertificate(sp.KeyDescriptors); cert != "" {
+		values["single_logout_certificate"] = cert
+	}
+	for _, binding := range []string{postBinding, redirectBinding} {
+		for _, slo := range sp.SingleLogoutServices {
+			if slo.Binding == binding && values["single_logout_url"] == nil {
+				values["single_logout_url"] = slo.Location
+				values["single_logout_issuer"] = entity.EntityID
+			}
+		}
+	}
+	if len(acs) > 1 {
+		// like the app is read, sequential indices make a list of endpoints
+		sequential := true
+		urls := make([]interface{}, len(acs))
+		indices := make([]interface{}, len(acs))
+		for i, endpoint := range acs {
+			sequential = sequential && endpoint.Index == i
+			urls[i] = endpoint.Location
+			indices[i] = map[string]interface{}{"url": endpoint.Location, "index": endpoint.Index}
+		}
+		if sequential {
+			values["acs_endpoints"] = urls
+		} else {
+			values["acs_endpoints_indices"] = indices
+		}
+	}
+	return values, nil
+}
+
+// acsEndpointPairs returns the ACS endpoints of a list of URLs and of a set of
+// URLs with indices as maps with url and index keys, sorted by index.
+func acsEndpointPairs(endpoints, indices interface{}) []interface{} {
+	var pairs []interface{}
+	urls, _ := endpoints.([]interface{})
+	for i, url := range urls {
+		pairs = append(pairs, map[string]interface{}{"url": url, "index": i})
+	}
+	switch indices := indices.(type) {
+	case *schema.Set:
+		pairs = append(pairs, indices.List()...)
+	case []interface{}:
+		pairs = append(pairs, indices...)
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].(map[string]interface{})["index"].(int) < pairs[j].(map[string]interface{})["index"].(int)
+	})
+	return pairs
+}
+
+// samlIdpMetadataValues returns the values of the okta_idp_saml arguments
+// described by the metadata of its identity provider, and its signing
+// certificate. The SSO endpoint is the one of binding.
+func samlIdpMetadataValues(entity *saml.EntityDescriptor, binding string) (map[string]interface{}, string, error) {
+	if len(entity.IDPSSODescriptors) == 0 {
+		return nil, "", errors.New("the SAML metadata has no IDPSSODescriptor")
+	}
+	idp := entity.IDPSSODescriptors[0]
+	samlBinding := postBinding
+	if binding == redirectBindingAlias {
+		samlBinding = redirectBinding
+	}
+	values := map[string]interface{}{
+		"issuer": entity.EntityID,
+	}
+	for _, sso := range idp.SingleSignOnServices {
+		if sso.Binding == samlBinding {
+			values["sso_url"] = sso.Location
+			break
+		}
+	}
+	if values["sso_url"] == nil {
+		return nil, "", fmt.Errorf("the SAML metadata has no SingleSignOnService with the %s binding of sso_binding", binding)
+	}
+	if len(idp.NameIDFormats) > 0 {
+		formats := make([]interface{}, len(idp.NameIDFormats))
+		for i, format := range idp.NameIDFormats {
+			formats[i] = string(format)
+		}
+		values["subject_format"] = formats
+	}
+	cert := signingCertificate(idp.KeyDescriptors)
+	if cert == "" {
+		return nil, "", errors.New("the SAML metadata has no signing certificate")
+	}
+	return values, cert, nil
+}
+
+// setFromSAMLMetadata sets the fields that aren't configured to their values
+// in the SAML metadata, and the fields the metadata leaves out to their zero
+// value as if they weren't computed. It returns an error listing the
+// configured fields whose value differs from the metadata.
+func setFromSAMLMetadata(d *schema.ResourceDiff, source string, fields []string, values map[string]interface{}) error {
+	var conflicts []string
+	for _, field := range fields {
+		raw := d.GetRawConfig().GetAttr(field)
+		if !raw.IsWhollyKnown() {
+			continue
+		}
+		value, described := values[field]
+		if !raw.IsNull() {
+			if described && !samlMetadataValueEqual(d.Get(field), value) {
+				conflicts = append(conflicts, fmt.Sprintf("%s is set to %v but the metadata has %v", field, d.Get(field), value))
+			}
+			continue
+		}
+		if described {
+			if err := d.SetNew(field, value); err != nil {
+				return err
+			}
+			continue
+		}
+		old, _ := d.GetChange(field)
+		if err := d.SetNew(field, zeroValueOf(old)); err != nil {
+			return err
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("the arguments set in the configuration conflict with %s, remove them or make them match: %s", source, strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// clearUnconfigured sets the fields that aren't configured to their zero
+// value, as if they weren't computed, when there is no metadata to fill them.
+func clearUnconfigured(d *schema.ResourceDiff, fields []string) error {
+	return setFromSAMLMetadata(d, "", fields, nil)
+}
+
+// setUnconfiguredComputed marks the fields that aren't configured as known
+// after apply, e.g. when the metadata isn't known yet.
+func setUnconfiguredComputed(d *schema.ResourceDiff, fields []string) error {
+	for _, field := range fields {
+		if !d.GetRawConfig().GetAttr(field).IsNull() {
+			continue
+		}
+		if err := d.SetNewComputed(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// samlMetadataValueEqual compares a configured value with a value of the
+// metadata, certificates by their content.
+func samlMetadataValueEqual(configured, value interface{}) bool {
+	if a, ok := configured.(string); ok {
+		b, _ := value.(string)
+		if a == b {
+			return true
+		}
+		aCert, aErr := utils.CertNormalize(a)
+		bCert, bErr := utils.CertNormalize(b)
+		return aErr == nil && bErr == nil && aCert.Equal(bCert)
+	}
+	set, unordered := configured.(*schema.Set)
+	if unordered {
+		configured = set.List()
+	}
+	a, _ := configured.([]interface{})
+	b, _ := value.([]interface{})
+	if len(a) != len(b) {
+		return false
+	}
+	if !unordered {
+		for i := range a {
+			if !samlMetadataElementEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	matched := make([]bool, len(b))
+	for _, x := range a {
+		found := false
+		for j, y := range b {
+			if !matched[j] && samlMetadataElementEqual(x, y) {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func samlMetadataElementEqual(a, b interface{}) bool {
+	am, aOK := a.(map[string]interface{})
+	bm, bOK := b.(map[string]interface{})
+	if aOK && bOK {
+		return am["url"] == bm["url"] && fmt.Sprint(am["index"]) == fmt.Sprint(bm["index"])
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func zeroValueOf(v interface{}) interface{} {
+	if _, ok := v.(string); ok {
+		return ""
+	}
+	return []interface{}{}
+}
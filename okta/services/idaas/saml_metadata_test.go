@@ -0,0 +1,355 @@
+package idaas
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSAMLEncryptionCert = "MIIencryption"
+	testSAMLSigningCert    = "MIIsigning"
+)
+
+// testSPMetadata returns the metadata of a service provider with the given
+// AssertionConsumerService elements.
+func testSPMetadata(acs string) string {
+	return fmt.Sprintf(`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="https://sp.example.com">
+  <md:SPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="encryption">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>
+        %s
+      </ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://sp.example.com/slo/redirect"/>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/slo/post"/>
+    <md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</md:NameIDFormat>
+    %s
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>`, testSAMLEncryptionCert, testSAMLSigningCert, acs)
+}
+
+func testACS(binding string, index int, isDefault string) string {
+	if isDefault != "" {
+		isDefault = fmt.Sprintf(` isDefault="%s"`, isDefault)
+	}
+	return fmt.Sprintf(`<md:AssertionConsumerService Binding="%s" Location="https://sp.example.com/acs/%d" index="%d"%s/>`, binding, index, index, isDefault)
+}
+
+const testIdPMetadata = `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="https://idp.example.com">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="encryption">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>MIIencryption</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:KeyDescriptor>
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>MIIsigning</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</md:NameIDFormat>
+    <md:NameIDFormat>urn:oasis:names:tc:SAML:2.0:nameid-format:persistent</md:NameIDFormat>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso/redirect"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/sso/post"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+
+func TestParseSAMLMetadata(t *testing.T) {
+	entity := `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://%s.example.com"></md:EntityDescriptor>`
+	tests := []struct {
+		name     string
+		raw      string
+		entityID string
+		err      string
+	}{
+		{name: "EntityDescriptor", raw: fmt.Sprintf(entity, "one"), entityID: "https://one.example.com"},
+		{
+			name:     "EntitiesDescriptor with one entity",
+			raw:      `<md:EntitiesDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata">` + fmt.Sprintf(entity, "one") + `</md:EntitiesDescriptor>`,
+			entityID: "https://one.example.com",
+		},
+		{
+			name: "EntitiesDescriptor with two entities",
+			raw:  `<md:EntitiesDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata">` + fmt.Sprintf(entity, "one") + fmt.Sprintf(entity, "two") + `</md:EntitiesDescriptor>`,
+			err:  "the SAML metadata describes 2 entities, expected one",
+		},
+		{name: "not XML", raw: "metadata", err: "invalid SAML metadata"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseSAMLMetadata(test.raw)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.entityID, got.EntityID)
+		})
+	}
+}
+
+func TestSAMLAppMetadataValues(t *testing.T) {
+	common := map[string]interface{}{
+		"audience":                  "https://sp.example.com",
+		"subject_name_id_format":    "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+		"single_logout_certificate": testSAMLSigningCert,
+		"single_logout_url":         "https://sp.example.com/slo/post",
+		"single_logout_issuer":      "https://sp.example.com",
+	}
+	withSSOURL := func(url string, more map[string]interface{}) map[string]interface{} {
+		values := map[string]interface{}{"sso_url": url, "recipient": url, "destination": url}
+		for k, v := range common {
+			values[k] = v
+		}
+		for k, v := range more {
+			values[k] = v
+		}
+		return values
+	}
+	tests := []struct {
+		name   string
+		acs    string
+		values map[string]interface{}
+		err    string
+	}{
+		{
+			name:   "one endpoint",
+			acs:    testACS(postBinding, 0, ""),
+			values: withSSOURL("https://sp.example.com/acs/0", nil),
+		},
+		{
+			name: "sequential indices",
+			acs:  testACS(postBinding, 1, "") + testACS(postBinding, 0, ""),
+			values: withSSOURL("https://sp.example.com/acs/0", map[string]interface{}{
+				"acs_endpoints": []interface{}{"https://sp.example.com/acs/0", "https://sp.example.com/acs/1"},
+			}),
+		},
+		{
+			name: "indices with gaps and a default endpoint",
+			acs:  testACS(postBinding, 5, "") + testACS(postBinding, 2, "") + testACS(postBinding, 7, "true"),
+			values: withSSOURL("https://sp.example.com/acs/7", map[string]interface{}{
+				"acs_endpoints_indices": []interface{}{
+					map[string]interface{}{"url": "https://sp.example.com/acs/2", "index": 2},
+					map[string]interface{}{"url": "https://sp.example.com/acs/5", "index": 5},
+					map[string]interface{}{"url": "https://sp.example.com/acs/7", "index": 7},
+				},
+			}),
+		},
+		{
+			name:   "endpoints of other bindings are left out",
+			acs:    testACS(redirectBinding, 0, "true") + testACS(postBinding, 1, ""),
+			values: withSSOURL("https://sp.example.com/acs/1", nil),
+		},
+		{
+			name: "no HTTP-POST endpoint",
+			acs:  testACS(redirectBinding, 0, ""),
+			err:  "the SAML metadata has no AssertionConsumerService with the HTTP-POST binding",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entity, err := parseSAMLMetadata(testSPMetadata(test.acs))
+			require.NoError(t, err)
+			values, err := samlAppMetadataValues(entity)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.values, values)
+		})
+	}
+
+	entity, err := parseSAMLMetadata(testIdPMetadata)
+	require.NoError(t, err)
+	_, err = samlAppMetadataValues(entity)
+	require.EqualError(t, err, "the SAML metadata has no SPSSODescriptor")
+}
+
+func TestSAMLIdpMetadataValues(t *testing.T) {
+	formats := []interface{}{
+		"urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+		"urn:oasis:names:tc:SAML:2.0:nameid-format:persistent",
+	}
+	tests := []struct {
+		name     string
+		metadata string
+		binding  string
+		values   map[string]interface{}
+		cert     string
+		err      string
+	}{
+		{
+			name:     "HTTP-POST",
+			metadata: testIdPMetadata,
+			binding:  postBindingAlias,
+			values:   map[string]interface{}{"issuer": "https://idp.example.com", "sso_url": "https://idp.example.com/sso/post", "subject_format": formats},
+			cert:     testSAMLSigningCert,
+		},
+		{
+			name:     "HTTP-REDIRECT",
+			metadata: testIdPMetadata,
+			binding:  redirectBindingAlias,
+			values:   map[string]interface{}{"issuer": "https://idp.example.com", "sso_url": "https://idp.example.com/sso/redirect", "subject_format": formats},
+			cert:     testSAMLSigningCert,
+		},
+		{
+			name:     "EntitiesDescriptor",
+			metadata: `<md:EntitiesDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata">` + testIdPMetadata + `</md:EntitiesDescriptor>`,
+			binding:  postBindingAlias,
+			values:   map[string]interface{}{"issuer": "https://idp.example.com", "sso_url": "https://idp.example.com/sso/post", "subject_format": formats},
+			cert:     testSAMLSigningCert,
+		},
+		{
+			name: "only an encryption certificate",
+			metadata: `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="https://idp.example.com">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="encryption">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>MIIencryption</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/sso/post"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`,
+			binding: postBindingAlias,
+			err:     "the SAML metadata has no signing certificate",
+		},
+		{
+			name:     "no IDPSSODescriptor",
+			metadata: testSPMetadata(testACS(postBinding, 0, "")),
+			binding:  postBindingAlias,
+			err:      "the SAML metadata has no IDPSSODescriptor",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entity, err := parseSAMLMetadata(test.metadata)
+			require.NoError(t, err)
+			values, cert, err := samlIdpMetadataValues(entity, test.binding)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.values, values)
+			require.Equal(t, test.cert, cert)
+		})
+	}
+}
+
+// testSAMLMetadataResource is a resource whose arguments are filled from SAML
+// metadata values by its CustomizeDiff.
+func testSAMLMetadataResource(values map[string]interface{}) *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"sso_url":        {Type: schema.TypeString, Optional: true, Computed: true},
+			"issuer":         {Type: schema.TypeString, Optional: true, Computed: true},
+			"subject_format": {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true, Computed: true},
+		},
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			return setFromSAMLMetadata(d, "the metadata", []string{"sso_url", "issuer", "subject_format"}, values)
+		},
+	}
+}
+
+func TestSetFromSAMLMetadata(t *testing.T) {
+	values := map[string]interface{}{
+		"sso_url":        "https://idp.example.com/sso/post",
+		"subject_format": []interface{}{"b", "a"},
+	}
+	tests := []struct {
+		name   string
+		config map[string]cty.Value
+		state  map[string]string
+		diff   map[string]string
+		err    string
+	}{
+		{
+			// the SDK plans the zero value of a computed argument as known
+			// after apply, the value of the state isn't kept
+			name: "unconfigured arguments are filled, the ones left out are cleared",
+			state: map[string]string{
+				"id":     "1",
+				"issuer": "https://old.example.com",
+			},
+			diff: map[string]string{
+				"sso_url":          "https://idp.example.com/sso/post",
+				"issuer":           testKnownAfterApply,
+				"subject_format.#": "2",
+			},
+		},
+		{
+			name: "configured arguments matching the metadata",
+			config: map[string]cty.Value{
+				"sso_url":        cty.StringVal("https://idp.example.com/sso/post"),
+				"issuer":         cty.StringVal("https://issuer.example.com"),
+				"subject_format": cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			},
+			diff: map[string]string{
+				"sso_url":          "https://idp.example.com/sso/post",
+				"issuer":           "https://issuer.example.com",
+				"subject_format.#": "2",
+			},
+		},
+		{
+			name: "configured arguments conflicting with the metadata",
+			config: map[string]cty.Value{
+				"sso_url":        cty.StringVal("https://other.example.com/sso"),
+				"subject_format": cty.SetVal([]cty.Value{cty.StringVal("a")}),
+			},
+			err: "the arguments set in the configuration conflict with the metadata, remove them or make them match: sso_url is set to https://other.example.com/sso but the metadata has https://idp.example.com/sso/post; subject_format is set to",
+		},
+		{
+			name:   "unknown arguments are left alone",
+			config: map[string]cty.Value{"sso_url": cty.UnknownVal(cty.String)},
+			diff: map[string]string{
+				"sso_url":          testKnownAfterApply,
+				"issuer":           testKnownAfterApply,
+				"subject_format.#": "2",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := testSAMLMetadataResource(values)
+			configSchema := r.CoreConfigSchema()
+			attributes := map[string]cty.Value{}
+			for name, attributeType := range configSchema.ImpliedType().AttributeTypes() {
+				attributes[name] = cty.NullVal(attributeType)
+				if value, ok := test.config[name]; ok {
+					attributes[name] = value
+				}
+			}
+			config := cty.ObjectVal(attributes)
+			state := &terraform.InstanceState{Attributes: test.state, RawConfig: config}
+			if id, ok := test.state["id"]; ok {
+				state.ID = id
+			}
+			diff, err := r.SimpleDiff(context.Background(), state, terraform.NewResourceConfigShimmed(config, configSchema), nil)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			got := map[string]string{}
+			for name, attribute := range diff.Attributes {
+				if attribute.NewComputed {
+					got[name] = testKnownAfterApply
+					continue
+				}
+				if name == "sso_url" || name == "issuer" || name == "subject_format.#" {
+					got[name] = attribute.New
+				}
+			}
+			require.Equal(t, test.diff, got)
+		})
+	}
+}
+
+// testKnownAfterApply stands for the values known after apply in the expected
+// diffs.
+const testKnownAfterApply = "(known after apply)"
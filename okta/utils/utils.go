@@ -737,6 +737,51 @@ func stripFalseBools(m map[string]any) {
 	}
 }
 
+// JSONIsSubset returns true if subsetJSON and supersetJSON are valid JSON and
+// every value of subsetJSON is also in supersetJSON: objects may have more
+// keys in supersetJSON, arrays must have the same length and the other values
+// must be equal. It tells whether what a user configured is still what the
+// API returns when the API adds defaults to the object.
+func JSONIsSubset(subsetJSON, supersetJSON string) bool {
+	var subset, superset any
+	if err := json.Unmarshal([]byte(subsetJSON), &subset); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(supersetJSON), &superset); err != nil {
+		return false
+	}
+	return isSubset(subset, superset)
+}
+
+func isSubset(subset, superset any) bool {
+	switch sub := subset.(type) {
+	case map[string]any:
+		super, ok := superset.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, v := range sub {
+			if !isSubset(v, super[k]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		super, ok := superset.([]any)
+		if !ok || len(sub) != len(super) {
+			return false
+		}
+		for i := range sub {
+			if !isSubset(sub[i], super[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(subset, superset)
+	}
+}
+
 // NoChangeInObjectWithSortedSlicesFromUnmarshaledJSON Intended for use by a DiffSuppressFunc,
 // returns true if old and new JSONs are equivalent object representations no matter the order of any slices...
 // It is true, there is no change!  Edge chase if newJSON is blank, will also
@@ -940,6 +985,51 @@ func StrMaxLength(max int) schema.SchemaValidateDiagFunc {
 	}
 }
 
+// RemovalLimit is the largest number of memberships a change may remove,
+// either as a number of memberships or as a percentage of the existing ones.
+type RemovalLimit struct {
+	Max     int
+	Percent bool
+}
+
+// ParseRemovalLimit parses a limit like "100" or "10%".
+func ParseRemovalLimit(s string) (RemovalLimit, error) {
+	value, percent := strings.CutSuffix(strings.TrimSpace(s), "%")
+	max, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || max < 0 || (percent && max > 100) {
+		return RemovalLimit{}, fmt.Errorf("%q is not a number of memberships like \"100\" or a percentage like \"10%%\"", s)
+	}
+	return RemovalLimit{Max: max, Percent: percent}, nil
+}
+
+// Allows reports whether removing removals of total memberships is within the
+// limit.
+func (l RemovalLimit) Allows(removals, total int) bool {
+	if !l.Percent {
+		return removals <= l.Max
+	}
+	return removals*100 <= l.Max*total
+}
+
+func (l RemovalLimit) String() string {
+	if l.Percent {
+		return fmt.Sprintf("%d%%", l.Max)
+	}
+	return strconv.Itoa(l.Max)
+}
+
+// ValidateRemovalLimit validates that the string is a RemovalLimit.
+func ValidateRemovalLimit(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %s to be string", k)
+	}
+	if _, err := ParseRemovalLimit(v); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
 // Helper function to convert []string to []types.String
 func ConvertStringSlice(slice []string) []types.String {
 	result := make([]types.String, len(slice))
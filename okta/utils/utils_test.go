@@ -372,6 +372,58 @@ func TestNoChangeInObjectUnmarshaledFromJSON(t *testing.T) {
 	}
 }
 
+func TestJSONIsSubset(t *testing.T) {
+	tests := []struct {
+		subset   string
+		superset string
+		expected bool
+	}{
+		{`{"a":1}`, `{"a":1,"b":2}`, true},
+		{`{"a":{"b":true}}`, `{"a":{"b":true,"c":"x"},"d":[]}`, true},
+		{`{"a":[{"b":1}]}`, `{"a":[{"b":1,"c":2}]}`, true},
+		{`{}`, `{"a":1}`, true},
+		{`{"a":1}`, `{"a":2}`, false},
+		{`{"a":1}`, `{"b":1}`, false},
+		{`{"a":null}`, `{}`, true},
+		{`{"a":[1]}`, `{"a":[1,2]}`, false},
+		{`{"a":[1,2]}`, `{"a":[2,1]}`, false},
+		{`{"a":"1"}`, `{"a":1}`, false},
+		{`{"a":1}`, `not json`, false},
+		{``, `{}`, false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, JSONIsSubset(test.subset, test.superset), "%s in %s", test.subset, test.superset)
+	}
+}
+
+func TestRemovalLimit(t *testing.T) {
+	tests := []struct {
+		limit    string
+		removals int
+		total    int
+		expected bool
+	}{
+		{"100", 100, 4000, true},
+		{"100", 101, 4000, false},
+		{" 0 ", 0, 10, true},
+		{"0", 1, 10, false},
+		{"10%", 400, 4000, true},
+		{"10%", 401, 4000, false},
+		{"10 %", 1, 10, true},
+		{"0%", 0, 0, true},
+		{"100%", 10, 10, true},
+	}
+	for _, test := range tests {
+		limit, err := ParseRemovalLimit(test.limit)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, limit.Allows(test.removals, test.total), "%d of %d within %s", test.removals, test.total, test.limit)
+	}
+	for _, invalid := range []string{"", "-1", "101%", "ten", "10%%", "1.5"} {
+		_, err := ParseRemovalLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
 func TestIntersection(t *testing.T) {
 	old := []string{"a", "b", "c", "d", "e"}
 	new := []string{"c", "d", "e", "f", "g"}